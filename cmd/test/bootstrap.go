@@ -1,7 +1,6 @@
 package main
 
 import (
-	"market-observer/src/analysis"
 	"market-observer/src/interfaces"
 	"market-observer/src/logger"
 	"market-observer/src/models"
@@ -13,11 +12,11 @@ import (
 func performInitialLoad(
 	source interfaces.IDataSource,
 	db interfaces.IDatabase,
-	analyzer *analysis.AnalysisFacade,
+	svc *pipelineServices,
 	memManager *utils.MemoryManager,
 	config *models.MConfig,
 	appLogger *logger.Logger,
-) (map[string]interface{}, map[string]map[string]models.MIntermediateStats, error) {
+) (map[string]interface{}, error) {
 
 	appLogger.Info("Fetching initial data...")
 	initialData, err := source.FetchInitialData()
@@ -27,6 +26,12 @@ func performInitialLoad(
 		// Original code warned but continued.
 	}
 
+	// History goes through the same validation as live updates
+	initialData = svc.Validator.ValidateBatch(initialData)
+
+	// Synthetic instruments are rebuilt from the validated history of their legs
+	initialData = svc.Synthetics.Apply(initialData)
+
 	// Populate Memory Manager with initial data
 	for sym, dataList := range initialData {
 		for _, p := range dataList {
@@ -34,45 +39,40 @@ func performInitialLoad(
 		}
 	}
 
+	// Candles are built from the gap-filled series (memory and raw storage keep the feed as received)
+	candleData := svc.Quality.ProcessBatch(initialData)
+
 	// Initial Processing and Aggregation
-	intermediateStats := make(map[string]map[string]models.MIntermediateStats)
 	initialAggsForServer := make(map[string]map[string][]models.MAggregation)
 	initialValidSymbols := len(initialData)
 
+	// Restore rolling stats persisted by a previous run
+	stats := svc.Engine.Stats
+	persisted, err := db.LoadIntermediateStats()
+	if err != nil {
+		appLogger.Warning("Failed to load persisted stats: %v", err)
+	}
+	stats.Load(persisted)
+	appLogger.Info("Restored %d rolling stats entries from storage", len(persisted))
+
 	// Process per window
-	wStatsMap := analyzer.CalculateStatsForWindows(initialData, config.WindowsAgg)
+	wStatsMap := svc.Analyzer.CalculateStatsForWindows(candleData, config.WindowsAgg)
 
 	for _, w := range config.WindowsAgg {
-		// Save stats
-		var statsList []models.MIntermediateStats
-
-		// Extract stats for this window from the bulk result
+		// Symbols without persisted stats start from the history batch
+		restored := make(map[string]models.MIntermediateStats)
 		for sym := range initialData {
-			if symStats, ok := wStatsMap[sym]; ok {
-				if s, ok := symStats[w]; ok {
-					statsList = append(statsList, s)
-					if intermediateStats[s.Symbol] == nil {
-						intermediateStats[s.Symbol] = make(map[string]models.MIntermediateStats)
-					}
-					intermediateStats[s.Symbol][w] = s
-				}
+			if s, ok := stats.Get(sym, w); ok {
+				restored[sym] = s
+				continue
 			}
-		}
-
-		if len(statsList) > 0 {
-			db.SaveIntermediateStats(statsList)
-		}
-
-		// Initial Aggregation
-		// Extract stats for this window
-		currentWindowStats := make(map[string]models.MIntermediateStats)
-		for sym, wMap := range intermediateStats {
-			if s, ok := wMap[w]; ok {
-				currentWindowStats[sym] = s
+			if s, ok := wStatsMap[sym][w]; ok {
+				stats.Set(s)
 			}
 		}
 
-		aggs := analyzer.AggregateHistorical(initialData, w, currentWindowStats)
+		// Initial Aggregation
+		aggs := svc.Analyzer.AggregateHistorical(candleData, w, stats.ForWindow(w))
 
 		// Save Aggs & Buffer for Server
 		aggMap := make(map[string]map[string][]models.MAggregation)
@@ -87,14 +87,40 @@ func performInitialLoad(
 			if candles, ok := innerMap[w]; ok {
 				aggMap[sym][w] = append(aggMap[sym][w], candles...)
 
-				// Capture Latest Candle for server state
 				if len(candles) > 0 {
+					// Capture Latest Candle for server state
 					latest := candles[len(candles)-1]
 					initialAggsForServer[sym][w] = []models.MAggregation{latest}
+
+					// Catch restored stats up with the closed candles they have not seen
+					// (the last candle is still open and will close in the data loop)
+					if s, ok := restored[sym]; ok {
+						for _, c := range candles[:len(candles)-1] {
+							if c.StartTime > s.LastHistoryTimestamp {
+								stats.Update(c)
+							}
+						}
+					}
 				}
 			}
 		}
+		// Historical candles carry their patterns (no events replayed)
+		svc.Patterns.Seed(aggMap)
 		db.SaveAggregations(aggMap)
+
+		// Breadth averages and session highs/lows start from the history
+		svc.Breadth.Seed(aggMap)
+
+		// Regimes are established on the history (no events replayed)
+		svc.Changepoints.Seed(aggMap)
+
+		// Leaderboards start from the latest historical candles
+		svc.Leaderboards.Seed(aggMap, time.Now().UTC().Unix())
+	}
+
+	// Save stats (batch-computed and caught-up entries)
+	if statsList := stats.TakeDirty(); len(statsList) > 0 {
+		db.SaveIntermediateStats(statsList)
 	}
 
 	// Save Raw Data (Bulk)
@@ -104,6 +130,9 @@ func performInitialLoad(
 	}
 	db.SaveStockPricesBulk(allRaw)
 
+	// Seed the incremental engine so realtime updates continue the historical candles
+	svc.Engine.Warmup(candleData)
+
 	appLogger.Info("Initialization complete.")
 
 	// Construct Initial Payload
@@ -126,5 +155,5 @@ func performInitialLoad(
 		},
 	}
 
-	return initialPayload, intermediateStats, nil
+	return initialPayload, nil
 }
@@ -1,11 +1,15 @@
 package main
 
 import (
+	"market-observer/src/alerts"
 	"market-observer/src/analysis"
 	"market-observer/src/interfaces"
 	"market-observer/src/logger"
 	"market-observer/src/models"
+	"market-observer/src/notifications"
+	"market-observer/src/synthetic"
 	"market-observer/src/utils"
+	"market-observer/src/validation"
 	"os"
 	"os/signal"
 	"syscall"
@@ -14,21 +18,53 @@ import (
 
 // -----------------------------------------------------------------------------
 
+// pipelineServices groups the processing stages shared by the bootstrap and
+// the data loop. Optional stages are nil-safe; new stages are added here.
+type pipelineServices struct {
+	Validator    *validation.Validator
+	Synthetics   *synthetic.Builder
+	Analyzer     *analysis.AnalysisFacade
+	Engine       *analysis.CandleEngine // State carried over from bootstrap
+	Quality      *analysis.DataQualityMonitor
+	Breadth      *analysis.BreadthCalculator
+	Changepoints *analysis.ChangepointDetector
+	Patterns     *analysis.PatternDetector
+	Leaderboards *analysis.LeaderboardService
+	Correlation  *analysis.CorrelationService
+	LeadLag      *analysis.LeadLagService
+	Levels       *analysis.LevelService
+	AlertRules   *alerts.RulesEngine
+	Notifier     *notifications.Dispatcher
+}
+
+// -----------------------------------------------------------------------------
+
 // runDataLoop handles the main data processing loop (direct push model)
 func runDataLoop(
 	updatesChan <-chan map[string][]models.MStockPrice,
 	db interfaces.IDatabase,
-	analyzer *analysis.AnalysisFacade,
+	svc *pipelineServices,
 	memManager *utils.MemoryManager,
 	srv interfaces.IDataExchanger,
 	config *models.MConfig,
 	appLogger *logger.Logger,
-	intermediateStats map[string]map[string]models.MIntermediateStats, // State carried over
 ) {
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
+	// Rolling stats are updated on every closed candle but only persisted periodically
+	statsInterval := time.Duration(config.RollingStats.PersistIntervalSeconds) * time.Second
+	if statsInterval <= 0 {
+		statsInterval = utils.DefaultStatsPersistInterval * time.Second
+	}
+	lastStatsSave := time.Now().UTC()
+
+	// Ended windows are closed on the wall clock, one update interval after their end
+	closeTicker := time.NewTicker(utils.CandleCloseCheckInterval * time.Second)
+	defer closeTicker.Stop()
+	closeDelay := int64(config.DataSource.UpdateIntervalSeconds)
+
 	appLogger.Info("Starting data loop (Push Model)...")
 
 	for {
@@ -54,84 +90,164 @@ func runDataLoop(
 			}
 			db.SaveStockPricesBulk(newRaw)
 
-			// Construct map with FULL history for Updated Symbols
-			// This ensures AggregateRealTime has enough data points to calculate Correlation/Anomaly
-			fullHistoryMap := make(map[string][]models.MStockPrice)
-			for sym := range updates {
-				// Get full history from RingBuffer
-				if buffer := memManager.GetBuffer(sym); buffer != nil {
-					fullHistoryMap[sym] = buffer.GetAll()
-				}
+			// Incremental aggregation: only the new points (plus gap fills) are folded into open candles
+			batch := svc.Engine.ProcessUpdates(svc.Quality.ProcessBatch(updates))
+			processCandleBatch(batch, updates, startProcess, db, svc, srv, config, appLogger)
+
+			if time.Since(lastStatsSave) >= statsInterval {
+				persistRollingStats(db, svc.Engine.Stats, appLogger)
+				lastStatsSave = time.Now().UTC()
 			}
 
-			// Aggregate Realtime using FULL history
+		case now := <-closeTicker.C:
+			// Candles of ended windows (e.g. the last one of a session) close without waiting for the next point
+			batch := svc.Engine.CloseExpired(now.UTC().Unix() - closeDelay)
+			if len(batch.Closed) == 0 {
+				continue
+			}
+			appLogger.Info("Closed ended candles for %d symbols", len(batch.Closed))
+			processCandleBatch(batch, nil, now.UTC(), db, svc, srv, config, appLogger)
 
-			accumulatedAggs := make(map[string]map[string][]models.MAggregation)
+		case <-quit:
+			appLogger.Info("Shutting down...")
+			persistRollingStats(db, svc.Engine.Stats, appLogger)
+			return
+		}
+	}
+}
 
-			for _, w := range config.WindowsAgg {
-				currentWindowStats := make(map[string]models.MIntermediateStats)
-				for sym, wMap := range intermediateStats {
-					if s, ok := wMap[w]; ok {
-						currentWindowStats[sym] = s
-					}
-				}
+// -----------------------------------------------------------------------------
 
-				// Pass fullHistoryMap instead of updates
-				wAggs := analyzer.AggregateRealTime(fullHistoryMap, w, currentWindowStats)
-
-				// Save
-				aggMap := make(map[string]map[string][]models.MAggregation)
-				for sym, innerMap := range wAggs {
-					if aggMap[sym] == nil {
-						aggMap[sym] = make(map[string][]models.MAggregation)
-					}
-					if candle, ok := innerMap[w]; ok {
-						aggMap[sym][w] = append(aggMap[sym][w], candle)
-					}
-				}
-				db.SaveAggregations(aggMap)
+// processCandleBatch runs the candle consumers on one engine batch, then saves
+// and broadcasts the results. updates holds the raw points behind the batch
+// (nil when candles were closed by the clock).
+func processCandleBatch(
+	batch models.MCandleBatch,
+	updates map[string][]models.MStockPrice,
+	startProcess time.Time,
+	db interfaces.IDatabase,
+	svc *pipelineServices,
+	srv interfaces.IDataExchanger,
+	config *models.MConfig,
+	appLogger *logger.Logger,
+) {
+	// Candlestick patterns are attached to the closed candles before they are saved
+	patternEvents := svc.Patterns.Process(batch.Closed)
+	if err := db.SavePatternEvents(patternEvents); err != nil {
+		appLogger.Error("Failed to save pattern events: %v", err)
+	}
 
-				// Accumulate for Broadcast
-				for sym, innerMap := range wAggs {
-					if _, ok := accumulatedAggs[sym]; !ok {
-						accumulatedAggs[sym] = make(map[string][]models.MAggregation)
-					}
+	// Save open candles (upserted on each update) and the ones closed by this batch
+	db.SaveAggregations(batch.Closed)
+	db.SaveAggregations(batch.Updated)
 
-					if candle, ok := innerMap[w]; ok {
-						accumulatedAggs[sym][w] = []models.MAggregation{candle}
-					}
-				}
-			}
+	// Alert rules run on every produced candle (closed and still open)
+	alertEvents := svc.AlertRules.Evaluate(batch)
+	svc.Notifier.NotifyAlerts(alertEvents)
 
-			elapsed := time.Since(startProcess).Seconds()
+	// Regime changes on the candles closed by this batch
+	changes := svc.Changepoints.Process(batch.Closed)
+	if err := db.SaveChangepointEvents(changes); err != nil {
+		appLogger.Error("Failed to save changepoint events: %v", err)
+	}
 
-			// Broadcast
-			rawInterfaceMap := make(map[string]interface{})
-			for k, v := range updates {
-				rawInterfaceMap[k] = v
-			}
+	// Support/resistance breaks against the current levels, then the periodic rebuild
+	levelBreaks := svc.Levels.Process(batch.Closed)
+	if err := db.SaveLevelEvents(levelBreaks); err != nil {
+		appLogger.Error("Failed to save level events: %v", err)
+	}
+	svc.Levels.Refresh(false)
+
+	// Correlation matrices and lead-lag estimates only move when a window closes
+	if len(batch.Closed) > 0 {
+		windows := closedWindows(batch.Closed)
+		breakdowns := svc.Correlation.Refresh(windows)
+		svc.Notifier.NotifyCorrelationAlerts(breakdowns)
+		svc.LeadLag.Refresh(windows)
+	}
 
-			payload := map[string]interface{}{
-				"type":         "UPDATE",
-				"raw_data":     rawInterfaceMap,
-				"aggregations": accumulatedAggs, // Only new candles
-				"timestamp":    time.Now().UTC().Unix(),
-				"processing_metrics": models.MProcessingMetrics{
-					AggregationTimeSeconds: elapsed,
-					ValidSymbols:           len(updates),
-					WindowsProcessed:       len(config.WindowsAgg),
-				},
-			}
+	elapsed := time.Since(startProcess).Seconds()
 
-			srv.UpdateAllDatas(payload)
-			srv.Broadcast(payload)
+	// Broadcast
+	rawInterfaceMap := make(map[string]interface{})
+	for k, v := range updates {
+		rawInterfaceMap[k] = v
+	}
 
-			// Cleanup
-			db.CleanupOldData()
+	timestamp := time.Now().UTC().Unix()
+	payload := map[string]interface{}{
+		"type":                "UPDATE",
+		"raw_data":            rawInterfaceMap,
+		"aggregations":        batch.Updated, // Only new candles
+		"closed_aggregations": batch.Closed,
+		"alerts":              alertEvents,
+		"changepoints":        changes,
+		"patterns":            patternEvents,
+		"level_breaks":        levelBreaks,
+		"timestamp":           timestamp,
+		"processing_metrics": models.MProcessingMetrics{
+			AggregationTimeSeconds: elapsed,
+			ValidSymbols:           len(updates),
+			WindowsProcessed:       len(config.WindowsAgg),
+		},
+	}
 
-		case <-quit:
-			appLogger.Info("Shutting down...")
-			return
+	srv.UpdateAllDatas(payload)
+	srv.Broadcast(payload)
+
+	// Market breadth of this cycle (own message type, stored as a time series)
+	if snapshots := svc.Breadth.Update(batch, timestamp); len(snapshots) > 0 {
+		if err := db.SaveBreadth(snapshots); err != nil {
+			appLogger.Error("Failed to save breadth: %v", err)
+		}
+		byWindow := make(map[string]models.MBreadthSnapshot, len(snapshots))
+		for _, snap := range snapshots {
+			byWindow[snap.WindowName] = snap
+		}
+		srv.BroadcastMessage(models.MBreadthMessage{
+			Type:      "BREADTH",
+			Breadth:   byWindow,
+			Timestamp: timestamp,
+		})
+	}
+
+	// Leaderboard diffs of this cycle (own message type)
+	if message := svc.Leaderboards.Update(batch, timestamp); message != nil {
+		srv.BroadcastMessage(*message)
+	}
+
+	// Cleanup
+	db.CleanupOldData()
+}
+
+// -----------------------------------------------------------------------------
+
+// persistRollingStats saves the rolling stats changed since the last save
+func persistRollingStats(db interfaces.IDatabase, stats *analysis.RollingStats, appLogger *logger.Logger) {
+	statsList := stats.TakeDirty()
+	if len(statsList) == 0 {
+		return
+	}
+	if err := db.SaveIntermediateStats(statsList); err != nil {
+		appLogger.Error("Failed to persist rolling stats: %v", err)
+		return
+	}
+	appLogger.Info("Persisted %d rolling stats entries", len(statsList))
+}
+
+// -----------------------------------------------------------------------------
+
+// closedWindows lists the windows that closed at least one candle in a batch
+func closedWindows(closed map[string]map[string][]models.MAggregation) []string {
+	seen := make(map[string]bool)
+	var windows []string
+	for _, wMap := range closed {
+		for w := range wMap {
+			if !seen[w] {
+				seen[w] = true
+				windows = append(windows, w)
+			}
 		}
 	}
+	return windows
 }
@@ -41,26 +41,96 @@ func main() {
 		os.Exit(1)
 	}
 
-	analyzer := setupAnalysis(conf.MConfig)
+	validator := setupValidation(conf.MConfig, db, appLogger)
+	synthetics := setupSynthetics(conf.MConfig)
+	quality := setupDataQuality(conf.MConfig)
+	analyzers := setupAnalyzers(conf.MConfig, appLogger)
+	benchmarks := setupBenchmarks(conf.MConfig)
+	volatility := setupVolatility(conf.MConfig)
+	seasonality := setupSeasonality(conf.MConfig)
+	breadth := setupBreadth(conf.MConfig)
+	changepoints := setupChangepoints(conf.MConfig)
+	patterns := setupPatterns(conf.MConfig)
+	leaderboards := setupLeaderboards(conf.MConfig)
+	analyzer := setupAnalysis(conf.MConfig, quality, analyzers, benchmarks, volatility, seasonality)
+	engine := setupCandleEngine(conf.MConfig, quality, analyzers, benchmarks, volatility, seasonality)
 	srv := server.NewFastAPIServer(conf.MConfig, appLogger)
+	srv.SetDataQualityProvider(quality)
+	srv.SetValidationManager(validator)
+	srv.SetBreadthProvider(breadth)
+	srv.SetLeaderboardProvider(leaderboards)
+	if changepoints != nil {
+		srv.SetChangepointProvider(changepoints)
+	}
+	if patterns != nil {
+		srv.SetPatternProvider(patterns)
+	}
 
 	// 5. Memory Manager
 	maxPoints := utils.CalculateMaxDataPoints(conf.DataSource.DataRetentionDays)
 	memLimit := helpers.GetRecommendedMemoryLimit()
 	appLogger.Info("Memory Limit set to: %d MB", memLimit)
 	memManager := utils.NewMemoryManager(memLimit, maxPoints)
+	correlation := setupCorrelation(conf.MConfig, memManager)
+	srv.SetCorrelationProvider(correlation)
+	leadLag := setupLeadLag(conf.MConfig, memManager)
+	if leadLag != nil {
+		srv.SetLeadLagProvider(leadLag)
+	}
+	clustering := setupClustering(conf.MConfig, memManager, db)
+	if clustering != nil {
+		srv.SetClusterProvider(clustering)
+	}
+	eventStudy := setupEventStudy(conf.MConfig, db)
+	if eventStudy != nil {
+		srv.SetEventStudyProvider(eventStudy)
+	}
+	volumeProfile := setupVolumeProfile(conf.MConfig, memManager, db)
+	srv.SetVolumeProfiler(volumeProfile)
+	levels := setupLevels(conf.MConfig, memManager)
+	if levels != nil {
+		srv.SetLevelProvider(levels)
+	}
 
 	// 6. Bootstrap (Initial Load)
-	initialPayload, intermediateStats, err := performInitialLoad(source, db, analyzer, memManager, conf.MConfig, appLogger)
+	svc := &pipelineServices{
+		Validator:    validator,
+		Synthetics:   synthetics,
+		Analyzer:     analyzer,
+		Engine:       engine,
+		Quality:      quality,
+		Breadth:      breadth,
+		Changepoints: changepoints,
+		Patterns:     patterns,
+		Leaderboards: leaderboards,
+		Correlation:  correlation,
+		LeadLag:      leadLag,
+		Levels:       levels,
+	}
+	initialPayload, err := performInitialLoad(source, db, svc, memManager, conf.MConfig, appLogger)
 	if err != nil {
 		appLogger.Warning("Bootstrap completed with warnings: %v", err)
 	}
 
 	// 7. Update Server State with Initial Data
 	srv.UpdateAllDatas(initialPayload)
+	correlation.Refresh(nil)
+	leadLag.Refresh(nil)
+	levels.Refresh(true)
+
+	alertRules := setupAlertRules(conf.MConfig, db, engine.Stats, appLogger)
+	srv.SetAlertRulesManager(alertRules)
+	svc.AlertRules = alertRules
+
+	screener := setupScreener(conf.MConfig, db, engine.Stats, appLogger)
+	srv.SetScreener(screener)
+
+	notifier := setupNotifications(conf.MConfig, db)
+	srv.SetNotifier(notifier)
+	svc.Notifier = notifier
 
 	// 8. Start Servers
-	startServers(srv, multiSource, conf, *configPath, appLogger, networkManager)
+	startServers(srv, multiSource, conf, *configPath, appLogger, networkManager, correlation, alertRules, screener, synthetics, volumeProfile)
 
 	// 9. Run Main Processing Loop
 	appLogger.Info("Starting Main Data Loop...")
@@ -71,21 +141,38 @@ func main() {
 
 	var wg sync.WaitGroup
 	updatesChan := make(chan map[string][]models.MStockPrice, 500)
+	validatedChan := make(chan map[string][]models.MStockPrice, 500)
+	syntheticChan := make(chan map[string][]models.MStockPrice, 500)
 
 	// Start Sources (Context-Based Direct Push)
 	if err := multiSource.Start(ctx, updatesChan, &wg); err != nil {
 		appLogger.Critical("Failed to start data sources: %v", err)
 	}
 
+	// Validation stage between the sources and the data loop (closes validatedChan on stop)
+	validator.Start(ctx, &wg, updatesChan, validatedChan)
+
+	// Synthetic instruments are computed from the validated points of their legs (closes syntheticChan on stop)
+	synthetics.Start(ctx, &wg, validatedChan, syntheticChan)
+
+	// Start notification delivery workers
+	notifier.Start(ctx, &wg)
+
+	// Periodic clustering of the universe by intraday profile
+	clustering.Start(ctx, &wg)
+
+	// Periodic event study of the stored volume anomalies
+	eventStudy.Start(ctx, &wg)
+
 	// Wait for cleanup on exit
 	defer func() {
 		appLogger.Info("Waiting for sources to stop...")
 		cancel()  // Signal stop
-		wg.Wait() // Wait for sources
+		wg.Wait() // Wait for sources and notification workers
 		close(updatesChan)
 		appLogger.Info("Shutdown complete.")
 	}()
 
 	// Run Loop (Blocking)
-	runDataLoop(updatesChan, db, analyzer, memManager, srv, conf.MConfig, appLogger, intermediateStats)
+	runDataLoop(syntheticChan, db, svc, memManager, srv, conf.MConfig, appLogger)
 }
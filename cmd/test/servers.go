@@ -24,6 +24,11 @@ func startServers(
 	configPath string,
 	appLogger *logger.Logger,
 	networkManager interfaces.INetworkManager,
+	correlation interfaces.ICorrelationProvider,
+	alertRules interfaces.IAlertRulesManager,
+	screener interfaces.IScreener,
+	synthetics interfaces.ISyntheticManager,
+	volumeProfile interfaces.IVolumeProfiler,
 ) {
 
 	// 1. FastAPIServer
@@ -49,7 +54,7 @@ func startServers(
 		}
 		grpcServer := grpc.NewServer()
 		grpcLogger := logger.NewLogger(config, "ControlService")
-		controlService := pb.NewControlService(config, multiSource, configPath, grpcLogger, networkManager)
+		controlService := pb.NewControlService(config, multiSource, configPath, grpcLogger, networkManager, correlation, alertRules, screener, synthetics, volumeProfile)
 		pb.RegisterMarketObserverControlServer(grpcServer, controlService)
 
 		appLogger.Info("Starting gRPC Control Server on :%d", port)
@@ -2,14 +2,22 @@ package main
 
 import (
 	"fmt"
+	"market-observer/src/alerts"
 	"market-observer/src/analysis"
+	"market-observer/src/analysis/plugins/momentum"
 	datasource "market-observer/src/data_source"
 	"market-observer/src/data_source/yahoo"
 	"market-observer/src/interfaces"
 	"market-observer/src/logger"
 	"market-observer/src/models"
 	"market-observer/src/network"
+	"market-observer/src/notifications"
+	"market-observer/src/profile"
+	"market-observer/src/screener"
 	"market-observer/src/storage"
+	"market-observer/src/synthetic"
+	"market-observer/src/utils"
+	"market-observer/src/validation"
 	"os"
 )
 
@@ -93,8 +101,240 @@ func setupDataSources(config *models.MConfig, appLogger *logger.Logger, networkM
 
 // -----------------------------------------------------------------------------
 
+// setupValidation initializes the inbound validation stage and restores the pending quarantine
+func setupValidation(config *models.MConfig, db interfaces.IDatabase, appLogger *logger.Logger) *validation.Validator {
+	validationLogger := logger.NewLogger(config, "Validation")
+	validator := validation.NewValidator(config, db, validationLogger)
+	if err := validator.Load(); err != nil {
+		appLogger.Error("Failed to load quarantined points: %v", err)
+	}
+	return validator
+}
+
+// -----------------------------------------------------------------------------
+
+// setupSynthetics initializes the synthetic instrument stage (ratios, spreads, baskets)
+func setupSynthetics(config *models.MConfig) *synthetic.Builder {
+	syntheticLogger := logger.NewLogger(config, "Synthetics")
+	return synthetic.NewBuilder(config, syntheticLogger)
+}
+
+// -----------------------------------------------------------------------------
+
+// setupDataQuality initializes the missing-bar detector (gap filling policy)
+func setupDataQuality(config *models.MConfig) *analysis.DataQualityMonitor {
+	qualityLogger := logger.NewLogger(config, "DataQuality")
+	return analysis.NewDataQualityMonitor(config, qualityLogger)
+}
+
+// -----------------------------------------------------------------------------
+
+// setupAnalyzers registers the analyzer plugins enabled in the config
+func setupAnalyzers(config *models.MConfig, appLogger *logger.Logger) *analysis.AnalyzerRegistry {
+	registry := analysis.NewAnalyzerRegistry(logger.NewLogger(config, "Analyzers"))
+
+	// Available plugins (add yours here)
+	available := map[string]func() interfaces.IAnalyzer{
+		momentum.Name: func() interfaces.IAnalyzer {
+			return momentum.New(momentum.DefaultFastPeriod, momentum.DefaultSlowPeriod)
+		},
+	}
+
+	for _, name := range config.Analyzers {
+		factory, ok := available[name]
+		if !ok {
+			appLogger.Warning("Unknown analyzer plugin %q, skipped", name)
+			continue
+		}
+		if err := registry.Register(factory()); err != nil {
+			appLogger.Warning("Failed to register analyzer %q: %v", name, err)
+		}
+	}
+	return registry
+}
+
+// -----------------------------------------------------------------------------
+
+// setupBenchmarks initializes the benchmark comparison (relative strength, beta/alpha)
+func setupBenchmarks(config *models.MConfig) *analysis.BenchmarkService {
+	benchmarkLogger := logger.NewLogger(config, "Benchmarks")
+	return analysis.NewBenchmarkService(config, benchmarkLogger)
+}
+
+// -----------------------------------------------------------------------------
+
+// setupSeasonality initializes the time-of-day volume baseline (nil = flat baseline)
+func setupSeasonality(config *models.MConfig) *analysis.VolumeSeasonality {
+	method := config.Seasonality.Method
+	if method == "" || method == utils.SeasonalityMethodFlat {
+		return nil
+	}
+	seasonalityLogger := logger.NewLogger(config, "Seasonality")
+	return analysis.NewVolumeSeasonality(config, seasonalityLogger)
+}
+
+// -----------------------------------------------------------------------------
+
+// setupVolatility initializes the volatility estimators and regime flags
+func setupVolatility(config *models.MConfig) *analysis.VolatilityService {
+	volatilityLogger := logger.NewLogger(config, "Volatility")
+	return analysis.NewVolatilityService(config, volatilityLogger)
+}
+
+// -----------------------------------------------------------------------------
+
+// setupBreadth initializes the market breadth calculator
+func setupBreadth(config *models.MConfig) *analysis.BreadthCalculator {
+	breadthLogger := logger.NewLogger(config, "Breadth")
+	return analysis.NewBreadthCalculator(config, breadthLogger)
+}
+
+// -----------------------------------------------------------------------------
+
+// setupLeaderboards initializes the per-window leaderboards
+func setupLeaderboards(config *models.MConfig) *analysis.LeaderboardService {
+	leaderboardLogger := logger.NewLogger(config, "Leaderboards")
+	return analysis.NewLeaderboardService(config, leaderboardLogger)
+}
+
+// -----------------------------------------------------------------------------
+
+// setupChangepoints initializes the regime change detector (nil = disabled)
+func setupChangepoints(config *models.MConfig) *analysis.ChangepointDetector {
+	if !config.Changepoints.Enabled {
+		return nil
+	}
+	changepointLogger := logger.NewLogger(config, "Changepoints")
+	return analysis.NewChangepointDetector(config, changepointLogger)
+}
+
+// -----------------------------------------------------------------------------
+
+// setupPatterns initializes the candlestick pattern detector (nil = disabled)
+func setupPatterns(config *models.MConfig) *analysis.PatternDetector {
+	if !config.Patterns.Enabled {
+		return nil
+	}
+	patternLogger := logger.NewLogger(config, "Patterns")
+	return analysis.NewPatternDetector(config, patternLogger)
+}
+
+// -----------------------------------------------------------------------------
+
 // setupAnalysis initializes the analysis facade
-func setupAnalysis(config *models.MConfig) *analysis.AnalysisFacade {
+func setupAnalysis(config *models.MConfig, quality *analysis.DataQualityMonitor, analyzers *analysis.AnalyzerRegistry, benchmarks *analysis.BenchmarkService, volatility *analysis.VolatilityService, seasonality *analysis.VolumeSeasonality) *analysis.AnalysisFacade {
 	analysisLogger := logger.NewLogger(config, "Analysis")
-	return analysis.NewAnalysisFacade(config, analysisLogger)
+	analyzer := analysis.NewAnalysisFacade(config, analysisLogger)
+	analyzer.Quality = quality
+	analyzer.Analyzers = analyzers
+	analyzer.Benchmarks = benchmarks
+	analyzer.Volatility = volatility
+	analyzer.Seasonality = seasonality
+	return analyzer
+}
+
+// -----------------------------------------------------------------------------
+
+// setupCandleEngine initializes the incremental candle engine
+func setupCandleEngine(config *models.MConfig, quality *analysis.DataQualityMonitor, analyzers *analysis.AnalyzerRegistry, benchmarks *analysis.BenchmarkService, volatility *analysis.VolatilityService, seasonality *analysis.VolumeSeasonality) *analysis.CandleEngine {
+	engineLogger := logger.NewLogger(config, "CandleEngine")
+	engine := analysis.NewCandleEngine(config, analysis.NewRollingStats(config), engineLogger)
+	engine.Quality = quality
+	engine.Analyzers = analyzers
+	engine.Benchmarks = benchmarks
+	engine.Volatility = volatility
+	engine.Seasonality = seasonality
+	return engine
+}
+
+// -----------------------------------------------------------------------------
+
+// setupCorrelation initializes the cross-symbol correlation service
+func setupCorrelation(config *models.MConfig, memManager *utils.MemoryManager) *analysis.CorrelationService {
+	correlationLogger := logger.NewLogger(config, "Correlation")
+	return analysis.NewCorrelationService(config, memManager, correlationLogger)
+}
+
+// -----------------------------------------------------------------------------
+
+// setupLeadLag initializes the pair lead-lag service (nil = disabled)
+func setupLeadLag(config *models.MConfig, memManager *utils.MemoryManager) *analysis.LeadLagService {
+	if !config.LeadLag.Enabled {
+		return nil
+	}
+	leadLagLogger := logger.NewLogger(config, "LeadLag")
+	return analysis.NewLeadLagService(config, memManager, leadLagLogger)
+}
+
+// -----------------------------------------------------------------------------
+
+// setupLevels initializes the support/resistance level service (nil = disabled)
+func setupLevels(config *models.MConfig, memManager *utils.MemoryManager) *analysis.LevelService {
+	if !config.Levels.Enabled {
+		return nil
+	}
+	levelLogger := logger.NewLogger(config, "Levels")
+	return analysis.NewLevelService(config, memManager, levelLogger)
+}
+
+// -----------------------------------------------------------------------------
+
+// setupClustering initializes the intraday profile clustering job (nil = disabled)
+func setupClustering(config *models.MConfig, memManager *utils.MemoryManager, db interfaces.IDatabase) *analysis.ClusterService {
+	if !config.Clustering.Enabled {
+		return nil
+	}
+	clusterLogger := logger.NewLogger(config, "Clustering")
+	return analysis.NewClusterService(config, memManager, db, clusterLogger)
+}
+
+// -----------------------------------------------------------------------------
+
+// setupEventStudy initializes the volume anomaly event study job (nil = disabled)
+func setupEventStudy(config *models.MConfig, db interfaces.IDatabase) *analysis.EventStudyService {
+	if !config.EventStudy.Enabled {
+		return nil
+	}
+	eventStudyLogger := logger.NewLogger(config, "EventStudy")
+	return analysis.NewEventStudyService(config, db, eventStudyLogger)
+}
+
+// -----------------------------------------------------------------------------
+
+// setupVolumeProfile initializes the on-demand volume profile builder
+func setupVolumeProfile(config *models.MConfig, memManager *utils.MemoryManager, db interfaces.IDatabase) *profile.Profiler {
+	profileLogger := logger.NewLogger(config, "VolumeProfile")
+	return profile.NewProfiler(config, memManager, db, profileLogger)
+}
+
+// -----------------------------------------------------------------------------
+
+// setupAlertRules initializes the alert rules engine and loads the stored rules
+func setupAlertRules(config *models.MConfig, db interfaces.IDatabase, stats *analysis.RollingStats, appLogger *logger.Logger) *alerts.RulesEngine {
+	rulesLogger := logger.NewLogger(config, "AlertRules")
+	rules := alerts.NewRulesEngine(config, db, stats, rulesLogger)
+	if err := rules.Load(); err != nil {
+		appLogger.Error("Failed to load alert rules: %v", err)
+	}
+	return rules
+}
+
+// -----------------------------------------------------------------------------
+
+// setupScreener initializes the screener and loads the saved screens
+func setupScreener(config *models.MConfig, db interfaces.IDatabase, stats *analysis.RollingStats, appLogger *logger.Logger) *screener.Screener {
+	screenerLogger := logger.NewLogger(config, "Screener")
+	s := screener.NewScreener(config, db, stats, screenerLogger)
+	if err := s.Load(); err != nil {
+		appLogger.Error("Failed to load saved screens: %v", err)
+	}
+	return s
+}
+
+// -----------------------------------------------------------------------------
+
+// setupNotifications initializes the notification dispatcher (workers start with Start)
+func setupNotifications(config *models.MConfig, db interfaces.IDatabase) *notifications.Dispatcher {
+	notifyLogger := logger.NewLogger(config, "Notifications")
+	return notifications.NewDispatcher(config, db, notifyLogger)
 }
@@ -0,0 +1,337 @@
+package alerts
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// -----------------------------------------------------------------------------
+// Expression is a parsed rule condition.
+//
+// Grammar (keywords are case-insensitive):
+//
+//	condition  := expr [ "on" window ]
+//	expr       := and { "OR" and }
+//	and        := term { "AND" term }
+//	term       := "(" expr ")" | field op number
+//	op         := ">" | ">=" | "<" | "<=" | "==" | "!="
+//
+// e.g. "volume_anomaly_ratio > 3 AND price_percent_change > 0.02 on 15m"
+// -----------------------------------------------------------------------------
+
+type Expression struct {
+	Window string   // Window the rule applies to ("" = all windows)
+	Fields []string // Fields referenced by the condition
+	root   exprNode
+}
+
+type exprNode interface {
+	// eval evaluates the node; margin relaxes every threshold (see comparison.eval)
+	eval(values map[string]float64, margin float64) bool
+}
+
+type logicalNode struct {
+	and   bool
+	nodes []exprNode
+}
+
+type comparison struct {
+	field     string
+	op        string
+	threshold float64
+}
+
+// -----------------------------------------------------------------------------
+
+// ParseExpression parses a rule condition. Fields are checked against known.
+func ParseExpression(condition string, known map[string]bool) (*Expression, error) {
+	tokens, err := tokenize(condition)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, known: known, fields: make(map[string]bool)}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	expr := &Expression{root: root}
+	if p.peekKeyword("on") {
+		p.pos++
+		if p.pos >= len(p.tokens) {
+			return nil, fmt.Errorf("missing window after 'on'")
+		}
+		expr.Window = p.tokens[p.pos]
+		p.pos++
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token %q", p.tokens[p.pos])
+	}
+
+	for f := range p.fields {
+		expr.Fields = append(expr.Fields, f)
+	}
+	return expr, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// Compare builds the condition "field op threshold" without parsing text.
+// The field is checked against known.
+func Compare(field, op string, threshold float64, known map[string]bool) (*Expression, error) {
+	field = strings.ToLower(field)
+	node, err := newComparison(field, op, threshold, known)
+	if err != nil {
+		return nil, err
+	}
+	return &Expression{Fields: []string{field}, root: node}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// All combines conditions into one that holds when every one of them holds.
+// Their windows are ignored.
+func All(exprs ...*Expression) *Expression {
+	fields := make(map[string]bool)
+	node := &logicalNode{and: true}
+	for _, e := range exprs {
+		node.nodes = append(node.nodes, e.root)
+		for _, f := range e.Fields {
+			fields[f] = true
+		}
+	}
+
+	expr := &Expression{root: node}
+	for f := range fields {
+		expr.Fields = append(expr.Fields, f)
+	}
+	return expr
+}
+
+// -----------------------------------------------------------------------------
+
+// Eval evaluates the condition against field values.
+func (e *Expression) Eval(values map[string]float64) bool {
+	return e.root.eval(values, 0)
+}
+
+// -----------------------------------------------------------------------------
+
+// EvalRelaxed evaluates the condition with every threshold moved by margin
+// (relative) in the direction that makes it easier to hold. Used for hysteresis:
+// a fired rule re-arms only once even the relaxed condition is false.
+func (e *Expression) EvalRelaxed(values map[string]float64, margin float64) bool {
+	return e.root.eval(values, margin)
+}
+
+// -----------------------------------------------------------------------------
+
+func (n *logicalNode) eval(values map[string]float64, margin float64) bool {
+	for _, child := range n.nodes {
+		result := child.eval(values, margin)
+		if n.and && !result {
+			return false
+		}
+		if !n.and && result {
+			return true
+		}
+	}
+	return n.and
+}
+
+// -----------------------------------------------------------------------------
+
+func (c *comparison) eval(values map[string]float64, margin float64) bool {
+	v, ok := values[c.field]
+	if !ok || math.IsNaN(v) {
+		return false
+	}
+
+	shift := margin * math.Abs(c.threshold)
+	if c.threshold == 0 {
+		shift = margin
+	}
+
+	switch c.op {
+	case ">":
+		return v > c.threshold-shift
+	case ">=":
+		return v >= c.threshold-shift
+	case "<":
+		return v < c.threshold+shift
+	case "<=":
+		return v <= c.threshold+shift
+	case "==":
+		return v == c.threshold
+	case "!=":
+		return v != c.threshold
+	}
+	return false
+}
+
+// -----------------------------------------------------------------------------
+// Parser
+// -----------------------------------------------------------------------------
+
+type parser struct {
+	tokens []string
+	pos    int
+	known  map[string]bool
+	fields map[string]bool
+}
+
+// -----------------------------------------------------------------------------
+
+func (p *parser) peekKeyword(keyword string) bool {
+	return p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], keyword)
+}
+
+// -----------------------------------------------------------------------------
+
+func (p *parser) parseOr() (exprNode, error) {
+	return p.parseLogical(false, p.parseAnd)
+}
+
+// -----------------------------------------------------------------------------
+
+func (p *parser) parseAnd() (exprNode, error) {
+	return p.parseLogical(true, p.parseTerm)
+}
+
+// -----------------------------------------------------------------------------
+
+func (p *parser) parseLogical(and bool, next func() (exprNode, error)) (exprNode, error) {
+	keyword := "or"
+	if and {
+		keyword = "and"
+	}
+
+	first, err := next()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []exprNode{first}
+	for p.peekKeyword(keyword) {
+		p.pos++
+		node, err := next()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	return &logicalNode{and: and, nodes: nodes}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (p *parser) parseTerm() (exprNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of condition")
+	}
+
+	if p.tokens[p.pos] == "(" {
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos] != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return node, nil
+	}
+
+	if p.pos+2 >= len(p.tokens) {
+		return nil, fmt.Errorf("incomplete comparison near %q", p.tokens[p.pos])
+	}
+
+	field := strings.ToLower(p.tokens[p.pos])
+	op := p.tokens[p.pos+1]
+	value := p.tokens[p.pos+2]
+	p.pos += 3
+
+	node, err := newComparison(field, op, 0, p.known)
+	if err != nil {
+		return nil, err
+	}
+	if node.threshold, err = strconv.ParseFloat(value, 64); err != nil {
+		return nil, fmt.Errorf("invalid number %q after %s %s", value, field, op)
+	}
+
+	p.fields[field] = true
+	return node, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// newComparison checks the field against known and the operator.
+func newComparison(field, op string, threshold float64, known map[string]bool) (*comparison, error) {
+	if !known[field] && !strings.HasPrefix(field, MetricFieldPrefix) {
+		return nil, fmt.Errorf("unknown field %q", field)
+	}
+	switch op {
+	case ">", ">=", "<", "<=", "==", "!=":
+	default:
+		return nil, fmt.Errorf("invalid operator %q after %s", op, field)
+	}
+	return &comparison{field: field, op: op, threshold: threshold}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// tokenize splits a condition into identifiers, numbers, operators and parentheses.
+func tokenize(s string) ([]string, error) {
+	var tokens []string
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+			i++
+
+		case strings.ContainsRune("<>=!", r):
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, string(runes[i:i+2]))
+				i += 2
+			} else if r == '=' || r == '!' {
+				return nil, fmt.Errorf("invalid operator at position %d", i)
+			} else {
+				tokens = append(tokens, string(r))
+				i++
+			}
+
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-' || r == '+':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || strings.ContainsRune("_.-+@", runes[i])) {
+				// A sign is only part of a number at its start or after an exponent
+				if (runes[i] == '-' || runes[i] == '+') && i > start && runes[i-1] != 'e' && runes[i-1] != 'E' {
+					break
+				}
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty condition")
+	}
+	return tokens, nil
+}
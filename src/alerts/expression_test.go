@@ -0,0 +1,179 @@
+package alerts
+
+import (
+	"math"
+	"testing"
+
+	"market-observer/src/models"
+)
+
+func TestParseExpressionPrecedence(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition string
+		values    map[string]float64
+		want      bool
+	}{
+		// AND binds tighter than OR: a OR (b AND c)
+		{"or-and left true", "volume > 1 OR close > 1 AND open > 1", map[string]float64{"volume": 2, "close": 0, "open": 0}, true},
+		{"or-and right half", "volume > 1 OR close > 1 AND open > 1", map[string]float64{"volume": 0, "close": 2, "open": 0}, false},
+		{"and-or right true", "volume > 1 AND close > 1 OR open > 1", map[string]float64{"volume": 0, "close": 0, "open": 2}, true},
+		{"parentheses override", "(volume > 1 OR close > 1) AND open > 1", map[string]float64{"volume": 2, "close": 0, "open": 0}, false},
+		{"nested parentheses", "((volume > 1) AND (close > 1 OR open > 1))", map[string]float64{"volume": 2, "close": 0, "open": 2}, true},
+
+		// Chains of the same operator
+		{"and chain all", "volume > 1 AND close > 1 AND open > 1", map[string]float64{"volume": 2, "close": 2, "open": 2}, true},
+		{"and chain one false", "volume > 1 AND close > 1 AND open > 1", map[string]float64{"volume": 2, "close": 2, "open": 0}, false},
+		{"or chain last", "volume > 1 OR close > 1 OR open > 1", map[string]float64{"volume": 0, "close": 0, "open": 2}, true},
+		{"or chain none", "volume > 1 OR close > 1 OR open > 1", map[string]float64{"volume": 0, "close": 0, "open": 0}, false},
+
+		// Keywords, operators and numbers
+		{"lower case keywords", "volume > 1 and close > 1 or open > 1", map[string]float64{"volume": 0, "close": 0, "open": 2}, true},
+		{"negative threshold", "price_percent_change < -0.02", map[string]float64{"price_percent_change": -0.03}, true},
+		{"exponent threshold", "volume >= 1e6", map[string]float64{"volume": 1e6}, true},
+		{"no spaces", "volume>=2", map[string]float64{"volume": 2}, true},
+		{"equality", "volume == 2", map[string]float64{"volume": 2}, true},
+		{"inequality", "volume != 2", map[string]float64{"volume": 2}, false},
+		{"missing value", "volume > 1", map[string]float64{}, false},
+		{"NaN value", "volume < 1", map[string]float64{"volume": math.NaN()}, false},
+		{"plugin metric", "metrics.momentum.rsi > 70", map[string]float64{"metrics.momentum.rsi": 75}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := ParseExpression(tt.condition, KnownFields())
+			if err != nil {
+				t.Fatalf("ParseExpression(%q) error: %v", tt.condition, err)
+			}
+			if got := expr.Eval(tt.values); got != tt.want {
+				t.Errorf("Eval(%q, %v) = %v, want %v", tt.condition, tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExpressionWindow(t *testing.T) {
+	expr, err := ParseExpression("volume_anomaly_ratio > 3 AND price_percent_change > 0.02 ON 15m", KnownFields())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expr.Window != "15m" {
+		t.Errorf("Window = %q, want 15m", expr.Window)
+	}
+	if len(expr.Fields) != 2 {
+		t.Errorf("Fields = %v, want 2 fields", expr.Fields)
+	}
+}
+
+func TestParseExpressionErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition string
+	}{
+		{"empty", ""},
+		{"blank", "   "},
+		{"unknown field", "foo > 1"},
+		{"unknown field in chain", "volume > 1 AND foo > 1"},
+		{"missing number", "volume >"},
+		{"missing operator", "volume 3"},
+		{"not a number", "volume > abc"},
+		{"single equals", "volume = 3"},
+		{"bang alone", "volume ! 3"},
+		{"doubled operator", "volume >> 3"},
+		{"operator as field", "> 3"},
+		{"unexpected character", "volume > 3 & close > 1"},
+		{"dangling AND", "volume > 1 AND"},
+		{"dangling OR", "volume > 1 OR"},
+		{"unclosed parenthesis", "(volume > 1"},
+		{"extra closing parenthesis", "volume > 1)"},
+		{"empty parentheses", "()"},
+		{"missing window", "volume > 1 on"},
+		{"trailing tokens", "volume > 1 on 15m extra"},
+		{"juxtaposed terms", "volume > 1 close > 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseExpression(tt.condition, KnownFields()); err == nil {
+				t.Errorf("ParseExpression(%q) accepted a malformed condition", tt.condition)
+			}
+		})
+	}
+}
+
+func TestEvalRelaxed(t *testing.T) {
+	tests := []struct {
+		condition string
+		value     float64
+		margin    float64
+		want      bool
+	}{
+		{"volume > 100", 95, 0.1, true},  // Threshold relaxed to 90
+		{"volume > 100", 85, 0.1, false}, // Clearly below
+		{"volume < 100", 105, 0.1, true}, // Threshold relaxed to 110
+		{"volume < -100", -95, 0.1, true},
+		{"volume > 0", -0.05, 0.1, true}, // Zero threshold: absolute margin
+		{"volume == 100", 95, 0.1, false},
+	}
+
+	for _, tt := range tests {
+		expr, err := ParseExpression(tt.condition, KnownFields())
+		if err != nil {
+			t.Fatalf("ParseExpression(%q) error: %v", tt.condition, err)
+		}
+		if got := expr.EvalRelaxed(map[string]float64{"volume": tt.value}, tt.margin); got != tt.want {
+			t.Errorf("EvalRelaxed(%q, %v, %v) = %v, want %v", tt.condition, tt.value, tt.margin, got, tt.want)
+		}
+	}
+}
+
+func TestFieldValuesZeroDenominators(t *testing.T) {
+	c := models.MAggregation{Close: 10, Volume: 500, VWAP: 0, SessionVWAP: 0, PricePercentChange: 0.01}
+	stats := &models.MIntermediateStats{AvgVolumeHistory: 500, StdVolumeHistory: 0, AvgReturnHistory: 0, StdReturnHistory: 0}
+	fields := []string{"vwap_distance", "session_vwap_distance", "volume_zscore", "return_zscore"}
+
+	values := FieldValues(c, stats, fields)
+	for _, name := range fields {
+		v, ok := values[name]
+		if !ok {
+			t.Fatalf("%s not resolved", name)
+		}
+		if math.IsNaN(v) || math.IsInf(v, 0) || v != 0 {
+			t.Errorf("%s = %v with a zero denominator, want 0", name, v)
+		}
+	}
+
+	// No rolling stats yet: z-scores are 0 as well
+	values = FieldValues(c, nil, []string{"volume_zscore"})
+	if values["volume_zscore"] != 0 {
+		t.Errorf("volume_zscore without stats = %v, want 0", values["volume_zscore"])
+	}
+}
+
+func TestCompareAndAll(t *testing.T) {
+	volume, err := Compare("Volume", ">", 100, KnownFields())
+	if err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+	cond, err := ParseExpression("close < 10 OR open < 10 on 5m", KnownFields())
+	if err != nil {
+		t.Fatalf("ParseExpression: %v", err)
+	}
+	expr := All(volume, cond)
+
+	if expr.Window != "" || len(expr.Fields) != 3 {
+		t.Errorf("window %q fields %v, want no window and 3 fields", expr.Window, expr.Fields)
+	}
+	if !expr.Eval(map[string]float64{"volume": 200, "close": 5, "open": 20}) {
+		t.Errorf("expected match")
+	}
+	if expr.Eval(map[string]float64{"volume": 50, "close": 5, "open": 5}) {
+		t.Errorf("volume filter ignored")
+	}
+
+	for _, tt := range []struct{ field, op string }{{"bogus", ">"}, {"volume", "=>"}, {"volume > 0 OR volume", ">"}} {
+		if _, err := Compare(tt.field, tt.op, 1, KnownFields()); err == nil {
+			t.Errorf("Compare(%q, %q) accepted", tt.field, tt.op)
+		}
+	}
+}
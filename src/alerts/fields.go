@@ -0,0 +1,115 @@
+package alerts
+
+import (
+	"reflect"
+	"strings"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/models"
+)
+
+// -----------------------------------------------------------------------------
+// Field resolution: every numeric MAggregation field (by JSON name) plus
+// indicators derived from the candle and its rolling stats.
+// -----------------------------------------------------------------------------
+
+// Indicators available to rule conditions in addition to the MAggregation fields
+var indicatorNames = []string{
+	"volume_zscore",         // (volume - avg volume) / std volume of the window
+	"return_zscore",         // (price % change - avg return) / std return of the window
+	"vwap_distance",         // (close - vwap) / vwap
+	"session_vwap_distance", // (close - session vwap) / session vwap
+}
+
+// MetricFieldPrefix selects an analyzer plugin metric: "metrics.<analyzer>.<metric>"
+// (0 while the analyzer has not produced it)
+const MetricFieldPrefix = "metrics."
+
+// aggregationFieldIndex maps JSON field names to MAggregation struct indices (numeric fields only)
+var aggregationFieldIndex = buildAggregationFieldIndex()
+
+// -----------------------------------------------------------------------------
+
+func buildAggregationFieldIndex() map[string]int {
+	index := make(map[string]int)
+	t := reflect.TypeOf(models.MAggregation{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		switch f.Type.Kind() {
+		case reflect.Float64, reflect.Float32, reflect.Int, reflect.Int32, reflect.Int64, reflect.Bool:
+			index[name] = i
+		}
+	}
+	return index
+}
+
+// -----------------------------------------------------------------------------
+
+// KnownFields returns every field name usable in a condition.
+func KnownFields() map[string]bool {
+	known := make(map[string]bool, len(aggregationFieldIndex)+len(indicatorNames))
+	for name := range aggregationFieldIndex {
+		known[name] = true
+	}
+	for _, name := range indicatorNames {
+		known[name] = true
+	}
+	return known
+}
+
+// -----------------------------------------------------------------------------
+
+// FieldValues resolves the requested fields for a candle. stats may be nil
+// (z-score indicators are then 0).
+func FieldValues(c models.MAggregation, stats *models.MIntermediateStats, fields []string) map[string]float64 {
+	v := reflect.ValueOf(c)
+	values := make(map[string]float64, len(fields))
+
+	for _, name := range fields {
+		if i, ok := aggregationFieldIndex[name]; ok {
+			f := v.Field(i)
+			switch f.Kind() {
+			case reflect.Float64, reflect.Float32:
+				values[name] = f.Float()
+			case reflect.Int, reflect.Int32, reflect.Int64:
+				values[name] = float64(f.Int())
+			case reflect.Bool:
+				if f.Bool() {
+					values[name] = 1
+				} else {
+					values[name] = 0
+				}
+			}
+			continue
+		}
+
+		if strings.HasPrefix(name, MetricFieldPrefix) {
+			values[name] = c.Metrics[strings.TrimPrefix(name, MetricFieldPrefix)]
+			continue
+		}
+
+		switch name {
+		case "volume_zscore":
+			if stats != nil {
+				values[name] = core.CalculateZScore(c.Volume, stats.AvgVolumeHistory, stats.StdVolumeHistory)
+			} else {
+				values[name] = 0
+			}
+		case "return_zscore":
+			if stats != nil {
+				values[name] = core.CalculateZScore(c.PricePercentChange, stats.AvgReturnHistory, stats.StdReturnHistory)
+			} else {
+				values[name] = 0
+			}
+		case "vwap_distance":
+			values[name] = core.CalculateChangePercent(c.Close, c.VWAP)
+		case "session_vwap_distance":
+			values[name] = core.CalculateChangePercent(c.Close, c.SessionVWAP)
+		}
+	}
+	return values
+}
@@ -0,0 +1,380 @@
+package alerts
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"market-observer/src/analysis"
+	"market-observer/src/interfaces"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+var (
+	// ErrRuleNotFound is returned when a rule ID does not exist
+	ErrRuleNotFound = errors.New("alert rule not found")
+	// ErrInvalidRule wraps rule validation errors
+	ErrInvalidRule = errors.New("invalid alert rule")
+)
+
+// -----------------------------------------------------------------------------
+// RulesEngine evaluates user-defined alert rules on the candles produced by
+// the data loop: closed candles always, still-open candles only for rules that
+// opt in (their intermediate values may never occur in the closed candle).
+// Rules are stored in the database and can be managed at runtime.
+// -----------------------------------------------------------------------------
+
+type RulesEngine struct {
+	Config *models.MConfig
+	DB     interfaces.IDatabase
+	Stats  *analysis.RollingStats // For z-score indicators (may be nil)
+	Logger *logger.Logger
+
+	rules     map[int64]*compiledRule
+	state     map[string]*ruleState // rule|symbol|window -> firing state
+	events    []models.MAlertEvent  // Most recent last
+	maxEvents int
+	known     map[string]bool
+	mu        sync.RWMutex
+}
+
+type compiledRule struct {
+	rule    models.MAlertRule
+	expr    *Expression
+	symbols map[string]bool // nil = all symbols
+}
+
+type ruleState struct {
+	fired     bool  // Waiting for the condition to clear (hysteresis)
+	lastFired int64 // Unix seconds (cooldown)
+}
+
+// -----------------------------------------------------------------------------
+
+func NewRulesEngine(cfg *models.MConfig, db interfaces.IDatabase, stats *analysis.RollingStats, log *logger.Logger) *RulesEngine {
+	maxEvents := cfg.Alerts.MaxEvents
+	if maxEvents <= 0 {
+		maxEvents = utils.DefaultAlertMaxEvents
+	}
+
+	return &RulesEngine{
+		Config:    cfg,
+		DB:        db,
+		Stats:     stats,
+		Logger:    log,
+		rules:     make(map[int64]*compiledRule),
+		state:     make(map[string]*ruleState),
+		maxEvents: maxEvents,
+		known:     KnownFields(),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Load restores the stored rules. Invalid rules are skipped with a warning.
+func (e *RulesEngine) Load() error {
+	rules, err := e.DB.LoadAlertRules()
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, r := range rules {
+		compiled, err := e.compile(r)
+		if err != nil {
+			e.Logger.Warning("Skipping alert rule %d (%s): %v", r.ID, r.Name, err)
+			continue
+		}
+		e.rules[r.ID] = compiled
+	}
+	e.Logger.Info("Loaded %d alert rule(s)", len(e.rules))
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (e *RulesEngine) ListRules() []models.MAlertRule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	rules := make([]models.MAlertRule, 0, len(e.rules))
+	for _, c := range e.rules {
+		rules = append(rules, c.rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules
+}
+
+// -----------------------------------------------------------------------------
+
+func (e *RulesEngine) CreateRule(rule models.MAlertRule) (models.MAlertRule, error) {
+	rule.ID = 0
+	compiled, err := e.compile(rule)
+	if err != nil {
+		return rule, err
+	}
+
+	now := time.Now().UTC().Unix()
+	compiled.rule.CreatedAt = now
+	compiled.rule.UpdatedAt = now
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.DB.SaveAlertRule(&compiled.rule); err != nil {
+		return rule, fmt.Errorf("failed to store alert rule: %w", err)
+	}
+	e.rules[compiled.rule.ID] = compiled
+	e.Logger.Info("Created alert rule %d (%s): %s", compiled.rule.ID, compiled.rule.Name, compiled.rule.Condition)
+	return compiled.rule, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (e *RulesEngine) UpdateRule(rule models.MAlertRule) (models.MAlertRule, error) {
+	compiled, err := e.compile(rule)
+	if err != nil {
+		return rule, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	existing, ok := e.rules[rule.ID]
+	if !ok {
+		return rule, ErrRuleNotFound
+	}
+	compiled.rule.CreatedAt = existing.rule.CreatedAt
+	compiled.rule.UpdatedAt = time.Now().UTC().Unix()
+
+	if err := e.DB.SaveAlertRule(&compiled.rule); err != nil {
+		return rule, fmt.Errorf("failed to store alert rule: %w", err)
+	}
+	e.rules[rule.ID] = compiled
+	e.resetState(rule.ID)
+	e.Logger.Info("Updated alert rule %d (%s): %s", rule.ID, compiled.rule.Name, compiled.rule.Condition)
+	return compiled.rule, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (e *RulesEngine) DeleteRule(id int64) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.rules[id]; !ok {
+		return ErrRuleNotFound
+	}
+	if err := e.DB.DeleteAlertRule(id); err != nil {
+		return fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+	delete(e.rules, id)
+	e.resetState(id)
+	e.Logger.Info("Deleted alert rule %d", id)
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (e *RulesEngine) RecentEvents(limit int) []models.MAlertEvent {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	events := e.events
+	if limit > 0 && limit < len(events) {
+		events = events[len(events)-limit:]
+	}
+	result := make([]models.MAlertEvent, len(events))
+	copy(result, events)
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// Evaluate runs every enabled rule on the candles of a batch (closed candles
+// first, then the latest open ones for rules with OpenCandles) and returns the
+// events fired.
+func (e *RulesEngine) Evaluate(batch models.MCandleBatch) []models.MAlertEvent {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.rules) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC().Unix()
+	var fired []models.MAlertEvent
+
+	for i, candles := range []map[string]map[string][]models.MAggregation{batch.Closed, batch.Updated} {
+		open := i == 1
+		for symbol, wMap := range candles {
+			for windowName, list := range wMap {
+				for _, c := range list {
+					fired = append(fired, e.evaluateCandle(symbol, windowName, c, open, now)...)
+				}
+			}
+		}
+	}
+
+	if len(fired) == 0 {
+		return nil
+	}
+
+	e.events = append(e.events, fired...)
+	if overflow := len(e.events) - e.maxEvents; overflow > 0 {
+		e.events = append([]models.MAlertEvent(nil), e.events[overflow:]...)
+	}
+
+	if err := e.DB.SaveAlertEvents(fired); err != nil {
+		e.Logger.Error("Failed to store alert events: %v", err)
+	}
+	e.Logger.Info("%d alert event(s) fired", len(fired))
+	return fired
+}
+
+// -----------------------------------------------------------------------------
+
+// evaluateCandle applies every matching rule to one candle (caller holds the lock).
+// Open candles are only seen by the rules that opt in.
+func (e *RulesEngine) evaluateCandle(symbol, windowName string, c models.MAggregation, open bool, now int64) []models.MAlertEvent {
+	var stats *models.MIntermediateStats
+	if e.Stats != nil {
+		if s, ok := e.Stats.Get(symbol, windowName); ok {
+			stats = &s
+		}
+	}
+
+	var fired []models.MAlertEvent
+	for id, cr := range e.rules {
+		r := cr.rule
+		if !r.Enabled || (open && !r.OpenCandles) {
+			continue
+		}
+		if cr.expr.Window != "" && cr.expr.Window != windowName {
+			continue
+		}
+		if cr.symbols != nil && !cr.symbols[symbol] {
+			continue
+		}
+
+		key := fmt.Sprintf("%d|%s|%s", id, symbol, windowName)
+		st, ok := e.state[key]
+		if !ok {
+			st = &ruleState{}
+			e.state[key] = st
+		}
+
+		values := FieldValues(c, stats, cr.expr.Fields)
+
+		if st.fired {
+			// Hysteresis: re-arm only once the (relaxed) condition is clearly false
+			if !cr.expr.EvalRelaxed(values, r.Hysteresis) {
+				st.fired = false
+			}
+			continue
+		}
+
+		if !cr.expr.Eval(values) {
+			continue
+		}
+		if r.CooldownSeconds > 0 && st.lastFired > 0 && now-st.lastFired < r.CooldownSeconds {
+			continue
+		}
+
+		st.fired = true
+		st.lastFired = now
+		fired = append(fired, models.MAlertEvent{
+			RuleID:     id,
+			RuleName:   r.Name,
+			Condition:  r.Condition,
+			Symbol:     symbol,
+			WindowName: windowName,
+			Timestamp:  now,
+			Values:     values,
+			Candle:     c,
+			Message:    fmt.Sprintf("%s: %s on %s matched %s", r.Name, symbol, windowName, formatValues(values)),
+		})
+	}
+	return fired
+}
+
+// -----------------------------------------------------------------------------
+
+// compile validates a rule and resolves its expression and symbol scope.
+func (e *RulesEngine) compile(rule models.MAlertRule) (*compiledRule, error) {
+	rule.Name = strings.TrimSpace(rule.Name)
+	rule.Condition = strings.TrimSpace(rule.Condition)
+
+	if rule.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidRule)
+	}
+	if rule.CooldownSeconds < 0 {
+		return nil, fmt.Errorf("%w: cooldown cannot be negative", ErrInvalidRule)
+	}
+	if rule.Hysteresis < 0 {
+		return nil, fmt.Errorf("%w: hysteresis cannot be negative", ErrInvalidRule)
+	}
+
+	expr, err := ParseExpression(rule.Condition, e.known)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+	if expr.Window != "" && !slices.Contains(e.Config.WindowsAgg, expr.Window) {
+		return nil, fmt.Errorf("%w: unknown window %q", ErrInvalidRule, expr.Window)
+	}
+
+	var symbols map[string]bool
+	if len(rule.Symbols) > 0 || rule.Watchlist != "" {
+		symbols = make(map[string]bool)
+		for _, sym := range rule.Symbols {
+			symbols[strings.TrimSpace(sym)] = true
+		}
+		if rule.Watchlist != "" {
+			list, ok := e.Config.Alerts.Watchlists[rule.Watchlist]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown watchlist %q", ErrInvalidRule, rule.Watchlist)
+			}
+			for _, sym := range list {
+				symbols[sym] = true
+			}
+		}
+	}
+
+	return &compiledRule{rule: rule, expr: expr, symbols: symbols}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// resetState clears the firing state of a rule (caller holds the lock).
+func (e *RulesEngine) resetState(id int64) {
+	prefix := fmt.Sprintf("%d|", id)
+	for key := range e.state {
+		if strings.HasPrefix(key, prefix) {
+			delete(e.state, key)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func formatValues(values map[string]float64) string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%.4g", name, values[name])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,144 @@
+package alerts
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/storage"
+)
+
+func newTestRulesEngine(t *testing.T) *RulesEngine {
+	t.Helper()
+	cfg := &models.MConfig{WindowsAgg: []string{"5m", "15m"}}
+	cfg.Storage.DBPath = filepath.Join(t.TempDir(), "alerts.db")
+	log := logger.NewLogger(cfg, "test")
+
+	db, err := storage.NewAsyncSQLiteDB(cfg, log)
+	if err != nil {
+		t.Fatalf("NewAsyncSQLiteDB: %v", err)
+	}
+	if err := db.Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return NewRulesEngine(cfg, db, nil, log)
+}
+
+// ratioBatch holds one candle of TEST with the given volume anomaly ratio
+func ratioBatch(window string, ratio float64, open bool) models.MCandleBatch {
+	c := models.MAggregation{Symbol: "TEST", WindowName: window, VolumeAnomalyRatio: ratio, IsClosed: !open}
+	candles := map[string]map[string][]models.MAggregation{"TEST": {window: {c}}}
+	if open {
+		return models.MCandleBatch{Updated: candles}
+	}
+	return models.MCandleBatch{Closed: candles}
+}
+
+func TestRulesEngineFireCooldownHysteresis(t *testing.T) {
+	e := newTestRulesEngine(t)
+	rule, err := e.CreateRule(models.MAlertRule{
+		Name:            "spike",
+		Condition:       "volume_anomaly_ratio > 3 on 15m",
+		CooldownSeconds: 600,
+		Hysteresis:      0.2, // Re-arms once the ratio is <= 2.4
+		Enabled:         true,
+	})
+	if err != nil {
+		t.Fatalf("CreateRule: %v", err)
+	}
+	key := fmt.Sprintf("%d|TEST|15m", rule.ID)
+
+	steps := []struct {
+		name  string
+		ratio float64
+		fires bool
+	}{
+		{"crosses the threshold", 4, true},
+		{"stays above: fired once", 5, false},
+		{"inside the hysteresis margin: not re-armed", 2.8, false},
+		{"above again without re-arming", 4, false},
+		{"clears the margin: re-armed", 2.0, false},
+		{"above again within the cooldown", 4, false},
+	}
+	for _, step := range steps {
+		events := e.Evaluate(ratioBatch("15m", step.ratio, false))
+		if got := len(events) == 1; got != step.fires || len(events) > 1 {
+			t.Fatalf("%s (ratio %v): %d event(s), want fire=%v", step.name, step.ratio, len(events), step.fires)
+		}
+	}
+
+	// Once the cooldown has elapsed the re-armed rule fires again
+	e.state[key].lastFired -= 601
+	events := e.Evaluate(ratioBatch("15m", 4, false))
+	if len(events) != 1 {
+		t.Fatalf("after the cooldown: %d event(s), want 1", len(events))
+	}
+	if events[0].Values["volume_anomaly_ratio"] != 4 || events[0].Symbol != "TEST" || events[0].WindowName != "15m" {
+		t.Errorf("unexpected event: %+v", events[0])
+	}
+
+	// Other windows are out of scope
+	if events := e.Evaluate(ratioBatch("5m", 10, false)); len(events) != 0 {
+		t.Errorf("rule on 15m fired on a 5m candle")
+	}
+	if got := len(e.RecentEvents(0)); got != 2 {
+		t.Errorf("RecentEvents = %d, want 2", got)
+	}
+}
+
+func TestRulesEngineOpenCandlesOptIn(t *testing.T) {
+	e := newTestRulesEngine(t)
+	closedOnly, err := e.CreateRule(models.MAlertRule{Name: "closed", Condition: "volume_anomaly_ratio > 3", Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateRule: %v", err)
+	}
+	withOpen, err := e.CreateRule(models.MAlertRule{Name: "open", Condition: "volume_anomaly_ratio > 3", OpenCandles: true, Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateRule: %v", err)
+	}
+
+	events := e.Evaluate(ratioBatch("5m", 10, true))
+	if len(events) != 1 || events[0].RuleID != withOpen.ID {
+		t.Fatalf("open candle: events %+v, want only rule %d", events, withOpen.ID)
+	}
+
+	events = e.Evaluate(ratioBatch("15m", 10, false))
+	if len(events) != 2 {
+		t.Fatalf("closed candle: %d event(s), want 2 (rules %d and %d)", len(events), closedOnly.ID, withOpen.ID)
+	}
+
+	// The flag survives a reload from storage
+	reloaded := NewRulesEngine(e.Config, e.DB, nil, e.Logger)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for _, r := range reloaded.ListRules() {
+		if r.OpenCandles != (r.ID == withOpen.ID) {
+			t.Errorf("rule %d reloaded with open_candles=%v", r.ID, r.OpenCandles)
+		}
+	}
+}
+
+func TestRulesEngineRejectsInvalidRules(t *testing.T) {
+	e := newTestRulesEngine(t)
+	invalid := []models.MAlertRule{
+		{Name: "", Condition: "volume > 1"},
+		{Name: "bad field", Condition: "foo > 1"},
+		{Name: "bad window", Condition: "volume > 1 on 2h"},
+		{Name: "bad watchlist", Condition: "volume > 1", Watchlist: "missing"},
+		{Name: "negative cooldown", Condition: "volume > 1", CooldownSeconds: -1},
+		{Name: "negative hysteresis", Condition: "volume > 1", Hysteresis: -0.1},
+	}
+	for _, r := range invalid {
+		if _, err := e.CreateRule(r); err == nil {
+			t.Errorf("CreateRule(%+v) accepted an invalid rule", r)
+		}
+	}
+	if len(e.ListRules()) != 0 {
+		t.Errorf("invalid rules were stored")
+	}
+}
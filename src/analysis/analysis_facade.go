@@ -3,33 +3,32 @@ package analysis
 import (
 	"market-observer/src/logger"
 	"sort"
-	"time"
 
 	"market-observer/src/analysis/core"
 	"market-observer/src/models"
+	"market-observer/src/utils"
 )
 
 type AnalysisFacade struct {
-	Config            *models.MConfig
-	WindowsSecondsMap map[string]int64 // Need to add this to config
-	Logger            *logger.Logger
+	Config      *models.MConfig
+	Windows     map[string]utils.WindowSpec // Parsed windows_aggregation entries
+	Hierarchy   *utils.WindowHierarchy      // Each window is built from its parent's candles
+	Quality     *DataQualityMonitor         // Missing bars per candle (optional)
+	Analyzers   *AnalyzerRegistry           // Plugin metrics (optional)
+	Benchmarks  *BenchmarkService           // Relative strength / beta versus benchmarks (optional)
+	Volatility  *VolatilityService          // Volatility estimators and regimes (optional)
+	Seasonality *VolumeSeasonality          // Time-of-day volume baseline (optional, flat baseline when nil)
+	Logger      *logger.Logger
 }
 
 // -----------------------------------------------------------------------------
 
 func NewAnalysisFacade(cfg *models.MConfig, log *logger.Logger) *AnalysisFacade {
-	// Initialize window mapping (should come from config)
-	windowsMap := make(map[string]int64)
-	for _, window := range cfg.WindowsAgg {
-		if dur, err := time.ParseDuration(window); err == nil {
-			windowsMap[window] = int64(dur.Seconds())
-		}
-	}
-
 	return &AnalysisFacade{
-		Config:            cfg,
-		WindowsSecondsMap: windowsMap,
-		Logger:            log,
+		Config:    cfg,
+		Windows:   utils.ParseWindows(cfg.WindowsAgg),
+		Hierarchy: windowHierarchy(cfg.WindowsAgg, log),
+		Logger:    log,
 	}
 }
 
@@ -45,7 +44,7 @@ func (a *AnalysisFacade) AggregateRealTime(
 
 	results := make(map[string]map[string]models.MAggregation)
 
-	windowSeconds, ok := a.WindowsSecondsMap[windowName]
+	spec, ok := a.Windows[windowName]
 	if !ok {
 		a.Logger.Error("Invalid window name %s", windowName)
 		return results
@@ -61,91 +60,62 @@ func (a *AnalysisFacade) AggregateRealTime(
 			return prices[i].Timestamp < prices[j].Timestamp
 		})
 
+		// Session VWAP as of the latest point
+		session := newSessionVWAP(symbol)
+		for _, p := range prices {
+			session.Add(p)
+		}
+
 		// 1. Identify the Current Aligned Window based on the LATEST data point
 		lastPt := prices[len(prices)-1]
-		currentWStart := lastPt.Timestamp - (lastPt.Timestamp % windowSeconds)
-		currentWEnd := currentWStart + windowSeconds
-		prevWStart := currentWStart - windowSeconds
-
-		// 2. Partition data into Current and Previous windows
-		var currentSubset []models.MStockPrice
-		var prevSubset []models.MStockPrice
+		calendar := utils.GetCalendar(symbol)
+		currentWStart, currentWEnd := spec.Bounds(lastPt.Timestamp, calendar)
+		prevWStart, _ := spec.Bounds(currentWStart-1, calendar)
 
+		// 2. Build the Current and Previous windows (through the window hierarchy)
+		var recent []models.MStockPrice
 		for _, p := range prices {
-			if p.Timestamp >= currentWStart && p.Timestamp < currentWEnd {
-				currentSubset = append(currentSubset, p)
-			} else if p.Timestamp >= prevWStart && p.Timestamp < currentWStart {
-				prevSubset = append(prevSubset, p)
+			if p.Timestamp >= prevWStart && p.Timestamp < currentWEnd {
+				recent = append(recent, p)
 			}
 		}
+		windows := a.buildWindowSums(recent, windowName, calendar)
 
-		if len(currentSubset) == 0 {
+		if len(windows) == 0 || windows[len(windows)-1].start != currentWStart {
 			continue
 		}
+		current := windows[len(windows)-1]
 
 		// 3. Process Current Window
-		pricesArr := make([]float64, len(currentSubset))
-		volsArr := make([]float64, len(currentSubset))
-		timestamps := make([]int64, len(currentSubset))
-		totalVol := 0.0
-
-		for i, p := range currentSubset {
-			pricesArr[i] = p.Price
-			volsArr[i] = p.Volume
-			timestamps[i] = p.Timestamp
-			totalVol += p.Volume
+		agg := models.MAggregation{
+			Symbol:     symbol,
+			WindowName: windowName,
+			StartTime:  currentWStart,
+			EndTime:    currentWEnd,
 		}
-
-		ohlcv := core.ComputeOHLCV(pricesArr, volsArr)
-		corr := core.CalculateCorrelation(pricesArr, volsArr)
+		current.sums.fill(&agg)
+		a.Quality.annotate(&agg)
 
 		// 4. Stats & Anomaly
 		avgVol := 1.0
 		if stat, ok := intermediateStats[symbol]; ok {
 			avgVol = stat.AvgVolumeHistory
 		}
-		anomaly := core.CalculateAnomalyRatio(totalVol, avgVol)
+		agg.VolumeAnomalyRatio = core.CalculateAnomalyRatio(agg.Volume, avgVol)
+		a.Seasonality.annotate(&agg)
 
 		// 5. Calculate Changes vs Previous Window
-		pctChange := 0.0
-		volPct := 0.0
-
-		if len(prevSubset) > 0 {
-			// Calculate Prev Window Close & Volume
-			// (Optimization: We don't need full OHLCV, just Close and Sum Volume)
-			prevClose := prevSubset[len(prevSubset)-1].Price
-			prevVolTotal := 0.0
-			for _, p := range prevSubset {
-				prevVolTotal += p.Volume
-			}
-
-			pctChange = core.CalculateChangePercent(ohlcv["close"], prevClose)
-			volPct = core.CalculateChangePercent(ohlcv["volume"], prevVolTotal)
-
+		if len(windows) > 1 && windows[len(windows)-2].start == prevWStart {
+			prev := windows[len(windows)-2].sums
+			agg.PricePercentChange = core.CalculateChangePercent(agg.Close, prev.close)
+			agg.VolumePercentChange = core.CalculateChangePercent(agg.Volume, prev.volume)
 		} else {
 			// Fallback if no previous window in memory (start of day/buffer)
-			// Compare to Open of current window ?? Or just 0.
-			pctChange = core.CalculateChangePercent(ohlcv["close"], ohlcv["open"])
+			agg.PricePercentChange = core.CalculateChangePercent(agg.Close, agg.Open)
 		}
 
-		// 6. Construct Aggregation
-		agg := models.MAggregation{
-			Symbol:                 symbol,
-			WindowName:             windowName,
-			Open:                   ohlcv["open"],
-			High:                   ohlcv["high"],
-			Low:                    ohlcv["low"],
-			Close:                  ohlcv["close"],
-			Volume:                 ohlcv["volume"],
-			AvgPrice:               ohlcv["avg_price"],
-			PricePercentChange:     pctChange,
-			VolumePercentChange:    volPct,
-			PriceVolumeCorrelation: corr,
-			VolumeAnomalyRatio:     anomaly,
-			StartTime:              currentWStart,
-			EndTime:                currentWEnd,
-			DataPoints:             len(currentSubset),
-		}
+		session.Apply(&agg)
+		a.Analyzers.Apply(&agg)
 
 		results[symbol] = map[string]models.MAggregation{
 			windowName: agg,
@@ -166,8 +136,7 @@ func (a *AnalysisFacade) AggregateHistorical(
 
 	results := make(map[string]map[string][]models.MAggregation)
 
-	windowSeconds, ok := a.WindowsSecondsMap[windowName]
-	if !ok {
+	if _, ok := a.Windows[windowName]; !ok {
 		return results
 	}
 
@@ -181,21 +150,8 @@ func (a *AnalysisFacade) AggregateHistorical(
 			return prices[i].Timestamp < prices[j].Timestamp
 		})
 
-		// Resample into windows
-		windows := make(map[int64][]models.MStockPrice)
-		for _, p := range prices {
-			wStart := p.Timestamp - (p.Timestamp % windowSeconds)
-			windows[wStart] = append(windows[wStart], p)
-		}
-
-		// Get window starts sorted
-		var windowStarts []int64
-		for wStart := range windows {
-			windowStarts = append(windowStarts, wStart)
-		}
-		sort.Slice(windowStarts, func(i, j int) bool {
-			return windowStarts[i] < windowStarts[j]
-		})
+		// Build the windows through the hierarchy (base window from raw points)
+		windows := a.buildWindowSums(prices, windowName, utils.GetCalendar(symbol))
 
 		var candles []models.MAggregation
 		avgVol := 1.0
@@ -205,59 +161,39 @@ func (a *AnalysisFacade) AggregateHistorical(
 
 		var prevClose, prevVolume float64
 		prevCloseSet := false
-
-		for _, wStart := range windowStarts {
-			subset := windows[wStart]
-			if len(subset) == 0 {
-				continue
+		session := newSessionVWAP(symbol)
+		next := 0
+
+		for i, w := range windows {
+			// Session VWAP as of the end of the window
+			for next < len(prices) && prices[next].Timestamp < w.end {
+				session.Add(prices[next])
+				next++
 			}
 
-			// Prepare arrays
-			pricesArr := make([]float64, len(subset))
-			volsArr := make([]float64, len(subset))
-
-			totalVol := 0.0
-			for i, p := range subset {
-				pricesArr[i] = p.Price
-				volsArr[i] = p.Volume
-				totalVol += p.Volume
+			candle := models.MAggregation{
+				Symbol:     symbol,
+				WindowName: windowName,
+				StartTime:  w.start,
+				EndTime:    w.end,
+				IsClosed:   i < len(windows)-1, // The last window stays open (continued by the CandleEngine)
 			}
-
-			// Calculate metrics
-			ohlcv := core.ComputeOHLCV(pricesArr, volsArr)
-			corr := core.CalculateCorrelation(pricesArr, volsArr)
-			anomaly := core.CalculateAnomalyRatio(totalVol, avgVol)
+			w.sums.fill(&candle)
+			a.Quality.annotate(&candle)
+			candle.VolumeAnomalyRatio = core.CalculateAnomalyRatio(candle.Volume, avgVol)
+			a.Seasonality.annotate(&candle)
 
 			// Calculate changes from previous window
-			pctChange := 0.0
-			volChange := 0.0
 			if prevCloseSet {
-				pctChange = core.CalculateChangePercent(ohlcv["close"], prevClose)
-				volChange = core.CalculateChangePercent(ohlcv["volume"], prevVolume)
-			}
-
-			// Create candle
-			candle := models.MAggregation{
-				Symbol:                 symbol,
-				WindowName:             windowName,
-				Open:                   ohlcv["open"],
-				High:                   ohlcv["high"],
-				Low:                    ohlcv["low"],
-				Close:                  ohlcv["close"],
-				Volume:                 ohlcv["volume"],
-				AvgPrice:               ohlcv["avg_price"],
-				PricePercentChange:     pctChange,
-				VolumePercentChange:    volChange, // Different from real-time!
-				PriceVolumeCorrelation: corr,
-				VolumeAnomalyRatio:     anomaly,
-				StartTime:              wStart,
-				EndTime:                wStart + windowSeconds,
-				DataPoints:             len(subset),
+				candle.PricePercentChange = core.CalculateChangePercent(candle.Close, prevClose)
+				candle.VolumePercentChange = core.CalculateChangePercent(candle.Volume, prevVolume) // Different from real-time!
 			}
+			session.Apply(&candle)
+			a.Analyzers.Apply(&candle)
 
 			candles = append(candles, candle)
-			prevClose = ohlcv["close"]
-			prevVolume = ohlcv["volume"]
+			prevClose = candle.Close
+			prevVolume = candle.Volume
 			prevCloseSet = true
 		}
 
@@ -268,11 +204,45 @@ func (a *AnalysisFacade) AggregateHistorical(
 		}
 	}
 
+	a.Benchmarks.Apply(results)
+	a.Volatility.Apply(results)
 	return results
 }
 
 // -----------------------------------------------------------------------------
 
+// buildWindowSums returns the windows of sorted prices (oldest first). The base
+// window is built from raw points, every other one from its parent's windows.
+func (a *AnalysisFacade) buildWindowSums(prices []models.MStockPrice, windowName string, calendar *utils.TradingCalendar) []windowSums {
+	spec := a.Windows[windowName]
+	var windows []windowSums
+
+	parent := a.Hierarchy.Parent[windowName]
+	if parent == "" {
+		for _, p := range prices {
+			if n := len(windows); n == 0 || p.Timestamp >= windows[n-1].end {
+				start, end := spec.Bounds(p.Timestamp, calendar)
+				windows = append(windows, windowSums{start: start, end: end})
+			} else if p.Timestamp < windows[n-1].start {
+				continue // Out of order point
+			}
+			windows[len(windows)-1].sums.addPoint(p)
+		}
+		return windows
+	}
+
+	for _, pw := range a.buildWindowSums(prices, parent, calendar) {
+		if n := len(windows); n == 0 || pw.start >= windows[n-1].end {
+			start, end := spec.Bounds(pw.start, calendar)
+			windows = append(windows, windowSums{start: start, end: end})
+		}
+		windows[len(windows)-1].sums.merge(pw.sums)
+	}
+	return windows
+}
+
+// -----------------------------------------------------------------------------
+
 // CalculateStatsForWindows calculates stats for multiple windows (matching Python)
 func (a *AnalysisFacade) CalculateStatsForWindows(
 	data map[string][]models.MStockPrice,
@@ -284,7 +254,7 @@ func (a *AnalysisFacade) CalculateStatsForWindows(
 	// Filter valid windows
 	targetWindows := make([]string, 0)
 	for _, wn := range windowNames {
-		if _, ok := a.WindowsSecondsMap[wn]; ok {
+		if _, ok := a.Windows[wn]; ok {
 			targetWindows = append(targetWindows, wn)
 		}
 	}
@@ -300,21 +270,35 @@ func (a *AnalysisFacade) CalculateStatsForWindows(
 		})
 
 		symbolStats := make(map[string]models.MIntermediateStats)
+		calendar := utils.GetCalendar(symbol)
 
 		for _, windowName := range targetWindows {
-			windowSeconds := a.WindowsSecondsMap[windowName]
+			spec := a.Windows[windowName]
 
-			// Resample into windows
+			// Resample into windows (prices are sorted, so the last write is the close)
 			windows := make(map[int64]float64)
+			closes := make(map[int64]float64)
+			var windowStarts []int64
+			var wStart, wEnd int64
 			for _, p := range prices {
-				wStart := p.Timestamp - (p.Timestamp % windowSeconds)
+				if p.Timestamp < wStart || p.Timestamp >= wEnd {
+					wStart, wEnd = spec.Bounds(p.Timestamp, calendar)
+				}
+				if _, ok := windows[wStart]; !ok {
+					windowStarts = append(windowStarts, wStart)
+				}
 				windows[wStart] += p.Volume
+				closes[wStart] = p.Price
 			}
 
-			// Collect volumes
+			// Collect volumes and close-to-close returns
 			var vols []float64
-			for _, vol := range windows {
-				vols = append(vols, vol)
+			var returns []float64
+			for i, wStart := range windowStarts {
+				vols = append(vols, windows[wStart])
+				if i > 0 {
+					returns = append(returns, core.CalculateChangePercent(closes[wStart], closes[windowStarts[i-1]]))
+				}
 			}
 
 			if len(vols) == 0 {
@@ -323,12 +307,15 @@ func (a *AnalysisFacade) CalculateStatsForWindows(
 
 			// Calculate stats
 			mean, std := core.CalculateMeanStd(vols)
+			retMean, retStd := core.CalculateMeanStd(returns)
 
 			symbolStats[windowName] = models.MIntermediateStats{
 				Symbol:               symbol,
 				WindowName:           windowName,
 				AvgVolumeHistory:     mean,
 				StdVolumeHistory:     std,
+				AvgReturnHistory:     retMean,
+				StdReturnHistory:     retStd,
 				DataPointsHistory:    len(vols),
 				LastHistoryTimestamp: prices[len(prices)-1].Timestamp,
 			}
@@ -0,0 +1,121 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"market-observer/src/interfaces"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+)
+
+// -----------------------------------------------------------------------------
+// AnalyzerRegistry runs the registered IAnalyzer plugins on every candle built
+// by the facade (history) and the CandleEngine (realtime), and keeps their
+// state per analyzer/symbol/window.
+// -----------------------------------------------------------------------------
+
+type AnalyzerRegistry struct {
+	Logger *logger.Logger
+
+	analyzers []interfaces.IAnalyzer
+	states    map[string]interface{} // analyzer|symbol|window -> state
+	mu        sync.Mutex
+}
+
+// -----------------------------------------------------------------------------
+
+func NewAnalyzerRegistry(log *logger.Logger) *AnalyzerRegistry {
+	return &AnalyzerRegistry{
+		Logger: log,
+		states: make(map[string]interface{}),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Register adds an analyzer. Names must be unique and free of dots.
+func (r *AnalyzerRegistry) Register(a interfaces.IAnalyzer) error {
+	name := a.Name()
+	if name == "" || strings.Contains(name, ".") {
+		return fmt.Errorf("invalid analyzer name %q", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.analyzers {
+		if existing.Name() == name {
+			return fmt.Errorf("analyzer %q already registered", name)
+		}
+	}
+	r.analyzers = append(r.analyzers, a)
+	r.Logger.Info("Registered analyzer %s", name)
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// Names returns the registered analyzer names in registration order.
+func (r *AnalyzerRegistry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.analyzers))
+	for _, a := range r.analyzers {
+		names = append(names, a.Name())
+	}
+	return names
+}
+
+// -----------------------------------------------------------------------------
+
+// Apply runs every analyzer on a candle and replaces its Metrics. r may be nil
+// (no plugins). Non-finite values are dropped; a panicking analyzer is logged
+// and skipped.
+func (r *AnalyzerRegistry) Apply(c *models.MAggregation) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.analyzers) == 0 {
+		return
+	}
+
+	// Always a new map: earlier copies of the candle may still be read elsewhere
+	metrics := make(map[string]float64)
+	for _, a := range r.analyzers {
+		key := a.Name() + "|" + c.Symbol + "|" + c.WindowName
+		state, ok := r.states[key]
+		if !ok {
+			state = a.NewState(c.Symbol, c.WindowName)
+			r.states[key] = state
+		}
+
+		for metric, value := range r.analyze(a, *c, state) {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+			metrics[a.Name()+"."+metric] = value
+		}
+	}
+	c.Metrics = metrics
+}
+
+// -----------------------------------------------------------------------------
+
+// analyze calls one analyzer, recovering from panics in plugin code.
+func (r *AnalyzerRegistry) analyze(a interfaces.IAnalyzer, c models.MAggregation, state interface{}) (metrics map[string]float64) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.Logger.Error("Analyzer %s panicked on %s/%s: %v", a.Name(), c.Symbol, c.WindowName, rec)
+			metrics = nil
+		}
+	}()
+	return a.Analyze(c, state)
+}
@@ -0,0 +1,246 @@
+package analysis
+
+import (
+	"slices"
+	"sort"
+	"sync"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// BenchmarkService compares every candle with the same window of the symbol's
+// benchmark (per watchlist, per source or default): relative strength, excess
+// return and a rolling OLS beta/alpha over the last closed windows. The
+// residual return is what the benchmark does not explain (own-news moves).
+// -----------------------------------------------------------------------------
+
+type BenchmarkService struct {
+	Config *models.MConfig
+	Logger *logger.Logger
+
+	Lookback        int
+	MinObservations int
+
+	benchmarks map[string]bool                         // Every configured benchmark symbol
+	resolved   map[string]string                       // symbol -> benchmark ("" = none)
+	returns    map[string]map[string]map[int64]float64 // benchmark -> window -> start -> return
+	history    map[string]*returnPairs                 // symbol|window -> closed window returns
+	mu         sync.Mutex
+}
+
+// returnPairs are aligned closed-window returns, oldest first
+type returnPairs struct {
+	symbol    []float64
+	benchmark []float64
+}
+
+// -----------------------------------------------------------------------------
+
+func NewBenchmarkService(cfg *models.MConfig, log *logger.Logger) *BenchmarkService {
+	bc := cfg.Benchmarks
+
+	lookback := bc.Lookback
+	if lookback <= 0 {
+		lookback = utils.DefaultBenchmarkLookback
+	}
+	minObs := bc.MinObservations
+	if minObs <= 0 {
+		minObs = utils.DefaultBenchmarkMinObservations
+	}
+
+	benchmarks := make(map[string]bool)
+	if bc.Default != "" {
+		benchmarks[bc.Default] = true
+	}
+	for _, b := range bc.Watchlists {
+		benchmarks[b] = true
+	}
+	for _, src := range cfg.DataSource.Sources {
+		if src.Benchmark != "" {
+			benchmarks[src.Benchmark] = true
+		}
+	}
+
+	return &BenchmarkService{
+		Config:          cfg,
+		Logger:          log,
+		Lookback:        lookback,
+		MinObservations: minObs,
+		benchmarks:      benchmarks,
+		resolved:        make(map[string]string),
+		returns:         make(map[string]map[string]map[int64]float64),
+		history:         make(map[string]*returnPairs),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// BenchmarkFor returns the benchmark of a symbol: its watchlist's (first by
+// name), then its source's, then the default ("" when none applies).
+func (s *BenchmarkService) BenchmarkFor(symbol string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.benchmarkFor(symbol)
+}
+
+// -----------------------------------------------------------------------------
+
+// ApplyBatch annotates the candles of an engine batch (closed ones first, so
+// open candles see the updated beta/alpha). s may be nil (no benchmarks).
+func (s *BenchmarkService) ApplyBatch(batch models.MCandleBatch) {
+	if s == nil {
+		return
+	}
+	s.Apply(batch.Closed)
+	s.Apply(batch.Updated)
+}
+
+// -----------------------------------------------------------------------------
+
+// Apply annotates candles (symbol -> window -> candles, oldest first) in place.
+// Benchmark candles of the same call are taken into account first; closed
+// candles advance the rolling regression. s may be nil (no benchmarks).
+func (s *BenchmarkService) Apply(candles map[string]map[string][]models.MAggregation) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for symbol, windows := range candles {
+		if !s.benchmarks[symbol] {
+			continue
+		}
+		for windowName, list := range windows {
+			for _, c := range list {
+				s.recordBenchmark(symbol, windowName, c)
+			}
+		}
+	}
+
+	for symbol, windows := range candles {
+		benchmark := s.benchmarkFor(symbol)
+		if benchmark == "" || benchmark == symbol {
+			continue
+		}
+		for _, list := range windows {
+			for i := range list {
+				s.annotate(&list[i], benchmark)
+			}
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// benchmarkFor resolves (and caches) the benchmark of a symbol (caller holds the lock).
+func (s *BenchmarkService) benchmarkFor(symbol string) string {
+	if b, ok := s.resolved[symbol]; ok {
+		return b
+	}
+
+	bc := s.Config.Benchmarks
+	benchmark := ""
+
+	names := make([]string, 0, len(bc.Watchlists))
+	for name := range bc.Watchlists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if slices.Contains(s.Config.Alerts.Watchlists[name], symbol) {
+			benchmark = bc.Watchlists[name]
+			break
+		}
+	}
+
+	if benchmark == "" {
+		for _, src := range s.Config.DataSource.Sources {
+			if src.Benchmark != "" && slices.Contains(src.Symbols, symbol) {
+				benchmark = src.Benchmark
+				break
+			}
+		}
+	}
+	if benchmark == "" {
+		benchmark = bc.Default
+	}
+
+	s.resolved[symbol] = benchmark
+	return benchmark
+}
+
+// -----------------------------------------------------------------------------
+
+// recordBenchmark keeps the latest return of each benchmark window (caller holds the lock).
+func (s *BenchmarkService) recordBenchmark(benchmark, windowName string, c models.MAggregation) {
+	if s.returns[benchmark] == nil {
+		s.returns[benchmark] = make(map[string]map[int64]float64)
+	}
+	byStart := s.returns[benchmark][windowName]
+	if byStart == nil {
+		byStart = make(map[int64]float64)
+		s.returns[benchmark][windowName] = byStart
+	}
+	byStart[c.StartTime] = c.PricePercentChange
+
+	// Symbols lag the benchmark by a few windows at most: keep a margin, drop the rest
+	if limit := 2*s.Lookback + 16; len(byStart) > limit {
+		starts := make([]int64, 0, len(byStart))
+		for start := range byStart {
+			starts = append(starts, start)
+		}
+		slices.Sort(starts)
+		for _, start := range starts[:len(starts)-limit] {
+			delete(byStart, start)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// annotate fills the benchmark fields of a candle (caller holds the lock). The
+// residual uses the regression of the previous closed windows only.
+func (s *BenchmarkService) annotate(c *models.MAggregation, benchmark string) {
+	rb, ok := s.returns[benchmark][c.WindowName][c.StartTime]
+	if !ok {
+		return // Benchmark window not seen (yet)
+	}
+	rs := c.PricePercentChange
+
+	c.Benchmark = benchmark
+	c.BenchmarkReturn = rb
+	c.ExcessReturn = rs - rb
+	if 1+rb != 0 {
+		c.RelativeStrength = (1+rs)/(1+rb) - 1
+	}
+
+	key := c.Symbol + "|" + c.WindowName
+	pairs, ok := s.history[key]
+	if !ok {
+		pairs = &returnPairs{}
+		s.history[key] = pairs
+	}
+
+	if len(pairs.symbol) >= s.MinObservations {
+		beta, alpha, residStd := core.CalculateRegression(pairs.benchmark, pairs.symbol)
+		c.Beta = beta
+		c.Alpha = alpha
+		c.ResidualReturn = rs - (alpha + beta*rb)
+		c.ResidualZScore = core.CalculateZScore(c.ResidualReturn, 0, residStd)
+	}
+
+	if c.IsClosed {
+		pairs.symbol = append(pairs.symbol, rs)
+		pairs.benchmark = append(pairs.benchmark, rb)
+		if overflow := len(pairs.symbol) - s.Lookback; overflow > 0 {
+			pairs.symbol = append([]float64(nil), pairs.symbol[overflow:]...)
+			pairs.benchmark = append([]float64(nil), pairs.benchmark[overflow:]...)
+		}
+	}
+}
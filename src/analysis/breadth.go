@@ -0,0 +1,275 @@
+package analysis
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// BreadthCalculator summarizes the monitored universe after each update cycle,
+// per window: advancers/decliners, up/down volume, share of symbols above
+// their N-period average, new session highs/lows and volume anomalies.
+// -----------------------------------------------------------------------------
+
+type BreadthCalculator struct {
+	Config *models.MConfig
+	Logger *logger.Logger
+
+	AveragePeriod int
+	AnomalyRatio  float64
+	MaxHistory    int
+
+	windows    map[string]utils.WindowSpec
+	closeDelay int64 // Seconds after its end a candle is closed by the clock
+
+	symbols map[string]*symbolBreadth            // symbol|window -> state
+	latest  map[string]map[string]*symbolBreadth // window -> symbol -> state
+	history map[string][]models.MBreadthSnapshot // window -> snapshots, oldest first
+	mu      sync.RWMutex
+}
+
+type symbolBreadth struct {
+	candle models.MAggregation // Latest version of the current candle
+	closes []float64           // Closes of the last closed candles, oldest first
+
+	session   int64 // Open of the session the high/low belong to
+	high, low float64
+	newHigh   bool // Session high/low extended in the current cycle
+	newLow    bool
+}
+
+// -----------------------------------------------------------------------------
+
+func NewBreadthCalculator(cfg *models.MConfig, log *logger.Logger) *BreadthCalculator {
+	bc := cfg.Breadth
+
+	period := bc.AveragePeriod
+	if period <= 0 {
+		period = utils.DefaultBreadthAveragePeriod
+	}
+	ratio := bc.AnomalyRatio
+	if ratio <= 0 {
+		ratio = utils.DefaultBreadthAnomalyRatio
+	}
+	maxHistory := bc.MaxHistory
+	if maxHistory <= 0 {
+		maxHistory = utils.DefaultBreadthMaxHistory
+	}
+
+	return &BreadthCalculator{
+		Config:        cfg,
+		Logger:        log,
+		AveragePeriod: period,
+		AnomalyRatio:  ratio,
+		MaxHistory:    maxHistory,
+		windows:       utils.ParseWindows(cfg.WindowsAgg),
+		closeDelay:    int64(cfg.DataSource.UpdateIntervalSeconds),
+		symbols:       make(map[string]*symbolBreadth),
+		latest:        make(map[string]map[string]*symbolBreadth),
+		history:       make(map[string][]models.MBreadthSnapshot),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Seed replays historical candles (symbol -> window -> candles, oldest first)
+// so averages and session highs/lows are in place before the first cycle.
+func (b *BreadthCalculator) Seed(candles map[string]map[string][]models.MAggregation) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, windows := range candles {
+		for _, list := range windows {
+			for _, c := range list {
+				b.observe(c)
+			}
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Update folds an engine batch in and returns one snapshot per configured
+// window (windows without candles are skipped). timestamp is the wall clock.
+func (b *BreadthCalculator) Update(batch models.MCandleBatch, timestamp int64) []models.MBreadthSnapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, st := range b.symbols {
+		st.newHigh, st.newLow = false, false
+	}
+
+	// Closed candles first: the open candle that follows replaces them as latest
+	for _, source := range []map[string]map[string][]models.MAggregation{batch.Closed, batch.Updated} {
+		for _, windows := range source {
+			for _, list := range windows {
+				for _, c := range list {
+					b.observe(c)
+				}
+			}
+		}
+	}
+
+	var snapshots []models.MBreadthSnapshot
+	for _, w := range b.Config.WindowsAgg {
+		snap, ok := b.snapshot(w, timestamp)
+		if !ok {
+			continue
+		}
+		snapshots = append(snapshots, snap)
+
+		history := append(b.history[w], snap)
+		if overflow := len(history) - b.MaxHistory; overflow > 0 {
+			history = append([]models.MBreadthSnapshot(nil), history[overflow:]...)
+		}
+		b.history[w] = history
+	}
+	return snapshots
+}
+
+// -----------------------------------------------------------------------------
+
+// Latest returns the most recent snapshot of every window.
+func (b *BreadthCalculator) Latest() map[string]models.MBreadthSnapshot {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	result := make(map[string]models.MBreadthSnapshot, len(b.history))
+	for w, history := range b.history {
+		if len(history) > 0 {
+			result[w] = history[len(history)-1]
+		}
+	}
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// History returns the last snapshots of a window, oldest first (limit <= 0 =
+// all kept). False when the window is not configured.
+func (b *BreadthCalculator) History(window string, limit int) ([]models.MBreadthSnapshot, bool) {
+	if !slices.Contains(b.Config.WindowsAgg, window) {
+		return nil, false
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	history := b.history[window]
+	if limit > 0 && len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	return append([]models.MBreadthSnapshot(nil), history...), true
+}
+
+// -----------------------------------------------------------------------------
+
+// observe records a candle version (caller holds the lock).
+func (b *BreadthCalculator) observe(c models.MAggregation) {
+	key := c.Symbol + "|" + c.WindowName
+	st, ok := b.symbols[key]
+	if !ok {
+		st = &symbolBreadth{}
+		b.symbols[key] = st
+		if b.latest[c.WindowName] == nil {
+			b.latest[c.WindowName] = make(map[string]*symbolBreadth)
+		}
+		b.latest[c.WindowName][c.Symbol] = st
+	}
+
+	if c.StartTime >= st.candle.StartTime {
+		st.candle = c
+	}
+
+	// Session high/low: the first candle of a session only sets the levels
+	open, _, _ := utils.GetCalendar(c.Symbol).SessionBounds(time.Unix(c.StartTime, 0))
+	if session := open.Unix(); session != st.session {
+		st.session, st.high, st.low = session, c.High, c.Low
+	} else {
+		if c.High > st.high {
+			st.high, st.newHigh = c.High, true
+		}
+		if c.Low < st.low {
+			st.low, st.newLow = c.Low, true
+		}
+	}
+
+	if c.IsClosed {
+		st.closes = append(st.closes, c.Close)
+		if overflow := len(st.closes) - b.AveragePeriod; overflow > 0 {
+			st.closes = append([]float64(nil), st.closes[overflow:]...)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// snapshot computes the breadth of a window over the symbols whose latest
+// candle is current on their own calendar (caller holds the lock).
+func (b *BreadthCalculator) snapshot(window string, timestamp int64) (models.MBreadthSnapshot, bool) {
+	spec := b.windows[window]
+	asOf := timestamp - b.closeDelay // Candles ended before are closed by now
+
+	snap := models.MBreadthSnapshot{WindowName: window, Timestamp: timestamp}
+	withAverage := 0
+	for symbol, st := range b.latest[window] {
+		c := st.candle
+		if !spec.IsCurrent(c.EndTime, asOf, utils.GetCalendar(symbol)) {
+			continue // Stale feed (halted or closed market)
+		}
+		snap.Symbols++
+
+		switch {
+		case c.PricePercentChange > 0:
+			snap.Advancers++
+			snap.UpVolume += c.Volume
+		case c.PricePercentChange < 0:
+			snap.Decliners++
+			snap.DownVolume += c.Volume
+		default:
+			snap.Unchanged++
+		}
+
+		// N-period average including the current candle (already in closes once closed)
+		closes := st.closes
+		if !c.IsClosed {
+			closes = append(closes[:len(closes):len(closes)], c.Close)
+		}
+		if len(closes) >= b.AveragePeriod {
+			sum := 0.0
+			for _, v := range closes[len(closes)-b.AveragePeriod:] {
+				sum += v
+			}
+			withAverage++
+			if c.Close > sum/float64(b.AveragePeriod) {
+				snap.AboveAverage++
+			}
+		}
+
+		if st.newHigh {
+			snap.NewHighs++
+		}
+		if st.newLow {
+			snap.NewLows++
+		}
+		if c.VolumeAnomalyRatio >= b.AnomalyRatio {
+			snap.Anomalies++
+		}
+	}
+
+	if snap.Symbols == 0 {
+		return snap, false
+	}
+	if snap.DownVolume > 0 {
+		snap.UpDownVolumeRatio = snap.UpVolume / snap.DownVolume
+	}
+	if withAverage > 0 {
+		snap.PctAboveAverage = float64(snap.AboveAverage) / float64(withAverage)
+	}
+	return snap, true
+}
@@ -0,0 +1,161 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+func newTestBreadth(period int) *BreadthCalculator {
+	cfg := &models.MConfig{WindowsAgg: []string{"5m", "session"}}
+	cfg.DataSource.UpdateIntervalSeconds = 60
+	cfg.Breadth.AveragePeriod = period
+	return NewBreadthCalculator(cfg, logger.NewLogger(cfg, "test"))
+}
+
+func breadthCandle(symbol, window string, start, end int64, change, volume float64) models.MAggregation {
+	return models.MAggregation{
+		Symbol: symbol, WindowName: window, StartTime: start, EndTime: end,
+		Open: 100, High: 101, Low: 99, Close: 100 * (1 + change),
+		PricePercentChange: change, Volume: volume,
+	}
+}
+
+func candleBatch(updated ...models.MAggregation) models.MCandleBatch {
+	batch := models.MCandleBatch{Updated: make(map[string]map[string][]models.MAggregation)}
+	for _, c := range updated {
+		if batch.Updated[c.Symbol] == nil {
+			batch.Updated[c.Symbol] = make(map[string][]models.MAggregation)
+		}
+		batch.Updated[c.Symbol][c.WindowName] = append(batch.Updated[c.Symbol][c.WindowName], c)
+	}
+	return batch
+}
+
+func TestBreadthCounts(t *testing.T) {
+	b := newTestBreadth(20)
+	open := nyseTime(t, 3, 12, 10, 5)
+
+	a := breadthCandle("AAA", "5m", open, open+300, 0.01, 100)
+	a.VolumeAnomalyRatio = 4
+	stale := breadthCandle("DDD", "5m", open-600, open-300, 0.05, 1000) // Two windows back
+	stale.IsClosed = true
+
+	snapshots := b.Update(candleBatch(
+		a,
+		breadthCandle("BBB", "5m", open, open+300, -0.01, 50),
+		breadthCandle("CCC", "5m", open, open+300, 0, 10),
+		stale,
+	), open+60)
+
+	if len(snapshots) != 1 || snapshots[0].WindowName != "5m" {
+		t.Fatalf("snapshots = %+v", snapshots)
+	}
+	s := snapshots[0]
+	if s.Symbols != 3 || s.Advancers != 1 || s.Decliners != 1 || s.Unchanged != 1 || s.Anomalies != 1 {
+		t.Errorf("counts = %+v", s)
+	}
+	if s.UpVolume != 100 || s.DownVolume != 50 || s.UpDownVolumeRatio != 2 {
+		t.Errorf("volumes = %+v", s)
+	}
+	if s.AboveAverage != 0 || s.PctAboveAverage != 0 {
+		t.Errorf("average without enough candles = %+v", s)
+	}
+
+	// The candle closed by the clock is still counted in the next cycle
+	closedA := a
+	closedA.IsClosed = true
+	s = b.Update(models.MCandleBatch{Closed: candleBatch(closedA).Updated}, open+360)[0]
+	if s.Symbols != 3 {
+		t.Errorf("after the clock close: %d symbols, want 3", s.Symbols)
+	}
+}
+
+func TestBreadthAverageAndSessionExtremes(t *testing.T) {
+	b := newTestBreadth(3)
+	start := nyseTime(t, 3, 12, 10, 0)
+
+	seed := func(symbol string, closes ...float64) []models.MAggregation {
+		var list []models.MAggregation
+		for i, close := range closes {
+			c := breadthCandle(symbol, "5m", start+int64(i)*300, start+int64(i+1)*300, 0, 1)
+			c.Close, c.High, c.Low, c.IsClosed = close, close+0.5, close-0.5, true
+			list = append(list, c)
+		}
+		return list
+	}
+	b.Seed(map[string]map[string][]models.MAggregation{
+		"UP":   {"5m": seed("UP", 10, 11)},
+		"DOWN": {"5m": seed("DOWN", 10, 11)},
+	})
+
+	up := breadthCandle("UP", "5m", start+600, start+900, 0.1, 1)
+	up.Close, up.High, up.Low = 13, 13, 12
+	down := breadthCandle("DOWN", "5m", start+600, start+900, -0.1, 1)
+	down.Close, down.High, down.Low = 8, 11, 7.5
+
+	s := b.Update(candleBatch(up, down), start+660)[0]
+	if s.AboveAverage != 1 || math.Abs(s.PctAboveAverage-0.5) > 1e-12 {
+		t.Errorf("above average = %d (%v), want 1 (0.5)", s.AboveAverage, s.PctAboveAverage)
+	}
+	if s.NewHighs != 1 || s.NewLows != 1 {
+		t.Errorf("new highs/lows = %d/%d, want 1/1", s.NewHighs, s.NewLows)
+	}
+
+	// Not extended again in the next cycle
+	s = b.Update(candleBatch(up, down), start+720)[0]
+	if s.NewHighs != 0 || s.NewLows != 0 {
+		t.Errorf("repeated candle: new highs/lows = %d/%d, want 0/0", s.NewHighs, s.NewLows)
+	}
+}
+
+func TestBreadthAcrossCalendars(t *testing.T) {
+	b := newTestBreadth(20)
+	now := nyseTime(t, 3, 12, 14, 0) // London closed at 12:30 New York time
+
+	london := breadthCandle("VOD.L", "session", nyseTime(t, 3, 12, 4, 0), nyseTime(t, 3, 12, 12, 30), 0.01, 100)
+	london.IsClosed = true
+	newYork := breadthCandle("AAPL", "session", nyseTime(t, 3, 12, 9, 30), nyseTime(t, 3, 12, 16, 0), -0.01, 100)
+	yesterday := breadthCandle("MSFT", "session", nyseTime(t, 3, 11, 9, 30), nyseTime(t, 3, 11, 16, 0), 0.02, 100)
+	yesterday.IsClosed = true
+
+	// Hong Kong's session of the day ended before New York opened
+	open, close, _ := utils.GetCalendar("0005.HK").SessionBounds(time.Unix(nyseTime(t, 3, 11, 23, 0), 0))
+	hongKong := breadthCandle("0005.HK", "session", open.Unix(), close.Unix(), 0.01, 100)
+	hongKong.IsClosed = true
+	if close.Unix() >= newYork.StartTime {
+		t.Fatalf("Hong Kong session [%s, %s) overlaps New York's", open, close)
+	}
+
+	snapshots := b.Update(candleBatch(london, newYork, yesterday, hongKong), now)
+	if len(snapshots) != 1 {
+		t.Fatalf("snapshots = %+v", snapshots)
+	}
+	// Sessions of the day are current on their own calendar; MSFT has not traded today
+	if s := snapshots[0]; s.Symbols != 3 || s.Advancers != 2 || s.Decliners != 1 {
+		t.Errorf("session breadth = %+v, want VOD.L, 0005.HK and AAPL", s)
+	}
+}
+
+func TestBreadthHistory(t *testing.T) {
+	b := newTestBreadth(20)
+	open := nyseTime(t, 3, 12, 10, 5)
+	for i := int64(0); i < 3; i++ {
+		b.Update(candleBatch(breadthCandle("AAA", "5m", open, open+300, 0.01, 1)), open+60+i)
+	}
+
+	if _, ok := b.History("1h", 0); ok {
+		t.Error("History of an unconfigured window")
+	}
+	history, ok := b.History("5m", 2)
+	if !ok || len(history) != 2 || history[1].Timestamp != open+62 {
+		t.Errorf("History(5m, 2) = %+v", history)
+	}
+	if latest := b.Latest(); latest["5m"].Timestamp != open+62 {
+		t.Errorf("Latest = %+v", latest)
+	}
+}
@@ -0,0 +1,382 @@
+package analysis
+
+import (
+	"sort"
+	"sync"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// CandleEngine keeps one open candle per symbol and window and updates it in
+// O(1) per incoming point, instead of re-aggregating the full history.
+// Only the base window reads raw points; every other window is built from the
+// closed candles of its parent plus the parent's open candle (WindowHierarchy).
+// -----------------------------------------------------------------------------
+
+type CandleEngine struct {
+	Config    *models.MConfig
+	Windows   map[string]utils.WindowSpec
+	Hierarchy *utils.WindowHierarchy
+	Logger    *logger.Logger
+
+	Stats       *RollingStats       // Volume baselines, updated on every closed candle
+	Quality     *DataQualityMonitor // Missing bars per candle (optional)
+	Analyzers   *AnalyzerRegistry   // Plugin metrics (optional)
+	Benchmarks  *BenchmarkService   // Relative strength / beta versus benchmarks (optional)
+	Volatility  *VolatilityService  // Volatility estimators and regimes (optional)
+	Seasonality *VolumeSeasonality  // Time-of-day volume baseline (optional, flat baseline when nil)
+
+	states   map[string]map[string]*candleState // symbol -> window -> state
+	sessions map[string]*sessionVWAP            // symbol -> anchored session VWAP
+	mu       sync.RWMutex
+}
+
+// candleState holds the open candle and the running sums needed to derive its metrics.
+type candleState struct {
+	candle models.MAggregation
+	open   bool
+
+	closed     candleSums  // Raw points (base window) or finished parent candles of the open window
+	total      candleSums  // closed + the parent's open candle (what the candle shows)
+	justClosed *windowSums // Candle finished by the current point (nil otherwise)
+	touched    bool        // Updated by the current point
+
+	// Last closed candle (for percent changes)
+	prevClose  float64
+	prevVolume float64
+	hasPrev    bool
+}
+
+// -----------------------------------------------------------------------------
+
+func NewCandleEngine(cfg *models.MConfig, stats *RollingStats, log *logger.Logger) *CandleEngine {
+	return &CandleEngine{
+		Config:    cfg,
+		Windows:   utils.ParseWindows(cfg.WindowsAgg),
+		Hierarchy: windowHierarchy(cfg.WindowsAgg, log),
+		Logger:    log,
+		Stats:     stats,
+		states:    make(map[string]map[string]*candleState),
+		sessions:  make(map[string]*sessionVWAP),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// windowHierarchy resolves the window hierarchy, falling back to building every
+// window from raw points when it is invalid (the config loader rejects it).
+func windowHierarchy(windows []string, log *logger.Logger) *utils.WindowHierarchy {
+	h, err := utils.BuildWindowHierarchy(windows)
+	if err != nil {
+		log.Error("Invalid window hierarchy, aggregating every window from raw points: %v", err)
+		return utils.FlatWindowHierarchy(windows)
+	}
+	return h
+}
+
+// -----------------------------------------------------------------------------
+
+// Warmup replays historical points to rebuild open candles and previous closes.
+// Produced candles are discarded (they are already built by AggregateHistorical)
+// and do not update the rolling stats.
+func (e *CandleEngine) Warmup(data map[string][]models.MStockPrice) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for symbol, prices := range data {
+		sorted := make([]models.MStockPrice, len(prices))
+		copy(sorted, prices)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].Timestamp < sorted[j].Timestamp
+		})
+
+		for _, p := range sorted {
+			e.addPoint(symbol, p, nil)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// ProcessUpdates applies a batch of new points and returns the latest open candle
+// of every touched symbol/window plus the candles closed by this batch.
+func (e *CandleEngine) ProcessUpdates(updates map[string][]models.MStockPrice) models.MCandleBatch {
+	batch := models.MCandleBatch{
+		Updated: make(map[string]map[string][]models.MAggregation),
+		Closed:  make(map[string]map[string][]models.MAggregation),
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for symbol, prices := range updates {
+		if len(prices) == 0 {
+			continue
+		}
+
+		sorted := make([]models.MStockPrice, len(prices))
+		copy(sorted, prices)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].Timestamp < sorted[j].Timestamp
+		})
+
+		for _, p := range sorted {
+			e.addPoint(symbol, p, func(closed models.MAggregation) {
+				e.Stats.Update(closed)
+				if batch.Closed[symbol] == nil {
+					batch.Closed[symbol] = make(map[string][]models.MAggregation)
+				}
+				batch.Closed[symbol][closed.WindowName] = append(batch.Closed[symbol][closed.WindowName], closed)
+			})
+		}
+
+		// Only the latest state of each open candle is reported
+		for w, st := range e.states[symbol] {
+			if !st.open {
+				continue
+			}
+			if batch.Updated[symbol] == nil {
+				batch.Updated[symbol] = make(map[string][]models.MAggregation)
+			}
+			batch.Updated[symbol][w] = []models.MAggregation{st.candle}
+		}
+	}
+
+	e.Benchmarks.ApplyBatch(batch)
+	e.Volatility.ApplyBatch(batch)
+	return batch
+}
+
+// -----------------------------------------------------------------------------
+
+// CloseExpired closes the open candles whose window ended at or before now
+// (Unix seconds), so the last candle of a session does not wait for the first
+// point of the next session. Higher windows take the expired candles of their
+// parent and close in the same pass when they have ended too.
+func (e *CandleEngine) CloseExpired(now int64) models.MCandleBatch {
+	batch := models.MCandleBatch{
+		Updated: make(map[string]map[string][]models.MAggregation),
+		Closed:  make(map[string]map[string][]models.MAggregation),
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for symbol, states := range e.states {
+		for _, windowName := range e.Hierarchy.Order {
+			st, ok := states[windowName]
+			if !ok {
+				continue
+			}
+			st.justClosed = nil
+
+			// The parent's candle is already part of the total: it becomes a closed part
+			if parent := states[e.Hierarchy.Parent[windowName]]; parent != nil && parent.justClosed != nil && st.open {
+				st.closed.merge(parent.justClosed.sums)
+				st.total = st.closed
+			}
+
+			if !st.open || st.candle.EndTime > now {
+				continue
+			}
+			e.closeCandle(st, func(closed models.MAggregation) {
+				e.Stats.Update(closed)
+				if batch.Closed[symbol] == nil {
+					batch.Closed[symbol] = make(map[string][]models.MAggregation)
+				}
+				batch.Closed[symbol][windowName] = append(batch.Closed[symbol][windowName], closed)
+			})
+		}
+	}
+
+	if len(batch.Closed) > 0 {
+		e.Benchmarks.ApplyBatch(batch)
+		e.Volatility.ApplyBatch(batch)
+	}
+	return batch
+}
+
+// -----------------------------------------------------------------------------
+
+// GetOpenCandle returns the current open candle for a symbol/window.
+func (e *CandleEngine) GetOpenCandle(symbol, windowName string) (models.MAggregation, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if st, ok := e.states[symbol][windowName]; ok && st.open {
+		return st.candle, true
+	}
+	return models.MAggregation{}, false
+}
+
+// -----------------------------------------------------------------------------
+
+// addPoint updates every window of a symbol with one point (caller holds the lock).
+// onClose is called for each candle finalized by the point; it may be nil.
+func (e *CandleEngine) addPoint(symbol string, p models.MStockPrice, onClose func(models.MAggregation)) {
+	if e.states[symbol] == nil {
+		e.states[symbol] = make(map[string]*candleState)
+	}
+
+	session, ok := e.sessions[symbol]
+	if !ok {
+		session = newSessionVWAP(symbol)
+		e.sessions[symbol] = session
+	}
+	session.Add(p)
+
+	for _, windowName := range e.Hierarchy.Order {
+		spec, ok := e.Windows[windowName]
+		if !ok {
+			continue
+		}
+
+		st, ok := e.states[symbol][windowName]
+		if !ok {
+			st = &candleState{}
+			e.states[symbol][windowName] = st
+		}
+		st.justClosed = nil
+		st.touched = false
+
+		parentName := e.Hierarchy.Parent[windowName]
+		if parentName == "" {
+			// Base window: built from raw points
+			wStart, wEnd := e.bounds(st, spec, p.Timestamp, session.calendar)
+			if st.isLate(wStart) {
+				// Late point for an already closed window: dropped
+				e.Logger.Debug("CandleEngine: Dropping late point for %s/%s at %d", symbol, windowName, p.Timestamp)
+				continue
+			}
+			e.roll(st, symbol, windowName, wStart, wEnd, onClose)
+			st.closed.addPoint(p)
+			st.total = st.closed
+		} else {
+			parent := e.states[symbol][parentName]
+			if parent == nil || !parent.touched {
+				continue
+			}
+
+			// A finished parent candle joins the window it belongs to
+			if parent.justClosed != nil {
+				wStart, wEnd := e.bounds(st, spec, parent.justClosed.start, session.calendar)
+				e.roll(st, symbol, windowName, wStart, wEnd, onClose)
+				st.closed.merge(parent.justClosed.sums)
+			}
+
+			// The parent's open candle may start the next window
+			wStart, wEnd := e.bounds(st, spec, parent.candle.StartTime, session.calendar)
+			e.roll(st, symbol, windowName, wStart, wEnd, onClose)
+			st.total = st.closed
+			st.total.merge(parent.total)
+		}
+
+		st.touched = true
+		e.refreshMetrics(symbol, windowName, st, session)
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// bounds returns the window containing ts, skipping the calendar lookup when
+// ts falls in the open candle.
+func (e *CandleEngine) bounds(st *candleState, spec utils.WindowSpec, ts int64, calendar *utils.TradingCalendar) (int64, int64) {
+	if st.open && ts >= st.candle.StartTime && ts < st.candle.EndTime {
+		return st.candle.StartTime, st.candle.EndTime
+	}
+	return spec.Bounds(ts, calendar)
+}
+
+// -----------------------------------------------------------------------------
+
+// roll makes [start, end) the open window, closing the open candle when it is
+// an earlier window.
+func (e *CandleEngine) roll(st *candleState, symbol, windowName string, start, end int64, onClose func(models.MAggregation)) {
+	if st.open && start <= st.candle.StartTime {
+		return
+	}
+
+	if st.open {
+		e.closeCandle(st, onClose)
+	}
+
+	st.reset(symbol, windowName, start, end)
+}
+
+// -----------------------------------------------------------------------------
+
+// closeCandle finalizes the open candle. The candle stays in the state so that
+// late points for its window are still recognized once it is closed.
+func (e *CandleEngine) closeCandle(st *candleState, onClose func(models.MAggregation)) {
+	closed := st.candle
+	closed.IsClosed = true
+	if onClose != nil {
+		e.Seasonality.annotate(&closed) // Records the slot volume (replayed candles were recorded by the facade)
+		e.Analyzers.Apply(&closed)      // Final metrics (replayed candles were analyzed by the facade)
+		onClose(closed)
+	}
+	st.justClosed = &windowSums{start: closed.StartTime, end: closed.EndTime, sums: st.total}
+	st.prevClose = closed.Close
+	st.prevVolume = closed.Volume
+	st.hasPrev = true
+	st.open = false
+}
+
+// -----------------------------------------------------------------------------
+
+// refreshMetrics recomputes the derived fields of an open candle from its running sums.
+func (e *CandleEngine) refreshMetrics(symbol, windowName string, st *candleState, session *sessionVWAP) {
+	c := &st.candle
+
+	st.total.fill(c)
+	e.Quality.annotate(c)
+	session.Apply(c)
+
+	avgVol := 1.0
+	if stat, ok := e.Stats.Get(symbol, windowName); ok {
+		avgVol = stat.AvgVolumeHistory
+	}
+	c.VolumeAnomalyRatio = core.CalculateAnomalyRatio(c.Volume, avgVol)
+	e.Seasonality.annotate(c)
+
+	if st.hasPrev {
+		c.PricePercentChange = core.CalculateChangePercent(c.Close, st.prevClose)
+		c.VolumePercentChange = core.CalculateChangePercent(c.Volume, st.prevVolume)
+	} else {
+		// Same fallback as AggregateRealTime when no previous window is known
+		c.PricePercentChange = core.CalculateChangePercent(c.Close, c.Open)
+		c.VolumePercentChange = 0
+	}
+
+	e.Analyzers.Apply(c)
+}
+
+// -----------------------------------------------------------------------------
+
+// reset starts a new empty candle, keeping the previous-close chain.
+func (st *candleState) reset(symbol, windowName string, start, end int64) {
+	st.candle = models.MAggregation{
+		Symbol:     symbol,
+		WindowName: windowName,
+		StartTime:  start,
+		EndTime:    end,
+	}
+	st.closed = candleSums{}
+	st.total = candleSums{}
+	st.open = true
+}
+
+// -----------------------------------------------------------------------------
+
+// isLate reports whether a point of the window starting at start arrives after
+// that window (or a later one) was opened or closed.
+func (st *candleState) isLate(start int64) bool {
+	if st.open {
+		return start < st.candle.StartTime
+	}
+	return st.hasPrev && start < st.candle.EndTime
+}
@@ -0,0 +1,212 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// base is aligned on both the 5m and the 15m grid
+const engineTestBase = int64(1_700_000_100)
+
+func newTestEngine() *CandleEngine {
+	cfg := &models.MConfig{WindowsAgg: []string{"5m", "15m"}}
+	log := logger.NewLogger(cfg, "test")
+	return NewCandleEngine(cfg, NewRollingStats(cfg), log)
+}
+
+func enginePoint(offset int64, price, volume float64) models.MStockPrice {
+	return models.MStockPrice{Symbol: "TEST", Price: price, Volume: volume, Timestamp: engineTestBase + offset}
+}
+
+func TestCandleEngineCloseExpired(t *testing.T) {
+	e := newTestEngine()
+	e.ProcessUpdates(map[string][]models.MStockPrice{"TEST": {
+		enginePoint(0, 10, 100),
+		enginePoint(60, 11, 100),
+		enginePoint(120, 12, 100),
+	}})
+
+	if batch := e.CloseExpired(engineTestBase + 299); len(batch.Closed) != 0 {
+		t.Fatalf("closed before the window ended: %+v", batch.Closed)
+	}
+
+	batch := e.CloseExpired(engineTestBase + 300)
+	closed := batch.Closed["TEST"]["5m"]
+	if len(closed) != 1 || !closed[0].IsClosed || closed[0].Close != 12 || closed[0].Volume != 300 {
+		t.Fatalf("5m candle not closed at its end: %+v", closed)
+	}
+	if _, ok := batch.Closed["TEST"]["15m"]; ok {
+		t.Fatalf("15m candle closed before its end")
+	}
+	if _, ok := e.GetOpenCandle("TEST", "15m"); !ok {
+		t.Fatalf("15m candle should stay open")
+	}
+	if again := e.CloseExpired(engineTestBase + 300); len(again.Closed) != 0 {
+		t.Fatalf("candle closed twice: %+v", again.Closed)
+	}
+
+	// A late point for the expired window is dropped
+	e.ProcessUpdates(map[string][]models.MStockPrice{"TEST": {enginePoint(250, 50, 1000)}})
+	if _, ok := e.GetOpenCandle("TEST", "5m"); ok {
+		t.Fatalf("late point reopened the expired window")
+	}
+
+	// The next window continues the 15m candle with the expired 5m candle included
+	e.ProcessUpdates(map[string][]models.MStockPrice{"TEST": {enginePoint(400, 13, 50)}})
+	c15, ok := e.GetOpenCandle("TEST", "15m")
+	if !ok || c15.Volume != 350 || c15.High != 13 || c15.Open != 10 {
+		t.Fatalf("15m candle after expiry = %+v", c15)
+	}
+
+	batch = e.CloseExpired(engineTestBase + 900)
+	if got := batch.Closed["TEST"]["5m"]; len(got) != 1 || got[0].Volume != 50 {
+		t.Fatalf("second 5m candle = %+v", got)
+	}
+	got := batch.Closed["TEST"]["15m"]
+	if len(got) != 1 || got[0].Volume != 350 || got[0].Close != 13 || got[0].Low != 10 {
+		t.Fatalf("15m candle = %+v", got)
+	}
+}
+
+// Higher windows built from their parents match the same windows aggregated from raw points
+func TestCandleEngineHierarchyMatchesRawAggregation(t *testing.T) {
+	cfg := &models.MConfig{WindowsAgg: []string{"5m", "15m", "1h", "2h45m"}}
+	log := logger.NewLogger(cfg, "test")
+	hierarchical := NewCandleEngine(cfg, NewRollingStats(cfg), log)
+	if hierarchical.Hierarchy.Parent["1h"] != "15m" || hierarchical.Hierarchy.Parent["2h45m"] != "15m" {
+		t.Fatalf("unexpected hierarchy: %+v", hierarchical.Hierarchy.Parent)
+	}
+	raw := NewCandleEngine(cfg, NewRollingStats(cfg), log)
+	raw.Hierarchy = utils.FlatWindowHierarchy(cfg.WindowsAgg)
+
+	// Five hours of one-minute points, fed in uneven batches
+	var points []models.MStockPrice
+	for i := int64(0); i < 300; i++ {
+		price := 100 + 5*math.Sin(float64(i)/17) + float64(i%7)/10
+		points = append(points, models.MStockPrice{Symbol: "TEST", Price: price, Volume: float64(100 + (i*37)%250), Timestamp: engineTestBase + 60*i})
+	}
+
+	collect := func(e *CandleEngine) map[string][]models.MAggregation {
+		closed := make(map[string][]models.MAggregation)
+		for start := 0; start < len(points); {
+			end := min(start+1+start%13, len(points))
+			batch := e.ProcessUpdates(map[string][]models.MStockPrice{"TEST": points[start:end]})
+			for w, list := range batch.Closed["TEST"] {
+				closed[w] = append(closed[w], list...)
+			}
+			start = end
+		}
+		for w, list := range e.CloseExpired(engineTestBase + 86400).Closed["TEST"] {
+			closed[w] = append(closed[w], list...)
+		}
+		return closed
+	}
+
+	got, want := collect(hierarchical), collect(raw)
+	for _, w := range cfg.WindowsAgg {
+		if len(got[w]) == 0 || len(got[w]) != len(want[w]) {
+			t.Fatalf("%s: %d hierarchical candles, %d raw", w, len(got[w]), len(want[w]))
+		}
+		for i := range got[w] {
+			if got[w][i].StartTime != want[w][i].StartTime || got[w][i].EndTime != want[w][i].EndTime {
+				t.Fatalf("%s[%d]: bounds differ", w, i)
+			}
+			assertSameCandle(t, w, got[w][i], want[w][i])
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Full-history re-aggregation (AnalysisFacade) vs incremental aggregation
+// (CandleEngine) for one update tick over the whole universe:
+//
+//	go test ./src/analysis -run '^$' -bench 'AnalysisFacade|CandleEngine' -benchmem
+// -----------------------------------------------------------------------------
+
+const (
+	benchSymbols = 1000
+	benchStep    = int64(300) // 5m bars
+)
+
+var benchWindows = []string{"5m", "10m", "15m", "30m", "1h", "2h", "4h", "6h"}
+
+// benchUniverse builds the synthetic 5m history of every symbol (retention-sized)
+// and the timestamp of its last point.
+func benchUniverse(mm *utils.MemoryManager) (map[string][]models.MStockPrice, int64) {
+	history := utils.CalculateMaxDataPoints(utils.DefaultRetentionDays)
+	rng := rand.New(rand.NewSource(42))
+	lastTs := int64(1_700_000_000) - int64(1_700_000_000)%benchStep
+
+	data := make(map[string][]models.MStockPrice, benchSymbols)
+	for i := 0; i < benchSymbols; i++ {
+		sym := fmt.Sprintf("SYM%04d", i)
+		price := 100.0
+		list := make([]models.MStockPrice, history)
+		for j := range list {
+			price *= 1 + (rng.Float64()-0.5)*0.002
+			list[j] = models.MStockPrice{
+				Symbol:    sym,
+				Price:     price,
+				Volume:    1000 + rng.Float64()*1000,
+				Timestamp: lastTs - int64(history-j)*benchStep,
+			}
+			if mm != nil {
+				mm.AddDataPoint(sym, list[j])
+			}
+		}
+		data[sym] = list
+	}
+	return data, lastTs
+}
+
+// benchTick is one new point per symbol, n bars after lastTs.
+func benchTick(data map[string][]models.MStockPrice, lastTs int64, n int) map[string][]models.MStockPrice {
+	updates := make(map[string][]models.MStockPrice, len(data))
+	for sym, list := range data {
+		last := list[len(list)-1]
+		updates[sym] = []models.MStockPrice{{Symbol: sym, Price: last.Price, Volume: last.Volume, Timestamp: lastTs + int64(n)*benchStep}}
+	}
+	return updates
+}
+
+func BenchmarkAnalysisFacadeFullHistory(b *testing.B) {
+	cfg := &models.MConfig{WindowsAgg: benchWindows}
+	mm := utils.NewMemoryManager(1<<20, utils.CalculateMaxDataPoints(utils.DefaultRetentionDays)+1)
+	data, _ := benchUniverse(mm)
+	facade := NewAnalysisFacade(cfg, logger.NewLogger(cfg, "bench"))
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		// Previous runDataLoop behavior: copy full history then aggregate every window
+		full := make(map[string][]models.MStockPrice, len(data))
+		for sym := range data {
+			full[sym] = mm.GetHistory(sym)
+		}
+		for _, w := range cfg.WindowsAgg {
+			facade.AggregateRealTime(full, w, nil)
+		}
+	}
+}
+
+func BenchmarkCandleEngineIncremental(b *testing.B) {
+	cfg := &models.MConfig{WindowsAgg: benchWindows}
+	data, lastTs := benchUniverse(nil)
+	engine := NewCandleEngine(cfg, NewRollingStats(cfg), logger.NewLogger(cfg, "bench"))
+	engine.Warmup(data)
+
+	ticks := make([]map[string][]models.MStockPrice, b.N)
+	for n := range ticks {
+		ticks[n] = benchTick(data, lastTs, n+1)
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		engine.ProcessUpdates(ticks[n])
+	}
+}
@@ -0,0 +1,120 @@
+package analysis
+
+import (
+	"math"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/models"
+)
+
+// -----------------------------------------------------------------------------
+// candleSums are the mergeable running sums behind a candle. A base window
+// accumulates raw points; a higher window is the merge of its parent candles,
+// which gives the same OHLCV, VWAP, average price and price/volume correlation
+// as aggregating the underlying points directly.
+// -----------------------------------------------------------------------------
+
+type candleSums struct {
+	open, high, low, close float64
+	volume                 float64
+	points                 int
+	filled                 int // Synthetic points (gap filling)
+	ranged                 int // Points carrying their bar's own high and low
+
+	sumPrice   float64
+	sumPV      float64
+	sumPrice2  float64
+	sumVolume2 float64
+}
+
+// windowSums are the sums of one window [start, end)
+type windowSums struct {
+	start, end int64
+	sums       candleSums
+}
+
+// -----------------------------------------------------------------------------
+
+// addPoint folds one raw point (points arrive in time order). The bar's own
+// open/high/low are used when the source provides them.
+func (s *candleSums) addPoint(p models.MStockPrice) {
+	open, high, low := p.Price, p.Price, p.Price
+	if p.High > 0 && p.Low > 0 {
+		high, low = math.Max(p.High, p.Price), math.Min(p.Low, p.Price)
+		if p.Open > 0 {
+			open = p.Open
+		}
+		s.ranged++
+	}
+
+	if s.points == 0 {
+		s.open, s.high, s.low = open, high, low
+	}
+	if high > s.high {
+		s.high = high
+	}
+	if low < s.low {
+		s.low = low
+	}
+	s.close = p.Price
+	s.volume += p.Volume
+	s.points++
+	if p.Filled {
+		s.filled++
+	}
+
+	s.sumPrice += p.Price
+	s.sumPV += p.Price * p.Volume
+	s.sumPrice2 += p.Price * p.Price
+	s.sumVolume2 += p.Volume * p.Volume
+}
+
+// -----------------------------------------------------------------------------
+
+// merge folds the sums of a later candle.
+func (s *candleSums) merge(o candleSums) {
+	if o.points == 0 {
+		return
+	}
+	if s.points == 0 {
+		*s = o
+		return
+	}
+	if o.high > s.high {
+		s.high = o.high
+	}
+	if o.low < s.low {
+		s.low = o.low
+	}
+	s.close = o.close
+	s.volume += o.volume
+	s.points += o.points
+	s.filled += o.filled
+	s.ranged += o.ranged
+
+	s.sumPrice += o.sumPrice
+	s.sumPV += o.sumPV
+	s.sumPrice2 += o.sumPrice2
+	s.sumVolume2 += o.sumVolume2
+}
+
+// -----------------------------------------------------------------------------
+
+// fill writes OHLCV, data points, filled bars, average price, VWAP and price/volume correlation.
+// The candle has a true OHLC when every point from the feed carried its high and low.
+func (s candleSums) fill(c *models.MAggregation) {
+	n := float64(s.points)
+
+	c.Open, c.High, c.Low, c.Close = s.open, s.high, s.low, s.close
+	c.Volume = s.volume
+	c.DataPoints = s.points
+	c.FilledBars = s.filled
+	c.TrueOHLC = s.ranged > 0 && s.ranged == s.points-s.filled
+	if s.points == 0 {
+		return
+	}
+
+	c.AvgPrice = s.sumPrice / n
+	c.VWAP = core.CalculateVWAP(s.sumPV, s.volume, c.AvgPrice)
+	c.PriceVolumeCorrelation = core.CalculateCorrelationFromSums(n, s.sumPrice, s.volume, s.sumPV, s.sumPrice2, s.sumVolume2)
+}
@@ -0,0 +1,113 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"market-observer/src/models"
+)
+
+func sumsTestPoints() []models.MStockPrice {
+	return []models.MStockPrice{
+		{Price: 10, Volume: 100},
+		{Price: 10.5, Volume: 250, Open: 10.1, High: 10.8, Low: 10.0},
+		{Price: 9.8, Volume: 50},
+		{Price: 11.2, Volume: 400, Open: 9.9, High: 11.5, Low: 9.7},
+		{Price: 11.0, Volume: 0, Filled: true},
+		{Price: 10.7, Volume: 120},
+	}
+}
+
+func sumsOf(points []models.MStockPrice) candleSums {
+	var s candleSums
+	for _, p := range points {
+		s.addPoint(p)
+	}
+	return s
+}
+
+func assertSameCandle(t *testing.T, label string, got, want models.MAggregation) {
+	t.Helper()
+	floats := []struct {
+		name      string
+		got, want float64
+	}{
+		{"open", got.Open, want.Open},
+		{"high", got.High, want.High},
+		{"low", got.Low, want.Low},
+		{"close", got.Close, want.Close},
+		{"volume", got.Volume, want.Volume},
+		{"avg_price", got.AvgPrice, want.AvgPrice},
+		{"vwap", got.VWAP, want.VWAP},
+		{"price_volume_correlation", got.PriceVolumeCorrelation, want.PriceVolumeCorrelation},
+	}
+	for _, f := range floats {
+		if math.Abs(f.got-f.want) > 1e-9 {
+			t.Errorf("%s: %s = %v, want %v", label, f.name, f.got, f.want)
+		}
+	}
+	if got.DataPoints != want.DataPoints || got.FilledBars != want.FilledBars || got.TrueOHLC != want.TrueOHLC {
+		t.Errorf("%s: points/filled/trueOHLC = %d/%d/%v, want %d/%d/%v", label,
+			got.DataPoints, got.FilledBars, got.TrueOHLC, want.DataPoints, want.FilledBars, want.TrueOHLC)
+	}
+}
+
+// Merging the sums of consecutive parts gives the candle of all the points
+func TestCandleSumsMergeMatchesDirect(t *testing.T) {
+	points := sumsTestPoints()
+	var direct models.MAggregation
+	sumsOf(points).fill(&direct)
+
+	for i := 0; i <= len(points); i++ {
+		for j := i; j <= len(points); j++ {
+			merged := sumsOf(points[:i])
+			merged.merge(sumsOf(points[i:j]))
+			merged.merge(sumsOf(points[j:]))
+
+			var got models.MAggregation
+			merged.fill(&got)
+			assertSameCandle(t, "split", got, direct)
+		}
+	}
+}
+
+func TestCandleSumsOHLC(t *testing.T) {
+	var c models.MAggregation
+	sumsOf(sumsTestPoints()).fill(&c)
+
+	// Open comes from the first point, extremes include the bars' own high/low
+	if c.Open != 10 || c.High != 11.5 || c.Low != 9.7 || c.Close != 10.7 || c.Volume != 920 {
+		t.Errorf("OHLCV = %v/%v/%v/%v/%v", c.Open, c.High, c.Low, c.Close, c.Volume)
+	}
+	if c.DataPoints != 6 || c.FilledBars != 1 || c.TrueOHLC {
+		t.Errorf("points/filled/trueOHLC = %d/%d/%v", c.DataPoints, c.FilledBars, c.TrueOHLC)
+	}
+
+	// A bar with its own OHLC opens at its open
+	var ranged models.MAggregation
+	sumsOf(sumsTestPoints()[1:2]).fill(&ranged)
+	if ranged.Open != 10.1 || ranged.High != 10.8 || ranged.Low != 10.0 || !ranged.TrueOHLC {
+		t.Errorf("ranged bar = %+v", ranged)
+	}
+}
+
+func TestCandleSumsEmpty(t *testing.T) {
+	s := sumsOf(sumsTestPoints()[:2])
+	before := s
+	s.merge(candleSums{})
+	if s != before {
+		t.Errorf("merging empty sums changed the candle")
+	}
+
+	var empty candleSums
+	empty.merge(before)
+	if empty != before {
+		t.Errorf("merging into empty sums = %+v, want %+v", empty, before)
+	}
+
+	var c models.MAggregation
+	candleSums{}.fill(&c)
+	if c.DataPoints != 0 || c.VWAP != 0 || c.AvgPrice != 0 {
+		t.Errorf("empty candle = %+v", c)
+	}
+}
@@ -0,0 +1,448 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// ChangepointDetector looks for sustained shifts (not single spikes) in the
+// return and log-volume streams of every symbol/window, on closed candles.
+// Two online methods, configurable per window:
+//   - cusum: two-sided CUSUM of observations standardized by the current regime
+//   - bocpd: Bayesian online changepoint detection (Normal-Gamma model)
+//
+// Each change starts a new regime; events carry the before/after statistics.
+// -----------------------------------------------------------------------------
+
+type ChangepointDetector struct {
+	Config *models.MConfig
+	Logger *logger.Logger
+
+	MaxEvents int
+
+	series map[string]*changepointSeries // symbol|window|series -> state
+	events []models.MChangepointEvent    // Most recent last
+	mu     sync.RWMutex
+}
+
+type changepointSeries struct {
+	settings models.MChangepointSettings
+
+	values []float64 // Observations of the current regime, oldest first
+	starts []int64   // Candle start of each observation
+
+	// CUSUM
+	pos, neg       float64
+	posRun, negRun int // Observations in the current excursion
+
+	// BOCPD
+	ready  bool
+	prior  normalGamma
+	probs  []float64     // Run-length posterior (index = observations in the run)
+	params []normalGamma // Posterior parameters per run length
+}
+
+// normalGamma are the parameters of the Normal-Gamma posterior of a run
+type normalGamma struct {
+	mu, kappa, alpha, beta float64
+}
+
+// -----------------------------------------------------------------------------
+
+func NewChangepointDetector(cfg *models.MConfig, log *logger.Logger) *ChangepointDetector {
+	maxEvents := cfg.Changepoints.MaxEvents
+	if maxEvents <= 0 {
+		maxEvents = utils.DefaultChangepointMaxEvents
+	}
+
+	return &ChangepointDetector{
+		Config:    cfg,
+		Logger:    log,
+		MaxEvents: maxEvents,
+		series:    make(map[string]*changepointSeries),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Seed feeds historical candles (symbol -> window -> candles, oldest first)
+// without emitting events, so regimes are established before the live feed.
+// d may be nil (disabled).
+func (d *ChangepointDetector) Seed(candles map[string]map[string][]models.MAggregation) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.process(candles)
+}
+
+// -----------------------------------------------------------------------------
+
+// Process feeds the closed candles of a cycle and returns the detected changes
+// (oldest first). d may be nil (disabled).
+func (d *ChangepointDetector) Process(closed map[string]map[string][]models.MAggregation) []models.MChangepointEvent {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	events := d.process(closed)
+	if len(events) == 0 {
+		return nil
+	}
+
+	d.events = append(d.events, events...)
+	if overflow := len(d.events) - d.MaxEvents; overflow > 0 {
+		d.events = append([]models.MChangepointEvent(nil), d.events[overflow:]...)
+	}
+	for _, e := range events {
+		d.Logger.Info("Changepoint %s %s/%s (%s): mean %.4g -> %.4g", e.Series, e.Symbol, e.WindowName, e.Direction, e.Before.Mean, e.After.Mean)
+	}
+	return events
+}
+
+// -----------------------------------------------------------------------------
+
+// Recent returns the latest events, optionally filtered by symbol and window
+// ("" = all), newest last (limit <= 0 = all kept).
+func (d *ChangepointDetector) Recent(symbol, window string, limit int) []models.MChangepointEvent {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var result []models.MChangepointEvent
+	for _, e := range d.events {
+		if (symbol == "" || e.Symbol == symbol) && (window == "" || e.WindowName == window) {
+			result = append(result, e)
+		}
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// process observes the closed candles and returns the changes (caller holds the lock).
+func (d *ChangepointDetector) process(candles map[string]map[string][]models.MAggregation) []models.MChangepointEvent {
+	var events []models.MChangepointEvent
+
+	for symbol, windows := range candles {
+		for windowName, list := range windows {
+			for _, c := range list {
+				if !c.IsClosed {
+					continue
+				}
+				if e, ok := d.observe(symbol, windowName, utils.ChangepointSeriesReturn, c, c.PricePercentChange); ok {
+					events = append(events, e)
+				}
+				if e, ok := d.observe(symbol, windowName, utils.ChangepointSeriesVolume, c, math.Log1p(c.Volume)); ok {
+					events = append(events, e)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp < events[j].Timestamp
+	})
+	return events
+}
+
+// -----------------------------------------------------------------------------
+
+// observe adds one observation to a series and reports a change (caller holds the lock).
+func (d *ChangepointDetector) observe(symbol, windowName, seriesName string, c models.MAggregation, x float64) (models.MChangepointEvent, bool) {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return models.MChangepointEvent{}, false
+	}
+
+	key := symbol + "|" + windowName + "|" + seriesName
+	s, ok := d.series[key]
+	if !ok {
+		s = &changepointSeries{settings: d.settingsFor(windowName)}
+		d.series[key] = s
+	}
+
+	var after int
+	var score float64
+	if s.settings.Method == utils.ChangepointMethodBOCPD {
+		after, score = s.bocpd(x)
+	} else {
+		after, score = s.cusum(x)
+	}
+
+	s.values = append(s.values, x)
+	s.starts = append(s.starts, c.StartTime)
+	if overflow := len(s.values) - utils.ChangepointMaxSegment; overflow > 0 {
+		s.values = append([]float64(nil), s.values[overflow:]...)
+		s.starts = append([]int64(nil), s.starts[overflow:]...)
+	}
+
+	if after == 0 || after >= len(s.values) {
+		return models.MChangepointEvent{}, false
+	}
+
+	// The last `after` observations form the new regime
+	split := len(s.values) - after
+	before, afterStats := segmentStats(s.values[:split]), segmentStats(s.values[split:])
+	event := models.MChangepointEvent{
+		Symbol:     symbol,
+		WindowName: windowName,
+		Series:     seriesName,
+		Method:     s.settings.Method,
+		Direction:  "down",
+		ChangeAt:   s.starts[split],
+		Timestamp:  c.EndTime,
+		Score:      score,
+		Before:     before,
+		After:      afterStats,
+	}
+	if afterStats.Mean > before.Mean {
+		event.Direction = "up"
+	}
+
+	s.values = append([]float64(nil), s.values[split:]...)
+	s.starts = append([]int64(nil), s.starts[split:]...)
+	s.pos, s.neg, s.posRun, s.negRun = 0, 0, 0, 0
+	return event, true
+}
+
+// -----------------------------------------------------------------------------
+
+// settingsFor returns the settings of a window (override, then defaults).
+func (d *ChangepointDetector) settingsFor(windowName string) models.MChangepointSettings {
+	cc := d.Config.Changepoints
+	s := cc.MChangepointSettings
+	if o, ok := cc.Windows[windowName]; ok {
+		if o.Method != "" {
+			s.Method = o.Method
+		}
+		if o.Threshold > 0 {
+			s.Threshold = o.Threshold
+		}
+		if o.Drift > 0 {
+			s.Drift = o.Drift
+		}
+		if o.Hazard > 0 {
+			s.Hazard = o.Hazard
+		}
+		if o.Confidence > 0 {
+			s.Confidence = o.Confidence
+		}
+		if o.MinSegment > 0 {
+			s.MinSegment = o.MinSegment
+		}
+	}
+
+	if s.Method == "" {
+		s.Method = utils.DefaultChangepointMethod
+	}
+	if s.Threshold <= 0 {
+		s.Threshold = utils.DefaultChangepointThreshold
+	}
+	if s.Drift <= 0 {
+		s.Drift = utils.DefaultChangepointDrift
+	}
+	if s.Hazard <= 1 {
+		s.Hazard = utils.DefaultChangepointHazard
+	}
+	if s.Confidence <= 0 {
+		s.Confidence = utils.DefaultChangepointConfidence
+	}
+	if s.MinSegment <= 1 {
+		s.MinSegment = utils.DefaultChangepointMinSegment
+	}
+	return s
+}
+
+// -----------------------------------------------------------------------------
+
+// cusum tests x against the current regime and returns the length of the new
+// regime (0 = no change) and the CUSUM statistic.
+func (s *changepointSeries) cusum(x float64) (int, float64) {
+	if len(s.values) < s.settings.MinSegment {
+		return 0, 0
+	}
+
+	mean, std := core.CalculateMeanStd(s.values)
+	if std <= 0 {
+		std = math.Max(math.Abs(mean)*1e-6, 1e-12)
+	}
+	z := (x - mean) / std
+
+	// An excursion starts when its statistic leaves 0
+	if s.pos == 0 {
+		s.posRun = 0
+	}
+	s.pos = math.Max(0, s.pos+z-s.settings.Drift)
+	if s.pos > 0 {
+		s.posRun++
+	}
+	if s.neg == 0 {
+		s.negRun = 0
+	}
+	s.neg = math.Max(0, s.neg-z-s.settings.Drift)
+	if s.neg > 0 {
+		s.negRun++
+	}
+
+	switch {
+	case s.pos > s.settings.Threshold:
+		return s.posRun, s.pos
+	case s.neg > s.settings.Threshold:
+		return s.negRun, s.neg
+	}
+	return 0, math.Max(s.pos, s.neg)
+}
+
+// -----------------------------------------------------------------------------
+
+// bocpd updates the run-length posterior with x and returns the length of the
+// new regime (0 = no change) and the posterior mass on a recent change.
+func (s *changepointSeries) bocpd(x float64) (int, float64) {
+	minSegment := s.settings.MinSegment
+
+	// The prior is fitted on the first regime, then the posterior is replayed
+	if !s.ready {
+		if len(s.values)+1 < minSegment {
+			return 0, 0
+		}
+		warmup := append(append([]float64(nil), s.values...), x)
+		mean, std := core.CalculateMeanStd(warmup)
+		variance := math.Max(std*std, math.Max(mean*mean*1e-6, 1e-12))
+		s.prior = normalGamma{mu: mean, kappa: 1, alpha: 1, beta: variance}
+		s.probs = []float64{1}
+		s.params = []normalGamma{s.prior}
+		s.ready = true
+		for _, v := range warmup {
+			s.updateRunLength(v)
+		}
+		return 0, 0
+	}
+
+	s.updateRunLength(x)
+
+	// Posterior mass on a change within the last minSegment observations
+	// (run length 0 is the prior probability of a change right now)
+	recent, best, bestProb := 0.0, 0, 0.0
+	for r := 1; r < minSegment && r < len(s.probs); r++ {
+		recent += s.probs[r]
+		if s.probs[r] > bestProb {
+			best, bestProb = r, s.probs[r]
+		}
+	}
+
+	// A new regime is only tested once the current one is long enough, and a
+	// single outlier is not a regime
+	if len(s.values) < minSegment || recent < s.settings.Confidence || best < 2 || !s.departs(best, x) {
+		return 0, recent
+	}
+	return best, recent
+}
+
+// -----------------------------------------------------------------------------
+
+// departs reports whether the last best observations (ending with x) still
+// depart from the current regime without their first one: a spike followed by
+// ordinary observations collapses the old run but is not a new regime.
+func (s *changepointSeries) departs(best int, x float64) bool {
+	split := len(s.values) - best + 1
+	if split < 2 {
+		return true
+	}
+
+	mean, std := core.CalculateMeanStd(s.values[:split])
+	rest := append(append([]float64(nil), s.values[split+1:]...), x)
+	restMean, _ := core.CalculateMeanStd(rest)
+	if std <= 0 {
+		return restMean != mean
+	}
+	z := (restMean - mean) / (std / math.Sqrt(float64(len(rest))))
+	return math.Abs(z) > utils.ChangepointMinDeparture
+}
+
+// -----------------------------------------------------------------------------
+
+// updateRunLength is one step of the run-length recursion (Adams & MacKay).
+func (s *changepointSeries) updateRunLength(x float64) {
+	hazard := 1 / s.settings.Hazard
+
+	n := len(s.probs)
+	probs := make([]float64, n+1)
+	params := make([]normalGamma, n+1)
+	params[0] = s.prior
+
+	total := 0.0
+	for r := 0; r < n; r++ {
+		joint := s.probs[r] * s.params[r].predictive(x)
+		probs[r+1] = joint * (1 - hazard)
+		probs[0] += joint * hazard
+		params[r+1] = s.params[r].update(x)
+		total += joint
+	}
+	if total <= 0 || math.IsNaN(total) {
+		// Observation impossible under every run: start over
+		s.probs = []float64{1}
+		s.params = []normalGamma{s.prior}
+		return
+	}
+
+	if len(probs) > utils.ChangepointMaxRunLength {
+		probs = probs[:utils.ChangepointMaxRunLength]
+		params = params[:utils.ChangepointMaxRunLength]
+	}
+	sum := 0.0
+	for _, p := range probs {
+		sum += p
+	}
+	for r := range probs {
+		probs[r] /= sum
+	}
+	s.probs, s.params = probs, params
+}
+
+// -----------------------------------------------------------------------------
+
+// predictive is the Student-t posterior predictive density of x.
+func (g normalGamma) predictive(x float64) float64 {
+	nu := 2 * g.alpha
+	scale := math.Sqrt(g.beta * (g.kappa + 1) / (g.alpha * g.kappa))
+	t := (x - g.mu) / scale
+
+	lg1, _ := math.Lgamma((nu + 1) / 2)
+	lg2, _ := math.Lgamma(nu / 2)
+	logPdf := lg1 - lg2 - 0.5*math.Log(nu*math.Pi) - math.Log(scale) - (nu+1)/2*math.Log1p(t*t/nu)
+	return math.Exp(logPdf)
+}
+
+// -----------------------------------------------------------------------------
+
+// update returns the posterior after observing x.
+func (g normalGamma) update(x float64) normalGamma {
+	return normalGamma{
+		mu:    (g.kappa*g.mu + x) / (g.kappa + 1),
+		kappa: g.kappa + 1,
+		alpha: g.alpha + 0.5,
+		beta:  g.beta + g.kappa*(x-g.mu)*(x-g.mu)/(2*(g.kappa+1)),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// segmentStats summarizes the observations of a regime.
+func segmentStats(values []float64) models.MSegmentStats {
+	mean, std := core.CalculateMeanStd(values)
+	return models.MSegmentStats{Mean: mean, Std: std, Count: len(values)}
+}
@@ -0,0 +1,139 @@
+package analysis
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// runChangepoints feeds returns as closed 5m candles of one symbol and returns
+// the return-series events.
+func runChangepoints(t *testing.T, method string, returns []float64) []models.MChangepointEvent {
+	t.Helper()
+	cfg := &models.MConfig{}
+	cfg.Changepoints.Method = method
+	d := NewChangepointDetector(cfg, logger.NewLogger(cfg, "test"))
+
+	var events []models.MChangepointEvent
+	for i, r := range returns {
+		start := engineTestBase + int64(i)*300
+		c := models.MAggregation{
+			Symbol: "TEST", WindowName: "5m", StartTime: start, EndTime: start + 300,
+			PricePercentChange: r, Volume: 1000, IsClosed: true,
+		}
+		for _, e := range d.Process(map[string]map[string][]models.MAggregation{"TEST": {"5m": {c}}}) {
+			if e.Series == utils.ChangepointSeriesReturn {
+				events = append(events, e)
+			}
+		}
+	}
+	return events
+}
+
+// noise returns n deterministic standard normal draws shifted by mean.
+func noise(rng *rand.Rand, n int, mean float64) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = mean + rng.NormFloat64()
+	}
+	return out
+}
+
+func TestChangepointDetection(t *testing.T) {
+	const shiftAt = 60
+
+	for _, method := range []string{utils.ChangepointMethodCUSUM, utils.ChangepointMethodBOCPD} {
+		tests := []struct {
+			name      string
+			returns   func(rng *rand.Rand) []float64
+			direction string // "" = no change expected
+		}{
+			{"stationary", func(rng *rand.Rand) []float64 { return noise(rng, 120, 0) }, ""},
+			{"single spike", func(rng *rand.Rand) []float64 {
+				r := noise(rng, 120, 0)
+				r[shiftAt] = 6
+				return r
+			}, ""},
+			{"shift up", func(rng *rand.Rand) []float64 {
+				return append(noise(rng, shiftAt, 0), noise(rng, 30, 4)...)
+			}, "up"},
+			{"shift down", func(rng *rand.Rand) []float64 {
+				return append(noise(rng, shiftAt, 0), noise(rng, 30, -4)...)
+			}, "down"},
+		}
+
+		for _, tt := range tests {
+			events := runChangepoints(t, method, tt.returns(rand.New(rand.NewSource(1))))
+
+			if tt.direction == "" {
+				if len(events) != 0 {
+					t.Errorf("%s/%s: %d event(s), want none: %+v", method, tt.name, len(events), events)
+				}
+				continue
+			}
+			if len(events) != 1 {
+				t.Errorf("%s/%s: %d event(s), want 1: %+v", method, tt.name, len(events), events)
+				continue
+			}
+
+			e := events[0]
+			changeIndex := int((e.ChangeAt - engineTestBase) / 300)
+			if e.Direction != tt.direction || e.Method != method || changeIndex < shiftAt-3 || changeIndex > shiftAt+3 {
+				t.Errorf("%s/%s: event %+v (change at bar %d, want %d)", method, tt.name, e, changeIndex, shiftAt)
+			}
+			if e.Before.Count < shiftAt-3 || math.Abs(e.Before.Mean) > 0.5 || e.After.Count < 2 {
+				t.Errorf("%s/%s: regimes before %+v after %+v", method, tt.name, e.Before, e.After)
+			}
+		}
+	}
+}
+
+func TestCUSUMExcursion(t *testing.T) {
+	s := &changepointSeries{settings: models.MChangepointSettings{Threshold: 3, Drift: 0.5, MinSegment: 4}}
+	s.values = []float64{-1, 1, -1, 1} // Mean 0, std 1
+
+	tests := []struct {
+		x     float64
+		after int
+		score float64
+	}{
+		{0.5, 0, 0},  // Within the drift
+		{1.5, 0, 1},  // Positive excursion starts
+		{-1.5, 0, 1}, // Positive side back to 0, negative side opens
+		{2, 0, 1.5},  // Positive side opens again
+		{2, 0, 3},    // At the threshold, not above it
+		{2, 3, 4.5},  // Change: the excursion spans the last 3 observations
+	}
+
+	for i, tt := range tests {
+		after, score := s.cusum(tt.x)
+		if after != tt.after || math.Abs(score-tt.score) > 1e-12 {
+			t.Errorf("step %d: (%d, %v), want (%d, %v)", i, after, score, tt.after, tt.score)
+		}
+	}
+}
+
+func TestNormalGammaPosterior(t *testing.T) {
+	g := normalGamma{mu: 0, kappa: 1, alpha: 1, beta: 1}
+
+	u := g.update(2)
+	if u.mu != 1 || u.kappa != 2 || u.alpha != 1.5 || u.beta != 2 {
+		t.Errorf("update(2) = %+v, want {1 2 1.5 2}", u)
+	}
+
+	// The predictive density integrates to 1 and peaks at the mean
+	sum := 0.0
+	for x := -200.0; x <= 200; x += 0.01 {
+		sum += g.predictive(x) * 0.01
+	}
+	if math.Abs(sum-1) > 0.01 {
+		t.Errorf("predictive integrates to %v, want 1", sum)
+	}
+	if g.predictive(0) <= g.predictive(0.1) || g.predictive(0.1) != g.predictive(-0.1) {
+		t.Errorf("predictive is not symmetric around its mean")
+	}
+}
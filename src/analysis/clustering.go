@@ -0,0 +1,453 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/interfaces"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// ClusterService periodically groups the universe by intraday behaviour. Each
+// session of a symbol (from the stored raw prices) becomes a profile over
+// intraday slots: share of the volume, share of the absolute returns and net
+// return per slot. Profiles averaged over the lookback are standardized across
+// symbols and clustered with k-means. The last two sessions of every symbol are
+// also placed in the nearest cluster; a symbol whose latest session lands in a
+// different cluster than the one before is reported as a jump.
+// -----------------------------------------------------------------------------
+
+type ClusterService struct {
+	Config *models.MConfig
+	Memory *utils.MemoryManager // Universe (symbols held in memory)
+	DB     interfaces.IDatabase
+	Logger *logger.Logger
+
+	Clusters         int
+	LookbackDays     int
+	Slots            int
+	MinSessionPoints int
+	Interval         time.Duration
+	Restarts         int
+	MaxJumps         int
+
+	snapshot    models.MClusterSnapshot
+	hasSnapshot bool
+	jumps       []models.MClusterJump // Most recent last
+	jumped      map[string]string     // symbol -> session of the last reported jump
+	mu          sync.RWMutex
+}
+
+// sessionProfile is the intraday profile of one session of a symbol
+type sessionProfile struct {
+	date     string
+	features []float64 // volume shares, then volatility shares, then net returns
+}
+
+// -----------------------------------------------------------------------------
+
+func NewClusterService(cfg *models.MConfig, memManager *utils.MemoryManager, db interfaces.IDatabase, log *logger.Logger) *ClusterService {
+	cc := cfg.Clustering
+
+	s := &ClusterService{
+		Config:           cfg,
+		Memory:           memManager,
+		DB:               db,
+		Logger:           log,
+		Clusters:         cc.Clusters,
+		LookbackDays:     cc.LookbackDays,
+		Slots:            cc.Slots,
+		MinSessionPoints: cc.MinSessionPoints,
+		Interval:         time.Duration(cc.RefreshIntervalSeconds) * time.Second,
+		Restarts:         cc.Restarts,
+		MaxJumps:         cc.MaxJumps,
+		jumped:           make(map[string]string),
+	}
+	if s.Clusters <= 0 {
+		s.Clusters = utils.DefaultClusterCount
+	}
+	if s.LookbackDays <= 0 {
+		s.LookbackDays = utils.DefaultClusterLookbackDays
+	}
+	if s.Slots <= 0 {
+		s.Slots = utils.DefaultClusterSlots
+	}
+	if s.MinSessionPoints <= 0 {
+		s.MinSessionPoints = utils.DefaultClusterMinPoints
+	}
+	if s.Interval <= 0 {
+		s.Interval = utils.DefaultClusterRefreshInterval * time.Second
+	}
+	if s.Restarts <= 0 {
+		s.Restarts = utils.DefaultClusterRestarts
+	}
+	if s.MaxJumps <= 0 {
+		s.MaxJumps = utils.DefaultClusterMaxJumps
+	}
+	return s
+}
+
+// -----------------------------------------------------------------------------
+
+// Start runs the clustering job now and then every Interval until ctx is done.
+// s may be nil (disabled).
+func (s *ClusterService) Start(ctx context.Context, wg *sync.WaitGroup) {
+	if s == nil {
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+		for {
+			s.Run()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// -----------------------------------------------------------------------------
+
+// Run clusters the universe and returns the new cluster jumps.
+func (s *ClusterService) Run() []models.MClusterJump {
+	now := time.Now().UTC()
+
+	// Session profiles of every symbol (calendar days cover weekends and holidays)
+	from := now.AddDate(0, 0, -2*(s.LookbackDays+1)-7).Unix()
+	var symbols []string
+	var skipped []models.MClusterSkipped
+	profiles := make(map[string][]sessionProfile)
+	for _, sym := range s.Memory.Symbols() {
+		points, err := s.DB.LoadStockPrices(sym, from, now.Unix()+1)
+		if err != nil {
+			s.Logger.Error("Clustering: failed to load %s: %v", sym, err)
+			continue
+		}
+		sessions := s.sessionProfiles(sym, points)
+		if len(sessions) < s.LookbackDays {
+			// A partial history (recent symbol, trimmed prices) is not comparable
+			skipped = append(skipped, models.MClusterSkipped{Symbol: sym, Sessions: len(sessions)})
+			continue
+		}
+		if len(sessions) > s.LookbackDays {
+			sessions = sessions[len(sessions)-s.LookbackDays:]
+		}
+		profiles[sym] = sessions
+		symbols = append(symbols, sym)
+	}
+	sort.Strings(symbols)
+	sort.Slice(skipped, func(i, j int) bool { return skipped[i].Symbol < skipped[j].Symbol })
+	if len(skipped) > 0 {
+		s.Logger.Info("Clustering: %d symbol(s) with fewer than %d sessions left out", len(skipped), s.LookbackDays)
+	}
+	if len(symbols) < 2 {
+		s.Logger.Info("Clustering: %d symbol(s) with %d session profiles, skipped", len(symbols), s.LookbackDays)
+		return nil
+	}
+
+	// Lookback profile = mean of the session profiles
+	dims := 3 * s.Slots
+	raw := make([][]float64, len(symbols))
+	for i, sym := range symbols {
+		raw[i] = make([]float64, dims)
+		for _, p := range profiles[sym] {
+			for d, v := range p.features {
+				raw[i][d] += v / float64(len(profiles[sym]))
+			}
+		}
+	}
+
+	// Standardize every feature across symbols (the same transform applies to single sessions)
+	means := make([]float64, dims)
+	stds := make([]float64, dims)
+	column := make([]float64, len(symbols))
+	for d := 0; d < dims; d++ {
+		for i := range raw {
+			column[i] = raw[i][d]
+		}
+		means[d], stds[d] = core.CalculateMeanStd(column)
+	}
+	standardize := func(v []float64) []float64 {
+		z := make([]float64, dims)
+		for d := range v {
+			if stds[d] > 0 {
+				z[d] = (v[d] - means[d]) / stds[d]
+			}
+		}
+		return z
+	}
+	points := make([][]float64, len(symbols))
+	for i := range raw {
+		points[i] = standardize(raw[i])
+	}
+
+	labels, centroids, inertia := core.CalculateKMeans(points, s.Clusters, s.Restarts, utils.ClusterIterations, utils.ClusterSeed)
+	labels, centroids = relabelClusters(labels, centroids)
+
+	snapshot := models.MClusterSnapshot{
+		Features:     s.featureNames(),
+		Slots:        s.Slots,
+		LookbackDays: s.LookbackDays,
+		Skipped:      skipped,
+		Inertia:      inertia,
+		Timestamp:    now.Unix(),
+	}
+	for c, centroid := range centroids {
+		cluster := models.MCluster{
+			ID:                c,
+			Centroid:          centroid,
+			VolumeProfile:     make([]float64, s.Slots),
+			VolatilityProfile: make([]float64, s.Slots),
+			ReturnProfile:     make([]float64, s.Slots),
+		}
+		for i, sym := range symbols {
+			if labels[i] != c {
+				continue
+			}
+			cluster.Symbols = append(cluster.Symbols, sym)
+			for slot := 0; slot < s.Slots; slot++ {
+				cluster.VolumeProfile[slot] += raw[i][slot]
+				cluster.VolatilityProfile[slot] += raw[i][s.Slots+slot]
+				cluster.ReturnProfile[slot] += raw[i][2*s.Slots+slot]
+			}
+		}
+		cluster.Size = len(cluster.Symbols)
+		for slot := 0; slot < s.Slots && cluster.Size > 0; slot++ {
+			cluster.VolumeProfile[slot] /= float64(cluster.Size)
+			cluster.VolatilityProfile[slot] /= float64(cluster.Size)
+			cluster.ReturnProfile[slot] /= float64(cluster.Size)
+		}
+		snapshot.Clusters = append(snapshot.Clusters, cluster)
+	}
+
+	// Day over day: the last two sessions placed in the nearest cluster
+	var jumps []models.MClusterJump
+	s.mu.Lock()
+
+	for i, sym := range symbols {
+		sessions := profiles[sym]
+		latest := sessions[len(sessions)-1]
+		latestCluster, latestDist := core.NearestCentroid(standardize(latest.features), centroids)
+
+		a := models.MClusterAssignment{
+			Symbol:          sym,
+			Cluster:         labels[i],
+			Sessions:        len(sessions),
+			LatestSession:   latest.date,
+			LatestCluster:   latestCluster,
+			PreviousCluster: -1,
+		}
+		_, a.Distance = core.NearestCentroid(points[i], centroids[labels[i]:labels[i]+1])
+
+		if len(sessions) >= 2 {
+			previous := sessions[len(sessions)-2]
+			a.PreviousSession = previous.date
+			a.PreviousCluster, _ = core.NearestCentroid(standardize(previous.features), centroids)
+			a.Jumped = a.PreviousCluster != a.LatestCluster
+
+			if a.Jumped && s.jumped[sym] != latest.date {
+				s.jumped[sym] = latest.date
+				jumps = append(jumps, models.MClusterJump{
+					Symbol:          sym,
+					Session:         latest.date,
+					PreviousSession: previous.date,
+					FromCluster:     a.PreviousCluster,
+					ToCluster:       a.LatestCluster,
+					Distance:        latestDist,
+					Timestamp:       now.Unix(),
+				})
+			}
+		}
+		snapshot.Assignments = append(snapshot.Assignments, a)
+	}
+
+	s.snapshot = snapshot
+	s.hasSnapshot = true
+	s.jumps = append(s.jumps, jumps...)
+	if overflow := len(s.jumps) - s.MaxJumps; overflow > 0 {
+		s.jumps = append([]models.MClusterJump(nil), s.jumps[overflow:]...)
+	}
+	s.mu.Unlock()
+
+	if err := s.DB.SaveClusterJumps(jumps); err != nil {
+		s.Logger.Error("Failed to save cluster jumps: %v", err)
+	}
+	s.Logger.Info("Clustering: %d symbols in %d clusters, %d new jump(s)", len(symbols), len(centroids), len(jumps))
+	return jumps
+}
+
+// -----------------------------------------------------------------------------
+
+// Snapshot returns the result of the last run (false before the first one).
+func (s *ClusterService) Snapshot() (models.MClusterSnapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.snapshot, s.hasSnapshot
+}
+
+// -----------------------------------------------------------------------------
+
+// Jumps returns the latest cluster jumps, optionally filtered by symbol ("" = all),
+// newest last (limit <= 0 = all kept).
+func (s *ClusterService) Jumps(symbol string, limit int) []models.MClusterJump {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.MClusterJump
+	for _, j := range s.jumps {
+		if symbol == "" || j.Symbol == symbol {
+			result = append(result, j)
+		}
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// sessionProfiles splits the points of a symbol (oldest first) into regular
+// sessions and returns the profiles of those with enough points, oldest first.
+func (s *ClusterService) sessionProfiles(symbol string, points []models.MStockPrice) []sessionProfile {
+	calendar := utils.GetCalendar(symbol)
+	location := time.UTC
+	if calendar.Timezone != nil {
+		location = calendar.Timezone
+	}
+
+	var profiles []sessionProfile
+	var open, close, nextOpen time.Time
+	var volume, volatility, net []float64
+	var count int
+	var prev float64
+
+	flush := func() {
+		if count < s.MinSessionPoints {
+			return
+		}
+		totalVolume, totalMove := 0.0, 0.0
+		for slot := 0; slot < s.Slots; slot++ {
+			totalVolume += volume[slot]
+			totalMove += volatility[slot]
+		}
+		if totalVolume <= 0 || totalMove <= 0 {
+			return
+		}
+		features := make([]float64, 0, 3*s.Slots)
+		for _, v := range volume {
+			features = append(features, v/totalVolume)
+		}
+		for _, v := range volatility {
+			features = append(features, v/totalMove)
+		}
+		for _, v := range net {
+			features = append(features, v/totalMove)
+		}
+		profiles = append(profiles, sessionProfile{date: open.In(location).Format("2006-01-02"), features: features})
+	}
+
+	for _, p := range points {
+		if p.Price <= 0 {
+			continue
+		}
+		t := time.Unix(p.Timestamp, 0)
+		if open.IsZero() || t.Before(open) || !t.Before(nextOpen) {
+			if !open.IsZero() {
+				flush()
+			}
+			open, close, nextOpen = calendar.SessionBounds(t)
+			volume = make([]float64, s.Slots)
+			volatility = make([]float64, s.Slots)
+			net = make([]float64, s.Slots)
+			count, prev = 0, 0
+		}
+		if !t.Before(close) {
+			continue // After the close: not part of the regular session
+		}
+
+		length := close.Sub(open).Seconds()
+		slot := int(t.Sub(open).Seconds() / length * float64(s.Slots))
+		slot = max(0, min(slot, s.Slots-1))
+
+		volume[slot] += p.Volume
+		if prev > 0 {
+			r := math.Log(p.Price / prev)
+			volatility[slot] += math.Abs(r)
+			net[slot] += r
+		}
+		prev = p.Price
+		count++
+	}
+	if !open.IsZero() {
+		flush()
+	}
+	return profiles
+}
+
+// -----------------------------------------------------------------------------
+
+// featureNames names the profile dimensions (volume_s0.., volatility_s0.., return_s0..).
+func (s *ClusterService) featureNames() []string {
+	names := make([]string, 0, 3*s.Slots)
+	for _, prefix := range []string{"volume", "volatility", "return"} {
+		for slot := 0; slot < s.Slots; slot++ {
+			names = append(names, fmt.Sprintf("%s_s%d", prefix, slot))
+		}
+	}
+	return names
+}
+
+// -----------------------------------------------------------------------------
+
+// relabelClusters renumbers clusters by decreasing size (ties by first member)
+// so that IDs stay comparable between runs, and drops empty ones.
+func relabelClusters(labels []int, centroids [][]float64) ([]int, [][]float64) {
+	type cluster struct {
+		old, size, first int
+	}
+	clusters := make([]cluster, len(centroids))
+	for c := range clusters {
+		clusters[c] = cluster{old: c, first: len(labels)}
+	}
+	for i, l := range labels {
+		clusters[l].size++
+		clusters[l].first = min(clusters[l].first, i)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].size != clusters[j].size {
+			return clusters[i].size > clusters[j].size
+		}
+		return clusters[i].first < clusters[j].first
+	})
+
+	mapping := make(map[int]int, len(clusters))
+	var sorted [][]float64
+	for _, c := range clusters {
+		if c.size == 0 {
+			continue
+		}
+		mapping[c.old] = len(sorted)
+		sorted = append(sorted, centroids[c.old])
+	}
+	relabeled := make([]int, len(labels))
+	for i, l := range labels {
+		relabeled[i] = mapping[l]
+	}
+	return relabeled, sorted
+}
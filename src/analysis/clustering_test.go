@@ -0,0 +1,103 @@
+package analysis
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/storage"
+	"market-observer/src/utils"
+)
+
+// recentSessions returns the bounds of the last n regular NYSE sessions before today.
+func recentSessions(n int) [][2]time.Time {
+	calendar := utils.GetCalendar("TEST")
+	var sessions [][2]time.Time
+	day := time.Now().UTC().AddDate(0, 0, -1)
+	for len(sessions) < n {
+		noon := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, calendar.Timezone)
+		if calendar.IsTradingDay(noon) {
+			open, close, _ := calendar.SessionBounds(noon)
+			sessions = append([][2]time.Time{{open, close}}, sessions...)
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+	return sessions
+}
+
+// sessionPrices returns 5m bars over a session, with the volume front- or back-loaded.
+func sessionPrices(symbol string, session [2]time.Time, frontLoaded bool) []models.MStockPrice {
+	var prices []models.MStockPrice
+	bars := int(session[1].Sub(session[0]) / (5 * time.Minute))
+	for i := 0; i < bars; i++ {
+		weight := float64(bars - i)
+		if !frontLoaded {
+			weight = float64(i + 1)
+		}
+		prices = append(prices, models.MStockPrice{
+			Symbol:    symbol,
+			Price:     100 + math.Sin(float64(i)),
+			Volume:    1000 * weight,
+			Timestamp: session[0].Add(time.Duration(i) * 5 * time.Minute).Unix(),
+		})
+	}
+	return prices
+}
+
+func TestClusterServiceSkipsPartialHistory(t *testing.T) {
+	cfg := &models.MConfig{}
+	cfg.Storage.DBPath = filepath.Join(t.TempDir(), "clusters.db")
+	cfg.Clustering.Clusters = 2
+	cfg.Clustering.LookbackDays = 3
+	log := logger.NewLogger(cfg, "test")
+
+	db, err := storage.NewAsyncSQLiteDB(cfg, log)
+	if err != nil {
+		t.Fatalf("NewAsyncSQLiteDB: %v", err)
+	}
+	if err := db.Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	memory := utils.NewMemoryManager(100, 100)
+	sessions := recentSessions(3)
+	history := map[string]int{"AAA": 3, "BBB": 3, "CCC": 3, "DDD": 3, "NEW": 1} // Sessions stored per symbol
+	for sym, n := range history {
+		var prices []models.MStockPrice
+		for _, session := range sessions[len(sessions)-n:] {
+			prices = append(prices, sessionPrices(sym, session, sym < "C")...)
+		}
+		if err := db.SaveStockPricesBulk(prices); err != nil {
+			t.Fatalf("SaveStockPricesBulk: %v", err)
+		}
+		memory.AddDataPoint(sym, prices[len(prices)-1])
+	}
+
+	s := NewClusterService(cfg, memory, db, log)
+	s.Run()
+	snapshot, ok := s.Snapshot()
+	if !ok {
+		t.Fatal("no snapshot after the run")
+	}
+
+	if len(snapshot.Skipped) != 1 || snapshot.Skipped[0] != (models.MClusterSkipped{Symbol: "NEW", Sessions: 1}) {
+		t.Errorf("skipped = %+v, want NEW with 1 session", snapshot.Skipped)
+	}
+	if snapshot.LookbackDays != 3 || len(snapshot.Assignments) != 4 {
+		t.Fatalf("snapshot lookback %d, %d assignment(s)", snapshot.LookbackDays, len(snapshot.Assignments))
+	}
+	for _, a := range snapshot.Assignments {
+		if a.Symbol == "NEW" || a.Sessions != 3 {
+			t.Errorf("assignment %+v", a)
+		}
+	}
+	// Front-loaded and back-loaded volume land in different clusters
+	a := snapshot.Assignments
+	if a[0].Cluster != a[1].Cluster || a[2].Cluster != a[3].Cluster || a[0].Cluster == a[2].Cluster {
+		t.Errorf("clusters = %d %d %d %d", a[0].Cluster, a[1].Cluster, a[2].Cluster, a[3].Cluster)
+	}
+}
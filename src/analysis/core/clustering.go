@@ -0,0 +1,141 @@
+package core
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Info: k-means clustering of feature vectors (rows of equal length).
+
+// -----------------------------------------------------------------------------
+
+// CalculateKMeans groups points into k clusters (k-means++ seeding, Lloyd
+// iterations) and keeps the run of lowest inertia (sum of squared distances to
+// the centroids) out of restarts. Deterministic for a given seed.
+func CalculateKMeans(points [][]float64, k, restarts, iterations int, seed int64) (labels []int, centroids [][]float64, inertia float64) {
+	if len(points) == 0 || k <= 0 {
+		return nil, nil, 0
+	}
+	if k > len(points) {
+		k = len(points)
+	}
+	if restarts <= 0 {
+		restarts = 1
+	}
+	if iterations <= 0 {
+		iterations = 1
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	inertia = math.Inf(1)
+	for run := 0; run < restarts; run++ {
+		l, c, in := kmeansRun(points, k, iterations, rng)
+		if in < inertia {
+			labels, centroids, inertia = l, c, in
+		}
+	}
+	return labels, centroids, inertia
+}
+
+// -----------------------------------------------------------------------------
+
+// NearestCentroid returns the index of the closest centroid and the Euclidean
+// distance to it (-1 when there are no centroids).
+func NearestCentroid(point []float64, centroids [][]float64) (int, float64) {
+	best, bestDist := -1, math.Inf(1)
+	for i, c := range centroids {
+		if d := squaredDistance(point, c); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	if best < 0 {
+		return -1, 0
+	}
+	return best, math.Sqrt(bestDist)
+}
+
+// -----------------------------------------------------------------------------
+
+// kmeansRun performs one seeded k-means run.
+func kmeansRun(points [][]float64, k, iterations int, rng *rand.Rand) ([]int, [][]float64, float64) {
+	// k-means++: each next seed drawn with probability proportional to its squared distance
+	centroids := [][]float64{append([]float64(nil), points[rng.Intn(len(points))]...)}
+	dist := make([]float64, len(points))
+	for len(centroids) < k {
+		total := 0.0
+		for i, p := range points {
+			_, d := NearestCentroid(p, centroids)
+			dist[i] = d * d
+			total += dist[i]
+		}
+		next := 0
+		if total > 0 {
+			target := rng.Float64() * total
+			for next = 0; next < len(points)-1; next++ {
+				target -= dist[next]
+				if target <= 0 {
+					break
+				}
+			}
+		} else {
+			next = rng.Intn(len(points)) // Identical points
+		}
+		centroids = append(centroids, append([]float64(nil), points[next]...))
+	}
+
+	labels := make([]int, len(points))
+	for i := range labels {
+		labels[i] = -1
+	}
+	for it := 0; it < iterations; it++ {
+		changed := false
+		for i, p := range points {
+			if c, _ := NearestCentroid(p, centroids); c != labels[i] {
+				labels[i] = c
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		// Move each centroid to the mean of its members (empty clusters stay put)
+		dims := len(points[0])
+		sums := make([][]float64, k)
+		counts := make([]int, k)
+		for c := range sums {
+			sums[c] = make([]float64, dims)
+		}
+		for i, p := range points {
+			counts[labels[i]]++
+			for d, v := range p {
+				sums[labels[i]][d] += v
+			}
+		}
+		for c := range centroids {
+			if counts[c] == 0 {
+				continue
+			}
+			for d := range centroids[c] {
+				centroids[c][d] = sums[c][d] / float64(counts[c])
+			}
+		}
+	}
+
+	inertia := 0.0
+	for i, p := range points {
+		inertia += squaredDistance(p, centroids[labels[i]])
+	}
+	return labels, centroids, inertia
+}
+
+// -----------------------------------------------------------------------------
+
+func squaredDistance(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return sum
+}
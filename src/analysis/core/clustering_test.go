@@ -0,0 +1,120 @@
+package core
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateKMeans(t *testing.T) {
+	// Three tight groups far apart
+	points := [][]float64{
+		{0, 0}, {0.1, 0}, {0, 0.1},
+		{10, 10}, {10.1, 10}, {10, 10.1},
+		{-10, 10}, {-10.1, 10}, {-10, 10.1},
+	}
+
+	tests := []struct {
+		name     string
+		k        int
+		clusters int // Distinct labels expected
+	}{
+		{"one cluster", 1, 1},
+		{"true number of clusters", 3, 3},
+		{"k above the number of points", 20, 9},
+	}
+
+	for _, tt := range tests {
+		labels, centroids, inertia := CalculateKMeans(points, tt.k, 5, 100, 1)
+		if len(labels) != len(points) || len(centroids) != tt.clusters {
+			t.Fatalf("%s: %d labels, %d centroids", tt.name, len(labels), len(centroids))
+		}
+
+		distinct := make(map[int]bool)
+		want := 0.0
+		for i, p := range points {
+			distinct[labels[i]] = true
+			want += squaredDistance(p, centroids[labels[i]])
+		}
+		if len(distinct) != tt.clusters {
+			t.Errorf("%s: %d distinct labels, want %d", tt.name, len(distinct), tt.clusters)
+		}
+		if math.Abs(inertia-want) > 1e-9 {
+			t.Errorf("%s: inertia %v, want %v", tt.name, inertia, want)
+		}
+	}
+}
+
+func TestCalculateKMeansGroups(t *testing.T) {
+	points := [][]float64{{0, 0}, {10, 10}, {0.1, 0}, {10.1, 10}, {0, 0.1}, {10, 10.1}}
+	labels, centroids, inertia := CalculateKMeans(points, 2, 5, 100, 1)
+
+	for i := 2; i < len(points); i++ {
+		if labels[i] != labels[i%2] {
+			t.Errorf("point %d in cluster %d, want %d", i, labels[i], labels[i%2])
+		}
+	}
+	if labels[0] == labels[1] {
+		t.Errorf("both groups in cluster %d", labels[0])
+	}
+	c := centroids[labels[0]]
+	if math.Abs(c[0]-0.1/3) > 1e-12 || math.Abs(c[1]-0.1/3) > 1e-12 {
+		t.Errorf("centroid of the first group = %v", c)
+	}
+	// Per group: the corner point is (1/30, 1/30) away from the centroid, the two others (2/30, 1/30)
+	if want := 2 * (2*math.Pow(0.1/3, 2) + 2*(math.Pow(0.2/3, 2)+math.Pow(0.1/3, 2))); math.Abs(inertia-want) > 1e-12 {
+		t.Errorf("inertia = %v, want %v", inertia, want)
+	}
+
+	// Same seed, same result
+	again, _, againInertia := CalculateKMeans(points, 2, 5, 100, 1)
+	for i := range labels {
+		if again[i] != labels[i] || againInertia != inertia {
+			t.Fatalf("run is not deterministic: %v vs %v", again, labels)
+		}
+	}
+}
+
+func TestCalculateKMeansDegenerate(t *testing.T) {
+	if labels, centroids, inertia := CalculateKMeans(nil, 3, 1, 10, 1); labels != nil || centroids != nil || inertia != 0 {
+		t.Errorf("no points: %v %v %v", labels, centroids, inertia)
+	}
+	if labels, _, _ := CalculateKMeans([][]float64{{1}}, 0, 1, 10, 1); labels != nil {
+		t.Errorf("k = 0: %v", labels)
+	}
+
+	// Identical points: every label valid, no inertia
+	same := [][]float64{{1, 1}, {1, 1}, {1, 1}}
+	labels, centroids, inertia := CalculateKMeans(same, 2, 3, 10, 1)
+	for _, l := range labels {
+		if l < 0 || l >= len(centroids) {
+			t.Errorf("label %d out of range", l)
+		}
+	}
+	if inertia != 0 {
+		t.Errorf("identical points: inertia %v, want 0", inertia)
+	}
+}
+
+func TestNearestCentroid(t *testing.T) {
+	centroids := [][]float64{{0, 0}, {3, 4}, {10, 0}}
+
+	tests := []struct {
+		point []float64
+		index int
+		dist  float64
+	}{
+		{[]float64{0, 0}, 0, 0},
+		{[]float64{3, 3}, 1, 1},
+		{[]float64{7, 0}, 2, 3},
+	}
+
+	for _, tt := range tests {
+		index, dist := NearestCentroid(tt.point, centroids)
+		if index != tt.index || math.Abs(dist-tt.dist) > 1e-12 {
+			t.Errorf("NearestCentroid(%v) = (%d, %v), want (%d, %v)", tt.point, index, dist, tt.index, tt.dist)
+		}
+	}
+	if index, dist := NearestCentroid([]float64{1}, nil); index != -1 || dist != 0 {
+		t.Errorf("no centroids: (%d, %v), want (-1, 0)", index, dist)
+	}
+}
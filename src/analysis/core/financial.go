@@ -4,46 +4,27 @@ import "math"
 
 // -----------------------------------------------------------------------------
 
-// ComputeOHLCV calculates OHLCV and AvgPrice from price/volume arrays.
-func ComputeOHLCV(prices []float64, volumes []float64) map[string]float64 {
-	if len(prices) == 0 {
-		return map[string]float64{
-			"open": 0, "high": 0, "low": 0, "close": 0, "volume": 0, "avg_price": 0,
-		}
+// CalculateVWAP returns sum(price*volume)/sum(volume), or fallback when there is no volume.
+func CalculateVWAP(sumPV, sumVolume, fallback float64) float64 {
+	if sumVolume <= 0 {
+		return fallback
 	}
+	return sumPV / sumVolume
+}
 
-	open := prices[0]
-	closePrice := prices[len(prices)-1]
-	high := -1.0
-	low := math.MaxFloat64
-	totalVol := 0.0
-	sumPrice := 0.0
-
-	for i, p := range prices {
-		v := volumes[i]
-		if p > high {
-			high = p
-		}
-		if p < low {
-			low = p
-		}
-		totalVol += v
-		sumPrice += p
-	}
+// -----------------------------------------------------------------------------
 
-	avgPrice := 0.0
-	if len(prices) > 0 {
-		avgPrice = sumPrice / float64(len(prices))
+// CalculateVWAPStd returns the volume-weighted standard deviation of price around the VWAP.
+// sumP2V is sum(price*price*volume).
+func CalculateVWAPStd(sumP2V, sumVolume, vwap float64) float64 {
+	if sumVolume <= 0 {
+		return 0
 	}
-
-	return map[string]float64{
-		"open":      open,
-		"high":      high,
-		"low":       low,
-		"close":     closePrice,
-		"volume":    totalVol,
-		"avg_price": avgPrice,
+	variance := sumP2V/sumVolume - vwap*vwap
+	if variance <= 0 {
+		return 0
 	}
+	return math.Sqrt(variance)
 }
 
 // -----------------------------------------------------------------------------
@@ -58,6 +39,16 @@ func CalculateChangePercent(current, previous float64) float64 {
 
 // -----------------------------------------------------------------------------
 
+// CalculateLogReturn computes ln(current/previous), 0 when either price is not positive.
+func CalculateLogReturn(current, previous float64) float64 {
+	if current <= 0 || previous <= 0 {
+		return 0.0
+	}
+	return math.Log(current / previous)
+}
+
+// -----------------------------------------------------------------------------
+
 // CalculateAnomalyRatio computes volume anomaly
 func CalculateAnomalyRatio(currentVol, avgVol float64) float64 {
 	if avgVol <= 0 {
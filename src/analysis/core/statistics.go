@@ -1,6 +1,9 @@
 package core
 
-import "math"
+import (
+	"math"
+	"sort"
+)
 
 // -----------------------------------------------------------------------------
 
@@ -86,3 +89,116 @@ func CalculateZScore(value, mean, std float64) float64 {
 	}
 	return (value - mean) / std
 }
+
+// -----------------------------------------------------------------------------
+
+// CalculateCorrelationFromSums computes Pearson correlation from running sums.
+// Used by incremental aggregation where the raw arrays are no longer kept.
+func CalculateCorrelationFromSums(n, sumX, sumY, sumXY, sumX2, sumY2 float64) float64 {
+	if n < 2 {
+		return 0
+	}
+
+	varX := (n * sumX2) - (sumX * sumX)
+	varY := (n * sumY2) - (sumY * sumY)
+
+	// Zero variance check (floating point residue is treated as zero)
+	if varX <= 1e-12*n*sumX2 || varY <= 1e-12*n*sumY2 {
+		return 0
+	}
+
+	result := ((n * sumXY) - (sumX * sumY)) / math.Sqrt(varX*varY)
+
+	if math.IsNaN(result) {
+		return 0
+	}
+
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// UpdateWelford folds x into a running mean and population variance.
+// n is the sample count including x.
+func UpdateWelford(n int, mean, variance, x float64) (float64, float64) {
+	if n <= 1 {
+		return x, 0
+	}
+
+	delta := x - mean
+	newMean := mean + delta/float64(n)
+	m2 := variance*float64(n-1) + delta*(x-newMean)
+	return newMean, m2 / float64(n)
+}
+
+// -----------------------------------------------------------------------------
+
+// UpdateEWMA folds x into an exponentially weighted mean and variance.
+// alpha is the weight of the new sample (0 < alpha <= 1).
+func UpdateEWMA(mean, variance, x, alpha float64) (float64, float64) {
+	diff := x - mean
+	incr := alpha * diff
+	return mean + incr, (1 - alpha) * (variance + diff*incr)
+}
+
+// -----------------------------------------------------------------------------
+
+// CalculateRegression fits y = alpha + beta*x by ordinary least squares and
+// returns beta, alpha and the standard deviation of the residuals.
+func CalculateRegression(x, y []float64) (float64, float64, float64) {
+	if len(x) != len(y) || len(x) < 2 {
+		return 0, 0, 0
+	}
+
+	meanX, stdX := CalculateMeanStd(x)
+	meanY, stdY := CalculateMeanStd(y)
+	if stdX == 0 {
+		return 0, meanY, stdY
+	}
+
+	cov := 0.0
+	for i := range x {
+		cov += (x[i] - meanX) * (y[i] - meanY)
+	}
+	cov /= float64(len(x))
+
+	beta := cov / (stdX * stdX)
+	alpha := meanY - beta*meanX
+
+	// Residual variance of the fit: var(y) - beta^2 var(x)
+	residVar := stdY*stdY - beta*beta*stdX*stdX
+	if residVar < 0 {
+		residVar = 0
+	}
+	return beta, alpha, math.Sqrt(residVar)
+}
+
+// -----------------------------------------------------------------------------
+
+// CalculateMedianMAD computes the median and the median absolute deviation.
+func CalculateMedianMAD(data []float64) (float64, float64) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+
+	median := calculateMedian(data)
+	deviations := make([]float64, len(data))
+	for i, v := range data {
+		deviations[i] = math.Abs(v - median)
+	}
+	return median, calculateMedian(deviations)
+}
+
+// -----------------------------------------------------------------------------
+
+// calculateMedian returns the median of data (left unchanged).
+func calculateMedian(data []float64) float64 {
+	sorted := append([]float64(nil), data...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
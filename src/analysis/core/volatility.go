@@ -0,0 +1,121 @@
+package core
+
+import "math"
+
+// Info: Per-bar variance estimators on OHLC bars (oldest first). Multiply by
+// the bars per year and take the square root to annualize.
+
+// -----------------------------------------------------------------------------
+
+// CalculateRealizedVariance computes the close-to-close realized variance
+// (mean squared log return of consecutive closes).
+func CalculateRealizedVariance(closes []float64) float64 {
+	sum, n := 0.0, 0
+	for i := 1; i < len(closes); i++ {
+		if closes[i] <= 0 || closes[i-1] <= 0 {
+			continue
+		}
+		r := math.Log(closes[i] / closes[i-1])
+		sum += r * r
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// -----------------------------------------------------------------------------
+
+// CalculateParkinsonVariance computes the high-low range estimator:
+// mean(ln(H/L)^2) / (4 ln 2).
+func CalculateParkinsonVariance(high, low []float64) float64 {
+	sum, n := 0.0, 0
+	for i := range high {
+		if high[i] <= 0 || low[i] <= 0 {
+			continue
+		}
+		hl := math.Log(high[i] / low[i])
+		sum += hl * hl
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n) / (4 * math.Ln2)
+}
+
+// -----------------------------------------------------------------------------
+
+// CalculateGarmanKlassVariance computes the Garman-Klass estimator:
+// mean(0.5 ln(H/L)^2 - (2 ln 2 - 1) ln(C/O)^2), floored at 0.
+func CalculateGarmanKlassVariance(open, high, low, close []float64) float64 {
+	sum, n := 0.0, 0
+	for i := range open {
+		if open[i] <= 0 || high[i] <= 0 || low[i] <= 0 || close[i] <= 0 {
+			continue
+		}
+		hl := math.Log(high[i] / low[i])
+		co := math.Log(close[i] / open[i])
+		sum += 0.5*hl*hl - (2*math.Ln2-1)*co*co
+		n++
+	}
+	if n == 0 || sum < 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// -----------------------------------------------------------------------------
+
+// CalculateYangZhangVariance computes the Yang-Zhang estimator: overnight
+// (open vs previous close) variance + k * open-to-close variance + (1 - k) *
+// Rogers-Satchell. prevClose[i] is the close of the bar before bar i.
+func CalculateYangZhangVariance(prevClose, open, high, low, close []float64) float64 {
+	var overnight, openClose []float64
+	rs := 0.0
+	for i := range open {
+		if prevClose[i] <= 0 || open[i] <= 0 || high[i] <= 0 || low[i] <= 0 || close[i] <= 0 {
+			continue
+		}
+		overnight = append(overnight, math.Log(open[i]/prevClose[i]))
+		openClose = append(openClose, math.Log(close[i]/open[i]))
+		rs += math.Log(high[i]/close[i])*math.Log(high[i]/open[i]) + math.Log(low[i]/close[i])*math.Log(low[i]/open[i])
+	}
+
+	n := float64(len(openClose))
+	if n < 2 {
+		return 0
+	}
+	k := 0.34 / (1.34 + (n+1)/(n-1))
+	variance := sampleVariance(overnight) + k*sampleVariance(openClose) + (1-k)*rs/n
+	return math.Max(variance, 0)
+}
+
+// -----------------------------------------------------------------------------
+
+// sampleVariance computes the variance with an N-1 denominator.
+func sampleVariance(data []float64) float64 {
+	if len(data) < 2 {
+		return 0
+	}
+	_, std := CalculateMeanStd(data)
+	n := float64(len(data))
+	return std * std * n / (n - 1)
+}
+
+// -----------------------------------------------------------------------------
+
+// CalculatePercentileRank returns the share of history values at or below value (0..1).
+func CalculatePercentileRank(value float64, history []float64) float64 {
+	if len(history) == 0 {
+		return 0
+	}
+	below := 0
+	for _, v := range history {
+		if v <= value {
+			below++
+		}
+	}
+	return float64(below) / float64(len(history))
+}
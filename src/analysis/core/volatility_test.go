@@ -0,0 +1,82 @@
+package core
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVarianceEstimators(t *testing.T) {
+	a := 0.02 // ln(H/L) of the range bars
+	up := 100 * math.Exp(a)
+	gk := 2*math.Ln2 - 1
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"realized", CalculateRealizedVariance([]float64{100, 110, 99}), (math.Pow(math.Log(1.1), 2) + math.Pow(math.Log(0.9), 2)) / 2},
+		{"realized skips non-positive closes", CalculateRealizedVariance([]float64{100, 0, 110, 121}), math.Pow(math.Log(1.1), 2)},
+		{"realized single close", CalculateRealizedVariance([]float64{100}), 0},
+
+		{"parkinson", CalculateParkinsonVariance([]float64{up, up}, []float64{100, 100}), a * a / (4 * math.Ln2)},
+		{"parkinson flat bars", CalculateParkinsonVariance([]float64{100, 100}, []float64{100, 100}), 0},
+		{"parkinson skips missing ranges", CalculateParkinsonVariance([]float64{up, 0}, []float64{100, 0}), a * a / (4 * math.Ln2)},
+
+		{"garman-klass open = close", CalculateGarmanKlassVariance([]float64{100}, []float64{up}, []float64{100}, []float64{100}), 0.5 * a * a},
+		{"garman-klass with drift", CalculateGarmanKlassVariance([]float64{100}, []float64{up}, []float64{100}, []float64{up}), 0.5*a*a - gk*a*a},
+		{"garman-klass floored at 0", CalculateGarmanKlassVariance([]float64{100}, []float64{110}, []float64{110}, []float64{110}), 0},
+
+		{"yang-zhang overnight only", CalculateYangZhangVariance(
+			[]float64{100, 100}, []float64{101, 99}, []float64{101, 99}, []float64{101, 99}, []float64{101, 99}),
+			math.Pow(math.Log(1.01)-math.Log(0.99), 2) / 2},
+		{"yang-zhang range only", CalculateYangZhangVariance(
+			[]float64{100, 100}, []float64{100, 100}, []float64{up, up}, []float64{100 * math.Exp(-a), 100 * math.Exp(-a)}, []float64{100, 100}),
+			(1 - 0.34/4.34) * 2 * a * a},
+		{"yang-zhang single bar", CalculateYangZhangVariance([]float64{100}, []float64{101}, []float64{up}, []float64{99}, []float64{100}), 0},
+	}
+
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-12 {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestRangeEstimatorsAgreeOnDriftlessBars(t *testing.T) {
+	// Symmetric bars around an unchanged open/close: Garman-Klass reduces to
+	// 0.5 ln(H/L)^2, Parkinson to ln(H/L)^2 / (4 ln 2), in a fixed ratio.
+	high := []float64{101, 102, 100.5}
+	low := []float64{99, 98.5, 99.8}
+	mid := []float64{100, 100.2, 100.1}
+
+	p := CalculateParkinsonVariance(high, low)
+	g := CalculateGarmanKlassVariance(mid, high, low, mid)
+	if want := 2 * math.Ln2; math.Abs(g/p-want) > 1e-12 {
+		t.Errorf("garman-klass / parkinson = %v, want %v", g/p, want)
+	}
+}
+
+func TestCalculatePercentileRank(t *testing.T) {
+	history := []float64{1, 2, 3, 4}
+
+	tests := []struct {
+		value float64
+		want  float64
+	}{
+		{0, 0},
+		{1, 0.25},
+		{2.5, 0.5},
+		{4, 1},
+		{9, 1},
+	}
+
+	for _, tt := range tests {
+		if got := CalculatePercentileRank(tt.value, history); got != tt.want {
+			t.Errorf("rank(%v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+	if got := CalculatePercentileRank(1, nil); got != 0 {
+		t.Errorf("rank over empty history = %v, want 0", got)
+	}
+}
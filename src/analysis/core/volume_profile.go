@@ -0,0 +1,100 @@
+package core
+
+import "math"
+
+// Info: Volume-by-price histograms of bars (oldest first). Buckets are
+// [origin + i*bucket, origin + (i+1)*bucket).
+
+// -----------------------------------------------------------------------------
+
+// CalculateVolumeByPrice distributes the volume of each bar uniformly over its
+// low-high range into n price buckets. Bars without a range put all their
+// volume in the bucket of their low; prices outside the buckets are clamped
+// into the first or last one.
+func CalculateVolumeByPrice(lows, highs, volumes []float64, origin, bucket float64, n int) []float64 {
+	hist := make([]float64, n)
+	if n == 0 || bucket <= 0 {
+		return hist
+	}
+
+	index := func(price float64) int {
+		i := int(math.Floor((price - origin) / bucket))
+		if i < 0 {
+			return 0
+		}
+		if i >= n {
+			return n - 1
+		}
+		return i
+	}
+
+	for k := range volumes {
+		lo, hi, v := lows[k], highs[k], volumes[k]
+		if v <= 0 {
+			continue
+		}
+		if hi <= lo {
+			hist[index(lo)] += v
+			continue
+		}
+
+		first, last := index(lo), index(hi)
+		if first == last {
+			hist[first] += v
+			continue
+		}
+		for i := first; i <= last; i++ {
+			bottom := math.Max(lo, origin+float64(i)*bucket)
+			top := math.Min(hi, origin+float64(i+1)*bucket)
+			if i == first {
+				bottom = lo // Clamped prices below the first bucket
+			}
+			if i == last {
+				top = hi // and above the last one
+			}
+			if top > bottom {
+				hist[i] += v * (top - bottom) / (hi - lo)
+			}
+		}
+	}
+	return hist
+}
+
+// -----------------------------------------------------------------------------
+
+// CalculateValueArea returns the point of control (fullest bucket) and the
+// bucket range around it holding at least share of the total volume. The
+// range grows one bucket at a time towards the fuller neighbour.
+func CalculateValueArea(hist []float64, share float64) (poc, low, high int) {
+	if len(hist) == 0 {
+		return 0, 0, 0
+	}
+
+	total := 0.0
+	for i, v := range hist {
+		total += v
+		if v > hist[poc] {
+			poc = i
+		}
+	}
+
+	low, high = poc, poc
+	volume := hist[poc]
+	for volume < share*total && (low > 0 || high < len(hist)-1) {
+		below, above := -1.0, -1.0
+		if low > 0 {
+			below = hist[low-1]
+		}
+		if high < len(hist)-1 {
+			above = hist[high+1]
+		}
+		if above >= below {
+			high++
+			volume += hist[high]
+		} else {
+			low--
+			volume += hist[low]
+		}
+	}
+	return poc, low, high
+}
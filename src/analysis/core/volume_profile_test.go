@@ -0,0 +1,72 @@
+package core
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateVolumeByPrice(t *testing.T) {
+	tests := []struct {
+		name    string
+		lows    []float64
+		highs   []float64
+		volumes []float64
+		origin  float64
+		bucket  float64
+		n       int
+		want    []float64
+	}{
+		{"close-only bars", []float64{100.5, 101.2, 101.9}, []float64{100.5, 101.2, 101.9}, []float64{10, 20, 30}, 100, 1, 3, []float64{10, 50, 0}},
+		{"ranged bar spread uniformly", []float64{100}, []float64{104}, []float64{40}, 100, 1, 4, []float64{10, 10, 10, 10}},
+		{"partial buckets", []float64{100.5}, []float64{102}, []float64{30}, 100, 1, 3, []float64{10, 20, 0}},
+		{"range inside one bucket", []float64{101.1}, []float64{101.9}, []float64{7}, 100, 1, 3, []float64{0, 7, 0}},
+		{"mixed bars", []float64{100, 102.5}, []float64{102, 102.5}, []float64{20, 5}, 100, 1, 3, []float64{10, 10, 5}},
+		{"clamped below and above", []float64{98, 103}, []float64{98, 106}, []float64{5, 9}, 100, 1, 3, []float64{5, 0, 9}},
+		{"range clamped at both ends", []float64{99}, []float64{103}, []float64{40}, 100, 1, 3, []float64{20, 10, 10}},
+		{"non-positive volume skipped", []float64{100, 101}, []float64{100, 101}, []float64{0, -5}, 100, 1, 2, []float64{0, 0}},
+		{"no buckets", []float64{100}, []float64{100}, []float64{5}, 100, 1, 0, []float64{}},
+		{"invalid bucket", []float64{100}, []float64{100}, []float64{5}, 100, 0, 2, []float64{0, 0}},
+	}
+
+	for _, tt := range tests {
+		got := CalculateVolumeByPrice(tt.lows, tt.highs, tt.volumes, tt.origin, tt.bucket, tt.n)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+				t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestCalculateValueArea(t *testing.T) {
+	tests := []struct {
+		name      string
+		hist      []float64
+		share     float64
+		poc       int
+		low, high int
+	}{
+		{"empty", nil, 0.7, 0, 0, 0},
+		{"single bucket", []float64{5}, 0.7, 0, 0, 0},
+		{"poc alone is enough", []float64{1, 8, 1}, 0.7, 1, 1, 1},
+		{"grows towards the fuller side", []float64{1, 2, 10, 5, 1}, 0.7, 2, 2, 3},
+		{"grows below", []float64{1, 6, 10, 2, 1}, 0.7, 2, 1, 2},
+		{"tie grows upward", []float64{3, 10, 3}, 0.8, 1, 1, 2},
+		{"first of equal maxima", []float64{4, 1, 4}, 0.4, 0, 0, 0},
+		{"poc at the edge", []float64{10, 1, 5, 2}, 0.9, 0, 0, 3},
+		{"whole range", []float64{1, 1, 1}, 1, 0, 0, 2},
+		{"zero share", []float64{1, 3, 1}, 0, 1, 1, 1},
+	}
+
+	for _, tt := range tests {
+		poc, low, high := CalculateValueArea(tt.hist, tt.share)
+		if poc != tt.poc || low != tt.low || high != tt.high {
+			t.Errorf("%s = (%d, %d, %d), want (%d, %d, %d)", tt.name, poc, low, high, tt.poc, tt.low, tt.high)
+		}
+	}
+}
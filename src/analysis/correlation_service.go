@@ -0,0 +1,358 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// CorrelationService maintains rolling return correlation matrices across the
+// monitored universe (one per window), built from the MemoryManager history.
+// It also raises breakdown alerts when a normally correlated pair decouples.
+// -----------------------------------------------------------------------------
+
+type CorrelationService struct {
+	Config  *models.MConfig
+	Windows map[string]utils.WindowSpec
+	Logger  *logger.Logger
+	Memory  *utils.MemoryManager
+
+	Lookback          int
+	BaselineLookback  int
+	MinObservations   int
+	BreakdownBaseline float64
+	BreakdownDrop     float64
+
+	matrices map[string]models.MCorrelationMatrix
+	pairs    map[string][]models.MCorrelationPair // window -> pairs sorted by correlation (desc)
+	broken   map[string]map[string]bool           // window -> pair key -> breakdown active
+	alerts   []models.MCorrelationAlert           // Most recent last
+	mu       sync.RWMutex
+}
+
+// -----------------------------------------------------------------------------
+
+func NewCorrelationService(cfg *models.MConfig, memManager *utils.MemoryManager, log *logger.Logger) *CorrelationService {
+	c := cfg.Correlation
+
+	lookback := c.Lookback
+	if lookback <= 0 {
+		lookback = utils.DefaultCorrelationLookback
+	}
+	baselineLookback := c.BaselineLookback
+	if baselineLookback <= 0 {
+		baselineLookback = utils.DefaultCorrelationBaselineLookback
+	}
+	minObs := c.MinObservations
+	if minObs <= 0 {
+		minObs = utils.DefaultCorrelationMinObservations
+	}
+	breakdownBaseline := c.BreakdownBaseline
+	if breakdownBaseline <= 0 {
+		breakdownBaseline = utils.DefaultCorrelationBreakdownBaseline
+	}
+	breakdownDrop := c.BreakdownDrop
+	if breakdownDrop <= 0 {
+		breakdownDrop = utils.DefaultCorrelationBreakdownDrop
+	}
+
+	return &CorrelationService{
+		Config:            cfg,
+		Windows:           utils.ParseWindows(cfg.WindowsAgg),
+		Logger:            log,
+		Memory:            memManager,
+		Lookback:          lookback,
+		BaselineLookback:  baselineLookback,
+		MinObservations:   minObs,
+		BreakdownBaseline: breakdownBaseline,
+		BreakdownDrop:     breakdownDrop,
+		matrices:          make(map[string]models.MCorrelationMatrix),
+		pairs:             make(map[string][]models.MCorrelationPair),
+		broken:            make(map[string]map[string]bool),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Refresh recomputes the matrices of the given windows (all configured windows
+// when empty) and returns the breakdown alerts raised by this refresh.
+func (s *CorrelationService) Refresh(windows []string) []models.MCorrelationAlert {
+	if len(windows) == 0 {
+		windows = s.Config.WindowsAgg
+	}
+
+	history := make(map[string][]models.MStockPrice)
+	for _, sym := range s.Memory.Symbols() {
+		if points := s.Memory.GetHistory(sym); len(points) > 0 {
+			history[sym] = points
+		}
+	}
+
+	var raised []models.MCorrelationAlert
+	for _, windowName := range windows {
+		spec, ok := s.Windows[windowName]
+		if !ok {
+			continue
+		}
+		raised = append(raised, s.refreshWindow(spec, history)...)
+	}
+
+	if len(raised) > 0 {
+		s.Logger.Info("Correlation: %d breakdown alert(s) raised", len(raised))
+	}
+	return raised
+}
+
+// -----------------------------------------------------------------------------
+
+// GetMatrix returns the latest correlation matrix of a window.
+func (s *CorrelationService) GetMatrix(windowName string) (models.MCorrelationMatrix, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	m, ok := s.matrices[windowName]
+	return m, ok
+}
+
+// -----------------------------------------------------------------------------
+
+// TopPairs returns the n most correlated pairs of a window (all pairs when n <= 0).
+func (s *CorrelationService) TopPairs(windowName string, n int) []models.MCorrelationPair {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pairs := s.pairs[windowName]
+	if n <= 0 || n > len(pairs) {
+		n = len(pairs)
+	}
+	result := make([]models.MCorrelationPair, n)
+	copy(result, pairs[:n])
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// GetAlerts returns the recent breakdown alerts of a window (all windows when empty),
+// oldest first.
+func (s *CorrelationService) GetAlerts(windowName string) []models.MCorrelationAlert {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]models.MCorrelationAlert, 0, len(s.alerts))
+	for _, a := range s.alerts {
+		if windowName == "" || a.WindowName == windowName {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// refreshWindow computes the matrix, pairs and alerts of one window.
+func (s *CorrelationService) refreshWindow(spec utils.WindowSpec, history map[string][]models.MStockPrice) []models.MCorrelationAlert {
+	windowName := spec.Name
+
+	// Only closed windows are used: anything at or after the window holding the
+	// newest point (in each symbol's own calendar) is still open
+	var latest int64
+	for _, prices := range history {
+		if len(prices) > 0 && prices[len(prices)-1].Timestamp > latest {
+			latest = prices[len(prices)-1].Timestamp
+		}
+	}
+
+	// Per-symbol log returns keyed by window start
+	returns := make(map[string]map[int64]float64, len(history))
+	timeline := make(map[int64]struct{})
+	for sym, prices := range history {
+		calendar := utils.GetCalendar(sym)
+		openStart, _ := spec.Bounds(latest, calendar)
+		closes := windowCloses(prices, spec, calendar, openStart)
+		if len(closes) < 2 {
+			continue
+		}
+		r := make(map[int64]float64, len(closes)-1)
+		for i := 1; i < len(closes); i++ {
+			r[closes[i].start] = core.CalculateLogReturn(closes[i].price, closes[i-1].price)
+			timeline[closes[i].start] = struct{}{}
+		}
+		returns[sym] = r
+	}
+
+	// Keep the most recent Lookback + BaselineLookback window starts: the last Lookback
+	// form the matrix, the ones before are the baseline period
+	starts := make([]int64, 0, len(timeline))
+	for ts := range timeline {
+		starts = append(starts, ts)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+	if keep := s.Lookback + s.BaselineLookback; len(starts) > keep {
+		starts = starts[len(starts)-keep:]
+	}
+	shortFrom := 0
+	if len(starts) > s.Lookback {
+		shortFrom = len(starts) - s.Lookback
+	}
+
+	symbols := make([]string, 0, len(returns))
+	for sym := range returns {
+		symbols = append(symbols, sym)
+	}
+	sort.Strings(symbols)
+
+	// Dense series (NaN = no return for that window)
+	series := make([][]float64, len(symbols))
+	for i, sym := range symbols {
+		row := make([]float64, len(starts))
+		for t, ts := range starts {
+			if v, ok := returns[sym][ts]; ok {
+				row[t] = v
+			} else {
+				row[t] = math.NaN()
+			}
+		}
+		series[i] = row
+	}
+
+	matrix := make([][]float64, len(symbols))
+	for i := range matrix {
+		matrix[i] = make([]float64, len(symbols))
+		matrix[i][i] = 1
+	}
+
+	var pairs []models.MCorrelationPair
+	for i := 0; i < len(symbols); i++ {
+		for j := i + 1; j < len(symbols); j++ {
+			corr, n := pairCorrelation(series[i], series[j], shortFrom, len(starts))
+			if n < s.MinObservations {
+				continue
+			}
+			// Baseline = the period preceding the short lookback (the pair's "normal" behavior)
+			baseline, nb := pairCorrelation(series[i], series[j], 0, shortFrom)
+			if nb < s.MinObservations {
+				baseline = corr
+			}
+
+			matrix[i][j] = corr
+			matrix[j][i] = corr
+			pairs = append(pairs, models.MCorrelationPair{
+				SymbolA:      symbols[i],
+				SymbolB:      symbols[j],
+				Correlation:  corr,
+				Baseline:     baseline,
+				Observations: n,
+			})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Correlation > pairs[j].Correlation })
+
+	now := time.Now().UTC().Unix()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.matrices[windowName] = models.MCorrelationMatrix{
+		WindowName: windowName,
+		Symbols:    symbols,
+		Matrix:     matrix,
+		Lookback:   len(starts) - shortFrom,
+		Timestamp:  now,
+	}
+	s.pairs[windowName] = pairs
+
+	return s.detectBreakdowns(windowName, pairs, now)
+}
+
+// -----------------------------------------------------------------------------
+
+// detectBreakdowns raises an alert when a pair enters the breakdown state
+// (an active breakdown is not re-raised until the pair recovers). Caller holds the lock.
+func (s *CorrelationService) detectBreakdowns(windowName string, pairs []models.MCorrelationPair, now int64) []models.MCorrelationAlert {
+	previous := s.broken[windowName]
+	current := make(map[string]bool)
+
+	var raised []models.MCorrelationAlert
+	for _, p := range pairs {
+		if p.Baseline < s.BreakdownBaseline || p.Correlation > p.Baseline-s.BreakdownDrop {
+			continue
+		}
+		key := p.SymbolA + "|" + p.SymbolB
+		current[key] = true
+		if previous[key] {
+			continue
+		}
+		raised = append(raised, models.MCorrelationAlert{
+			WindowName:  windowName,
+			SymbolA:     p.SymbolA,
+			SymbolB:     p.SymbolB,
+			Correlation: p.Correlation,
+			Baseline:    p.Baseline,
+			Timestamp:   now,
+		})
+	}
+	s.broken[windowName] = current
+
+	s.alerts = append(s.alerts, raised...)
+	if overflow := len(s.alerts) - utils.DefaultCorrelationMaxAlerts; overflow > 0 {
+		s.alerts = append([]models.MCorrelationAlert(nil), s.alerts[overflow:]...)
+	}
+	return raised
+}
+
+// -----------------------------------------------------------------------------
+
+type windowClose struct {
+	start int64
+	price float64
+}
+
+// windowCloses returns the last price of each window before openStart (oldest first).
+func windowCloses(prices []models.MStockPrice, spec utils.WindowSpec, calendar *utils.TradingCalendar, openStart int64) []windowClose {
+	var closes []windowClose
+	var start, end int64
+	for _, p := range prices {
+		if p.Timestamp < start || p.Timestamp >= end {
+			start, end = spec.Bounds(p.Timestamp, calendar)
+		}
+		if start >= openStart {
+			continue
+		}
+		if n := len(closes); n > 0 && closes[n-1].start == start {
+			closes[n-1].price = p.Price
+			continue
+		}
+		if n := len(closes); n > 0 && closes[n-1].start > start {
+			continue // Out of order point
+		}
+		closes = append(closes, windowClose{start: start, price: p.Price})
+	}
+	return closes
+}
+
+// -----------------------------------------------------------------------------
+
+// pairCorrelation computes the correlation of two aligned series over [from, to),
+// using only the positions where both have a value. Returns the overlap count.
+func pairCorrelation(x, y []float64, from, to int) (float64, int) {
+	var n, sumX, sumY, sumXY, sumX2, sumY2 float64
+	for t := from; t < to; t++ {
+		if math.IsNaN(x[t]) || math.IsNaN(y[t]) {
+			continue
+		}
+		n++
+		sumX += x[t]
+		sumY += y[t]
+		sumXY += x[t] * y[t]
+		sumX2 += x[t] * x[t]
+		sumY2 += y[t] * y[t]
+	}
+	return core.CalculateCorrelationFromSums(n, sumX, sumY, sumXY, sumX2, sumY2), int(n)
+}
@@ -0,0 +1,272 @@
+package analysis
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// DataQualityMonitor checks each symbol's feed against its trading calendar:
+// a bar expected while the market is open but absent from the feed is missing.
+// Depending on the gap policy, missing bars are left out, forward-filled or
+// interpolated (synthetic points with zero volume, flagged as Filled).
+// -----------------------------------------------------------------------------
+
+type DataQualityMonitor struct {
+	Config *models.MConfig
+	Logger *logger.Logger
+
+	Policy      string
+	BarInterval int64
+	MaxFillBars int
+	MaxGaps     int
+
+	symbols map[string]*symbolQuality
+	mu      sync.RWMutex
+}
+
+type symbolQuality struct {
+	last    models.MStockPrice
+	hasLast bool
+
+	points      int
+	missingBars int
+	filledBars  int
+	gaps        []models.MDataGap // Most recent last
+	missing     []int64           // Missing bar timestamps of the kept gaps (sorted)
+}
+
+// -----------------------------------------------------------------------------
+
+func NewDataQualityMonitor(cfg *models.MConfig, log *logger.Logger) *DataQualityMonitor {
+	dq := cfg.DataQuality
+
+	policy := dq.GapPolicy
+	if policy == "" {
+		policy = utils.DefaultGapPolicy
+	}
+	interval := int64(dq.BarIntervalSeconds)
+	if interval <= 0 {
+		interval = utils.DefaultBarInterval
+	}
+	maxFill := dq.MaxFillBars
+	if maxFill <= 0 {
+		maxFill = utils.DefaultMaxFillBars
+	}
+	maxGaps := dq.MaxGaps
+	if maxGaps <= 0 {
+		maxGaps = utils.DefaultDataQualityGaps
+	}
+
+	return &DataQualityMonitor{
+		Config:      cfg,
+		Logger:      log,
+		Policy:      policy,
+		BarInterval: interval,
+		MaxFillBars: maxFill,
+		MaxGaps:     maxGaps,
+		symbols:     make(map[string]*symbolQuality),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Process checks new points of a symbol against the ones already seen and
+// returns them (sorted) with the gaps filled according to the policy.
+// Real points are never altered.
+func (m *DataQualityMonitor) Process(symbol string, prices []models.MStockPrice) []models.MStockPrice {
+	sorted := make([]models.MStockPrice, len(prices))
+	copy(sorted, prices)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp < sorted[j].Timestamp
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sq, ok := m.symbols[symbol]
+	if !ok {
+		sq = &symbolQuality{}
+		m.symbols[symbol] = sq
+	}
+	calendar := utils.GetCalendar(symbol)
+
+	result := make([]models.MStockPrice, 0, len(sorted))
+	for _, p := range sorted {
+		if sq.hasLast && p.Timestamp <= sq.last.Timestamp {
+			// Same bar again (or late): nothing to check
+			result = append(result, p)
+			continue
+		}
+		if sq.hasLast {
+			result = append(result, m.checkGap(symbol, sq, calendar, sq.last, p)...)
+		}
+		result = append(result, p)
+		sq.points++
+		sq.last = p
+		sq.hasLast = true
+	}
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// ProcessBatch applies Process to every symbol of an update batch.
+func (m *DataQualityMonitor) ProcessBatch(updates map[string][]models.MStockPrice) map[string][]models.MStockPrice {
+	result := make(map[string][]models.MStockPrice, len(updates))
+	for symbol, prices := range updates {
+		result[symbol] = m.Process(symbol, prices)
+	}
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// MissingBars counts the missing bars of a symbol in [start, end).
+func (m *DataQualityMonitor) MissingBars(symbol string, start, end int64) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sq, ok := m.symbols[symbol]
+	if !ok {
+		return 0
+	}
+	from := sort.Search(len(sq.missing), func(i int) bool { return sq.missing[i] >= start })
+	to := sort.Search(len(sq.missing), func(i int) bool { return sq.missing[i] >= end })
+	return to - from
+}
+
+// -----------------------------------------------------------------------------
+
+// Report returns the data-quality report of a symbol, with its recent gaps.
+func (m *DataQualityMonitor) Report(symbol string) (models.MDataQualityReport, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sq, ok := m.symbols[symbol]
+	if !ok {
+		return models.MDataQualityReport{}, false
+	}
+	report := m.report(symbol, sq)
+	report.Gaps = make([]models.MDataGap, len(sq.gaps))
+	copy(report.Gaps, sq.gaps)
+	return report, true
+}
+
+// -----------------------------------------------------------------------------
+
+// Summary returns the report of every symbol (without gaps), least complete first.
+func (m *DataQualityMonitor) Summary() []models.MDataQualityReport {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	reports := make([]models.MDataQualityReport, 0, len(m.symbols))
+	for symbol, sq := range m.symbols {
+		reports = append(reports, m.report(symbol, sq))
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Completeness != reports[j].Completeness {
+			return reports[i].Completeness < reports[j].Completeness
+		}
+		return reports[i].Symbol < reports[j].Symbol
+	})
+	return reports
+}
+
+// -----------------------------------------------------------------------------
+
+// annotate sets the completeness fields of a candle. m may be nil (no tracking).
+func (m *DataQualityMonitor) annotate(c *models.MAggregation) {
+	c.MissingBars = 0
+	if m != nil {
+		c.MissingBars = m.MissingBars(c.Symbol, c.StartTime, c.EndTime)
+	}
+	c.IsComplete = c.MissingBars == 0
+}
+
+// -----------------------------------------------------------------------------
+
+// checkGap records the bars missing between two consecutive points and returns
+// the synthetic points required by the policy (caller holds the lock).
+func (m *DataQualityMonitor) checkGap(symbol string, sq *symbolQuality, calendar *utils.TradingCalendar, prev, next models.MStockPrice) []models.MStockPrice {
+	// Half a bar of tolerance for feed jitter
+	if next.Timestamp-prev.Timestamp <= m.BarInterval*3/2 {
+		return nil
+	}
+
+	var missing []int64
+	for t := prev.Timestamp + m.BarInterval; t <= next.Timestamp-m.BarInterval/2; t += m.BarInterval {
+		if calendar.IsOpenOnMinute(time.Unix(t, 0)) {
+			missing = append(missing, t)
+		}
+	}
+	if len(missing) == 0 {
+		return nil // Market closed in between (overnight, weekend, holiday)
+	}
+
+	gap := models.MDataGap{
+		Symbol:      symbol,
+		From:        prev.Timestamp,
+		To:          next.Timestamp,
+		MissingBars: len(missing),
+		DetectedAt:  time.Now().UTC().Unix(),
+	}
+
+	var filled []models.MStockPrice
+	if m.Policy != utils.GapPolicyNone && len(missing) <= m.MaxFillBars {
+		for _, t := range missing {
+			price := prev.Price
+			if m.Policy == utils.GapPolicyInterpolate {
+				frac := float64(t-prev.Timestamp) / float64(next.Timestamp-prev.Timestamp)
+				price = prev.Price + (next.Price-prev.Price)*frac
+			}
+			filled = append(filled, models.MStockPrice{
+				Symbol:    symbol,
+				Price:     price,
+				Timestamp: t,
+				FetchedAt: next.FetchedAt,
+				Filled:    true,
+			})
+		}
+		gap.FilledBars = len(filled)
+	}
+
+	sq.missingBars += gap.MissingBars
+	sq.filledBars += gap.FilledBars
+	sq.gaps = append(sq.gaps, gap)
+	sq.missing = append(sq.missing, missing...)
+	if overflow := len(sq.gaps) - m.MaxGaps; overflow > 0 {
+		sq.gaps = append([]models.MDataGap(nil), sq.gaps[overflow:]...)
+		oldest := sq.gaps[0].From
+		keep := sort.Search(len(sq.missing), func(i int) bool { return sq.missing[i] > oldest })
+		sq.missing = append([]int64(nil), sq.missing[keep:]...)
+	}
+
+	m.Logger.Debug("Data gap for %s: %d missing bar(s) between %d and %d (%d filled)", symbol, gap.MissingBars, gap.From, gap.To, gap.FilledBars)
+	return filled
+}
+
+// -----------------------------------------------------------------------------
+
+// report builds the summary part of a symbol report (caller holds the lock).
+func (m *DataQualityMonitor) report(symbol string, sq *symbolQuality) models.MDataQualityReport {
+	completeness := 1.0
+	if total := sq.points + sq.missingBars; total > 0 {
+		completeness = float64(sq.points) / float64(total)
+	}
+	return models.MDataQualityReport{
+		Symbol:        symbol,
+		Policy:        m.Policy,
+		BarInterval:   m.BarInterval,
+		Points:        sq.points,
+		MissingBars:   sq.missingBars,
+		FilledBars:    sq.filledBars,
+		Completeness:  completeness,
+		LastTimestamp: sq.last.Timestamp,
+	}
+}
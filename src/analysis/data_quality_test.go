@@ -0,0 +1,144 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+func newTestQuality(t *testing.T, policy string, maxFill int) *DataQualityMonitor {
+	t.Helper()
+	cfg := &models.MConfig{}
+	cfg.DataQuality.GapPolicy = policy
+	cfg.DataQuality.MaxFillBars = maxFill
+	return NewDataQualityMonitor(cfg, logger.NewLogger(cfg, "test"))
+}
+
+// nyseTime returns a New York wall-clock time as Unix seconds
+func nyseTime(t *testing.T, month time.Month, day, hour, min int) int64 {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	return time.Date(2024, month, day, hour, min, 0, 0, loc).Unix()
+}
+
+func TestDataQualityGapPolicies(t *testing.T) {
+	t0 := nyseTime(t, 3, 12, 10, 0) // Tuesday, market open
+	prev := models.MStockPrice{Symbol: "AAPL", Price: 100, Volume: 10, Timestamp: t0}
+	next := models.MStockPrice{Symbol: "AAPL", Price: 108, Volume: 10, Timestamp: t0 + 4*300} // 3 bars missing
+
+	tests := []struct {
+		policy  string
+		maxFill int
+		prices  []float64 // Synthetic points expected between prev and next
+	}{
+		{utils.GapPolicyNone, 12, nil},
+		{utils.GapPolicyForwardFill, 12, []float64{100, 100, 100}},
+		{utils.GapPolicyInterpolate, 12, []float64{102, 104, 106}},
+		{utils.GapPolicyInterpolate, 2, nil}, // Longer than max_fill_bars: reported, not filled
+	}
+
+	for _, tt := range tests {
+		m := newTestQuality(t, tt.policy, tt.maxFill)
+		out := m.Process("AAPL", []models.MStockPrice{next, prev}) // Unsorted on purpose
+
+		if len(out) != 2+len(tt.prices) {
+			t.Fatalf("%s/%d: %d points out, want %d", tt.policy, tt.maxFill, len(out), 2+len(tt.prices))
+		}
+		if out[0] != prev || out[len(out)-1] != next {
+			t.Errorf("%s: real points altered or out of order", tt.policy)
+		}
+		for i, want := range tt.prices {
+			p := out[i+1]
+			if !p.Filled || p.Volume != 0 || p.Timestamp != t0+int64(i+1)*300 || math.Abs(p.Price-want) > 1e-9 {
+				t.Errorf("%s: synthetic point %d = %+v, want price %v", tt.policy, i, p, want)
+			}
+		}
+
+		report, _ := m.Report("AAPL")
+		if report.Points != 2 || report.MissingBars != 3 || report.FilledBars != len(tt.prices) || len(report.Gaps) != 1 {
+			t.Errorf("%s: report = %+v", tt.policy, report)
+		}
+		if math.Abs(report.Completeness-0.4) > 1e-9 {
+			t.Errorf("%s: completeness = %v, want 0.4", tt.policy, report.Completeness)
+		}
+		if got := m.MissingBars("AAPL", t0, t0+900); got != 2 {
+			t.Errorf("%s: MissingBars in the first 15m = %d, want 2", tt.policy, got)
+		}
+	}
+}
+
+func TestDataQualityNoGap(t *testing.T) {
+	t0 := nyseTime(t, 3, 12, 10, 0)
+	close := nyseTime(t, 3, 12, 16, 0)
+	nextOpen := nyseTime(t, 3, 13, 9, 30)
+	friday := nyseTime(t, 3, 15, 16, 0)
+	monday := nyseTime(t, 3, 18, 9, 30)
+
+	tests := []struct {
+		name string
+		a, b int64
+	}{
+		{"consecutive bars", t0, t0 + 300},
+		{"jitter within half a bar", t0, t0 + 420},
+		{"overnight", close, nextOpen},
+		{"weekend", friday, monday},
+	}
+
+	for _, tt := range tests {
+		m := newTestQuality(t, utils.GapPolicyForwardFill, 12)
+		out := m.Process("AAPL", []models.MStockPrice{
+			{Symbol: "AAPL", Price: 1, Timestamp: tt.a},
+			{Symbol: "AAPL", Price: 2, Timestamp: tt.b},
+		})
+		report, _ := m.Report("AAPL")
+		if len(out) != 2 || report.MissingBars != 0 || report.Completeness != 1 {
+			t.Errorf("%s: %d points, report %+v", tt.name, len(out), report)
+		}
+	}
+}
+
+func TestDataQualityRepeatedPoints(t *testing.T) {
+	t0 := nyseTime(t, 3, 12, 10, 0)
+	m := newTestQuality(t, utils.GapPolicyForwardFill, 12)
+	m.Process("AAPL", []models.MStockPrice{{Symbol: "AAPL", Price: 1, Timestamp: t0 + 600}})
+
+	// The same bar again and a late one pass through without being counted or checked
+	out := m.Process("AAPL", []models.MStockPrice{
+		{Symbol: "AAPL", Price: 1.1, Timestamp: t0 + 600},
+		{Symbol: "AAPL", Price: 0.9, Timestamp: t0},
+	})
+	report, _ := m.Report("AAPL")
+	if len(out) != 2 || report.Points != 1 || report.MissingBars != 0 {
+		t.Errorf("%d points out, report %+v", len(out), report)
+	}
+}
+
+func TestDataQualityGapRetention(t *testing.T) {
+	t0 := nyseTime(t, 3, 12, 10, 0)
+	cfg := &models.MConfig{}
+	cfg.DataQuality.MaxGaps = 2
+	m := NewDataQualityMonitor(cfg, logger.NewLogger(cfg, "test"))
+
+	// Three gaps of one missing bar each
+	var points []models.MStockPrice
+	for i := int64(0); i < 4; i++ {
+		points = append(points, models.MStockPrice{Symbol: "AAPL", Price: 1, Timestamp: t0 + i*600})
+	}
+	m.Process("AAPL", points)
+
+	report, _ := m.Report("AAPL")
+	if report.MissingBars != 3 || len(report.Gaps) != 2 || report.Gaps[0].From != t0+600 {
+		t.Errorf("report = %+v", report)
+	}
+	// Missing bars of dropped gaps are no longer attributed to candles
+	if got := m.MissingBars("AAPL", t0, t0+3600); got != 2 {
+		t.Errorf("MissingBars = %d, want 2", got)
+	}
+}
@@ -0,0 +1,450 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/interfaces"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// EventStudyService periodically measures what volume anomalies meant. Every
+// stored candle whose volume anomaly ratio reaches the first severity bound is
+// an event; its forward returns are taken from the closes of the following
+// contiguous candles of the same window, and its abnormal returns remove the benchmark's
+// move over the same candles (market model, or beta-scaled with the candle's
+// rolling beta). Events are summarized per symbol, window and severity, plus
+// pooled groups across symbols and severities.
+// -----------------------------------------------------------------------------
+
+type EventStudyService struct {
+	Config *models.MConfig
+	DB     interfaces.IDatabase
+	Logger *logger.Logger
+
+	Windows      []string
+	Horizons     []int
+	Severities   []float64
+	Model        string
+	LookbackDays int
+	Interval     time.Duration
+	MaxEvents    int
+
+	report    models.MEventStudyReport
+	hasReport bool
+	events    []models.MAnomalyEvent // Most recent last
+	mu        sync.RWMutex
+}
+
+// -----------------------------------------------------------------------------
+
+func NewEventStudyService(cfg *models.MConfig, db interfaces.IDatabase, log *logger.Logger) *EventStudyService {
+	ec := cfg.EventStudy
+
+	s := &EventStudyService{
+		Config:       cfg,
+		DB:           db,
+		Logger:       log,
+		Windows:      ec.Windows,
+		Horizons:     append([]int(nil), ec.Horizons...),
+		Severities:   ec.Severities,
+		Model:        ec.Model,
+		LookbackDays: ec.LookbackDays,
+		Interval:     time.Duration(ec.RefreshIntervalSeconds) * time.Second,
+		MaxEvents:    ec.MaxEvents,
+	}
+	if len(s.Windows) == 0 {
+		s.Windows = cfg.WindowsAgg
+	}
+	if len(s.Horizons) == 0 {
+		s.Horizons = append([]int(nil), utils.DefaultEventStudyHorizons...)
+	}
+	sort.Ints(s.Horizons)
+	if len(s.Severities) == 0 {
+		s.Severities = utils.DefaultEventStudySeverities
+	}
+	if s.Model == "" {
+		s.Model = utils.DefaultEventStudyModel
+	}
+	// Aggregations older than the retention are purged (and tables are rebuilt at startup)
+	retention := cfg.DataSource.DataRetentionDays
+	if s.LookbackDays <= 0 {
+		s.LookbackDays = retention
+	} else if retention > 0 && s.LookbackDays > retention {
+		log.Warning("Event study: lookback_days %d exceeds data_retention_days, using %d", s.LookbackDays, retention)
+		s.LookbackDays = retention
+	}
+	if s.Interval <= 0 {
+		s.Interval = utils.DefaultEventStudyRefreshInterval * time.Second
+	}
+	if s.MaxEvents <= 0 {
+		s.MaxEvents = utils.DefaultEventStudyMaxEvents
+	}
+	return s
+}
+
+// -----------------------------------------------------------------------------
+
+// Start runs the event study now and then every Interval until ctx is done.
+// s may be nil (disabled).
+func (s *EventStudyService) Start(ctx context.Context, wg *sync.WaitGroup) {
+	if s == nil {
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+		for {
+			s.Run()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// -----------------------------------------------------------------------------
+
+// Run studies the anomalies of the stored candles over the lookback and
+// replaces the report.
+func (s *EventStudyService) Run() models.MEventStudyReport {
+	now := time.Now().UTC()
+	from := now.AddDate(0, 0, -s.LookbackDays).Unix()
+
+	var events []models.MAnomalyEvent
+	var candleCount, gapHorizons int
+	var historyFrom, historyTo int64
+	for _, window := range s.Windows {
+		candles, err := s.DB.LoadAggregations(window, from, now.Unix()+1)
+		if err != nil {
+			s.Logger.Error("Event study: failed to load %s candles: %v", window, err)
+			continue
+		}
+
+		// Candles come by symbol then start time; the open candle has no future yet
+		for start := 0; start < len(candles); {
+			end := start
+			for end < len(candles) && candles[end].Symbol == candles[start].Symbol {
+				end++
+			}
+			series := candles[start:end]
+			for len(series) > 0 && series[len(series)-1].EndTime > now.Unix() {
+				series = series[:len(series)-1]
+			}
+			if len(series) > 0 {
+				candleCount += len(series)
+				if historyFrom == 0 || series[0].StartTime < historyFrom {
+					historyFrom = series[0].StartTime
+				}
+				historyTo = max(historyTo, series[len(series)-1].EndTime)
+			}
+			symbolEvents, gaps := s.symbolEvents(series)
+			events = append(events, symbolEvents...)
+			gapHorizons += gaps
+			start = end
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].EndTime < events[j].EndTime
+	})
+
+	report := models.MEventStudyReport{
+		Model:       s.Model,
+		Horizons:    s.Horizons,
+		From:        from,
+		To:          now.Unix(),
+		Candles:     candleCount,
+		HistoryFrom: historyFrom,
+		HistoryTo:   historyTo,
+		Events:      len(events),
+		GapHorizons: gapHorizons,
+		Groups:      s.groups(events),
+		Timestamp:   now.Unix(),
+	}
+	for i := range s.Severities {
+		report.Severities = append(report.Severities, s.severityLabel(i))
+	}
+
+	s.mu.Lock()
+	s.report = report
+	s.hasReport = true
+	if overflow := len(events) - s.MaxEvents; overflow > 0 {
+		events = events[overflow:]
+	}
+	s.events = events
+	s.mu.Unlock()
+
+	s.Logger.Info("Event study: %d anomalies in %d groups from %d candles (%d horizon(s) dropped at gaps)", report.Events, len(report.Groups), report.Candles, report.GapHorizons)
+	return report
+}
+
+// -----------------------------------------------------------------------------
+
+// Report returns the last report restricted to a symbol, window and severity
+// ("" = all; "*" selects the pooled groups). False before the first run.
+func (s *EventStudyService) Report(symbol, window, severity string) (models.MEventStudyReport, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.hasReport {
+		return models.MEventStudyReport{}, false
+	}
+	report := s.report
+	report.Groups = nil
+	for _, g := range s.report.Groups {
+		if (symbol == "" || g.Symbol == symbol) && (window == "" || g.WindowName == window) && (severity == "" || g.Severity == severity) {
+			report.Groups = append(report.Groups, g)
+		}
+	}
+	return report, true
+}
+
+// -----------------------------------------------------------------------------
+
+// Events returns the latest studied anomalies, optionally filtered by symbol,
+// window and severity ("" = all), newest last (limit <= 0 = all kept).
+func (s *EventStudyService) Events(symbol, window, severity string, limit int) []models.MAnomalyEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.MAnomalyEvent
+	for _, e := range s.events {
+		if (symbol == "" || e.Symbol == symbol) && (window == "" || e.WindowName == window) && (severity == "" || e.Severity == severity) {
+			result = append(result, e)
+		}
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// symbolEvents returns the anomalies of the closed candles of one symbol and
+// window (oldest first) with their forward returns, and the number of forward
+// horizons left out because a candle is missing within them.
+func (s *EventStudyService) symbolEvents(series []models.MAggregation) ([]models.MAnomalyEvent, int) {
+	if len(series) == 0 {
+		return nil, 0
+	}
+
+	// reach[i] is the last candle reachable from candle i without a gap
+	calendar := utils.GetCalendar(series[0].Symbol)
+	reach := make([]int, len(series))
+	reach[len(series)-1] = len(series) - 1
+	for i := len(series) - 2; i >= 0; i-- {
+		reach[i] = i
+		if contiguousCandles(series[i], series[i+1], calendar) {
+			reach[i] = reach[i+1]
+		}
+	}
+
+	var events []models.MAnomalyEvent
+	gaps := 0
+	for i, c := range series {
+		level := s.severityLevel(c.VolumeAnomalyRatio)
+		if level < 0 || c.Close <= 0 {
+			continue
+		}
+
+		e := models.MAnomalyEvent{
+			Symbol:             c.Symbol,
+			WindowName:         c.WindowName,
+			StartTime:          c.StartTime,
+			EndTime:            c.EndTime,
+			Severity:           s.severityLabel(level),
+			VolumeAnomalyRatio: c.VolumeAnomalyRatio,
+			Return:             c.PricePercentChange,
+			Close:              c.Close,
+			Benchmark:          c.Benchmark,
+		}
+
+		beta := 1.0
+		if s.Model == utils.EventStudyModelBeta && c.Beta != 0 {
+			beta = c.Beta
+		}
+		for _, h := range s.Horizons {
+			if i+h >= len(series) {
+				break
+			}
+			if i+h > reach[i] {
+				gaps++ // A candle is missing within the horizon
+				continue
+			}
+			f := models.MEventForwardReturn{
+				Bars:         h,
+				Return:       core.CalculateChangePercent(series[i+h].Close, c.Close),
+				HasBenchmark: true,
+			}
+			growth := 1.0
+			for _, next := range series[i+1 : i+h+1] {
+				if next.Benchmark == "" {
+					f.HasBenchmark = false
+					break
+				}
+				growth *= 1 + next.BenchmarkReturn
+			}
+			if f.HasBenchmark {
+				f.BenchmarkReturn = growth - 1
+				f.AbnormalReturn = f.Return - beta*f.BenchmarkReturn
+			}
+			e.Forward = append(e.Forward, f)
+		}
+		events = append(events, e)
+	}
+	return events, gaps
+}
+
+// -----------------------------------------------------------------------------
+
+// contiguousCandles reports whether next is the candle right after prev: it
+// starts where prev ends, or the market is closed in between (overnight,
+// weekends and holidays are not gaps).
+func contiguousCandles(prev, next models.MAggregation, calendar *utils.TradingCalendar) bool {
+	if next.StartTime == prev.EndTime {
+		return true
+	}
+	if next.StartTime < prev.EndTime {
+		return false
+	}
+	_, close, nextOpen := calendar.SessionBounds(time.Unix(prev.EndTime, 0))
+	return prev.EndTime >= close.Unix() && next.StartTime <= nextOpen.Unix()
+}
+
+// -----------------------------------------------------------------------------
+
+// groups summarizes the events per symbol/window/severity, with the pooled
+// groups ("*") across symbols and/or severities.
+func (s *EventStudyService) groups(events []models.MAnomalyEvent) []models.MEventStudyGroup {
+	members := make(map[[3]string][]models.MAnomalyEvent)
+	for _, e := range events {
+		for _, symbol := range []string{e.Symbol, utils.EventStudyAll} {
+			for _, severity := range []string{e.Severity, utils.EventStudyAll} {
+				key := [3]string{symbol, e.WindowName, severity}
+				members[key] = append(members[key], e)
+			}
+		}
+	}
+
+	groups := make([]models.MEventStudyGroup, 0, len(members))
+	for key, list := range members {
+		g := models.MEventStudyGroup{
+			Symbol:     key[0],
+			WindowName: key[1],
+			Severity:   key[2],
+			Events:     len(list),
+			From:       list[0].StartTime,
+			To:         list[0].StartTime,
+		}
+		for _, e := range list {
+			g.MeanRatio += e.VolumeAnomalyRatio / float64(len(list))
+			g.From = min(g.From, e.StartTime)
+			g.To = max(g.To, e.StartTime)
+		}
+		for _, h := range s.Horizons {
+			g.Horizons = append(g.Horizons, summarizeHorizon(list, h))
+		}
+		groups = append(groups, g)
+	}
+
+	rank := map[string]int{utils.EventStudyAll: -1} // Pooled first, then by bound
+	for i := range s.Severities {
+		rank[s.severityLabel(i)] = i
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.WindowName != b.WindowName {
+			return a.WindowName < b.WindowName
+		}
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol // "*" first
+		}
+		return rank[a.Severity] < rank[b.Severity]
+	})
+	return groups
+}
+
+// -----------------------------------------------------------------------------
+
+// summarizeHorizon aggregates the forward returns of events at horizon h.
+func summarizeHorizon(events []models.MAnomalyEvent, h int) models.MEventStudyHorizon {
+	summary := models.MEventStudyHorizon{Bars: h}
+
+	var returns, abnormal []float64
+	var continuation, hits float64
+	for _, e := range events {
+		for _, f := range e.Forward {
+			if f.Bars != h {
+				continue
+			}
+			returns = append(returns, f.Return)
+			if f.HasBenchmark {
+				abnormal = append(abnormal, f.AbnormalReturn)
+				if f.AbnormalReturn > 0 {
+					hits++
+				}
+				if e.Return > 0 {
+					continuation += f.AbnormalReturn
+				} else if e.Return < 0 {
+					continuation -= f.AbnormalReturn
+				}
+			}
+		}
+	}
+
+	summary.Events = len(returns)
+	summary.AbnormalEvents = len(abnormal)
+	if len(returns) > 0 {
+		summary.MeanReturn, _ = core.CalculateMeanStd(returns)
+		summary.MedianReturn, _ = core.CalculateMedianMAD(returns)
+	}
+	if n := len(abnormal); n > 0 {
+		summary.MeanAbnormal, summary.StdAbnormal = core.CalculateMeanStd(abnormal)
+		summary.HitRate = hits / float64(n)
+		summary.MeanContinuation = continuation / float64(n)
+		if n > 1 && summary.StdAbnormal > 1e-12 { // Not for numerically constant returns
+			// Standard error from the sample std (population std * sqrt(n/(n-1)))
+			summary.TStat = summary.MeanAbnormal / (summary.StdAbnormal / math.Sqrt(float64(n-1)))
+		}
+	}
+	return summary
+}
+
+// -----------------------------------------------------------------------------
+
+// severityLevel returns the index of the highest severity bound reached by a
+// volume anomaly ratio (-1 = not an anomaly).
+func (s *EventStudyService) severityLevel(ratio float64) int {
+	level := -1
+	for i, bound := range s.Severities {
+		if ratio >= bound {
+			level = i
+		}
+	}
+	return level
+}
+
+// -----------------------------------------------------------------------------
+
+// severityLabel names a severity bucket ("2-3x", the last one "5x+").
+func (s *EventStudyService) severityLabel(level int) string {
+	if level == len(s.Severities)-1 {
+		return fmt.Sprintf("%gx+", s.Severities[level])
+	}
+	return fmt.Sprintf("%g-%gx", s.Severities[level], s.Severities[level+1])
+}
@@ -0,0 +1,236 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+func newTestEventStudy(t *testing.T, model string) *EventStudyService {
+	t.Helper()
+	cfg := &models.MConfig{}
+	cfg.EventStudy.Horizons = []int{1, 2, 3}
+	cfg.EventStudy.Model = model
+	return NewEventStudyService(cfg, nil, logger.NewLogger(cfg, "test"))
+}
+
+// studyCandle is a closed 5m candle of AAPL with a benchmark return of 0.1%.
+func studyCandle(start int64, close, ratio float64) models.MAggregation {
+	return models.MAggregation{
+		Symbol: "AAPL", WindowName: "5m", StartTime: start, EndTime: start + 300,
+		Close: close, VolumeAnomalyRatio: ratio, PricePercentChange: 0.01,
+		Benchmark: "SPY", BenchmarkReturn: 0.001, Beta: 2, IsClosed: true,
+	}
+}
+
+func TestEventStudyContiguousHorizons(t *testing.T) {
+	morning := nyseTime(t, 3, 12, 10, 0)
+	lastBar := nyseTime(t, 3, 12, 15, 50)
+	nextOpen := nyseTime(t, 3, 13, 9, 30)
+	friday := nyseTime(t, 3, 15, 15, 50)
+	monday := nyseTime(t, 3, 18, 9, 30)
+
+	tests := []struct {
+		name   string
+		starts []int64 // The event is the first candle
+		bars   []int   // Forward horizons expected
+		gaps   int
+	}{
+		{"contiguous", []int64{morning, morning + 300, morning + 600, morning + 900}, []int{1, 2, 3}, 0},
+		{"not reached yet", []int64{morning, morning + 300}, []int{1}, 0},
+		{"missing candle", []int64{morning, morning + 300, morning + 900, morning + 1200}, []int{1}, 2},
+		{"missing first candle", []int64{morning, morning + 600, morning + 900, morning + 1200}, nil, 3},
+		{"overnight", []int64{lastBar, lastBar + 300, nextOpen, nextOpen + 300}, []int{1, 2, 3}, 0},
+		{"weekend", []int64{friday, friday + 300, monday, monday + 300}, []int{1, 2, 3}, 0},
+		{"missing open after the close", []int64{lastBar, lastBar + 300, nextOpen + 300, nextOpen + 600}, []int{1}, 2},
+	}
+
+	for _, tt := range tests {
+		s := newTestEventStudy(t, utils.EventStudyModelMarket)
+		series := []models.MAggregation{studyCandle(tt.starts[0], 100, 4)}
+		for i, start := range tt.starts[1:] {
+			series = append(series, studyCandle(start, 100+float64(i+1), 1))
+		}
+
+		events, gaps := s.symbolEvents(series)
+		if len(events) != 1 || gaps != tt.gaps {
+			t.Errorf("%s: %d event(s), %d gap horizon(s), want 1 and %d", tt.name, len(events), gaps, tt.gaps)
+			continue
+		}
+		var bars []int
+		for _, f := range events[0].Forward {
+			bars = append(bars, f.Bars)
+		}
+		if len(bars) != len(tt.bars) {
+			t.Errorf("%s: horizons %v, want %v", tt.name, bars, tt.bars)
+			continue
+		}
+		for i := range bars {
+			if bars[i] != tt.bars[i] {
+				t.Errorf("%s: horizons %v, want %v", tt.name, bars, tt.bars)
+				break
+			}
+		}
+	}
+}
+
+func TestEventStudyForwardReturns(t *testing.T) {
+	start := nyseTime(t, 3, 12, 10, 0)
+	series := []models.MAggregation{
+		studyCandle(start, 100, 3.5),
+		studyCandle(start+300, 101, 1),
+		studyCandle(start+600, 102, 1),
+		studyCandle(start+900, 99, 1),
+	}
+	bench := []float64{0.001, math.Pow(1.001, 2) - 1, math.Pow(1.001, 3) - 1}
+
+	for _, model := range []string{utils.EventStudyModelMarket, utils.EventStudyModelBeta} {
+		s := newTestEventStudy(t, model)
+		events, _ := s.symbolEvents(series)
+		if len(events) != 1 || events[0].Severity != "3-5x" {
+			t.Fatalf("%s: events %+v", model, events)
+		}
+
+		beta := 1.0
+		if model == utils.EventStudyModelBeta {
+			beta = 2
+		}
+		for i, f := range events[0].Forward {
+			ret := series[i+1].Close/100 - 1
+			if !f.HasBenchmark || math.Abs(f.Return-ret) > 1e-12 || math.Abs(f.BenchmarkReturn-bench[i]) > 1e-12 ||
+				math.Abs(f.AbnormalReturn-(ret-beta*bench[i])) > 1e-12 {
+				t.Errorf("%s: horizon %d = %+v", model, f.Bars, f)
+			}
+		}
+	}
+
+	// A candle without a benchmark leaves the longer horizons without abnormal return
+	series[2].Benchmark = ""
+	events, _ := newTestEventStudy(t, utils.EventStudyModelMarket).symbolEvents(series)
+	if f := events[0].Forward; !f[0].HasBenchmark || f[1].HasBenchmark || f[2].HasBenchmark || f[2].AbnormalReturn != 0 {
+		t.Errorf("forward without benchmark = %+v", f)
+	}
+}
+
+func TestSummarizeHorizon(t *testing.T) {
+	event := func(ret float64, abnormal float64) models.MAnomalyEvent {
+		return models.MAnomalyEvent{Return: ret, Forward: []models.MEventForwardReturn{
+			{Bars: 1, Return: abnormal + 0.001, BenchmarkReturn: 0.001, AbnormalReturn: abnormal, HasBenchmark: true},
+		}}
+	}
+	events := []models.MAnomalyEvent{event(0.01, 0.01), event(0.02, -0.02), event(-0.01, 0.03)}
+
+	got := summarizeHorizon(events, 1)
+	mean := (0.01 - 0.02 + 0.03) / 3
+	std := math.Sqrt((math.Pow(0.01-mean, 2) + math.Pow(-0.02-mean, 2) + math.Pow(0.03-mean, 2)) / 3)
+
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"events", float64(got.Events), 3},
+		{"abnormal events", float64(got.AbnormalEvents), 3},
+		{"mean return", got.MeanReturn, mean + 0.001},
+		{"median return", got.MedianReturn, 0.011},
+		{"mean abnormal", got.MeanAbnormal, mean},
+		{"std abnormal", got.StdAbnormal, std},
+		{"t-stat", got.TStat, mean / (std / math.Sqrt(2))},
+		{"hit rate", got.HitRate, 2.0 / 3},
+		{"continuation", got.MeanContinuation, (0.01 - 0.02 - 0.03) / 3},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-12 {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if empty := summarizeHorizon(events, 3); empty.Events != 0 || empty.TStat != 0 {
+		t.Errorf("horizon without returns = %+v", empty)
+	}
+	single := summarizeHorizon(events[:1], 1)
+	if single.TStat != 0 || single.HitRate != 1 {
+		t.Errorf("single event = %+v", single)
+	}
+}
+
+func TestEventStudyLookbackWithinRetention(t *testing.T) {
+	tests := []struct {
+		lookback, retention, want int
+	}{
+		{0, 7, 7},   // Default: everything stored
+		{3, 7, 3},   // Shorter lookback kept
+		{30, 7, 7},  // Capped: older aggregations are purged
+		{30, 0, 30}, // Unknown retention
+	}
+	for _, tt := range tests {
+		cfg := &models.MConfig{}
+		cfg.EventStudy.LookbackDays = tt.lookback
+		cfg.DataSource.DataRetentionDays = tt.retention
+		if got := NewEventStudyService(cfg, nil, logger.NewLogger(cfg, "test")).LookbackDays; got != tt.want {
+			t.Errorf("lookback %d, retention %d: LookbackDays = %d, want %d", tt.lookback, tt.retention, got, tt.want)
+		}
+	}
+}
+
+func TestEventStudySeverity(t *testing.T) {
+	s := newTestEventStudy(t, "")
+
+	tests := []struct {
+		ratio float64
+		label string
+	}{
+		{1.9, ""},
+		{2, "2-3x"},
+		{4.99, "3-5x"},
+		{5, "5x+"},
+		{40, "5x+"},
+	}
+	for _, tt := range tests {
+		level := s.severityLevel(tt.ratio)
+		label := ""
+		if level >= 0 {
+			label = s.severityLabel(level)
+		}
+		if label != tt.label {
+			t.Errorf("ratio %v: severity %q, want %q", tt.ratio, label, tt.label)
+		}
+	}
+}
+
+func TestEventStudyGroups(t *testing.T) {
+	s := newTestEventStudy(t, "")
+	events := []models.MAnomalyEvent{
+		{Symbol: "AAPL", WindowName: "5m", Severity: "2-3x", StartTime: 300, VolumeAnomalyRatio: 2},
+		{Symbol: "MSFT", WindowName: "5m", Severity: "5x+", StartTime: 100, VolumeAnomalyRatio: 6},
+		{Symbol: "AAPL", WindowName: "5m", Severity: "2-3x", StartTime: 200, VolumeAnomalyRatio: 2.5},
+	}
+
+	type want struct {
+		events   int
+		from, to int64
+		ratio    float64
+	}
+	expected := map[[2]string]want{
+		{"*", "*"}:       {3, 100, 300, 3.5},
+		{"*", "2-3x"}:    {2, 200, 300, 2.25},
+		{"*", "5x+"}:     {1, 100, 100, 6},
+		{"AAPL", "*"}:    {2, 200, 300, 2.25},
+		{"AAPL", "2-3x"}: {2, 200, 300, 2.25},
+		{"MSFT", "*"}:    {1, 100, 100, 6},
+		{"MSFT", "5x+"}:  {1, 100, 100, 6},
+	}
+
+	groups := s.groups(events)
+	if len(groups) != len(expected) || groups[0].Symbol != "*" || groups[0].Severity != "*" {
+		t.Fatalf("%d group(s), first %s/%s", len(groups), groups[0].Symbol, groups[0].Severity)
+	}
+	for _, g := range groups {
+		w, ok := expected[[2]string{g.Symbol, g.Severity}]
+		if !ok || g.Events != w.events || g.From != w.from || g.To != w.to || math.Abs(g.MeanRatio-w.ratio) > 1e-12 {
+			t.Errorf("group %s/%s = %d events %d..%d ratio %v", g.Symbol, g.Severity, g.Events, g.From, g.To, g.MeanRatio)
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// LeadLagService measures which symbol of a pair moves first: the lagged
+// cross-correlation of their window returns over the rolling MemoryManager
+// history, the lag where it peaks, and how stable that lag is across
+// sub-periods of the lookback. Pairs are configured explicitly or as every
+// pair within a watchlist.
+// -----------------------------------------------------------------------------
+
+type LeadLagService struct {
+	Config *models.MConfig
+	Logger *logger.Logger
+	Memory *utils.MemoryManager
+	Spec   utils.WindowSpec
+
+	MaxLag          int
+	Lookback        int
+	Segments        int
+	MinObservations int
+
+	closeDelay int64 // Seconds after its end a window is closed by the clock
+	pairs      [][2]string
+	results    map[string]models.MLeadLagResult // "A|B" -> result
+	mu         sync.RWMutex
+}
+
+// -----------------------------------------------------------------------------
+
+func NewLeadLagService(cfg *models.MConfig, memManager *utils.MemoryManager, log *logger.Logger) *LeadLagService {
+	lc := cfg.LeadLag
+
+	s := &LeadLagService{
+		Config:          cfg,
+		Logger:          log,
+		Memory:          memManager,
+		MaxLag:          lc.MaxLag,
+		Lookback:        lc.Lookback,
+		Segments:        lc.Segments,
+		MinObservations: lc.MinObservations,
+		closeDelay:      int64(cfg.DataSource.UpdateIntervalSeconds),
+		results:         make(map[string]models.MLeadLagResult),
+	}
+	if s.MaxLag <= 0 {
+		s.MaxLag = utils.DefaultLeadLagMaxLag
+	}
+	if s.Lookback <= 0 {
+		s.Lookback = utils.DefaultLeadLagLookback
+	}
+	if s.Segments <= 0 {
+		s.Segments = utils.DefaultLeadLagSegments
+	}
+	if s.MinObservations <= 0 {
+		s.MinObservations = utils.DefaultLeadLagMinObservations
+	}
+
+	window := lc.Window
+	if window == "" && len(cfg.WindowsAgg) > 0 {
+		window = cfg.WindowsAgg[0]
+	}
+	s.Spec, _ = utils.ParseWindow(window)
+
+	// Explicit pairs first, then every pair of each watchlist (no duplicates)
+	seen := make(map[string]bool)
+	addPair := func(a, b string) {
+		if a == b || seen[a+"|"+b] || seen[b+"|"+a] {
+			return
+		}
+		seen[a+"|"+b] = true
+		s.pairs = append(s.pairs, [2]string{a, b})
+	}
+	for _, p := range lc.Pairs {
+		addPair(p.A, p.B)
+	}
+	for _, name := range lc.Watchlists {
+		symbols := append([]string(nil), cfg.Alerts.Watchlists[name]...)
+		sort.Strings(symbols)
+		for i := range symbols {
+			for j := i + 1; j < len(symbols); j++ {
+				addPair(symbols[i], symbols[j])
+			}
+		}
+	}
+	return s
+}
+
+// -----------------------------------------------------------------------------
+
+// Refresh recomputes every pair when the service window is among the given
+// windows (always when empty). s may be nil (disabled).
+func (s *LeadLagService) Refresh(windows []string) {
+	if s == nil {
+		return
+	}
+	if len(windows) > 0 {
+		found := false
+		for _, w := range windows {
+			found = found || w == s.Spec.Name
+		}
+		if !found {
+			return
+		}
+	}
+
+	// Window returns of every symbol of a pair, keyed by window start
+	now := time.Now().UTC().Unix()
+	returns := make(map[string]map[int64]float64)
+	for _, pair := range s.pairs {
+		for _, sym := range pair {
+			if _, ok := returns[sym]; ok {
+				continue
+			}
+			if prices := s.Memory.GetHistory(sym); len(prices) > 0 {
+				returns[sym] = s.windowReturns(sym, prices, now)
+			}
+		}
+	}
+
+	results := make(map[string]models.MLeadLagResult, len(s.pairs))
+	for _, pair := range s.pairs {
+		if result, ok := s.computePair(pair[0], pair[1], returns[pair[0]], returns[pair[1]], now); ok {
+			results[pair[0]+"|"+pair[1]] = result
+		}
+	}
+
+	s.mu.Lock()
+	s.results = results
+	s.mu.Unlock()
+	s.Logger.Info("Lead-lag: %d of %d pair(s) estimated on %s", len(results), len(s.pairs), s.Spec.Name)
+}
+
+// -----------------------------------------------------------------------------
+
+// windowReturns returns the log returns of the closed windows of a symbol,
+// keyed by window start. The window still open at timestamp (the wall clock)
+// is left out, on the symbol's own calendar.
+func (s *LeadLagService) windowReturns(symbol string, prices []models.MStockPrice, timestamp int64) map[int64]float64 {
+	calendar := utils.GetCalendar(symbol)
+	openStart, _ := s.Spec.Bounds(timestamp-s.closeDelay, calendar)
+	closes := windowCloses(prices, s.Spec, calendar, openStart)
+
+	returns := make(map[int64]float64, len(closes))
+	for i := 1; i < len(closes); i++ {
+		returns[closes[i].start] = core.CalculateLogReturn(closes[i].price, closes[i-1].price)
+	}
+	return returns
+}
+
+// -----------------------------------------------------------------------------
+
+// Results returns the estimated pairs involving a symbol ("" = all), strongest
+// lead first (largest edge over the contemporaneous correlation).
+func (s *LeadLagService) Results(symbol string) []models.MLeadLagResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]models.MLeadLagResult, 0, len(s.results))
+	for _, r := range s.results {
+		if symbol == "" || r.SymbolA == symbol || r.SymbolB == symbol {
+			result = append(result, r)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Edge != result[j].Edge {
+			return result[i].Edge > result[j].Edge
+		}
+		return result[i].SymbolA+"|"+result[i].SymbolB < result[j].SymbolA+"|"+result[j].SymbolB
+	})
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// Pair returns the result of a pair, in either symbol order.
+func (s *LeadLagService) Pair(a, b string) (models.MLeadLagResult, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if r, ok := s.results[a+"|"+b]; ok {
+		return r, true
+	}
+	r, ok := s.results[b+"|"+a]
+	return r, ok
+}
+
+// -----------------------------------------------------------------------------
+
+// computePair estimates the cross-correlation function of a pair over the
+// last Lookback window starts and the best lag of each sub-period.
+func (s *LeadLagService) computePair(a, b string, ra, rb map[int64]float64, now int64) (models.MLeadLagResult, bool) {
+	if len(ra) == 0 || len(rb) == 0 {
+		return models.MLeadLagResult{}, false
+	}
+
+	// Shared timeline: lags are counted in windows of the union of both histories
+	timeline := make(map[int64]struct{}, len(ra)+len(rb))
+	for ts := range ra {
+		timeline[ts] = struct{}{}
+	}
+	for ts := range rb {
+		timeline[ts] = struct{}{}
+	}
+	starts := make([]int64, 0, len(timeline))
+	for ts := range timeline {
+		starts = append(starts, ts)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+	if len(starts) > s.Lookback {
+		starts = starts[len(starts)-s.Lookback:]
+	}
+
+	x := make([]float64, len(starts))
+	y := make([]float64, len(starts))
+	for t, ts := range starts {
+		x[t], y[t] = math.NaN(), math.NaN()
+		if v, ok := ra[ts]; ok {
+			x[t] = v
+		}
+		if v, ok := rb[ts]; ok {
+			y[t] = v
+		}
+	}
+
+	result := models.MLeadLagResult{
+		SymbolA:    a,
+		SymbolB:    b,
+		WindowName: s.Spec.Name,
+		Timestamp:  now,
+	}
+
+	ccf := make([]models.MLagCorrelation, 0, 2*s.MaxLag+1)
+	for lag := -s.MaxLag; lag <= s.MaxLag; lag++ {
+		corr, n := laggedCorrelation(x, y, lag, 0, len(starts))
+		ccf = append(ccf, models.MLagCorrelation{Lag: lag, Correlation: corr, Observations: n})
+		if lag == 0 {
+			result.Contemporaneous = corr
+		}
+	}
+	result.CrossCorrelation = ccf
+
+	best, ok := bestLag(ccf, s.MinObservations)
+	if !ok {
+		return models.MLeadLagResult{}, false
+	}
+	result.LagBars = best.Lag
+	result.LagSeconds = int64(best.Lag) * s.Spec.Seconds
+	result.Correlation = best.Correlation
+	result.Observations = best.Observations
+	result.Edge = math.Abs(best.Correlation) - math.Abs(result.Contemporaneous)
+	switch {
+	case best.Lag > 0:
+		result.Leader, result.Follower = a, b
+	case best.Lag < 0:
+		result.Leader, result.Follower = b, a
+	}
+
+	// Stability: best lag of each sub-period
+	agree := 0
+	for seg := 0; seg < s.Segments; seg++ {
+		from := seg * len(starts) / s.Segments
+		to := (seg + 1) * len(starts) / s.Segments
+		segment := make([]models.MLagCorrelation, 0, len(ccf))
+		for lag := -s.MaxLag; lag <= s.MaxLag; lag++ {
+			corr, n := laggedCorrelation(x, y, lag, from, to)
+			segment = append(segment, models.MLagCorrelation{Lag: lag, Correlation: corr, Observations: n})
+		}
+		if segBest, ok := bestLag(segment, utils.LeadLagMinSegmentObservations); ok {
+			result.SegmentLags = append(result.SegmentLags, segBest.Lag)
+			if segBest.Lag == best.Lag {
+				agree++
+			}
+		}
+	}
+	if len(result.SegmentLags) > 0 {
+		result.Stability = float64(agree) / float64(len(result.SegmentLags))
+	}
+	return result, true
+}
+
+// -----------------------------------------------------------------------------
+
+// bestLag returns the lag with the largest absolute correlation among those
+// with enough observations; ties go to the shortest lag.
+func bestLag(ccf []models.MLagCorrelation, minObservations int) (models.MLagCorrelation, bool) {
+	var best models.MLagCorrelation
+	found := false
+	for _, c := range ccf {
+		if c.Observations < minObservations {
+			continue
+		}
+		if !found || math.Abs(c.Correlation) > math.Abs(best.Correlation) ||
+			(math.Abs(c.Correlation) == math.Abs(best.Correlation) && absInt(c.Lag) < absInt(best.Lag)) {
+			best, found = c, true
+		}
+	}
+	return best, found
+}
+
+// -----------------------------------------------------------------------------
+
+// laggedCorrelation computes the correlation of x[t] with y[t+lag] for t and
+// t+lag in [from, to), using only the positions where both have a value.
+func laggedCorrelation(x, y []float64, lag, from, to int) (float64, int) {
+	var n, sumX, sumY, sumXY, sumX2, sumY2 float64
+	for t := from; t < to; t++ {
+		u := t + lag
+		if u < from || u >= to || math.IsNaN(x[t]) || math.IsNaN(y[u]) {
+			continue
+		}
+		n++
+		sumX += x[t]
+		sumY += y[u]
+		sumXY += x[t] * y[u]
+		sumX2 += x[t] * x[t]
+		sumY2 += y[u] * y[u]
+	}
+	return core.CalculateCorrelationFromSums(n, sumX, sumY, sumXY, sumX2, sumY2), int(n)
+}
+
+// -----------------------------------------------------------------------------
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
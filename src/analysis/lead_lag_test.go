@@ -0,0 +1,175 @@
+package analysis
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+func newTestLeadLag(window string) *LeadLagService {
+	cfg := &models.MConfig{WindowsAgg: []string{window}}
+	cfg.DataSource.UpdateIntervalSeconds = 60
+	cfg.LeadLag.MaxLag = 3
+	cfg.LeadLag.Lookback = 200
+	cfg.LeadLag.Segments = 4
+	cfg.LeadLag.MinObservations = 30
+	return NewLeadLagService(cfg, nil, logger.NewLogger(cfg, "test"))
+}
+
+func TestLaggedCorrelation(t *testing.T) {
+	nan := math.NaN()
+	x := []float64{1, 2, 3, 5, 4, 6}
+	follower := []float64{0, 1, 2, 3, 5, 4} // x one position later
+	leader := []float64{2, 3, 5, 4, 6, 0}   // x one position earlier
+
+	tests := []struct {
+		name     string
+		x, y     []float64
+		lag      int
+		from, to int
+		corr     float64
+		n        int
+	}{
+		{"same series", x, x, 0, 0, 6, 1, 6},
+		{"y follows x: positive lag", x, follower, 1, 0, 6, 1, 5},
+		{"y leads x: negative lag", x, leader, -1, 0, 6, 1, 5},
+		{"opposite moves", x, []float64{-1, -2, -3, -5, -4, -6}, 0, 0, 6, -1, 6},
+		{"missing values skipped", x, []float64{1, nan, 3, 5, nan, 6}, 0, 0, 6, 1, 4},
+		{"lag stays inside the segment", x, follower, 1, 1, 4, 1, 2},
+		{"lag beyond the segment", x, follower, 3, 0, 3, 0, 0},
+	}
+
+	for _, tt := range tests {
+		corr, n := laggedCorrelation(tt.x, tt.y, tt.lag, tt.from, tt.to)
+		if n != tt.n || math.Abs(corr-tt.corr) > 0.05 {
+			t.Errorf("%s = (%v, %d), want (%v, %d)", tt.name, corr, n, tt.corr, tt.n)
+		}
+	}
+}
+
+func TestBestLag(t *testing.T) {
+	c := func(lag int, corr float64, n int) models.MLagCorrelation {
+		return models.MLagCorrelation{Lag: lag, Correlation: corr, Observations: n}
+	}
+
+	tests := []struct {
+		name string
+		ccf  []models.MLagCorrelation
+		lag  int
+		ok   bool
+	}{
+		{"largest correlation", []models.MLagCorrelation{c(-1, 0.2, 50), c(0, 0.3, 50), c(1, 0.6, 50)}, 1, true},
+		{"absolute value", []models.MLagCorrelation{c(-2, -0.7, 50), c(0, 0.3, 50), c(1, 0.6, 50)}, -2, true},
+		{"too few observations", []models.MLagCorrelation{c(-1, 0.2, 50), c(2, 0.9, 10)}, -1, true},
+		{"tie goes to the shortest lag", []models.MLagCorrelation{c(-2, 0.5, 50), c(1, -0.5, 50), c(2, 0.5, 50)}, 1, true},
+		{"nothing usable", []models.MLagCorrelation{c(0, 0.9, 5)}, 0, false},
+	}
+
+	for _, tt := range tests {
+		best, ok := bestLag(tt.ccf, 30)
+		if ok != tt.ok || (ok && best.Lag != tt.lag) {
+			t.Errorf("%s = %+v %v, want lag %d %v", tt.name, best, ok, tt.lag, tt.ok)
+		}
+	}
+}
+
+// leadLagReturns returns the returns of B following those of A lag windows later
+// (B leads when lag < 0), over n windows of 5 minutes.
+func leadLagReturns(n int, lag func(i int) int, seed int64) (map[int64]float64, map[int64]float64) {
+	rng := rand.New(rand.NewSource(seed))
+	a := make([]float64, n)
+	for i := range a {
+		a[i] = rng.NormFloat64()
+	}
+	ra, rb := make(map[int64]float64, n), make(map[int64]float64, n)
+	for i := range a {
+		ra[int64(i*300)] = a[i]
+		if j := i - lag(i); j >= 0 && j < n {
+			rb[int64(i*300)] = a[j] + 0.3*rng.NormFloat64()
+		} else {
+			rb[int64(i*300)] = rng.NormFloat64()
+		}
+	}
+	return ra, rb
+}
+
+func TestLeadLagComputePair(t *testing.T) {
+	s := newTestLeadLag("5m")
+	constant := func(lag int) func(int) int { return func(int) int { return lag } }
+
+	tests := []struct {
+		name      string
+		lag       func(i int) int
+		lagBars   int
+		leader    string
+		segments  []int
+		stability float64
+	}{
+		{"A leads by 2", constant(2), 2, "AAA", []int{2, 2, 2, 2}, 1},
+		{"B leads by 1", constant(-1), -1, "BBB", []int{-1, -1, -1, -1}, 1},
+		{"together", constant(0), 0, "", []int{0, 0, 0, 0}, 1},
+		// The lead flips after three quarters of the lookback
+		{"unstable lead", func(i int) int {
+			if i < 150 {
+				return 2
+			}
+			return -2
+		}, 2, "AAA", []int{2, 2, 2, -2}, 0.75},
+	}
+
+	for _, tt := range tests {
+		ra, rb := leadLagReturns(200, tt.lag, 7)
+		result, ok := s.computePair("AAA", "BBB", ra, rb, 1000)
+		if !ok {
+			t.Fatalf("%s: no estimate", tt.name)
+		}
+		if result.LagBars != tt.lagBars || result.LagSeconds != int64(tt.lagBars)*300 || result.Leader != tt.leader {
+			t.Errorf("%s: lag %d (%ds) leader %q, want %d %q", tt.name, result.LagBars, result.LagSeconds, result.Leader, tt.lagBars, tt.leader)
+		}
+		if len(result.CrossCorrelation) != 7 || result.Correlation < 0.5 {
+			t.Errorf("%s: ccf %+v", tt.name, result.CrossCorrelation)
+		}
+		if !slices.Equal(result.SegmentLags, tt.segments) || result.Stability != tt.stability {
+			t.Errorf("%s: segments %v stability %v, want %v %v", tt.name, result.SegmentLags, result.Stability, tt.segments, tt.stability)
+		}
+	}
+
+	if _, ok := s.computePair("AAA", "BBB", map[int64]float64{}, map[int64]float64{300: 1}, 1000); ok {
+		t.Error("estimate without returns of A")
+	}
+}
+
+func TestLeadLagWindowReturns(t *testing.T) {
+	s := newTestLeadLag("1h@session")
+	now := nyseTime(t, 3, 12, 14, 0) // Hong Kong has closed, New York is in its 13:30 window
+
+	// New York: one point per session-aligned hour since the open
+	var newYork []models.MStockPrice
+	for h := 0; h < 5; h++ {
+		newYork = append(newYork, models.MStockPrice{Price: 100 + float64(h), Timestamp: nyseTime(t, 3, 12, 9+h, 40)})
+	}
+	returns := s.windowReturns("AAPL", newYork, now)
+	if len(returns) != 3 {
+		t.Errorf("AAPL returns = %v, want 10:30-12:30 (13:30 is still open)", returns)
+	}
+	if r, ok := returns[nyseTime(t, 3, 12, 12, 30)]; !ok || math.Abs(r-math.Log(103.0/102)) > 1e-12 {
+		t.Errorf("AAPL 12:30 return = %v", r)
+	}
+
+	// Hong Kong: its last window of the day is closed, whatever New York does
+	open, close, _ := utils.GetCalendar("0005.HK").SessionBounds(time.Unix(nyseTime(t, 3, 11, 23, 0), 0))
+	var hongKong []models.MStockPrice
+	for ts := open.Unix() + 600; ts < close.Unix(); ts += 3600 {
+		hongKong = append(hongKong, models.MStockPrice{Price: 50, Timestamp: ts})
+	}
+	returns = s.windowReturns("0005.HK", hongKong, now)
+	if len(returns) != len(hongKong)-1 {
+		t.Errorf("0005.HK returns = %v, want %d", returns, len(hongKong)-1)
+	}
+}
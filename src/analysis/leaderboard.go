@@ -0,0 +1,394 @@
+package analysis
+
+import (
+	"slices"
+	"sort"
+	"sync"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// LeaderboardService keeps ranked leaderboards per window (top gainers, losers,
+// volume anomalies, price/volume correlation extremes) over the latest candle
+// of every symbol. Rankings are updated in place as candles change; each update
+// cycle produces the diff of the top entries since the previous cycle.
+// -----------------------------------------------------------------------------
+
+type LeaderboardService struct {
+	Config *models.MConfig
+	Logger *logger.Logger
+
+	Size int
+
+	windows    map[string]utils.WindowSpec
+	closeDelay int64                                            // Seconds after its end a candle is closed by the clock
+	candles    map[string]map[string]models.MAggregation        // window -> symbol -> latest candle
+	boards     map[string]map[string]*rankedBoard               // window -> board -> ranking
+	published  map[string]map[string][]models.MLeaderboardEntry // window -> board -> top entries last pushed
+	sequence   int64                                            // Last message sent
+	timestamp  int64                                            // Update of the published entries
+	mu         sync.RWMutex
+}
+
+// leaderboardSpec defines a board: the ranked value, its order and which candles qualify
+type leaderboardSpec struct {
+	name      string
+	value     func(c models.MAggregation) float64
+	ascending bool
+	eligible  func(v float64) bool
+}
+
+var leaderboardSpecs = []leaderboardSpec{
+	{
+		name:     utils.LeaderboardGainers,
+		value:    func(c models.MAggregation) float64 { return c.PricePercentChange },
+		eligible: func(v float64) bool { return v > 0 },
+	},
+	{
+		name:      utils.LeaderboardLosers,
+		value:     func(c models.MAggregation) float64 { return c.PricePercentChange },
+		ascending: true,
+		eligible:  func(v float64) bool { return v < 0 },
+	},
+	{
+		name:     utils.LeaderboardVolumeAnomalies,
+		value:    func(c models.MAggregation) float64 { return c.VolumeAnomalyRatio },
+		eligible: func(v float64) bool { return v > 0 },
+	},
+	{
+		name:     utils.LeaderboardCorrelationHigh,
+		value:    func(c models.MAggregation) float64 { return c.PriceVolumeCorrelation },
+		eligible: func(v float64) bool { return v > 0 },
+	},
+	{
+		name:      utils.LeaderboardCorrelationLow,
+		value:     func(c models.MAggregation) float64 { return c.PriceVolumeCorrelation },
+		ascending: true,
+		eligible:  func(v float64) bool { return v < 0 },
+	},
+}
+
+// rankedBoard is a sorted ranking with O(log n) lookup of a symbol's position
+type rankedBoard struct {
+	ascending bool
+	entries   []rankedEntry      // Best first
+	values    map[string]float64 // symbol -> ranked value
+}
+
+type rankedEntry struct {
+	symbol string
+	value  float64
+}
+
+// -----------------------------------------------------------------------------
+
+func NewLeaderboardService(cfg *models.MConfig, log *logger.Logger) *LeaderboardService {
+	size := cfg.Leaderboards.Size
+	if size <= 0 {
+		size = utils.DefaultLeaderboardSize
+	}
+
+	return &LeaderboardService{
+		Config:     cfg,
+		Logger:     log,
+		Size:       size,
+		windows:    utils.ParseWindows(cfg.WindowsAgg),
+		closeDelay: int64(cfg.DataSource.UpdateIntervalSeconds),
+		candles:    make(map[string]map[string]models.MAggregation),
+		boards:     make(map[string]map[string]*rankedBoard),
+		published:  make(map[string]map[string][]models.MLeaderboardEntry),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Seed ranks the latest historical candle of every symbol/window (symbol ->
+// window -> candles, oldest first) without producing a message. timestamp is
+// the wall clock.
+func (l *LeaderboardService) Seed(candles map[string]map[string][]models.MAggregation, timestamp int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, windows := range candles {
+		for _, list := range windows {
+			if len(list) > 0 {
+				l.observe(list[len(list)-1])
+			}
+		}
+	}
+	for window := range l.candles {
+		l.prune(window, timestamp)
+		for _, spec := range leaderboardSpecs {
+			l.setPublished(window, spec.name, l.board(window, spec).top(l.Size))
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Update folds an engine batch in and returns the diff of the top entries
+// since the previous cycle (nil when no leaderboard changed). timestamp is the
+// wall clock.
+func (l *LeaderboardService) Update(batch models.MCandleBatch, timestamp int64) *models.MLeaderboardMessage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Closed candles first: the open candle that follows replaces them
+	touched := make(map[string]bool)
+	for _, source := range []map[string]map[string][]models.MAggregation{batch.Closed, batch.Updated} {
+		for _, windows := range source {
+			for window, list := range windows {
+				for _, c := range list {
+					l.observe(c)
+				}
+				touched[window] = true
+			}
+		}
+	}
+
+	var changes []models.MLeaderboardChange
+	for _, window := range l.Config.WindowsAgg {
+		if !touched[window] {
+			continue
+		}
+		l.prune(window, timestamp)
+
+		for _, spec := range leaderboardSpecs {
+			top := l.board(window, spec).top(l.Size)
+			updated, removed := diffLeaderboard(l.published[window][spec.name], top)
+			l.setPublished(window, spec.name, top)
+			if len(updated) == 0 && len(removed) == 0 {
+				continue
+			}
+			changes = append(changes, models.MLeaderboardChange{
+				Window:  window,
+				Board:   spec.name,
+				Updated: updated,
+				Removed: removed,
+			})
+		}
+	}
+
+	l.timestamp = timestamp
+	if len(changes) == 0 {
+		return nil
+	}
+	l.sequence++
+	return &models.MLeaderboardMessage{
+		Type:      "LEADERBOARD",
+		Sequence:  l.sequence,
+		Changes:   changes,
+		Timestamp: timestamp,
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Snapshot returns the published leaderboards of a window ("" = all windows)
+// with the sequence of the last message they include.
+func (l *LeaderboardService) Snapshot(window string) models.MLeaderboardSnapshot {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	snapshot := models.MLeaderboardSnapshot{
+		Sequence:     l.sequence,
+		Timestamp:    l.timestamp,
+		Leaderboards: make(map[string]map[string][]models.MLeaderboardEntry),
+	}
+	for w, boards := range l.published {
+		if window != "" && w != window {
+			continue
+		}
+		copied := make(map[string][]models.MLeaderboardEntry, len(boards))
+		for name, entries := range boards {
+			copied[name] = append([]models.MLeaderboardEntry{}, entries...)
+		}
+		snapshot.Leaderboards[w] = copied
+	}
+	return snapshot
+}
+
+// -----------------------------------------------------------------------------
+
+// Ranking returns the first entries of a leaderboard beyond the pushed size
+// (limit <= 0 = all). False when the window or board is unknown.
+func (l *LeaderboardService) Ranking(window, board string, limit int) ([]models.MLeaderboardEntry, bool) {
+	if !slices.Contains(l.Config.WindowsAgg, window) {
+		return nil, false
+	}
+
+	for _, spec := range leaderboardSpecs {
+		if spec.name != board {
+			continue
+		}
+		l.mu.RLock()
+		defer l.mu.RUnlock()
+
+		b, ok := l.boards[window][board]
+		if !ok {
+			return []models.MLeaderboardEntry{}, true
+		}
+		return b.top(limit), true
+	}
+	return nil, false
+}
+
+// -----------------------------------------------------------------------------
+
+// observe records the latest candle of a symbol/window and re-ranks it on
+// every board (caller holds the lock).
+func (l *LeaderboardService) observe(c models.MAggregation) {
+	latest, ok := l.candles[c.WindowName]
+	if !ok {
+		latest = make(map[string]models.MAggregation)
+		l.candles[c.WindowName] = latest
+	}
+	if previous, ok := latest[c.Symbol]; ok && c.StartTime < previous.StartTime {
+		return // Late version of an older window
+	}
+	latest[c.Symbol] = c
+
+	for _, spec := range leaderboardSpecs {
+		b := l.board(c.WindowName, spec)
+		if v := spec.value(c); spec.eligible(v) {
+			b.set(c.Symbol, v)
+		} else {
+			b.remove(c.Symbol)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// prune drops the symbols whose latest candle is older than the previous
+// window of their own calendar, i.e. stale feeds (caller holds the lock).
+func (l *LeaderboardService) prune(window string, timestamp int64) {
+	latest := l.candles[window]
+	spec := l.windows[window]
+	asOf := timestamp - l.closeDelay // Candles ended before are closed by now
+
+	for symbol, c := range latest {
+		if spec.IsCurrent(c.EndTime, asOf, utils.GetCalendar(symbol)) {
+			continue
+		}
+		delete(latest, symbol)
+		for _, b := range l.boards[window] {
+			b.remove(symbol)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// board returns the ranking of a window/board, creating it (caller holds the lock).
+func (l *LeaderboardService) board(window string, spec leaderboardSpec) *rankedBoard {
+	boards, ok := l.boards[window]
+	if !ok {
+		boards = make(map[string]*rankedBoard)
+		l.boards[window] = boards
+	}
+	b, ok := boards[spec.name]
+	if !ok {
+		b = &rankedBoard{ascending: spec.ascending, values: make(map[string]float64)}
+		boards[spec.name] = b
+	}
+	return b
+}
+
+// -----------------------------------------------------------------------------
+
+func (l *LeaderboardService) setPublished(window, board string, entries []models.MLeaderboardEntry) {
+	if l.published[window] == nil {
+		l.published[window] = make(map[string][]models.MLeaderboardEntry)
+	}
+	l.published[window][board] = entries
+}
+
+// -----------------------------------------------------------------------------
+
+// before orders two entries (best first, ties by symbol).
+func (b *rankedBoard) before(x, y rankedEntry) bool {
+	if x.value != y.value {
+		if b.ascending {
+			return x.value < y.value
+		}
+		return x.value > y.value
+	}
+	return x.symbol < y.symbol
+}
+
+// -----------------------------------------------------------------------------
+
+// set moves a symbol to the position of its new value.
+func (b *rankedBoard) set(symbol string, value float64) {
+	if old, ok := b.values[symbol]; ok {
+		if old == value {
+			return
+		}
+		b.remove(symbol)
+	}
+
+	entry := rankedEntry{symbol: symbol, value: value}
+	i := sort.Search(len(b.entries), func(i int) bool { return !b.before(b.entries[i], entry) })
+	b.entries = append(b.entries, rankedEntry{})
+	copy(b.entries[i+1:], b.entries[i:])
+	b.entries[i] = entry
+	b.values[symbol] = value
+}
+
+// -----------------------------------------------------------------------------
+
+func (b *rankedBoard) remove(symbol string) {
+	value, ok := b.values[symbol]
+	if !ok {
+		return
+	}
+
+	entry := rankedEntry{symbol: symbol, value: value}
+	i := sort.Search(len(b.entries), func(i int) bool { return !b.before(b.entries[i], entry) })
+	if i < len(b.entries) && b.entries[i].symbol == symbol {
+		b.entries = append(b.entries[:i], b.entries[i+1:]...)
+	}
+	delete(b.values, symbol)
+}
+
+// -----------------------------------------------------------------------------
+
+// top returns the first n entries with their rank (n <= 0 = all).
+func (b *rankedBoard) top(n int) []models.MLeaderboardEntry {
+	if n <= 0 || n > len(b.entries) {
+		n = len(b.entries)
+	}
+	entries := make([]models.MLeaderboardEntry, n)
+	for i, e := range b.entries[:n] {
+		entries[i] = models.MLeaderboardEntry{Symbol: e.symbol, Rank: i + 1, Value: e.value}
+	}
+	return entries
+}
+
+// -----------------------------------------------------------------------------
+
+// diffLeaderboard returns the entries that are new or changed rank/value and
+// the symbols that left the top.
+func diffLeaderboard(previous, current []models.MLeaderboardEntry) (updated []models.MLeaderboardEntry, removed []string) {
+	before := make(map[string]models.MLeaderboardEntry, len(previous))
+	for _, e := range previous {
+		before[e.Symbol] = e
+	}
+
+	for _, e := range current {
+		if old, ok := before[e.Symbol]; !ok || old.Rank != e.Rank || old.Value != e.Value {
+			updated = append(updated, e)
+		}
+		delete(before, e.Symbol)
+	}
+	for _, e := range previous {
+		if _, ok := before[e.Symbol]; ok {
+			removed = append(removed, e.Symbol)
+		}
+	}
+	return updated, removed
+}
@@ -0,0 +1,150 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+func newTestLeaderboards(size int) *LeaderboardService {
+	cfg := &models.MConfig{WindowsAgg: []string{"5m", "session"}}
+	cfg.DataSource.UpdateIntervalSeconds = 60
+	cfg.Leaderboards.Size = size
+	return NewLeaderboardService(cfg, logger.NewLogger(cfg, "test"))
+}
+
+// boardSymbols returns the symbols of a published leaderboard in rank order.
+func boardSymbols(l *LeaderboardService, window, board string) []string {
+	var symbols []string
+	for _, e := range l.Snapshot(window).Leaderboards[window][board] {
+		symbols = append(symbols, e.Symbol)
+	}
+	return symbols
+}
+
+func TestDiffLeaderboard(t *testing.T) {
+	entry := func(symbol string, rank int, value float64) models.MLeaderboardEntry {
+		return models.MLeaderboardEntry{Symbol: symbol, Rank: rank, Value: value}
+	}
+
+	tests := []struct {
+		name     string
+		previous []models.MLeaderboardEntry
+		current  []models.MLeaderboardEntry
+		updated  []models.MLeaderboardEntry
+		removed  []string
+	}{
+		{"unchanged", []models.MLeaderboardEntry{entry("A", 1, 3), entry("B", 2, 2)}, []models.MLeaderboardEntry{entry("A", 1, 3), entry("B", 2, 2)}, nil, nil},
+		{"first push", nil, []models.MLeaderboardEntry{entry("A", 1, 3)}, []models.MLeaderboardEntry{entry("A", 1, 3)}, nil},
+		{"value change", []models.MLeaderboardEntry{entry("A", 1, 3)}, []models.MLeaderboardEntry{entry("A", 1, 4)}, []models.MLeaderboardEntry{entry("A", 1, 4)}, nil},
+		{"swap", []models.MLeaderboardEntry{entry("A", 1, 3), entry("B", 2, 2)}, []models.MLeaderboardEntry{entry("B", 1, 5), entry("A", 2, 3)},
+			[]models.MLeaderboardEntry{entry("B", 1, 5), entry("A", 2, 3)}, nil},
+		{"replaced", []models.MLeaderboardEntry{entry("A", 1, 3), entry("B", 2, 2)}, []models.MLeaderboardEntry{entry("A", 1, 3), entry("C", 2, 2.5)},
+			[]models.MLeaderboardEntry{entry("C", 2, 2.5)}, []string{"B"}},
+		{"emptied", []models.MLeaderboardEntry{entry("A", 1, 3), entry("B", 2, 2)}, nil, nil, []string{"A", "B"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, removed := diffLeaderboard(tt.previous, tt.current)
+			if !reflect.DeepEqual(updated, tt.updated) || !reflect.DeepEqual(removed, tt.removed) {
+				t.Errorf("diff = %v %v, want %v %v", updated, removed, tt.updated, tt.removed)
+			}
+		})
+	}
+}
+
+func TestLeaderboardUpdate(t *testing.T) {
+	l := newTestLeaderboards(2)
+	open := nyseTime(t, 3, 12, 10, 5)
+	c := func(symbol string, change float64) models.MAggregation {
+		return breadthCandle(symbol, "5m", open, open+300, change, 100)
+	}
+
+	m := l.Update(candleBatch(c("AAA", 0.01), c("BBB", 0.03), c("CCC", 0.02), c("DDD", -0.01)), open+60)
+	if m == nil || m.Sequence != 1 {
+		t.Fatalf("first message = %+v", m)
+	}
+	if got := boardSymbols(l, "5m", utils.LeaderboardGainers); !reflect.DeepEqual(got, []string{"BBB", "CCC"}) {
+		t.Errorf("gainers = %v", got)
+	}
+	if got := boardSymbols(l, "5m", utils.LeaderboardLosers); !reflect.DeepEqual(got, []string{"DDD"}) {
+		t.Errorf("losers = %v", got)
+	}
+
+	// Below the top: no message
+	if m := l.Update(candleBatch(c("AAA", 0.015)), open+120); m != nil {
+		t.Errorf("change below the top pushed %+v", m)
+	}
+
+	// AAA takes the lead, CCC leaves the top, DDD turns positive and leaves the losers
+	m = l.Update(candleBatch(c("AAA", 0.05), c("DDD", 0.001)), open+180)
+	if m == nil || m.Sequence != 2 || len(m.Changes) != 2 {
+		t.Fatalf("second message = %+v", m)
+	}
+	for _, change := range m.Changes {
+		switch change.Board {
+		case utils.LeaderboardGainers:
+			want := []models.MLeaderboardEntry{{Symbol: "AAA", Rank: 1, Value: 0.05}, {Symbol: "BBB", Rank: 2, Value: 0.03}}
+			if !reflect.DeepEqual(change.Updated, want) || !reflect.DeepEqual(change.Removed, []string{"CCC"}) {
+				t.Errorf("gainers change = %+v", change)
+			}
+		case utils.LeaderboardLosers:
+			if len(change.Updated) != 0 || !reflect.DeepEqual(change.Removed, []string{"DDD"}) {
+				t.Errorf("losers change = %+v", change)
+			}
+		default:
+			t.Errorf("unexpected change %+v", change)
+		}
+	}
+
+	// Ranking goes beyond the pushed size
+	if ranking, ok := l.Ranking("5m", utils.LeaderboardGainers, 0); !ok || len(ranking) != 4 || ranking[3].Symbol != "DDD" {
+		t.Errorf("Ranking = %+v %v", ranking, ok)
+	}
+	if _, ok := l.Ranking("1h", utils.LeaderboardGainers, 0); ok {
+		t.Error("Ranking of an unconfigured window")
+	}
+	if _, ok := l.Ranking("5m", "bogus", 0); ok {
+		t.Error("Ranking of an unknown board")
+	}
+}
+
+func TestLeaderboardPrune(t *testing.T) {
+	l := newTestLeaderboards(10)
+	open := nyseTime(t, 3, 12, 10, 5)
+
+	// A feed two windows behind is stale; the previous window is still current
+	previous := breadthCandle("AAA", "5m", open-300, open, 0.01, 100)
+	previous.IsClosed = true
+	stale := breadthCandle("BBB", "5m", open-600, open-300, 0.02, 100)
+	stale.IsClosed = true
+	l.Update(candleBatch(previous, stale, breadthCandle("CCC", "5m", open, open+300, 0.03, 100)), open+60)
+	if got := boardSymbols(l, "5m", utils.LeaderboardGainers); !reflect.DeepEqual(got, []string{"CCC", "AAA"}) {
+		t.Errorf("gainers = %v, want CCC and AAA", got)
+	}
+
+	// Sessions of the day are current on their own calendar, even when another
+	// exchange has opened since; MSFT has not traded today
+	l = newTestLeaderboards(10)
+	now := nyseTime(t, 3, 12, 14, 0)
+	newYork := breadthCandle("AAPL", "session", nyseTime(t, 3, 12, 9, 30), nyseTime(t, 3, 12, 16, 0), 0.01, 100)
+	yesterday := breadthCandle("MSFT", "session", nyseTime(t, 3, 11, 9, 30), nyseTime(t, 3, 11, 16, 0), 0.02, 100)
+	yesterday.IsClosed = true
+	hkOpen, hkClose, _ := utils.GetCalendar("0005.HK").SessionBounds(time.Unix(nyseTime(t, 3, 11, 23, 0), 0))
+	hongKong := breadthCandle("0005.HK", "session", hkOpen.Unix(), hkClose.Unix(), 0.03, 100)
+	hongKong.IsClosed = true
+
+	l.Seed(map[string]map[string][]models.MAggregation{
+		"AAPL":    {"session": {newYork}},
+		"MSFT":    {"session": {yesterday}},
+		"0005.HK": {"session": {hongKong}},
+	}, now)
+	if got := boardSymbols(l, "session", utils.LeaderboardGainers); !reflect.DeepEqual(got, []string{"0005.HK", "AAPL"}) {
+		t.Errorf("session gainers = %v, want 0005.HK and AAPL", got)
+	}
+}
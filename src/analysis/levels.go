@@ -0,0 +1,435 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// LevelService derives support and resistance levels of every symbol from the
+// MemoryManager history: swing highs/lows (a bar above/below its neighbours)
+// and high-volume nodes of the volume-by-price histogram. Candidates close to
+// each other are merged and the strongest levels are kept. Levels are rebuilt
+// periodically; in between, a closed candle crossing a level on elevated
+// volume is reported as a breakout or breakdown.
+// -----------------------------------------------------------------------------
+
+type LevelService struct {
+	Config *models.MConfig
+	Logger *logger.Logger
+	Memory *utils.MemoryManager
+
+	Interval        time.Duration
+	SwingBars       int
+	BucketPct       float64
+	VolumeNodeRatio float64
+	MergePct        float64
+	MaxLevels       int
+	Window          string
+	VolumeRatio     float64
+	MaxEvents       int
+
+	levels      map[string]models.MSymbolLevels // symbol -> levels
+	lastClose   map[string]float64              // symbol -> close of the last candle of Window
+	events      []models.MLevelBreakEvent       // Most recent last
+	lastRefresh time.Time
+	mu          sync.RWMutex
+}
+
+// levelCandidate is a swing or a volume node before merging
+type levelCandidate struct {
+	price float64
+	swing bool
+	ts    int64
+}
+
+// -----------------------------------------------------------------------------
+
+func NewLevelService(cfg *models.MConfig, memManager *utils.MemoryManager, log *logger.Logger) *LevelService {
+	lc := cfg.Levels
+
+	s := &LevelService{
+		Config:          cfg,
+		Logger:          log,
+		Memory:          memManager,
+		Interval:        time.Duration(lc.RefreshIntervalSeconds) * time.Second,
+		SwingBars:       lc.SwingBars,
+		BucketPct:       lc.BucketPct,
+		VolumeNodeRatio: lc.VolumeNodeRatio,
+		MergePct:        lc.MergePct,
+		MaxLevels:       lc.MaxLevels,
+		Window:          lc.Window,
+		VolumeRatio:     lc.BreakoutVolumeRatio,
+		MaxEvents:       lc.MaxEvents,
+		levels:          make(map[string]models.MSymbolLevels),
+		lastClose:       make(map[string]float64),
+	}
+
+	if s.Interval <= 0 {
+		s.Interval = utils.DefaultLevelRefreshInterval * time.Second
+	}
+	if s.SwingBars <= 0 {
+		s.SwingBars = utils.DefaultLevelSwingBars
+	}
+	if s.BucketPct <= 0 {
+		s.BucketPct = utils.DefaultLevelBucketPct
+	}
+	if s.VolumeNodeRatio <= 0 {
+		s.VolumeNodeRatio = utils.DefaultLevelVolumeNodeRatio
+	}
+	if s.MergePct <= 0 {
+		s.MergePct = utils.DefaultLevelMergePct
+	}
+	if s.MaxLevels <= 0 {
+		s.MaxLevels = utils.DefaultLevelMaxLevels
+	}
+	if s.Window == "" && len(cfg.WindowsAgg) > 0 {
+		s.Window = cfg.WindowsAgg[0]
+	}
+	if s.VolumeRatio <= 0 {
+		s.VolumeRatio = utils.DefaultLevelBreakoutVolumeRatio
+	}
+	if s.MaxEvents <= 0 {
+		s.MaxEvents = utils.DefaultLevelMaxEvents
+	}
+	return s
+}
+
+// -----------------------------------------------------------------------------
+
+// Refresh rebuilds the levels of every symbol once the refresh interval has
+// elapsed (always when force is set). s may be nil (disabled).
+func (s *LevelService) Refresh(force bool) {
+	if s == nil || (!force && time.Since(s.lastRefresh) < s.Interval) {
+		return
+	}
+	s.lastRefresh = time.Now().UTC()
+	now := s.lastRefresh.Unix()
+
+	levels := make(map[string]models.MSymbolLevels)
+	for _, sym := range s.Memory.Symbols() {
+		points := s.Memory.GetHistory(sym)
+		if len(points) == 0 {
+			continue
+		}
+		if lv, ok := s.compute(sym, points, now); ok {
+			levels[sym] = lv
+		}
+	}
+
+	s.mu.Lock()
+	s.levels = levels
+	s.mu.Unlock()
+	s.Logger.Info("Levels: refreshed %d symbol(s)", len(levels))
+}
+
+// -----------------------------------------------------------------------------
+
+// Process checks the closed candles of the level window against the current
+// levels and returns the breakouts/breakdowns (oldest first). A resistance
+// closed above or a support closed below is broken when the candle's volume
+// confirms it; only then does the level change kind. s may be nil (disabled).
+func (s *LevelService) Process(closed map[string]map[string][]models.MAggregation) []models.MLevelBreakEvent {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []models.MLevelBreakEvent
+	for symbol, windows := range closed {
+		for _, c := range windows[s.Window] {
+			if !c.IsClosed || c.DataPoints == 0 || c.FilledBars >= c.DataPoints {
+				continue
+			}
+
+			lv, hasLevels := s.levels[symbol]
+			prev, ok := s.lastClose[symbol]
+			if !ok && hasLevels {
+				prev, ok = lv.Price, true // First candle since start: last price of the history
+			}
+			s.lastClose[symbol] = c.Close
+			if !ok || !hasLevels {
+				continue
+			}
+
+			for i := range lv.Levels {
+				level := &lv.Levels[i]
+				eventType, flipped := "", ""
+				if level.Kind == utils.LevelResistance && prev <= level.Price && c.Close > level.Price {
+					eventType, flipped = utils.LevelBreakout, utils.LevelSupport
+				} else if level.Kind == utils.LevelSupport && prev >= level.Price && c.Close < level.Price {
+					eventType, flipped = utils.LevelBreakdown, utils.LevelResistance
+				} else {
+					continue
+				}
+				if c.VolumeAnomalyRatio < s.VolumeRatio {
+					continue // Crossed on ordinary volume: not a break, the level keeps its kind
+				}
+
+				kind := level.Kind
+				level.Kind = flipped
+				level.Broken = true
+
+				events = append(events, models.MLevelBreakEvent{
+					Symbol:        symbol,
+					WindowName:    c.WindowName,
+					Type:          eventType,
+					Level:         level.Price,
+					Kind:          kind,
+					Source:        level.Source,
+					Strength:      level.Strength,
+					PreviousClose: prev,
+					Close:         c.Close,
+					VolumeRatio:   c.VolumeAnomalyRatio,
+					Timestamp:     c.EndTime,
+				})
+			}
+		}
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp < events[j].Timestamp
+	})
+
+	s.events = append(s.events, events...)
+	if overflow := len(s.events) - s.MaxEvents; overflow > 0 {
+		s.events = append([]models.MLevelBreakEvent(nil), s.events[overflow:]...)
+	}
+	return events
+}
+
+// -----------------------------------------------------------------------------
+
+// Levels returns the current levels of a symbol.
+func (s *LevelService) Levels(symbol string) (models.MSymbolLevels, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	lv, ok := s.levels[symbol]
+	if !ok {
+		return models.MSymbolLevels{}, false
+	}
+	lv.Levels = append([]models.MPriceLevel(nil), lv.Levels...)
+	return lv, true
+}
+
+// -----------------------------------------------------------------------------
+
+// All returns the current levels of every symbol, sorted by symbol.
+func (s *LevelService) All() []models.MSymbolLevels {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]models.MSymbolLevels, 0, len(s.levels))
+	for _, lv := range s.levels {
+		lv.Levels = append([]models.MPriceLevel(nil), lv.Levels...)
+		result = append(result, lv)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Symbol < result[j].Symbol })
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// Recent returns the latest break events, optionally filtered by symbol and
+// type ("" = all), newest last (limit <= 0 = all kept).
+func (s *LevelService) Recent(symbol, eventType string, limit int) []models.MLevelBreakEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.MLevelBreakEvent
+	for _, e := range s.events {
+		if (symbol == "" || e.Symbol == symbol) && (eventType == "" || e.Type == eventType) {
+			result = append(result, e)
+		}
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// compute derives the levels of a symbol from its history (oldest first).
+// Bars use their high/low when the source provides them, the close otherwise.
+// False when the history is too short for a single swing.
+func (s *LevelService) compute(symbol string, points []models.MStockPrice, now int64) (models.MSymbolLevels, bool) {
+	var lows, highs, volumes []float64
+	var timestamps []int64
+	for _, p := range points {
+		if p.Price <= 0 || p.Filled {
+			continue
+		}
+		low, high := p.Price, p.Price
+		if p.High > 0 && p.Low > 0 {
+			low, high = p.Low, p.High
+		}
+		lows = append(lows, low)
+		highs = append(highs, high)
+		volumes = append(volumes, p.Volume)
+		timestamps = append(timestamps, p.Timestamp)
+	}
+
+	n, k := len(lows), s.SwingBars
+	if n < 2*k+1 {
+		return models.MSymbolLevels{}, false
+	}
+	last := points[len(points)-1].Price
+
+	// Volume-by-price histogram over the whole range
+	minLow, maxHigh := lows[0], highs[0]
+	for i := 1; i < n; i++ {
+		minLow = math.Min(minLow, lows[i])
+		maxHigh = math.Max(maxHigh, highs[i])
+	}
+	bucket := last * s.BucketPct
+	buckets := int((maxHigh-minLow)/bucket) + 1
+	if buckets > utils.LevelMaxBuckets {
+		buckets = utils.LevelMaxBuckets
+		bucket = (maxHigh - minLow) / float64(buckets-1)
+	}
+	hist := core.CalculateVolumeByPrice(lows, highs, volumes, minLow, bucket, buckets)
+
+	total, traded := 0.0, 0
+	for _, v := range hist {
+		if v > 0 {
+			total += v
+			traded++
+		}
+	}
+	mean := 0.0
+	if traded > 0 {
+		mean = total / float64(traded)
+	}
+
+	// Swing highs and lows (strictly above/below the bars before, not exceeded after)
+	var candidates []levelCandidate
+	for i := k; i < n-k; i++ {
+		swingHigh, swingLow := true, true
+		for j := i - k; j <= i+k && (swingHigh || swingLow); j++ {
+			if j == i {
+				continue
+			}
+			if (j < i && highs[j] >= highs[i]) || (j > i && highs[j] > highs[i]) {
+				swingHigh = false
+			}
+			if (j < i && lows[j] <= lows[i]) || (j > i && lows[j] < lows[i]) {
+				swingLow = false
+			}
+		}
+		if swingHigh {
+			candidates = append(candidates, levelCandidate{price: highs[i], swing: true, ts: timestamps[i]})
+		}
+		if swingLow {
+			candidates = append(candidates, levelCandidate{price: lows[i], swing: true, ts: timestamps[i]})
+		}
+	}
+
+	// High-volume nodes (local maxima of the histogram well above the mean)
+	if mean > 0 {
+		for j, v := range hist {
+			if v < s.VolumeNodeRatio*mean || (j > 0 && v < hist[j-1]) || (j < buckets-1 && v <= hist[j+1]) {
+				continue
+			}
+			candidates = append(candidates, levelCandidate{price: minLow + (float64(j)+0.5)*bucket})
+		}
+	}
+	if len(candidates) == 0 {
+		return models.MSymbolLevels{}, false
+	}
+
+	// Merge candidates within merge_pct of the first one of their group
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].price < candidates[j].price })
+	tolerance := last * s.MergePct
+
+	var levels []models.MPriceLevel
+	for start := 0; start < len(candidates); {
+		end := start + 1
+		for end < len(candidates) && candidates[end].price-candidates[start].price <= tolerance {
+			end++
+		}
+		levels = append(levels, s.mergeLevel(candidates[start:end], hist, minLow, bucket, tolerance, mean))
+		start = end
+	}
+
+	// Strongest levels, then highest price first
+	sort.SliceStable(levels, func(i, j int) bool { return levels[i].Strength > levels[j].Strength })
+	if len(levels) > s.MaxLevels {
+		levels = levels[:s.MaxLevels]
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Price > levels[j].Price })
+	for i := range levels {
+		if levels[i].Price > last {
+			levels[i].Kind = utils.LevelResistance
+		} else {
+			levels[i].Kind = utils.LevelSupport
+		}
+	}
+
+	return models.MSymbolLevels{
+		Symbol:    symbol,
+		Price:     last,
+		Levels:    levels,
+		From:      timestamps[0],
+		To:        timestamps[n-1],
+		UpdatedAt: now,
+	}, true
+}
+
+// -----------------------------------------------------------------------------
+
+// mergeLevel turns a group of nearby candidates into a level: swings set the
+// price (their mean) and the touches, the histogram around it the volume.
+func (s *LevelService) mergeLevel(group []levelCandidate, hist []float64, origin, bucket, tolerance, mean float64) models.MPriceLevel {
+	var level models.MPriceLevel
+	swingSum, nodeSum, nodes := 0.0, 0.0, 0
+	for _, c := range group {
+		if c.swing {
+			swingSum += c.price
+			level.Touches++
+			if c.ts > level.LastTouch {
+				level.LastTouch = c.ts
+			}
+		} else {
+			nodeSum += c.price
+			nodes++
+		}
+	}
+
+	switch {
+	case level.Touches > 0 && nodes > 0:
+		level.Source = utils.LevelSourceBoth
+		level.Price = swingSum / float64(level.Touches)
+	case level.Touches > 0:
+		level.Source = utils.LevelSourceSwing
+		level.Price = swingSum / float64(level.Touches)
+	default:
+		level.Source = utils.LevelSourceVolume
+		level.Price = nodeSum / float64(nodes)
+	}
+
+	first := int(math.Floor((level.Price - tolerance/2 - origin) / bucket))
+	last := int(math.Floor((level.Price + tolerance/2 - origin) / bucket))
+	for i := max(first, 0); i <= last && i < len(hist); i++ {
+		level.Volume += hist[i]
+	}
+
+	level.Strength = float64(level.Touches)
+	if mean > 0 {
+		level.Strength += level.Volume / mean
+	}
+	return level
+}
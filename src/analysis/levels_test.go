@@ -0,0 +1,145 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+func newTestLevels(maxLevels int) *LevelService {
+	cfg := &models.MConfig{WindowsAgg: []string{"5m"}}
+	cfg.Levels.SwingBars = 2
+	cfg.Levels.BucketPct = 0.01
+	cfg.Levels.MergePct = 0.005
+	cfg.Levels.MaxLevels = maxLevels
+	return NewLevelService(cfg, nil, logger.NewLogger(cfg, "test"))
+}
+
+func TestLevelsCompute(t *testing.T) {
+	// Swing high at 105, swing low at 95 and a high-volume node around 100
+	prices := []float64{100, 101, 105, 101, 100, 98, 95, 98, 100, 100}
+	var points []models.MStockPrice
+	for i, price := range prices {
+		volume := 10.0
+		switch price {
+		case 100:
+			volume = 50
+		case 95:
+			volume = 30
+		}
+		points = append(points, models.MStockPrice{Symbol: "AAA", Price: price, Volume: volume, Timestamp: int64(i * 60)})
+	}
+
+	tests := []struct {
+		name      string
+		maxLevels int
+		prices    []float64
+		sources   []string
+		kinds     []string
+	}{
+		{"all levels", 10, []float64{105, 100.5, 95},
+			[]string{utils.LevelSourceSwing, utils.LevelSourceVolume, utils.LevelSourceSwing},
+			[]string{utils.LevelResistance, utils.LevelResistance, utils.LevelSupport}},
+		// 105 and 95 are single touches; 95 traded more volume
+		{"strongest levels", 2, []float64{100.5, 95},
+			[]string{utils.LevelSourceVolume, utils.LevelSourceSwing},
+			[]string{utils.LevelResistance, utils.LevelSupport}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lv, ok := newTestLevels(tt.maxLevels).compute("AAA", points, 1000)
+			if !ok {
+				t.Fatal("compute found no level")
+			}
+			if lv.Price != 100 || lv.From != 0 || lv.To != 540 || len(lv.Levels) != len(tt.prices) {
+				t.Fatalf("levels = %+v", lv)
+			}
+			for i, level := range lv.Levels {
+				if math.Abs(level.Price-tt.prices[i]) > 1e-9 || level.Source != tt.sources[i] || level.Kind != tt.kinds[i] {
+					t.Errorf("level %d = %+v, want %v %s %s", i, level, tt.prices[i], tt.sources[i], tt.kinds[i])
+				}
+			}
+		})
+	}
+
+	if _, ok := newTestLevels(10).compute("AAA", points[:4], 1000); ok {
+		t.Error("levels from a history shorter than a swing")
+	}
+}
+
+func TestLevelsMerge(t *testing.T) {
+	s := newTestLevels(10)
+	hist := []float64{0, 40, 0}
+
+	level := s.mergeLevel([]levelCandidate{
+		{price: 105, swing: true, ts: 60},
+		{price: 105.2},
+		{price: 105.4, swing: true, ts: 120},
+	}, hist, 104, 1, 0.5, 20)
+
+	if level.Source != utils.LevelSourceBoth || math.Abs(level.Price-105.2) > 1e-9 || level.Touches != 2 || level.LastTouch != 120 {
+		t.Errorf("merged level = %+v", level)
+	}
+	if level.Volume != 40 || level.Strength != 4 {
+		t.Errorf("volume %v strength %v, want 40 and 2 touches + 40/20", level.Volume, level.Strength)
+	}
+}
+
+func TestLevelsBreakConfirmation(t *testing.T) {
+	s := newTestLevels(10)
+	s.levels["AAA"] = models.MSymbolLevels{Symbol: "AAA", Price: 100, Levels: []models.MPriceLevel{
+		{Price: 105, Kind: utils.LevelResistance},
+		{Price: 95, Kind: utils.LevelSupport},
+	}}
+
+	closed := func(ts int64, close, volumeRatio float64) map[string]map[string][]models.MAggregation {
+		c := models.MAggregation{Symbol: "AAA", WindowName: "5m", Close: close, VolumeAnomalyRatio: volumeRatio,
+			EndTime: ts, IsClosed: true, DataPoints: 5}
+		return map[string]map[string][]models.MAggregation{"AAA": {"5m": {c}}}
+	}
+	kinds := func() [2]string {
+		lv, _ := s.Levels("AAA")
+		return [2]string{lv.Levels[0].Kind, lv.Levels[1].Kind}
+	}
+
+	steps := []struct {
+		name   string
+		close  float64
+		ratio  float64
+		events []string // Event type and level
+		kinds  [2]string
+	}{
+		{"cross on ordinary volume", 106, 1, nil, [2]string{utils.LevelResistance, utils.LevelSupport}},
+		{"back below an unbroken resistance", 104, 3, nil, [2]string{utils.LevelResistance, utils.LevelSupport}},
+		{"confirmed breakout", 106, 3, []string{"breakout 105"}, [2]string{utils.LevelSupport, utils.LevelSupport}},
+		{"confirmed breakdown of both", 94, 2, []string{"breakdown 105", "breakdown 95"}, [2]string{utils.LevelResistance, utils.LevelResistance}},
+	}
+
+	for i, step := range steps {
+		events := s.Process(closed(int64(i+1)*300, step.close, step.ratio))
+		if len(events) != len(step.events) {
+			t.Fatalf("%s: events = %+v, want %v", step.name, events, step.events)
+		}
+		for j, e := range events {
+			if got := fmt.Sprintf("%s %g", e.Type, e.Level); got != step.events[j] {
+				t.Errorf("%s: event %d = %s, want %s", step.name, j, got, step.events[j])
+			}
+		}
+		if got := kinds(); got != step.kinds {
+			t.Errorf("%s: kinds = %v, want %v", step.name, got, step.kinds)
+		}
+	}
+
+	recent := s.Recent("AAA", utils.LevelBreakdown, 0)
+	if len(recent) != 2 || recent[0].Kind != utils.LevelSupport || recent[0].PreviousClose != 106 {
+		t.Errorf("Recent breakdowns = %+v", recent)
+	}
+	if len(s.Recent("BBB", "", 0)) != 0 || len(s.Recent("", "", 1)) != 1 {
+		t.Error("Recent filters")
+	}
+}
@@ -0,0 +1,287 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// PatternDetector recognizes candlestick patterns on the closed candles of
+// every symbol/window: doji, hammer, engulfing, morning/evening star,
+// inside/outside bars and three-bar reversals. Patterns are attached to the
+// candle that completes them and reported as events. Shadows are only
+// meaningful on candles built from real highs and lows (TrueOHLC); events say
+// whether that was the case, and require_true_ohlc skips the others.
+// -----------------------------------------------------------------------------
+
+type PatternDetector struct {
+	Config *models.MConfig
+	Logger *logger.Logger
+
+	MaxEvents       int
+	dojiBody        float64
+	shadow          float64
+	starBody        float64
+	requireTrueOHLC bool
+	enabled         map[string]bool
+
+	history map[string][]models.MAggregation // symbol|window -> last closed candles, oldest first
+	events  []models.MPatternEvent           // Most recent last
+	mu      sync.RWMutex
+}
+
+// -----------------------------------------------------------------------------
+
+func NewPatternDetector(cfg *models.MConfig, log *logger.Logger) *PatternDetector {
+	pc := cfg.Patterns
+
+	d := &PatternDetector{
+		Config:          cfg,
+		Logger:          log,
+		MaxEvents:       pc.MaxEvents,
+		dojiBody:        pc.DojiBodyRatio,
+		shadow:          pc.ShadowRatio,
+		starBody:        pc.StarBodyRatio,
+		requireTrueOHLC: pc.RequireTrueOHLC,
+		enabled:         make(map[string]bool),
+		history:         make(map[string][]models.MAggregation),
+	}
+
+	if d.MaxEvents <= 0 {
+		d.MaxEvents = utils.DefaultPatternMaxEvents
+	}
+	if d.dojiBody <= 0 {
+		d.dojiBody = utils.DefaultPatternDojiBodyRatio
+	}
+	if d.shadow <= 0 {
+		d.shadow = utils.DefaultPatternShadowRatio
+	}
+	if d.starBody <= 0 {
+		d.starBody = utils.DefaultPatternStarBodyRatio
+	}
+
+	names := pc.Patterns
+	if len(names) == 0 {
+		names = utils.CandlePatterns
+	}
+	for _, name := range names {
+		d.enabled[name] = true
+	}
+	return d
+}
+
+// -----------------------------------------------------------------------------
+
+// Seed annotates historical candles (symbol -> window -> candles, oldest
+// first) with their patterns without emitting events. d may be nil (disabled).
+func (d *PatternDetector) Seed(candles map[string]map[string][]models.MAggregation) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.process(candles)
+}
+
+// -----------------------------------------------------------------------------
+
+// Process annotates the closed candles of a cycle in place and returns the
+// detected patterns (oldest first). d may be nil (disabled).
+func (d *PatternDetector) Process(closed map[string]map[string][]models.MAggregation) []models.MPatternEvent {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	events := d.process(closed)
+	if len(events) == 0 {
+		return nil
+	}
+
+	d.events = append(d.events, events...)
+	if overflow := len(d.events) - d.MaxEvents; overflow > 0 {
+		d.events = append([]models.MPatternEvent(nil), d.events[overflow:]...)
+	}
+	return events
+}
+
+// -----------------------------------------------------------------------------
+
+// Recent returns the latest events, optionally filtered by symbol, window and
+// pattern ("" = all), newest last (limit <= 0 = all kept).
+func (d *PatternDetector) Recent(symbol, window, pattern string, limit int) []models.MPatternEvent {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var result []models.MPatternEvent
+	for _, e := range d.events {
+		if (symbol == "" || e.Symbol == symbol) && (window == "" || e.WindowName == window) && (pattern == "" || e.Pattern == pattern) {
+			result = append(result, e)
+		}
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// process runs the detection on the closed candles and attaches the patterns
+// to them (caller holds the lock).
+func (d *PatternDetector) process(candles map[string]map[string][]models.MAggregation) []models.MPatternEvent {
+	var events []models.MPatternEvent
+
+	for symbol, windows := range candles {
+		for windowName, list := range windows {
+			for i := range list {
+				c := &list[i]
+				if !c.IsClosed {
+					continue
+				}
+				key := symbol + "|" + windowName
+
+				// A candle without trades (or only gap fills) breaks the sequence
+				if c.DataPoints == 0 || c.FilledBars >= c.DataPoints {
+					delete(d.history, key)
+					continue
+				}
+
+				bars := append(d.history[key], *c)
+				if len(bars) > utils.PatternMaxBars {
+					bars = append([]models.MAggregation(nil), bars[len(bars)-utils.PatternMaxBars:]...)
+				}
+				d.history[key] = bars
+
+				c.Patterns = nil
+				for _, e := range d.detect(bars) {
+					c.Patterns = append(c.Patterns, e.Pattern)
+					events = append(events, e)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp < events[j].Timestamp
+	})
+	return events
+}
+
+// -----------------------------------------------------------------------------
+
+// detect returns the patterns completed by the last candle of bars.
+func (d *PatternDetector) detect(bars []models.MAggregation) []models.MPatternEvent {
+	n := len(bars)
+	c := bars[n-1]
+
+	var events []models.MPatternEvent
+	found := func(pattern, direction string, count int) {
+		if !d.enabled[pattern] || count > n {
+			return
+		}
+		trueOHLC := true
+		for _, b := range bars[n-count:] {
+			trueOHLC = trueOHLC && b.TrueOHLC
+		}
+		if d.requireTrueOHLC && !trueOHLC {
+			return
+		}
+		events = append(events, models.MPatternEvent{
+			Symbol:     c.Symbol,
+			WindowName: c.WindowName,
+			Pattern:    pattern,
+			Direction:  direction,
+			Bars:       count,
+			StartTime:  bars[n-count].StartTime,
+			Timestamp:  c.EndTime,
+			Close:      c.Close,
+			TrueOHLC:   trueOHLC,
+		})
+	}
+
+	// Single candle
+	rng := c.High - c.Low
+	if rng > 0 {
+		if candleBody(c) <= d.dojiBody*rng {
+			found(utils.PatternDoji, utils.PatternNeutral, 1)
+		} else if lowerShadow(c) >= d.shadow*candleBody(c) && upperShadow(c) <= candleBody(c) && n >= 2 && isBearish(bars[n-2]) {
+			found(utils.PatternHammer, utils.PatternBullish, 1) // After a down candle
+		}
+	}
+	if n < 2 {
+		return events
+	}
+
+	// Two candles
+	p := bars[n-2]
+	if isBearish(p) && isBullish(c) && c.Open <= p.Close && c.Close >= p.Open && candleBody(c) > candleBody(p) {
+		found(utils.PatternBullishEngulfing, utils.PatternBullish, 2)
+	}
+	if isBullish(p) && isBearish(c) && c.Open >= p.Close && c.Close <= p.Open && candleBody(c) > candleBody(p) {
+		found(utils.PatternBearishEngulfing, utils.PatternBearish, 2)
+	}
+	if c.High < p.High && c.Low > p.Low {
+		found(utils.PatternInsideBar, utils.PatternNeutral, 2)
+	}
+	if c.High > p.High && c.Low < p.Low {
+		direction := utils.PatternNeutral
+		if isBullish(c) {
+			direction = utils.PatternBullish
+		} else if isBearish(c) {
+			direction = utils.PatternBearish
+		}
+		found(utils.PatternOutsideBar, direction, 2)
+	}
+	if n < 3 {
+		return events
+	}
+
+	// Three candles
+	pp := bars[n-3]
+	star := candleBody(p) <= d.starBody*candleBody(pp)
+	mid := (pp.Open + pp.Close) / 2
+	if isBearish(pp) && star && isBullish(c) && c.Close > mid {
+		found(utils.PatternMorningStar, utils.PatternBullish, 3)
+	}
+	if isBullish(pp) && star && isBearish(c) && c.Close < mid {
+		found(utils.PatternEveningStar, utils.PatternBearish, 3)
+	}
+	if isBearish(pp) && p.Low < pp.Low && p.Low < c.Low && c.Close > p.High {
+		found(utils.PatternBullishThreeBarReversal, utils.PatternBullish, 3)
+	}
+	if isBullish(pp) && p.High > pp.High && p.High > c.High && c.Close < p.Low {
+		found(utils.PatternBearishThreeBarReversal, utils.PatternBearish, 3)
+	}
+	return events
+}
+
+// -----------------------------------------------------------------------------
+
+func candleBody(c models.MAggregation) float64 {
+	return math.Abs(c.Close - c.Open)
+}
+
+func upperShadow(c models.MAggregation) float64 {
+	return c.High - math.Max(c.Open, c.Close)
+}
+
+func lowerShadow(c models.MAggregation) float64 {
+	return math.Min(c.Open, c.Close) - c.Low
+}
+
+func isBullish(c models.MAggregation) bool {
+	return c.Close > c.Open
+}
+
+func isBearish(c models.MAggregation) bool {
+	return c.Close < c.Open
+}
@@ -0,0 +1,90 @@
+package momentum
+
+import (
+	"market-observer/src/models"
+)
+
+const (
+	Name = "momentum"
+
+	DefaultFastPeriod = 12
+	DefaultSlowPeriod = 26
+)
+
+// -----------------------------------------------------------------------------
+// Analyzer is an example IAnalyzer plugin: fast/slow EMAs of the close per
+// symbol/window and their spread. Open candles get provisional values; the
+// EMAs only advance on closed candles.
+// -----------------------------------------------------------------------------
+
+type Analyzer struct {
+	FastPeriod int
+	SlowPeriod int
+}
+
+type state struct {
+	emaFast float64
+	emaSlow float64
+	count   int // Closed candles seen
+}
+
+// -----------------------------------------------------------------------------
+
+func New(fastPeriod, slowPeriod int) *Analyzer {
+	if fastPeriod <= 0 {
+		fastPeriod = DefaultFastPeriod
+	}
+	if slowPeriod <= fastPeriod {
+		slowPeriod = DefaultSlowPeriod
+	}
+	return &Analyzer{FastPeriod: fastPeriod, SlowPeriod: slowPeriod}
+}
+
+// -----------------------------------------------------------------------------
+
+func (a *Analyzer) Name() string {
+	return Name
+}
+
+// -----------------------------------------------------------------------------
+
+func (a *Analyzer) NewState(symbol, window string) interface{} {
+	return &state{}
+}
+
+// -----------------------------------------------------------------------------
+
+func (a *Analyzer) Analyze(candle models.MAggregation, st interface{}) map[string]float64 {
+	s := st.(*state)
+	if candle.DataPoints == 0 {
+		return nil
+	}
+
+	fast := ema(s.emaFast, candle.Close, a.FastPeriod, s.count)
+	slow := ema(s.emaSlow, candle.Close, a.SlowPeriod, s.count)
+	if candle.IsClosed {
+		s.emaFast, s.emaSlow = fast, slow
+		s.count++
+	}
+
+	metrics := map[string]float64{
+		"ema_fast": fast,
+		"ema_slow": slow,
+		"spread":   fast - slow,
+	}
+	if slow != 0 {
+		metrics["spread_pct"] = (fast - slow) / slow
+	}
+	return metrics
+}
+
+// -----------------------------------------------------------------------------
+
+// ema folds value into prev (seeded with the first value).
+func ema(prev, value float64, period, count int) float64 {
+	if count == 0 {
+		return value
+	}
+	alpha := 2.0 / float64(period+1)
+	return alpha*value + (1-alpha)*prev
+}
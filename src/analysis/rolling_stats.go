@@ -0,0 +1,180 @@
+package analysis
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// RollingStats keeps per symbol/window volume and return statistics current.
+// It is updated on every closed candle and tracks which entries need saving.
+// -----------------------------------------------------------------------------
+
+type RollingStats struct {
+	Method   string
+	Lookback int
+
+	stats map[string]map[string]models.MIntermediateStats // symbol -> window -> stats
+	dirty map[string]map[string]bool
+	mu    sync.RWMutex
+}
+
+// -----------------------------------------------------------------------------
+
+func NewRollingStats(cfg *models.MConfig) *RollingStats {
+	method := cfg.RollingStats.Method
+	if method == "" {
+		method = utils.DefaultStatsMethod
+	}
+	lookback := cfg.RollingStats.Lookback
+	if lookback <= 0 {
+		lookback = utils.DefaultStatsLookback
+	}
+
+	return &RollingStats{
+		Method:   method,
+		Lookback: lookback,
+		stats:    make(map[string]map[string]models.MIntermediateStats),
+		dirty:    make(map[string]map[string]bool),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Load sets stats entries (e.g. restored from storage). Loaded entries are not dirty.
+func (r *RollingStats) Load(list []models.MIntermediateStats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range list {
+		if r.stats[s.Symbol] == nil {
+			r.stats[s.Symbol] = make(map[string]models.MIntermediateStats)
+		}
+		r.stats[s.Symbol][s.WindowName] = s
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Set stores an entry computed elsewhere (e.g. batch history) and marks it dirty.
+func (r *RollingStats) Set(s models.MIntermediateStats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stats[s.Symbol] == nil {
+		r.stats[s.Symbol] = make(map[string]models.MIntermediateStats)
+	}
+	r.stats[s.Symbol][s.WindowName] = s
+	r.markDirty(s.Symbol, s.WindowName)
+}
+
+// -----------------------------------------------------------------------------
+
+// Get returns the stats for a symbol/window.
+func (r *RollingStats) Get(symbol, windowName string) (models.MIntermediateStats, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.stats[symbol][windowName]
+	return s, ok
+}
+
+// -----------------------------------------------------------------------------
+
+// ForWindow returns the stats of all symbols for one window.
+func (r *RollingStats) ForWindow(windowName string) map[string]models.MIntermediateStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]models.MIntermediateStats)
+	for sym, wMap := range r.stats {
+		if s, ok := wMap[windowName]; ok {
+			result[sym] = s
+		}
+	}
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// Update folds a closed candle into the stats of its symbol/window.
+func (r *RollingStats) Update(candle models.MAggregation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stats[candle.Symbol] == nil {
+		r.stats[candle.Symbol] = make(map[string]models.MIntermediateStats)
+	}
+	s, ok := r.stats[candle.Symbol][candle.WindowName]
+	if !ok {
+		s = models.MIntermediateStats{Symbol: candle.Symbol, WindowName: candle.WindowName}
+	}
+
+	volVar := s.StdVolumeHistory * s.StdVolumeHistory
+	retVar := s.StdReturnHistory * s.StdReturnHistory
+
+	switch {
+	case s.DataPointsHistory == 0:
+		// First sample seeds both methods
+		s.AvgVolumeHistory, volVar = candle.Volume, 0
+		s.AvgReturnHistory, retVar = candle.PricePercentChange, 0
+		s.DataPointsHistory = 1
+
+	case r.Method == "welford" && s.DataPointsHistory < r.Lookback:
+		// Cumulative until the lookback is reached
+		s.DataPointsHistory++
+		s.AvgVolumeHistory, volVar = core.UpdateWelford(s.DataPointsHistory, s.AvgVolumeHistory, volVar, candle.Volume)
+		s.AvgReturnHistory, retVar = core.UpdateWelford(s.DataPointsHistory, s.AvgReturnHistory, retVar, candle.PricePercentChange)
+
+	default:
+		// EWMA (or Welford capped at lookback, i.e. weight 1/lookback per new candle)
+		alpha := 2.0 / float64(r.Lookback+1)
+		if r.Method == "welford" {
+			alpha = 1.0 / float64(r.Lookback)
+		} else {
+			s.DataPointsHistory++
+		}
+		s.AvgVolumeHistory, volVar = core.UpdateEWMA(s.AvgVolumeHistory, volVar, candle.Volume, alpha)
+		s.AvgReturnHistory, retVar = core.UpdateEWMA(s.AvgReturnHistory, retVar, candle.PricePercentChange, alpha)
+	}
+
+	s.StdVolumeHistory = math.Sqrt(math.Max(volVar, 0))
+	s.StdReturnHistory = math.Sqrt(math.Max(retVar, 0))
+	s.LastHistoryTimestamp = candle.StartTime
+	s.UpdatedAt = time.Now().UTC()
+
+	r.stats[candle.Symbol][candle.WindowName] = s
+	r.markDirty(candle.Symbol, candle.WindowName)
+}
+
+// -----------------------------------------------------------------------------
+
+// TakeDirty returns the entries changed since the last call and clears the dirty set.
+func (r *RollingStats) TakeDirty() []models.MIntermediateStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var list []models.MIntermediateStats
+	for sym, wMap := range r.dirty {
+		for w := range wMap {
+			list = append(list, r.stats[sym][w])
+		}
+	}
+	r.dirty = make(map[string]map[string]bool)
+	return list
+}
+
+// -----------------------------------------------------------------------------
+
+// markDirty flags an entry for the next persistence cycle (caller holds the lock).
+func (r *RollingStats) markDirty(symbol, windowName string) {
+	if r.dirty[symbol] == nil {
+		r.dirty[symbol] = make(map[string]bool)
+	}
+	r.dirty[symbol][windowName] = true
+}
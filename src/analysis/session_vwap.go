@@ -0,0 +1,66 @@
+package analysis
+
+import (
+	"time"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// sessionVWAP accumulates an anchored VWAP that resets at each session open
+// of the symbol's exchange (from its TradingCalendar).
+// -----------------------------------------------------------------------------
+
+type sessionVWAP struct {
+	calendar    *utils.TradingCalendar
+	sessionOpen int64 // Unix seconds of the current session open
+
+	sumVolume float64
+	sumPV     float64
+	sumP2V    float64
+	lastPrice float64
+}
+
+// -----------------------------------------------------------------------------
+
+func newSessionVWAP(symbol string) *sessionVWAP {
+	return &sessionVWAP{calendar: utils.GetCalendar(symbol)}
+}
+
+// -----------------------------------------------------------------------------
+
+// Add folds a point into the current session, starting a new one when needed.
+// Points belonging to an earlier session are ignored.
+func (s *sessionVWAP) Add(p models.MStockPrice) {
+	// Pre-open points still belong to the previous session
+	sessionOpen, _, _ := s.calendar.SessionBounds(time.Unix(p.Timestamp, 0))
+	open := sessionOpen.Unix()
+	if open < s.sessionOpen {
+		return
+	}
+	if open > s.sessionOpen {
+		s.sessionOpen = open
+		s.sumVolume, s.sumPV, s.sumP2V = 0, 0, 0
+	}
+
+	s.sumVolume += p.Volume
+	s.sumPV += p.Price * p.Volume
+	s.sumP2V += p.Price * p.Price * p.Volume
+	s.lastPrice = p.Price
+}
+
+// -----------------------------------------------------------------------------
+
+// Apply writes the current session VWAP and its ±1σ/±2σ bands to a candle.
+func (s *sessionVWAP) Apply(c *models.MAggregation) {
+	vwap := core.CalculateVWAP(s.sumPV, s.sumVolume, s.lastPrice)
+	std := core.CalculateVWAPStd(s.sumP2V, s.sumVolume, vwap)
+
+	c.SessionVWAP = vwap
+	c.SessionVWAPUpper1 = vwap + std
+	c.SessionVWAPLower1 = vwap - std
+	c.SessionVWAPUpper2 = vwap + 2*std
+	c.SessionVWAPLower2 = vwap - 2*std
+}
@@ -0,0 +1,283 @@
+package analysis
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// VolatilityService annotates every candle with annualized volatility
+// estimators over the last bars of its window (close-to-close, Parkinson,
+// Garman-Klass, Yang-Zhang) and flags high/low volatility regimes when the
+// regime estimator leaves the band of its rolling percentile.
+// -----------------------------------------------------------------------------
+
+type VolatilityService struct {
+	Config  *models.MConfig
+	Logger  *logger.Logger
+	Windows map[string]utils.WindowSpec
+
+	Lookback         int
+	RegimeEstimator  string
+	RegimeLookback   int
+	RegimeMinSamples int
+	HighPercentile   float64
+	LowPercentile    float64
+
+	history map[string]*volatilityHistory // symbol|window -> closed bars
+	annual  map[string]annualization      // symbol|window -> bars per year
+	mu      sync.Mutex
+}
+
+type volatilityHistory struct {
+	bars   []ohlcBar // Closed bars, oldest first (up to Lookback)
+	regime []float64 // Regime estimator of the closed bars, oldest first
+}
+
+type ohlcBar struct {
+	open, high, low, close float64
+}
+
+// annualization caches the bars per year of a symbol/window for one day
+type annualization struct {
+	day  int64
+	bars float64
+}
+
+// -----------------------------------------------------------------------------
+
+func NewVolatilityService(cfg *models.MConfig, log *logger.Logger) *VolatilityService {
+	vc := cfg.Volatility
+
+	lookback := vc.Lookback
+	if lookback <= 0 {
+		lookback = utils.DefaultVolLookback
+	}
+	estimator := vc.RegimeEstimator
+	if estimator == "" {
+		estimator = utils.DefaultVolRegimeEstimator
+	}
+	regimeLookback := vc.RegimeLookback
+	if regimeLookback <= 0 {
+		regimeLookback = utils.DefaultVolRegimeLookback
+	}
+	minSamples := vc.RegimeMinSamples
+	if minSamples <= 0 {
+		minSamples = utils.DefaultVolRegimeMinSamples
+	}
+	high := vc.HighPercentile
+	if high <= 0 {
+		high = utils.DefaultVolHighPercentile
+	}
+	low := vc.LowPercentile
+	if low <= 0 {
+		low = utils.DefaultVolLowPercentile
+	}
+
+	return &VolatilityService{
+		Config:           cfg,
+		Logger:           log,
+		Windows:          utils.ParseWindows(cfg.WindowsAgg),
+		Lookback:         lookback,
+		RegimeEstimator:  estimator,
+		RegimeLookback:   regimeLookback,
+		RegimeMinSamples: minSamples,
+		HighPercentile:   high,
+		LowPercentile:    low,
+		history:          make(map[string]*volatilityHistory),
+		annual:           make(map[string]annualization),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// ApplyBatch annotates the candles of an engine batch (closed ones first, so
+// open candles see the updated history). s may be nil (disabled).
+func (s *VolatilityService) ApplyBatch(batch models.MCandleBatch) {
+	if s == nil {
+		return
+	}
+	s.Apply(batch.Closed)
+	s.Apply(batch.Updated)
+}
+
+// -----------------------------------------------------------------------------
+
+// Apply annotates candles (symbol -> window -> candles, oldest first) in place.
+// Closed candles advance the history. s may be nil (disabled).
+func (s *VolatilityService) Apply(candles map[string]map[string][]models.MAggregation) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, windows := range candles {
+		for _, list := range windows {
+			for i := range list {
+				s.annotate(&list[i])
+			}
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// annotate fills the volatility fields of a candle (caller holds the lock).
+func (s *VolatilityService) annotate(c *models.MAggregation) {
+	key := c.Symbol + "|" + c.WindowName
+	h, ok := s.history[key]
+	if !ok {
+		h = &volatilityHistory{}
+		s.history[key] = h
+	}
+
+	current := ohlcBar{open: c.Open, high: c.High, low: c.Low, close: c.Close}
+	bars := append(h.bars[:len(h.bars):len(h.bars)], current)
+
+	if len(bars) >= 2 {
+		s.estimate(c, bars)
+
+		regime := s.regimeValue(c)
+		if len(h.regime) >= s.RegimeMinSamples {
+			c.VolPercentile = core.CalculatePercentileRank(regime, h.regime)
+			c.HighVolRegime = c.VolPercentile >= s.HighPercentile
+			c.LowVolRegime = c.VolPercentile <= s.LowPercentile
+		}
+
+		if c.IsClosed {
+			h.regime = append(h.regime, regime)
+			if overflow := len(h.regime) - s.RegimeLookback; overflow > 0 {
+				h.regime = append([]float64(nil), h.regime[overflow:]...)
+			}
+		}
+	}
+
+	if c.IsClosed {
+		h.bars = append(h.bars, current)
+		if overflow := len(h.bars) - s.Lookback; overflow > 0 {
+			h.bars = append([]ohlcBar(nil), h.bars[overflow:]...)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// estimate computes the annualized estimators over bars (closed history +
+// current candle, oldest first); the oldest bar only provides a previous close
+// when the history is full.
+func (s *VolatilityService) estimate(c *models.MAggregation, bars []ohlcBar) {
+	n := len(bars)
+	window := bars
+	if n > s.Lookback {
+		window = bars[n-s.Lookback:]
+	}
+
+	closes := make([]float64, n)
+	for i, b := range bars {
+		closes[i] = b.close
+	}
+
+	open := make([]float64, len(window))
+	high := make([]float64, len(window))
+	low := make([]float64, len(window))
+	close := make([]float64, len(window))
+	for i, b := range window {
+		open[i], high[i], low[i], close[i] = b.open, b.high, b.low, b.close
+	}
+
+	// Yang-Zhang needs the previous close of every bar
+	offset := n - len(window)
+	prevClose := make([]float64, 0, len(window))
+	var yzOpen, yzHigh, yzLow, yzClose []float64
+	for i := range window {
+		if offset+i == 0 {
+			continue
+		}
+		prevClose = append(prevClose, bars[offset+i-1].close)
+		yzOpen = append(yzOpen, open[i])
+		yzHigh = append(yzHigh, high[i])
+		yzLow = append(yzLow, low[i])
+		yzClose = append(yzClose, close[i])
+	}
+
+	perYear := s.barsPerYear(c.Symbol, c.WindowName, c.StartTime)
+	annualize := func(variance float64) float64 {
+		return math.Sqrt(variance * perYear)
+	}
+
+	c.RealizedVol = annualize(core.CalculateRealizedVariance(closes))
+	c.ParkinsonVol = annualize(core.CalculateParkinsonVariance(high, low))
+	c.GarmanKlassVol = annualize(core.CalculateGarmanKlassVariance(open, high, low, close))
+	c.YangZhangVol = annualize(core.CalculateYangZhangVariance(prevClose, yzOpen, yzHigh, yzLow, yzClose))
+}
+
+// -----------------------------------------------------------------------------
+
+// regimeValue returns the configured regime estimator of an annotated candle.
+func (s *VolatilityService) regimeValue(c *models.MAggregation) float64 {
+	switch s.RegimeEstimator {
+	case utils.VolEstimatorRealized:
+		return c.RealizedVol
+	case utils.VolEstimatorParkinson:
+		return c.ParkinsonVol
+	case utils.VolEstimatorGarmanKlass:
+		return c.GarmanKlassVol
+	default:
+		return c.YangZhangVol
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// barsPerYear counts the windows of a year from the symbol's calendar: the
+// trading days of the last 365 days times the windows per trading day.
+// Cached per symbol/window for a day (caller holds the lock).
+func (s *VolatilityService) barsPerYear(symbol, windowName string, ts int64) float64 {
+	key := symbol + "|" + windowName
+	day := ts / 86400
+	if a, ok := s.annual[key]; ok && a.day == day {
+		return a.bars
+	}
+
+	cal := utils.GetCalendar(symbol)
+	t := time.Unix(ts, 0)
+
+	tradingDays := 0
+	for i := 0; i < 365; i++ {
+		if cal.IsTradingDay(t.AddDate(0, 0, -i)) {
+			tradingDays++
+		}
+	}
+
+	open, close, _ := cal.SessionBounds(t)
+	sessionSeconds := close.Sub(open).Seconds()
+	if sessionSeconds <= 0 {
+		sessionSeconds = (6*time.Hour + 30*time.Minute).Seconds()
+	}
+
+	spec := s.Windows[windowName]
+	var bars float64
+	switch {
+	case spec.Period == utils.WindowPeriodWeek:
+		bars = float64(tradingDays) / 5
+	case spec.Period == utils.WindowPeriodMonth:
+		bars = 12
+	case spec.Period != "" || spec.Seconds <= 0:
+		bars = float64(tradingDays) // session, day
+	case float64(spec.Seconds) < sessionSeconds:
+		bars = float64(tradingDays) * sessionSeconds / float64(spec.Seconds)
+	default:
+		bars = float64(tradingDays) * math.Min(1, 86400/float64(spec.Seconds))
+	}
+
+	s.annual[key] = annualization{day: day, bars: bars}
+	return bars
+}
@@ -0,0 +1,156 @@
+package analysis
+
+import (
+	"sync"
+	"time"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// VolumeSeasonality replaces the flat volume baseline of intraday windows with
+// a time-of-day profile: each slot (offset of the window from the session
+// open) is compared with the volume of the same slot over the last sessions,
+// so the open/close auctions and the midday lull are not anomalies by default.
+// Baselines only change when a new session starts (refreshed daily).
+// -----------------------------------------------------------------------------
+
+type VolumeSeasonality struct {
+	Config  *models.MConfig
+	Logger  *logger.Logger
+	Windows map[string]utils.WindowSpec
+
+	Method       string
+	LookbackDays int
+	MinDays      int
+
+	profiles map[string]*slotProfile // symbol|window -> profile
+	mu       sync.Mutex
+}
+
+type slotProfile struct {
+	session  int64                  // Open of the session being collected
+	length   int64                  // Regular hours of that session (seconds)
+	pending  map[int64]float64      // slot -> volume of the closed candles of the session
+	history  map[int64][]float64    // slot -> volume of the last sessions, oldest first
+	baseline map[int64]slotBaseline // slot -> baseline as of the session open
+}
+
+type slotBaseline struct {
+	expected float64 // Mean or median
+	scale    float64 // Std or scaled MAD
+	days     int
+}
+
+// -----------------------------------------------------------------------------
+
+func NewVolumeSeasonality(cfg *models.MConfig, log *logger.Logger) *VolumeSeasonality {
+	sc := cfg.Seasonality
+
+	lookback := sc.LookbackDays
+	if lookback <= 0 {
+		lookback = utils.DefaultSeasonalityLookbackDays
+	}
+	minDays := sc.MinDays
+	if minDays <= 0 {
+		minDays = utils.DefaultSeasonalityMinDays
+	}
+
+	return &VolumeSeasonality{
+		Config:       cfg,
+		Logger:       log,
+		Windows:      utils.ParseWindows(cfg.WindowsAgg),
+		Method:       sc.Method,
+		LookbackDays: lookback,
+		MinDays:      minDays,
+		profiles:     make(map[string]*slotProfile),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// annotate compares the candle volume with its slot baseline (when the slot
+// has enough sessions) and records closed candles. s may be nil (flat
+// baseline only); calendar-period windows are left unchanged.
+func (s *VolumeSeasonality) annotate(c *models.MAggregation) {
+	if s == nil {
+		return
+	}
+	spec, ok := s.Windows[c.WindowName]
+	if !ok || spec.Period != "" {
+		return
+	}
+
+	open, close, _ := utils.GetCalendar(c.Symbol).SessionBounds(time.Unix(c.StartTime, 0))
+	session := open.Unix()
+	slot := c.StartTime - session
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := c.Symbol + "|" + c.WindowName
+	p, ok := s.profiles[key]
+	if !ok {
+		p = &slotProfile{
+			pending:  make(map[int64]float64),
+			history:  make(map[int64][]float64),
+			baseline: make(map[int64]slotBaseline),
+		}
+		s.profiles[key] = p
+	}
+	if session > p.session {
+		s.roll(p)
+		p.session, p.length = session, close.Unix()-session
+	}
+
+	if b, ok := p.baseline[slot]; ok && b.days >= s.MinDays && b.expected > 0 {
+		c.ExpectedVolume = b.expected
+		c.VolumeAnomalyRatio = core.CalculateAnomalyRatio(c.Volume, b.expected)
+		c.VolumeSlotZScore = core.CalculateZScore(c.Volume, b.expected, b.scale)
+	}
+
+	// Late candles of an older session are not recorded
+	if c.IsClosed && session == p.session {
+		p.pending[slot] = c.Volume
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// roll folds the finished session into the slot history and refreshes the
+// baselines (caller holds the lock). Known slots of the regular session
+// without a closed candle traded nothing: they record 0.
+func (s *VolumeSeasonality) roll(p *slotProfile) {
+	if len(p.pending) == 0 {
+		return // No data for the whole session: feed down, not a quiet day
+	}
+
+	for slot := range p.history {
+		if _, ok := p.pending[slot]; !ok && slot >= 0 && slot < p.length {
+			p.pending[slot] = 0
+		}
+	}
+	for slot, volume := range p.pending {
+		history := append(p.history[slot], volume)
+		if overflow := len(history) - s.LookbackDays; overflow > 0 {
+			history = append([]float64(nil), history[overflow:]...)
+		}
+		p.history[slot] = history
+	}
+	p.pending = make(map[int64]float64)
+
+	for slot, history := range p.history {
+		var b slotBaseline
+		if s.Method == utils.SeasonalityMethodMedian {
+			median, mad := core.CalculateMedianMAD(history)
+			b = slotBaseline{expected: median, scale: utils.MADScale * mad}
+		} else {
+			b.expected, b.scale = core.CalculateMeanStd(history)
+		}
+		b.days = len(history)
+		p.baseline[slot] = b
+	}
+}
@@ -0,0 +1,118 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+func newTestSeasonality(method string) *VolumeSeasonality {
+	cfg := &models.MConfig{WindowsAgg: []string{"5m", "day"}}
+	cfg.Seasonality.Method = method
+	cfg.Seasonality.LookbackDays = 5
+	cfg.Seasonality.MinDays = 2
+	return NewVolumeSeasonality(cfg, logger.NewLogger(cfg, "test"))
+}
+
+// slotCandle is the 5m AAPL candle starting offset seconds after the open of a March 2024 session.
+func slotCandle(t *testing.T, day int, offset int64, volume float64, closed bool) models.MAggregation {
+	start := nyseTime(t, 3, day, 9, 30) + offset
+	return models.MAggregation{Symbol: "AAPL", WindowName: "5m", StartTime: start, EndTime: start + 300, Volume: volume, IsClosed: closed}
+}
+
+func TestVolumeSeasonalityBaselines(t *testing.T) {
+	// Slot 0 trades every session; slot 300 has no trade on the 12th
+	sessions := []struct {
+		day     int
+		volumes map[int64]float64
+	}{
+		{11, map[int64]float64{0: 100, 300: 10}},
+		{12, map[int64]float64{0: 200}},
+		{13, map[int64]float64{0: 300, 300: 40}},
+	}
+
+	tests := []struct {
+		method   string
+		slot     int64
+		volume   float64
+		expected float64
+		scale    float64
+	}{
+		{utils.SeasonalityMethodMean, 0, 400, 200, math.Sqrt(20000.0 / 3)},
+		{utils.SeasonalityMethodMean, 300, 50, 50.0 / 3, math.Sqrt((math.Pow(10-50.0/3, 2) + math.Pow(50.0/3, 2) + math.Pow(40-50.0/3, 2)) / 3)},
+		{utils.SeasonalityMethodMedian, 0, 400, 200, utils.MADScale * 100},
+		{utils.SeasonalityMethodMedian, 300, 50, 10, utils.MADScale * 10},
+	}
+
+	for _, tt := range tests {
+		s := newTestSeasonality(tt.method)
+		for _, session := range sessions {
+			for offset, volume := range session.volumes {
+				c := slotCandle(t, session.day, offset, volume, true)
+				s.annotate(&c)
+			}
+		}
+
+		c := slotCandle(t, 14, tt.slot, tt.volume, false)
+		s.annotate(&c)
+		if math.Abs(c.ExpectedVolume-tt.expected) > 1e-9 || math.Abs(c.VolumeAnomalyRatio-tt.volume/tt.expected) > 1e-9 ||
+			math.Abs(c.VolumeSlotZScore-(tt.volume-tt.expected)/tt.scale) > 1e-9 {
+			t.Errorf("%s slot %d: expected %v ratio %v z %v, want %v %v %v", tt.method, tt.slot,
+				c.ExpectedVolume, c.VolumeAnomalyRatio, c.VolumeSlotZScore, tt.expected, tt.volume/tt.expected, (tt.volume-tt.expected)/tt.scale)
+		}
+	}
+}
+
+func TestVolumeSeasonalityHistory(t *testing.T) {
+	s := newTestSeasonality(utils.SeasonalityMethodMean)
+
+	// A slot seen once, then never again: its history decays with zeros
+	c := slotCandle(t, 11, 600, 90, true)
+	s.annotate(&c)
+	for day := 12; day <= 18; day++ {
+		if day == 16 || day == 17 {
+			continue // Weekend
+		}
+		c := slotCandle(t, day, 0, 100, true)
+		s.annotate(&c)
+	}
+
+	p := s.profiles["AAPL|5m"]
+	if got := p.history[600]; len(got) != 5 || got[0] != 90 || got[4] != 0 {
+		t.Errorf("history of a dropped slot = %v, want [90 0 0 0 0]", got)
+	}
+	if got := p.history[0]; len(got) != 4 {
+		t.Errorf("history of slot 0 = %v, want 4 sessions (the 18th is still open)", got)
+	}
+	// Off-hours slots (after the close) are not filled
+	if _, ok := p.history[p.length]; ok {
+		t.Errorf("slot at the close recorded")
+	}
+}
+
+func TestVolumeSeasonalityFlatFallback(t *testing.T) {
+	s := newTestSeasonality(utils.SeasonalityMethodMean)
+
+	// Fewer than min_days sessions: no slot baseline
+	first := slotCandle(t, 11, 0, 100, true)
+	s.annotate(&first)
+	c := slotCandle(t, 12, 0, 400, false)
+	s.annotate(&c)
+	if c.ExpectedVolume != 0 || c.VolumeAnomalyRatio != 0 {
+		t.Errorf("baseline from one session: %+v", c)
+	}
+
+	// Calendar-period windows are left unchanged
+	day := models.MAggregation{Symbol: "AAPL", WindowName: "day", StartTime: nyseTime(t, 3, 12, 0, 0), Volume: 400, IsClosed: true}
+	s.annotate(&day)
+	if day.ExpectedVolume != 0 || len(s.profiles) != 1 {
+		t.Errorf("day window annotated: %+v", day)
+	}
+
+	// Disabled
+	var disabled *VolumeSeasonality
+	disabled.annotate(&c)
+}
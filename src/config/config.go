@@ -3,8 +3,10 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
 
 	"market-observer/src/models"
+	"market-observer/src/utils"
 
 	"gopkg.in/yaml.v3"
 )
@@ -103,12 +105,393 @@ func (c *Config) Validate() error {
 	}
 
 	// Validate Windows aggregation
+	tableSuffixes := make(map[string]string)
 	for i, window := range c.WindowsAgg {
 		if window == "" {
 			return fmt.Errorf("window aggregation %d cannot be empty", i)
 		}
+		if _, err := utils.ParseWindow(window); err != nil {
+			return fmt.Errorf("invalid window aggregation: %w", err)
+		}
+		// Windows share storage tables by name suffix
+		suffix := utils.WindowTableSuffix(window)
+		if other, ok := tableSuffixes[suffix]; ok {
+			return fmt.Errorf("windows '%s' and '%s' map to the same storage tables", other, window)
+		}
+		tableSuffixes[suffix] = window
+	}
+	// Every window must be buildable from the closed candles of a finer one
+	if _, err := utils.BuildWindowHierarchy(c.WindowsAgg); err != nil {
+		return fmt.Errorf("invalid window hierarchy: %w", err)
+	}
+
+	// Validate Rolling stats (zero values fall back to defaults)
+	switch c.RollingStats.Method {
+	case "", "ewma", "welford":
+	default:
+		return fmt.Errorf("invalid rolling stats method: %s (must be 'ewma' or 'welford')", c.RollingStats.Method)
+	}
+	if c.RollingStats.Lookback < 0 {
+		return fmt.Errorf("rolling stats lookback cannot be negative")
+	}
+	if c.RollingStats.PersistIntervalSeconds < 0 {
+		return fmt.Errorf("rolling stats persist interval cannot be negative")
+	}
+
+	// Validate Correlation (zero values fall back to defaults)
+	if c.Correlation.Lookback < 0 || c.Correlation.BaselineLookback < 0 || c.Correlation.MinObservations < 0 {
+		return fmt.Errorf("correlation lookbacks and min observations cannot be negative")
+	}
+	if c.Correlation.BreakdownBaseline < 0 || c.Correlation.BreakdownBaseline > 1 {
+		return fmt.Errorf("correlation breakdown baseline must be between 0 and 1")
+	}
+	if c.Correlation.BreakdownDrop < 0 || c.Correlation.BreakdownDrop > 2 {
+		return fmt.Errorf("correlation breakdown drop must be between 0 and 2")
+	}
+
+	// Validate Alerts
+	if c.Alerts.MaxEvents < 0 {
+		return fmt.Errorf("alerts max events cannot be negative")
+	}
+	for name, symbols := range c.Alerts.Watchlists {
+		if name == "" {
+			return fmt.Errorf("watchlist name cannot be empty")
+		}
+		if len(symbols) == 0 {
+			return fmt.Errorf("watchlist '%s' must have at least one symbol", name)
+		}
+	}
+
+	// Validate Notifications
+	n := c.Notifications
+	if n.DedupWindowSeconds < 0 || n.QueueSize < 0 || n.MaxDeliveries < 0 {
+		return fmt.Errorf("notifications dedup window, queue size and max deliveries cannot be negative")
+	}
+	channelNames := make(map[string]bool)
+	for _, ch := range n.Channels {
+		if ch.Name == "" {
+			return fmt.Errorf("notification channel name cannot be empty")
+		}
+		if channelNames[ch.Name] {
+			return fmt.Errorf("duplicate notification channel '%s'", ch.Name)
+		}
+		channelNames[ch.Name] = true
+
+		if ch.RateLimitPerMinute < 0 || ch.MaxRetries < 0 || ch.InitialBackoffMs < 0 || ch.MaxBackoffMs < 0 || ch.TimeoutSeconds < 0 {
+			return fmt.Errorf("notification channel '%s' policies cannot be negative", ch.Name)
+		}
+
+		switch ch.Type {
+		case "webhook", "slack", "teams":
+			if ch.Enabled && ch.URL == "" {
+				return fmt.Errorf("notification channel '%s' requires a url", ch.Name)
+			}
+		case "smtp":
+			if ch.Enabled && (ch.SMTPHost == "" || ch.SMTPPort <= 0 || ch.From == "" || len(ch.To) == 0) {
+				return fmt.Errorf("notification channel '%s' requires smtp_host, smtp_port, from and to", ch.Name)
+			}
+		default:
+			return fmt.Errorf("notification channel '%s' has invalid type '%s' (webhook, slack, teams, smtp)", ch.Name, ch.Type)
+		}
+	}
+
+	// Validate Data Quality
+	dq := c.DataQuality
+	switch dq.GapPolicy {
+	case "", utils.GapPolicyNone, utils.GapPolicyForwardFill, utils.GapPolicyInterpolate:
+	default:
+		return fmt.Errorf("data_quality gap_policy '%s' is invalid (none, forward_fill, interpolate)", dq.GapPolicy)
+	}
+	if dq.BarIntervalSeconds < 0 || dq.MaxFillBars < 0 || dq.MaxGaps < 0 {
+		return fmt.Errorf("data_quality bar interval, max fill bars and max gaps cannot be negative")
+	}
+
+	// Validate Benchmarks
+	b := c.Benchmarks
+	if b.Lookback < 0 || b.MinObservations < 0 {
+		return fmt.Errorf("benchmarks lookback and min observations cannot be negative")
+	}
+	if b.MinObservations == 1 || (b.Lookback > 0 && b.MinObservations > b.Lookback) {
+		return fmt.Errorf("benchmarks min_observations must be between 2 and lookback")
+	}
+	for watchlist, symbol := range b.Watchlists {
+		if _, ok := c.Alerts.Watchlists[watchlist]; !ok {
+			return fmt.Errorf("benchmark watchlist '%s' is not defined in alerts.watchlists", watchlist)
+		}
+		if symbol == "" {
+			return fmt.Errorf("benchmark of watchlist '%s' cannot be empty", watchlist)
+		}
 	}
 
+	// Validate inbound validation
+	v := c.Validation
+	if v.JumpSigma < 0 || v.MinJumpPct < 0 || v.JumpMinSamples < 0 || v.JumpConfirmPoints < 0 ||
+		v.StaleRepeatLimit < 0 || v.MaxFutureSeconds < 0 || v.MaxQuarantine < 0 {
+		return fmt.Errorf("validation thresholds cannot be negative")
+	}
+
+	// Validate Volume seasonality
+	vs := c.Seasonality
+	switch vs.Method {
+	case "", utils.SeasonalityMethodFlat, utils.SeasonalityMethodMean, utils.SeasonalityMethodMedian:
+	default:
+		return fmt.Errorf("invalid volume seasonality method: %s", vs.Method)
+	}
+	if vs.LookbackDays < 0 || vs.MinDays < 0 {
+		return fmt.Errorf("volume seasonality lookback_days and min_days cannot be negative")
+	}
+	if vs.LookbackDays > 0 && vs.MinDays > vs.LookbackDays {
+		return fmt.Errorf("volume seasonality min_days cannot exceed lookback_days")
+	}
+
+	// Validate Volatility
+	vol := c.Volatility
+	switch vol.RegimeEstimator {
+	case "", utils.VolEstimatorRealized, utils.VolEstimatorParkinson, utils.VolEstimatorGarmanKlass, utils.VolEstimatorYangZhang:
+	default:
+		return fmt.Errorf("invalid volatility regime estimator: %s", vol.RegimeEstimator)
+	}
+	if vol.Lookback < 0 || vol.RegimeLookback < 0 || vol.RegimeMinSamples < 0 {
+		return fmt.Errorf("volatility lookbacks cannot be negative")
+	}
+	if vol.Lookback == 1 {
+		return fmt.Errorf("volatility lookback must be at least 2")
+	}
+	if vol.HighPercentile < 0 || vol.HighPercentile > 1 || vol.LowPercentile < 0 || vol.LowPercentile > 1 {
+		return fmt.Errorf("volatility percentiles must be between 0 and 1")
+	}
+	if vol.HighPercentile > 0 && vol.LowPercentile >= vol.HighPercentile {
+		return fmt.Errorf("volatility low_percentile must be below high_percentile")
+	}
+
+	// Validate Breadth
+	br := c.Breadth
+	if br.AveragePeriod < 0 || br.AnomalyRatio < 0 || br.MaxHistory < 0 {
+		return fmt.Errorf("breadth settings cannot be negative")
+	}
+
+	// Validate Leaderboards
+	if c.Leaderboards.Size < 0 {
+		return fmt.Errorf("leaderboards size cannot be negative")
+	}
+
+	// Validate Synthetic instruments (legs must be symbols of a source)
+	symbols := SourceSymbols(c.MConfig)
+	names := make(map[string]bool)
+	for _, def := range c.Synthetics {
+		if names[def.Name] {
+			return fmt.Errorf("duplicate synthetic instrument '%s'", def.Name)
+		}
+		names[def.Name] = true
+		if err := ValidateSyntheticInstrument(def, symbols); err != nil {
+			return err
+		}
+	}
+
+	// Validate Candlestick patterns
+	pc := c.Patterns
+	for _, name := range pc.Patterns {
+		if !slices.Contains(utils.CandlePatterns, name) {
+			return fmt.Errorf("unknown candle pattern: %s", name)
+		}
+	}
+	if pc.DojiBodyRatio < 0 || pc.ShadowRatio < 0 || pc.StarBodyRatio < 0 || pc.MaxEvents < 0 {
+		return fmt.Errorf("candle_patterns settings cannot be negative")
+	}
+	if pc.DojiBodyRatio > 1 || pc.StarBodyRatio > 1 {
+		return fmt.Errorf("candle_patterns doji_body_ratio and star_body_ratio must be between 0 and 1")
+	}
+
+	// Validate Support/resistance levels
+	lc := c.Levels
+	if lc.RefreshIntervalSeconds < 0 || lc.SwingBars < 0 || lc.BucketPct < 0 || lc.VolumeNodeRatio < 0 ||
+		lc.MergePct < 0 || lc.MaxLevels < 0 || lc.BreakoutVolumeRatio < 0 || lc.MaxEvents < 0 {
+		return fmt.Errorf("levels settings cannot be negative")
+	}
+	if lc.BucketPct >= 1 || lc.MergePct >= 1 {
+		return fmt.Errorf("levels bucket_pct and merge_pct must be below 1")
+	}
+	if lc.Window != "" {
+		if err := c.validateWindowName("levels", lc.Window); err != nil {
+			return err
+		}
+	}
+
+	// Validate Lead-lag (pairs and watchlists are only required when enabled)
+	ll := c.LeadLag
+	if ll.MaxLag < 0 || ll.Lookback < 0 || ll.Segments < 0 || ll.MinObservations < 0 {
+		return fmt.Errorf("lead_lag settings cannot be negative")
+	}
+	if ll.Window != "" {
+		if err := c.validateWindowName("lead_lag", ll.Window); err != nil {
+			return err
+		}
+	}
+	for _, pair := range ll.Pairs {
+		if pair.A == "" || pair.B == "" || pair.A == pair.B {
+			return fmt.Errorf("lead_lag pair %s/%s must name two different symbols", pair.A, pair.B)
+		}
+	}
+	for _, name := range ll.Watchlists {
+		if _, ok := c.Alerts.Watchlists[name]; !ok {
+			return fmt.Errorf("lead_lag watchlist '%s' is not defined in alerts.watchlists", name)
+		}
+	}
+	if ll.Enabled && len(ll.Pairs) == 0 && len(ll.Watchlists) == 0 {
+		return fmt.Errorf("lead_lag needs pairs or watchlists when enabled")
+	}
+
+	// Validate Clustering
+	cl := c.Clustering
+	if cl.Clusters < 0 || cl.LookbackDays < 0 || cl.Slots < 0 || cl.MinSessionPoints < 0 ||
+		cl.RefreshIntervalSeconds < 0 || cl.Restarts < 0 || cl.MaxJumps < 0 {
+		return fmt.Errorf("clustering settings cannot be negative")
+	}
+	if cl.Clusters == 1 {
+		return fmt.Errorf("clustering needs at least 2 clusters")
+	}
+
+	// Validate Event study
+	es := c.EventStudy
+	if es.LookbackDays < 0 || es.RefreshIntervalSeconds < 0 || es.MaxEvents < 0 {
+		return fmt.Errorf("event_study settings cannot be negative")
+	}
+	for _, window := range es.Windows {
+		if err := c.validateWindowName("event_study", window); err != nil {
+			return err
+		}
+	}
+	for _, h := range es.Horizons {
+		if h <= 0 {
+			return fmt.Errorf("event_study horizons must be positive, got %d", h)
+		}
+	}
+	for i, s := range es.Severities {
+		if s <= 0 || (i > 0 && s <= es.Severities[i-1]) {
+			return fmt.Errorf("event_study severities must be positive and ascending")
+		}
+	}
+	if es.Model != "" && es.Model != utils.EventStudyModelMarket && es.Model != utils.EventStudyModelBeta {
+		return fmt.Errorf("event_study model must be '%s' or '%s', got '%s'", utils.EventStudyModelMarket, utils.EventStudyModelBeta, es.Model)
+	}
+
+	// Validate Volume profile
+	vp := c.VolumeProfile
+	if vp.Buckets < 0 || vp.MaxBuckets < 0 {
+		return fmt.Errorf("volume_profile buckets and max_buckets cannot be negative")
+	}
+	if vp.ValueAreaPct < 0 || vp.ValueAreaPct > 1 {
+		return fmt.Errorf("volume_profile value_area_pct must be between 0 and 1")
+	}
+	if vp.MaxBuckets > 0 && vp.Buckets > vp.MaxBuckets {
+		return fmt.Errorf("volume_profile buckets cannot exceed max_buckets")
+	}
+
+	// Validate Changepoints (defaults and per-window overrides)
+	cp := c.Changepoints
+	if cp.MaxEvents < 0 {
+		return fmt.Errorf("changepoints max_events cannot be negative")
+	}
+	if err := validateChangepointSettings("changepoints", cp.MChangepointSettings); err != nil {
+		return err
+	}
+	for name, settings := range cp.Windows {
+		if err := c.validateWindowName("changepoints", name); err != nil {
+			return err
+		}
+		if err := validateChangepointSettings("changepoints window '"+name+"'", settings); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// validateWindowName checks that a window referenced by a config section is aggregated.
+func (c *Config) validateWindowName(section, name string) error {
+	if !slices.Contains(c.WindowsAgg, name) {
+		return fmt.Errorf("%s window '%s' is not in windows_aggregation", section, name)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func validateChangepointSettings(label string, s models.MChangepointSettings) error {
+	switch s.Method {
+	case "", utils.ChangepointMethodCUSUM, utils.ChangepointMethodBOCPD:
+	default:
+		return fmt.Errorf("invalid %s method: %s", label, s.Method)
+	}
+	if s.Threshold < 0 || s.Drift < 0 || s.Hazard < 0 || s.MinSegment < 0 {
+		return fmt.Errorf("%s settings cannot be negative", label)
+	}
+	if s.Confidence < 0 || s.Confidence > 1 {
+		return fmt.Errorf("%s confidence must be between 0 and 1", label)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// SourceSymbols returns the set of symbols configured on the data sources.
+func SourceSymbols(cfg *models.MConfig) map[string]bool {
+	symbols := make(map[string]bool)
+	for _, src := range cfg.DataSource.Sources {
+		for _, sym := range src.Symbols {
+			symbols[sym] = true
+		}
+	}
+	return symbols
+}
+
+// -----------------------------------------------------------------------------
+
+// ValidateSyntheticInstrument checks a synthetic instrument definition against
+// the symbols of the data sources (legs cannot be other synthetic instruments).
+func ValidateSyntheticInstrument(def models.MSyntheticInstrument, symbols map[string]bool) error {
+	if def.Name == "" {
+		return fmt.Errorf("synthetic instrument must have a name")
+	}
+	if symbols[def.Name] {
+		return fmt.Errorf("synthetic instrument '%s' is already a source symbol", def.Name)
+	}
+
+	switch def.Type {
+	case utils.SyntheticRatio:
+		if len(def.Legs) != 2 {
+			return fmt.Errorf("synthetic ratio '%s' must have exactly 2 legs", def.Name)
+		}
+	case utils.SyntheticSpread:
+		if len(def.Legs) < 2 {
+			return fmt.Errorf("synthetic spread '%s' must have at least 2 legs", def.Name)
+		}
+	case utils.SyntheticBasket:
+		if len(def.Legs) == 0 {
+			return fmt.Errorf("synthetic basket '%s' must have at least 1 leg", def.Name)
+		}
+	default:
+		return fmt.Errorf("invalid synthetic instrument type '%s' for '%s'", def.Type, def.Name)
+	}
+
+	seen := make(map[string]bool)
+	for i, leg := range def.Legs {
+		if leg.Symbol == "" {
+			return fmt.Errorf("synthetic instrument '%s' leg %d must have a symbol", def.Name, i)
+		}
+		if !symbols[leg.Symbol] {
+			return fmt.Errorf("synthetic instrument '%s' leg '%s' is not a source symbol", def.Name, leg.Symbol)
+		}
+		if seen[leg.Symbol] {
+			return fmt.Errorf("synthetic instrument '%s' has duplicate leg '%s'", def.Name, leg.Symbol)
+		}
+		seen[leg.Symbol] = true
+		if def.Type == utils.SyntheticSpread && leg.Weight < 0 {
+			return fmt.Errorf("synthetic spread '%s' hedge ratios cannot be negative", def.Name)
+		}
+	}
 	return nil
 }
 
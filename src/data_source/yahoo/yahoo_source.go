@@ -391,6 +391,9 @@ func (s *YahooFinanceSource) parseChartResponse(symbol string, data []byte) ([]m
 			PricePercentChange:  pricePct,
 			VolumePercentChange: volPct,
 			CreatedAt:           time.Now().UTC(),
+			Open:                point.open,
+			High:                point.high,
+			Low:                 point.low,
 		}
 
 		timeSeries = append(timeSeries, item)
@@ -549,6 +549,2154 @@ func (x *SourceStatus) GetType() string {
 	return ""
 }
 
+type CorrelationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        string                 `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"` // e.g., "15m"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CorrelationRequest) Reset() {
+	*x = CorrelationRequest{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CorrelationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CorrelationRequest) ProtoMessage() {}
+
+func (x *CorrelationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CorrelationRequest.ProtoReflect.Descriptor instead.
+func (*CorrelationRequest) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CorrelationRequest) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+type CorrelationRow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []float64              `protobuf:"fixed64,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CorrelationRow) Reset() {
+	*x = CorrelationRow{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CorrelationRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CorrelationRow) ProtoMessage() {}
+
+func (x *CorrelationRow) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CorrelationRow.ProtoReflect.Descriptor instead.
+func (*CorrelationRow) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CorrelationRow) GetValues() []float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type CorrelationMatrixResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        string                 `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	Symbols       []string               `protobuf:"bytes,2,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	Rows          []*CorrelationRow      `protobuf:"bytes,3,rep,name=rows,proto3" json:"rows,omitempty"` // rows[i].values[j] = corr(symbols[i], symbols[j])
+	Lookback      int32                  `protobuf:"varint,4,opt,name=lookback,proto3" json:"lookback,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CorrelationMatrixResponse) Reset() {
+	*x = CorrelationMatrixResponse{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CorrelationMatrixResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CorrelationMatrixResponse) ProtoMessage() {}
+
+func (x *CorrelationMatrixResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CorrelationMatrixResponse.ProtoReflect.Descriptor instead.
+func (*CorrelationMatrixResponse) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CorrelationMatrixResponse) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *CorrelationMatrixResponse) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *CorrelationMatrixResponse) GetRows() []*CorrelationRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *CorrelationMatrixResponse) GetLookback() int32 {
+	if x != nil {
+		return x.Lookback
+	}
+	return 0
+}
+
+func (x *CorrelationMatrixResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type TopCorrelatedPairsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        string                 `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // 0 = all pairs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TopCorrelatedPairsRequest) Reset() {
+	*x = TopCorrelatedPairsRequest{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TopCorrelatedPairsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopCorrelatedPairsRequest) ProtoMessage() {}
+
+func (x *TopCorrelatedPairsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopCorrelatedPairsRequest.ProtoReflect.Descriptor instead.
+func (*TopCorrelatedPairsRequest) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TopCorrelatedPairsRequest) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *TopCorrelatedPairsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type CorrelationPair struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SymbolA       string                 `protobuf:"bytes,1,opt,name=symbol_a,json=symbolA,proto3" json:"symbol_a,omitempty"`
+	SymbolB       string                 `protobuf:"bytes,2,opt,name=symbol_b,json=symbolB,proto3" json:"symbol_b,omitempty"`
+	Correlation   float64                `protobuf:"fixed64,3,opt,name=correlation,proto3" json:"correlation,omitempty"`
+	Baseline      float64                `protobuf:"fixed64,4,opt,name=baseline,proto3" json:"baseline,omitempty"`
+	Observations  int32                  `protobuf:"varint,5,opt,name=observations,proto3" json:"observations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CorrelationPair) Reset() {
+	*x = CorrelationPair{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CorrelationPair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CorrelationPair) ProtoMessage() {}
+
+func (x *CorrelationPair) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CorrelationPair.ProtoReflect.Descriptor instead.
+func (*CorrelationPair) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CorrelationPair) GetSymbolA() string {
+	if x != nil {
+		return x.SymbolA
+	}
+	return ""
+}
+
+func (x *CorrelationPair) GetSymbolB() string {
+	if x != nil {
+		return x.SymbolB
+	}
+	return ""
+}
+
+func (x *CorrelationPair) GetCorrelation() float64 {
+	if x != nil {
+		return x.Correlation
+	}
+	return 0
+}
+
+func (x *CorrelationPair) GetBaseline() float64 {
+	if x != nil {
+		return x.Baseline
+	}
+	return 0
+}
+
+func (x *CorrelationPair) GetObservations() int32 {
+	if x != nil {
+		return x.Observations
+	}
+	return 0
+}
+
+type TopCorrelatedPairsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        string                 `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	Pairs         []*CorrelationPair     `protobuf:"bytes,2,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TopCorrelatedPairsResponse) Reset() {
+	*x = TopCorrelatedPairsResponse{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TopCorrelatedPairsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopCorrelatedPairsResponse) ProtoMessage() {}
+
+func (x *TopCorrelatedPairsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopCorrelatedPairsResponse.ProtoReflect.Descriptor instead.
+func (*TopCorrelatedPairsResponse) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *TopCorrelatedPairsResponse) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *TopCorrelatedPairsResponse) GetPairs() []*CorrelationPair {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+type CorrelationAlert struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        string                 `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	SymbolA       string                 `protobuf:"bytes,2,opt,name=symbol_a,json=symbolA,proto3" json:"symbol_a,omitempty"`
+	SymbolB       string                 `protobuf:"bytes,3,opt,name=symbol_b,json=symbolB,proto3" json:"symbol_b,omitempty"`
+	Correlation   float64                `protobuf:"fixed64,4,opt,name=correlation,proto3" json:"correlation,omitempty"`
+	Baseline      float64                `protobuf:"fixed64,5,opt,name=baseline,proto3" json:"baseline,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CorrelationAlert) Reset() {
+	*x = CorrelationAlert{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CorrelationAlert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CorrelationAlert) ProtoMessage() {}
+
+func (x *CorrelationAlert) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CorrelationAlert.ProtoReflect.Descriptor instead.
+func (*CorrelationAlert) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CorrelationAlert) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *CorrelationAlert) GetSymbolA() string {
+	if x != nil {
+		return x.SymbolA
+	}
+	return ""
+}
+
+func (x *CorrelationAlert) GetSymbolB() string {
+	if x != nil {
+		return x.SymbolB
+	}
+	return ""
+}
+
+func (x *CorrelationAlert) GetCorrelation() float64 {
+	if x != nil {
+		return x.Correlation
+	}
+	return 0
+}
+
+func (x *CorrelationAlert) GetBaseline() float64 {
+	if x != nil {
+		return x.Baseline
+	}
+	return 0
+}
+
+func (x *CorrelationAlert) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type CorrelationAlertsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alerts        []*CorrelationAlert    `protobuf:"bytes,1,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CorrelationAlertsResponse) Reset() {
+	*x = CorrelationAlertsResponse{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CorrelationAlertsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CorrelationAlertsResponse) ProtoMessage() {}
+
+func (x *CorrelationAlertsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CorrelationAlertsResponse.ProtoReflect.Descriptor instead.
+func (*CorrelationAlertsResponse) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CorrelationAlertsResponse) GetAlerts() []*CorrelationAlert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+type AlertRule struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Condition       string                 `protobuf:"bytes,3,opt,name=condition,proto3" json:"condition,omitempty"` // e.g., "volume_anomaly_ratio > 3 AND price_percent_change > 0.02 on 15m"
+	Symbols         []string               `protobuf:"bytes,4,rep,name=symbols,proto3" json:"symbols,omitempty"`     // Empty = all symbols
+	Watchlist       string                 `protobuf:"bytes,5,opt,name=watchlist,proto3" json:"watchlist,omitempty"` // Named list from config (alerts.watchlists)
+	CooldownSeconds int64                  `protobuf:"varint,6,opt,name=cooldown_seconds,json=cooldownSeconds,proto3" json:"cooldown_seconds,omitempty"`
+	Hysteresis      float64                `protobuf:"fixed64,7,opt,name=hysteresis,proto3" json:"hysteresis,omitempty"`
+	Enabled         bool                   `protobuf:"varint,8,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	CreatedAt       int64                  `protobuf:"varint,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       int64                  `protobuf:"varint,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	OpenCandles     bool                   `protobuf:"varint,11,opt,name=open_candles,json=openCandles,proto3" json:"open_candles,omitempty"` // Also evaluate still-open candles (may fire on intermediate values)
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AlertRule) Reset() {
+	*x = AlertRule{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRule) ProtoMessage() {}
+
+func (x *AlertRule) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRule.ProtoReflect.Descriptor instead.
+func (*AlertRule) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AlertRule) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AlertRule) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AlertRule) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *AlertRule) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *AlertRule) GetWatchlist() string {
+	if x != nil {
+		return x.Watchlist
+	}
+	return ""
+}
+
+func (x *AlertRule) GetCooldownSeconds() int64 {
+	if x != nil {
+		return x.CooldownSeconds
+	}
+	return 0
+}
+
+func (x *AlertRule) GetHysteresis() float64 {
+	if x != nil {
+		return x.Hysteresis
+	}
+	return 0
+}
+
+func (x *AlertRule) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *AlertRule) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *AlertRule) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *AlertRule) GetOpenCandles() bool {
+	if x != nil {
+		return x.OpenCandles
+	}
+	return false
+}
+
+type AlertRuleIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertRuleIdRequest) Reset() {
+	*x = AlertRuleIdRequest{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertRuleIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRuleIdRequest) ProtoMessage() {}
+
+func (x *AlertRuleIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRuleIdRequest.ProtoReflect.Descriptor instead.
+func (*AlertRuleIdRequest) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AlertRuleIdRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type AlertRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Rule          *AlertRule             `protobuf:"bytes,3,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertRuleResponse) Reset() {
+	*x = AlertRuleResponse{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRuleResponse) ProtoMessage() {}
+
+func (x *AlertRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRuleResponse.ProtoReflect.Descriptor instead.
+func (*AlertRuleResponse) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AlertRuleResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AlertRuleResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AlertRuleResponse) GetRule() *AlertRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type ListAlertRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*AlertRule           `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertRulesResponse) Reset() {
+	*x = ListAlertRulesResponse{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertRulesResponse) ProtoMessage() {}
+
+func (x *ListAlertRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListAlertRulesResponse) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListAlertRulesResponse) GetRules() []*AlertRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type ListAlertEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // 0 = all kept events
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertEventsRequest) Reset() {
+	*x = ListAlertEventsRequest{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertEventsRequest) ProtoMessage() {}
+
+func (x *ListAlertEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAlertEventsRequest) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListAlertEventsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type AlertEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleId        int64                  `protobuf:"varint,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	RuleName      string                 `protobuf:"bytes,2,opt,name=rule_name,json=ruleName,proto3" json:"rule_name,omitempty"`
+	Condition     string                 `protobuf:"bytes,3,opt,name=condition,proto3" json:"condition,omitempty"`
+	Symbol        string                 `protobuf:"bytes,4,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Window        string                 `protobuf:"bytes,5,opt,name=window,proto3" json:"window,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Values        map[string]float64     `protobuf:"bytes,7,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	Message       string                 `protobuf:"bytes,8,opt,name=message,proto3" json:"message,omitempty"`
+	StartTime     int64                  `protobuf:"varint,9,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       int64                  `protobuf:"varint,10,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Open          float64                `protobuf:"fixed64,11,opt,name=open,proto3" json:"open,omitempty"`
+	High          float64                `protobuf:"fixed64,12,opt,name=high,proto3" json:"high,omitempty"`
+	Low           float64                `protobuf:"fixed64,13,opt,name=low,proto3" json:"low,omitempty"`
+	Close         float64                `protobuf:"fixed64,14,opt,name=close,proto3" json:"close,omitempty"`
+	Volume        float64                `protobuf:"fixed64,15,opt,name=volume,proto3" json:"volume,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertEvent) Reset() {
+	*x = AlertEvent{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertEvent) ProtoMessage() {}
+
+func (x *AlertEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertEvent.ProtoReflect.Descriptor instead.
+func (*AlertEvent) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AlertEvent) GetRuleId() int64 {
+	if x != nil {
+		return x.RuleId
+	}
+	return 0
+}
+
+func (x *AlertEvent) GetRuleName() string {
+	if x != nil {
+		return x.RuleName
+	}
+	return ""
+}
+
+func (x *AlertEvent) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *AlertEvent) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *AlertEvent) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *AlertEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *AlertEvent) GetValues() map[string]float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *AlertEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AlertEvent) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *AlertEvent) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *AlertEvent) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *AlertEvent) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *AlertEvent) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *AlertEvent) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *AlertEvent) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+type ListAlertEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*AlertEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertEventsResponse) Reset() {
+	*x = ListAlertEventsResponse{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertEventsResponse) ProtoMessage() {}
+
+func (x *ListAlertEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListAlertEventsResponse) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListAlertEventsResponse) GetEvents() []*AlertEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type ScreenFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"` // Candle field, indicator or "metrics.<analyzer>.<metric>"
+	Op            string                 `protobuf:"bytes,2,opt,name=op,proto3" json:"op,omitempty"`       // ">", ">=", "<", "<=", "==", "!="
+	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScreenFilter) Reset() {
+	*x = ScreenFilter{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScreenFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScreenFilter) ProtoMessage() {}
+
+func (x *ScreenFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScreenFilter.ProtoReflect.Descriptor instead.
+func (*ScreenFilter) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ScreenFilter) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ScreenFilter) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *ScreenFilter) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type ScreenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        string                 `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	Filters       []*ScreenFilter        `protobuf:"bytes,2,rep,name=filters,proto3" json:"filters,omitempty"`             // All must hold
+	Condition     string                 `protobuf:"bytes,3,opt,name=condition,proto3" json:"condition,omitempty"`         // Extra alert rule expression (no "on")
+	Symbols       []string               `protobuf:"bytes,4,rep,name=symbols,proto3" json:"symbols,omitempty"`             // Empty = all symbols
+	Watchlist     string                 `protobuf:"bytes,5,opt,name=watchlist,proto3" json:"watchlist,omitempty"`         // Named list from config (alerts.watchlists)
+	SortBy        string                 `protobuf:"bytes,6,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"` // Empty = by symbol
+	Ascending     bool                   `protobuf:"varint,7,opt,name=ascending,proto3" json:"ascending,omitempty"`        // Default descending
+	Limit         int32                  `protobuf:"varint,8,opt,name=limit,proto3" json:"limit,omitempty"`                // 0 = all matches
+	Fields        []string               `protobuf:"bytes,9,rep,name=fields,proto3" json:"fields,omitempty"`               // Extra values returned with each match
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScreenRequest) Reset() {
+	*x = ScreenRequest{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScreenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScreenRequest) ProtoMessage() {}
+
+func (x *ScreenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScreenRequest.ProtoReflect.Descriptor instead.
+func (*ScreenRequest) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ScreenRequest) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *ScreenRequest) GetFilters() []*ScreenFilter {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *ScreenRequest) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *ScreenRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *ScreenRequest) GetWatchlist() string {
+	if x != nil {
+		return x.Watchlist
+	}
+	return ""
+}
+
+func (x *ScreenRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ScreenRequest) GetAscending() bool {
+	if x != nil {
+		return x.Ascending
+	}
+	return false
+}
+
+func (x *ScreenRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ScreenRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type ScreenMatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Values        map[string]float64     `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	StartTime     int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       int64                  `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Open          float64                `protobuf:"fixed64,5,opt,name=open,proto3" json:"open,omitempty"`
+	High          float64                `protobuf:"fixed64,6,opt,name=high,proto3" json:"high,omitempty"`
+	Low           float64                `protobuf:"fixed64,7,opt,name=low,proto3" json:"low,omitempty"`
+	Close         float64                `protobuf:"fixed64,8,opt,name=close,proto3" json:"close,omitempty"`
+	Volume        float64                `protobuf:"fixed64,9,opt,name=volume,proto3" json:"volume,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScreenMatch) Reset() {
+	*x = ScreenMatch{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScreenMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScreenMatch) ProtoMessage() {}
+
+func (x *ScreenMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScreenMatch.ProtoReflect.Descriptor instead.
+func (*ScreenMatch) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ScreenMatch) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *ScreenMatch) GetValues() map[string]float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *ScreenMatch) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *ScreenMatch) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *ScreenMatch) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *ScreenMatch) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *ScreenMatch) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *ScreenMatch) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *ScreenMatch) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+type ScreenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        string                 `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Total         int32                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"` // Matches before the limit
+	Matches       []*ScreenMatch         `protobuf:"bytes,4,rep,name=matches,proto3" json:"matches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScreenResponse) Reset() {
+	*x = ScreenResponse{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScreenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScreenResponse) ProtoMessage() {}
+
+func (x *ScreenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScreenResponse.ProtoReflect.Descriptor instead.
+func (*ScreenResponse) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ScreenResponse) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *ScreenResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *ScreenResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ScreenResponse) GetMatches() []*ScreenMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+type SavedScreen struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Request       *ScreenRequest         `protobuf:"bytes,3,opt,name=request,proto3" json:"request,omitempty"`
+	Enabled       bool                   `protobuf:"varint,4,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SavedScreen) Reset() {
+	*x = SavedScreen{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SavedScreen) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SavedScreen) ProtoMessage() {}
+
+func (x *SavedScreen) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SavedScreen.ProtoReflect.Descriptor instead.
+func (*SavedScreen) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SavedScreen) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SavedScreen) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SavedScreen) GetRequest() *ScreenRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *SavedScreen) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *SavedScreen) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *SavedScreen) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type ScreenIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScreenIdRequest) Reset() {
+	*x = ScreenIdRequest{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScreenIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScreenIdRequest) ProtoMessage() {}
+
+func (x *ScreenIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScreenIdRequest.ProtoReflect.Descriptor instead.
+func (*ScreenIdRequest) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ScreenIdRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type SavedScreenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Screen        *SavedScreen           `protobuf:"bytes,3,opt,name=screen,proto3" json:"screen,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SavedScreenResponse) Reset() {
+	*x = SavedScreenResponse{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SavedScreenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SavedScreenResponse) ProtoMessage() {}
+
+func (x *SavedScreenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SavedScreenResponse.ProtoReflect.Descriptor instead.
+func (*SavedScreenResponse) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SavedScreenResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SavedScreenResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SavedScreenResponse) GetScreen() *SavedScreen {
+	if x != nil {
+		return x.Screen
+	}
+	return nil
+}
+
+type ListScreensResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Screens       []*SavedScreen         `protobuf:"bytes,1,rep,name=screens,proto3" json:"screens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScreensResponse) Reset() {
+	*x = ListScreensResponse{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScreensResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScreensResponse) ProtoMessage() {}
+
+func (x *ListScreensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScreensResponse.ProtoReflect.Descriptor instead.
+func (*ListScreensResponse) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListScreensResponse) GetScreens() []*SavedScreen {
+	if x != nil {
+		return x.Screens
+	}
+	return nil
+}
+
+type SyntheticLeg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Weight        float64                `protobuf:"fixed64,2,opt,name=weight,proto3" json:"weight,omitempty"` // Basket weight or spread hedge ratio (0 = 1)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyntheticLeg) Reset() {
+	*x = SyntheticLeg{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyntheticLeg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyntheticLeg) ProtoMessage() {}
+
+func (x *SyntheticLeg) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyntheticLeg.ProtoReflect.Descriptor instead.
+func (*SyntheticLeg) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *SyntheticLeg) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *SyntheticLeg) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type SyntheticInstrument struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // "ratio", "spread" or "basket"
+	Legs          []*SyntheticLeg        `protobuf:"bytes,3,rep,name=legs,proto3" json:"legs,omitempty"`
+	Offset        float64                `protobuf:"fixed64,4,opt,name=offset,proto3" json:"offset,omitempty"` // Added to the computed price
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyntheticInstrument) Reset() {
+	*x = SyntheticInstrument{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyntheticInstrument) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyntheticInstrument) ProtoMessage() {}
+
+func (x *SyntheticInstrument) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyntheticInstrument.ProtoReflect.Descriptor instead.
+func (*SyntheticInstrument) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *SyntheticInstrument) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SyntheticInstrument) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SyntheticInstrument) GetLegs() []*SyntheticLeg {
+	if x != nil {
+		return x.Legs
+	}
+	return nil
+}
+
+func (x *SyntheticInstrument) GetOffset() float64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type SyntheticInstrumentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyntheticInstrumentRequest) Reset() {
+	*x = SyntheticInstrumentRequest{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyntheticInstrumentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyntheticInstrumentRequest) ProtoMessage() {}
+
+func (x *SyntheticInstrumentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyntheticInstrumentRequest.ProtoReflect.Descriptor instead.
+func (*SyntheticInstrumentRequest) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SyntheticInstrumentRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type SyntheticInstrumentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Instrument    *SyntheticInstrument   `protobuf:"bytes,3,opt,name=instrument,proto3" json:"instrument,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyntheticInstrumentResponse) Reset() {
+	*x = SyntheticInstrumentResponse{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyntheticInstrumentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyntheticInstrumentResponse) ProtoMessage() {}
+
+func (x *SyntheticInstrumentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyntheticInstrumentResponse.ProtoReflect.Descriptor instead.
+func (*SyntheticInstrumentResponse) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SyntheticInstrumentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SyntheticInstrumentResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SyntheticInstrumentResponse) GetInstrument() *SyntheticInstrument {
+	if x != nil {
+		return x.Instrument
+	}
+	return nil
+}
+
+type ListSyntheticInstrumentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instruments   []*SyntheticInstrument `protobuf:"bytes,1,rep,name=instruments,proto3" json:"instruments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSyntheticInstrumentsResponse) Reset() {
+	*x = ListSyntheticInstrumentsResponse{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSyntheticInstrumentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSyntheticInstrumentsResponse) ProtoMessage() {}
+
+func (x *ListSyntheticInstrumentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSyntheticInstrumentsResponse.ProtoReflect.Descriptor instead.
+func (*ListSyntheticInstrumentsResponse) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListSyntheticInstrumentsResponse) GetInstruments() []*SyntheticInstrument {
+	if x != nil {
+		return x.Instruments
+	}
+	return nil
+}
+
+type VolumeProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Session       string                 `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`                                   // YYYY-MM-DD in the exchange timezone (exclusive with from/to)
+	From          int64                  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`                                        // Unix seconds, inclusive (session and from/to empty = latest session)
+	To            int64                  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`                                            // Unix seconds, exclusive (0 = now)
+	Buckets       int32                  `protobuf:"varint,5,opt,name=buckets,proto3" json:"buckets,omitempty"`                                  // 0 = configured default
+	BucketSize    float64                `protobuf:"fixed64,6,opt,name=bucket_size,json=bucketSize,proto3" json:"bucket_size,omitempty"`         // Takes precedence over buckets
+	ValueAreaPct  float64                `protobuf:"fixed64,7,opt,name=value_area_pct,json=valueAreaPct,proto3" json:"value_area_pct,omitempty"` // 0 = configured default
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VolumeProfileRequest) Reset() {
+	*x = VolumeProfileRequest{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VolumeProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VolumeProfileRequest) ProtoMessage() {}
+
+func (x *VolumeProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VolumeProfileRequest.ProtoReflect.Descriptor instead.
+func (*VolumeProfileRequest) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *VolumeProfileRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *VolumeProfileRequest) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+func (x *VolumeProfileRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *VolumeProfileRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *VolumeProfileRequest) GetBuckets() int32 {
+	if x != nil {
+		return x.Buckets
+	}
+	return 0
+}
+
+func (x *VolumeProfileRequest) GetBucketSize() float64 {
+	if x != nil {
+		return x.BucketSize
+	}
+	return 0
+}
+
+func (x *VolumeProfileRequest) GetValueAreaPct() float64 {
+	if x != nil {
+		return x.ValueAreaPct
+	}
+	return 0
+}
+
+type VolumeProfileBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PriceLow      float64                `protobuf:"fixed64,1,opt,name=price_low,json=priceLow,proto3" json:"price_low,omitempty"`
+	PriceHigh     float64                `protobuf:"fixed64,2,opt,name=price_high,json=priceHigh,proto3" json:"price_high,omitempty"`
+	Volume        float64                `protobuf:"fixed64,3,opt,name=volume,proto3" json:"volume,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VolumeProfileBucket) Reset() {
+	*x = VolumeProfileBucket{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VolumeProfileBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VolumeProfileBucket) ProtoMessage() {}
+
+func (x *VolumeProfileBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VolumeProfileBucket.ProtoReflect.Descriptor instead.
+func (*VolumeProfileBucket) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *VolumeProfileBucket) GetPriceLow() float64 {
+	if x != nil {
+		return x.PriceLow
+	}
+	return 0
+}
+
+func (x *VolumeProfileBucket) GetPriceHigh() float64 {
+	if x != nil {
+		return x.PriceHigh
+	}
+	return 0
+}
+
+func (x *VolumeProfileBucket) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+type VolumeProfileResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Symbol          string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Session         string                 `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
+	From            int64                  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`
+	To              int64                  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`
+	Source          string                 `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"` // "memory" or "storage"
+	Bars            int32                  `protobuf:"varint,6,opt,name=bars,proto3" json:"bars,omitempty"`
+	RangedBars      int32                  `protobuf:"varint,7,opt,name=ranged_bars,json=rangedBars,proto3" json:"ranged_bars,omitempty"`
+	BucketSize      float64                `protobuf:"fixed64,8,opt,name=bucket_size,json=bucketSize,proto3" json:"bucket_size,omitempty"`
+	Buckets         []*VolumeProfileBucket `protobuf:"bytes,9,rep,name=buckets,proto3" json:"buckets,omitempty"` // Lowest price first
+	TotalVolume     float64                `protobuf:"fixed64,10,opt,name=total_volume,json=totalVolume,proto3" json:"total_volume,omitempty"`
+	PointOfControl  float64                `protobuf:"fixed64,11,opt,name=point_of_control,json=pointOfControl,proto3" json:"point_of_control,omitempty"`
+	PocVolume       float64                `protobuf:"fixed64,12,opt,name=poc_volume,json=pocVolume,proto3" json:"poc_volume,omitempty"`
+	ValueAreaLow    float64                `protobuf:"fixed64,13,opt,name=value_area_low,json=valueAreaLow,proto3" json:"value_area_low,omitempty"`
+	ValueAreaHigh   float64                `protobuf:"fixed64,14,opt,name=value_area_high,json=valueAreaHigh,proto3" json:"value_area_high,omitempty"`
+	ValueAreaVolume float64                `protobuf:"fixed64,15,opt,name=value_area_volume,json=valueAreaVolume,proto3" json:"value_area_volume,omitempty"`
+	ValueAreaPct    float64                `protobuf:"fixed64,16,opt,name=value_area_pct,json=valueAreaPct,proto3" json:"value_area_pct,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *VolumeProfileResponse) Reset() {
+	*x = VolumeProfileResponse{}
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VolumeProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VolumeProfileResponse) ProtoMessage() {}
+
+func (x *VolumeProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_src_grpc_control_market_observer_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VolumeProfileResponse.ProtoReflect.Descriptor instead.
+func (*VolumeProfileResponse) Descriptor() ([]byte, []int) {
+	return file_src_grpc_control_market_observer_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *VolumeProfileResponse) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *VolumeProfileResponse) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+func (x *VolumeProfileResponse) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *VolumeProfileResponse) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *VolumeProfileResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *VolumeProfileResponse) GetBars() int32 {
+	if x != nil {
+		return x.Bars
+	}
+	return 0
+}
+
+func (x *VolumeProfileResponse) GetRangedBars() int32 {
+	if x != nil {
+		return x.RangedBars
+	}
+	return 0
+}
+
+func (x *VolumeProfileResponse) GetBucketSize() float64 {
+	if x != nil {
+		return x.BucketSize
+	}
+	return 0
+}
+
+func (x *VolumeProfileResponse) GetBuckets() []*VolumeProfileBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *VolumeProfileResponse) GetTotalVolume() float64 {
+	if x != nil {
+		return x.TotalVolume
+	}
+	return 0
+}
+
+func (x *VolumeProfileResponse) GetPointOfControl() float64 {
+	if x != nil {
+		return x.PointOfControl
+	}
+	return 0
+}
+
+func (x *VolumeProfileResponse) GetPocVolume() float64 {
+	if x != nil {
+		return x.PocVolume
+	}
+	return 0
+}
+
+func (x *VolumeProfileResponse) GetValueAreaLow() float64 {
+	if x != nil {
+		return x.ValueAreaLow
+	}
+	return 0
+}
+
+func (x *VolumeProfileResponse) GetValueAreaHigh() float64 {
+	if x != nil {
+		return x.ValueAreaHigh
+	}
+	return 0
+}
+
+func (x *VolumeProfileResponse) GetValueAreaVolume() float64 {
+	if x != nil {
+		return x.ValueAreaVolume
+	}
+	return 0
+}
+
+func (x *VolumeProfileResponse) GetValueAreaPct() float64 {
+	if x != nil {
+		return x.ValueAreaPct
+	}
+	return 0
+}
+
 var File_src_grpc_control_market_observer_proto protoreflect.FileDescriptor
 
 const file_src_grpc_control_market_observer_proto_rawDesc = "" +
@@ -588,7 +2736,192 @@ const file_src_grpc_control_market_observer_proto_rawDesc = "" +
 	"\fsymbol_count\x18\x03 \x01(\x05R\vsymbolCount\x12 \n" +
 	"\fis_real_time\x18\x04 \x01(\bR\n" +
 	"isRealTime\x12\x12\n" +
-	"\x04type\x18\x05 \x01(\tR\x04type2\xd5\x03\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\",\n" +
+	"\x12CorrelationRequest\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\"(\n" +
+	"\x0eCorrelationRow\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\x01R\x06values\"\xb4\x01\n" +
+	"\x19CorrelationMatrixResponse\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x12\x18\n" +
+	"\asymbols\x18\x02 \x03(\tR\asymbols\x12+\n" +
+	"\x04rows\x18\x03 \x03(\v2\x17.control.CorrelationRowR\x04rows\x12\x1a\n" +
+	"\blookback\x18\x04 \x01(\x05R\blookback\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\"I\n" +
+	"\x19TopCorrelatedPairsRequest\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"\xa9\x01\n" +
+	"\x0fCorrelationPair\x12\x19\n" +
+	"\bsymbol_a\x18\x01 \x01(\tR\asymbolA\x12\x19\n" +
+	"\bsymbol_b\x18\x02 \x01(\tR\asymbolB\x12 \n" +
+	"\vcorrelation\x18\x03 \x01(\x01R\vcorrelation\x12\x1a\n" +
+	"\bbaseline\x18\x04 \x01(\x01R\bbaseline\x12\"\n" +
+	"\fobservations\x18\x05 \x01(\x05R\fobservations\"d\n" +
+	"\x1aTopCorrelatedPairsResponse\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x12.\n" +
+	"\x05pairs\x18\x02 \x03(\v2\x18.control.CorrelationPairR\x05pairs\"\xbc\x01\n" +
+	"\x10CorrelationAlert\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x12\x19\n" +
+	"\bsymbol_a\x18\x02 \x01(\tR\asymbolA\x12\x19\n" +
+	"\bsymbol_b\x18\x03 \x01(\tR\asymbolB\x12 \n" +
+	"\vcorrelation\x18\x04 \x01(\x01R\vcorrelation\x12\x1a\n" +
+	"\bbaseline\x18\x05 \x01(\x01R\bbaseline\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\"N\n" +
+	"\x19CorrelationAlertsResponse\x121\n" +
+	"\x06alerts\x18\x01 \x03(\v2\x19.control.CorrelationAlertR\x06alerts\"\xcb\x02\n" +
+	"\tAlertRule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\tcondition\x18\x03 \x01(\tR\tcondition\x12\x18\n" +
+	"\asymbols\x18\x04 \x03(\tR\asymbols\x12\x1c\n" +
+	"\twatchlist\x18\x05 \x01(\tR\twatchlist\x12)\n" +
+	"\x10cooldown_seconds\x18\x06 \x01(\x03R\x0fcooldownSeconds\x12\x1e\n" +
+	"\n" +
+	"hysteresis\x18\a \x01(\x01R\n" +
+	"hysteresis\x12\x18\n" +
+	"\aenabled\x18\b \x01(\bR\aenabled\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\x03R\tupdatedAt\x12!\n" +
+	"\fopen_candles\x18\v \x01(\bR\vopenCandles\"$\n" +
+	"\x12AlertRuleIdRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"o\n" +
+	"\x11AlertRuleResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12&\n" +
+	"\x04rule\x18\x03 \x01(\v2\x12.control.AlertRuleR\x04rule\"B\n" +
+	"\x16ListAlertRulesResponse\x12(\n" +
+	"\x05rules\x18\x01 \x03(\v2\x12.control.AlertRuleR\x05rules\".\n" +
+	"\x16ListAlertEventsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\"\xde\x03\n" +
+	"\n" +
+	"AlertEvent\x12\x17\n" +
+	"\arule_id\x18\x01 \x01(\x03R\x06ruleId\x12\x1b\n" +
+	"\trule_name\x18\x02 \x01(\tR\bruleName\x12\x1c\n" +
+	"\tcondition\x18\x03 \x01(\tR\tcondition\x12\x16\n" +
+	"\x06symbol\x18\x04 \x01(\tR\x06symbol\x12\x16\n" +
+	"\x06window\x18\x05 \x01(\tR\x06window\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\x127\n" +
+	"\x06values\x18\a \x03(\v2\x1f.control.AlertEvent.ValuesEntryR\x06values\x12\x18\n" +
+	"\amessage\x18\b \x01(\tR\amessage\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\t \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\n" +
+	" \x01(\x03R\aendTime\x12\x12\n" +
+	"\x04open\x18\v \x01(\x01R\x04open\x12\x12\n" +
+	"\x04high\x18\f \x01(\x01R\x04high\x12\x10\n" +
+	"\x03low\x18\r \x01(\x01R\x03low\x12\x14\n" +
+	"\x05close\x18\x0e \x01(\x01R\x05close\x12\x16\n" +
+	"\x06volume\x18\x0f \x01(\x01R\x06volume\x1a9\n" +
+	"\vValuesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"F\n" +
+	"\x17ListAlertEventsResponse\x12+\n" +
+	"\x06events\x18\x01 \x03(\v2\x13.control.AlertEventR\x06events\"J\n" +
+	"\fScreenFilter\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x0e\n" +
+	"\x02op\x18\x02 \x01(\tR\x02op\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\"\x93\x02\n" +
+	"\rScreenRequest\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x12/\n" +
+	"\afilters\x18\x02 \x03(\v2\x15.control.ScreenFilterR\afilters\x12\x1c\n" +
+	"\tcondition\x18\x03 \x01(\tR\tcondition\x12\x18\n" +
+	"\asymbols\x18\x04 \x03(\tR\asymbols\x12\x1c\n" +
+	"\twatchlist\x18\x05 \x01(\tR\twatchlist\x12\x17\n" +
+	"\asort_by\x18\x06 \x01(\tR\x06sortBy\x12\x1c\n" +
+	"\tascending\x18\a \x01(\bR\tascending\x12\x14\n" +
+	"\x05limit\x18\b \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06fields\x18\t \x03(\tR\x06fields\"\xbc\x02\n" +
+	"\vScreenMatch\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x128\n" +
+	"\x06values\x18\x02 \x03(\v2 .control.ScreenMatch.ValuesEntryR\x06values\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x04 \x01(\x03R\aendTime\x12\x12\n" +
+	"\x04open\x18\x05 \x01(\x01R\x04open\x12\x12\n" +
+	"\x04high\x18\x06 \x01(\x01R\x04high\x12\x10\n" +
+	"\x03low\x18\a \x01(\x01R\x03low\x12\x14\n" +
+	"\x05close\x18\b \x01(\x01R\x05close\x12\x16\n" +
+	"\x06volume\x18\t \x01(\x01R\x06volume\x1a9\n" +
+	"\vValuesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\x8c\x01\n" +
+	"\x0eScreenResponse\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x05R\x05total\x12.\n" +
+	"\amatches\x18\x04 \x03(\v2\x14.control.ScreenMatchR\amatches\"\xbb\x01\n" +
+	"\vSavedScreen\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x120\n" +
+	"\arequest\x18\x03 \x01(\v2\x16.control.ScreenRequestR\arequest\x12\x18\n" +
+	"\aenabled\x18\x04 \x01(\bR\aenabled\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\x03R\tupdatedAt\"!\n" +
+	"\x0fScreenIdRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"w\n" +
+	"\x13SavedScreenResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12,\n" +
+	"\x06screen\x18\x03 \x01(\v2\x14.control.SavedScreenR\x06screen\"E\n" +
+	"\x13ListScreensResponse\x12.\n" +
+	"\ascreens\x18\x01 \x03(\v2\x14.control.SavedScreenR\ascreens\">\n" +
+	"\fSyntheticLeg\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x16\n" +
+	"\x06weight\x18\x02 \x01(\x01R\x06weight\"\x80\x01\n" +
+	"\x13SyntheticInstrument\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12)\n" +
+	"\x04legs\x18\x03 \x03(\v2\x15.control.SyntheticLegR\x04legs\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x01R\x06offset\"0\n" +
+	"\x1aSyntheticInstrumentRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x8f\x01\n" +
+	"\x1bSyntheticInstrumentResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12<\n" +
+	"\n" +
+	"instrument\x18\x03 \x01(\v2\x1c.control.SyntheticInstrumentR\n" +
+	"instrument\"b\n" +
+	" ListSyntheticInstrumentsResponse\x12>\n" +
+	"\vinstruments\x18\x01 \x03(\v2\x1c.control.SyntheticInstrumentR\vinstruments\"\xcd\x01\n" +
+	"\x14VolumeProfileRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x18\n" +
+	"\asession\x18\x02 \x01(\tR\asession\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\x12\x18\n" +
+	"\abuckets\x18\x05 \x01(\x05R\abuckets\x12\x1f\n" +
+	"\vbucket_size\x18\x06 \x01(\x01R\n" +
+	"bucketSize\x12$\n" +
+	"\x0evalue_area_pct\x18\a \x01(\x01R\fvalueAreaPct\"i\n" +
+	"\x13VolumeProfileBucket\x12\x1b\n" +
+	"\tprice_low\x18\x01 \x01(\x01R\bpriceLow\x12\x1d\n" +
+	"\n" +
+	"price_high\x18\x02 \x01(\x01R\tpriceHigh\x12\x16\n" +
+	"\x06volume\x18\x03 \x01(\x01R\x06volume\"\x9f\x04\n" +
+	"\x15VolumeProfileResponse\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x18\n" +
+	"\asession\x18\x02 \x01(\tR\asession\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\x12\x12\n" +
+	"\x04bars\x18\x06 \x01(\x05R\x04bars\x12\x1f\n" +
+	"\vranged_bars\x18\a \x01(\x05R\n" +
+	"rangedBars\x12\x1f\n" +
+	"\vbucket_size\x18\b \x01(\x01R\n" +
+	"bucketSize\x126\n" +
+	"\abuckets\x18\t \x03(\v2\x1c.control.VolumeProfileBucketR\abuckets\x12!\n" +
+	"\ftotal_volume\x18\n" +
+	" \x01(\x01R\vtotalVolume\x12(\n" +
+	"\x10point_of_control\x18\v \x01(\x01R\x0epointOfControl\x12\x1d\n" +
+	"\n" +
+	"poc_volume\x18\f \x01(\x01R\tpocVolume\x12$\n" +
+	"\x0evalue_area_low\x18\r \x01(\x01R\fvalueAreaLow\x12&\n" +
+	"\x0fvalue_area_high\x18\x0e \x01(\x01R\rvalueAreaHigh\x12*\n" +
+	"\x11value_area_volume\x18\x0f \x01(\x01R\x0fvalueAreaVolume\x12$\n" +
+	"\x0evalue_area_pct\x18\x10 \x01(\x01R\fvalueAreaPct2\x8f\x0e\n" +
 	"\x15MarketObserverControl\x12N\n" +
 	"\rUpdateSymbols\x12\x1d.control.UpdateSymbolsRequest\x1a\x1e.control.UpdateSymbolsResponse\x12L\n" +
 	"\vStartSource\x12\x1d.control.SourceControlRequest\x1a\x1e.control.SourceControlResponse\x12K\n" +
@@ -596,7 +2929,24 @@ const file_src_grpc_control_market_observer_proto_rawDesc = "" +
 	"StopSource\x12\x1d.control.SourceControlRequest\x1a\x1e.control.SourceControlResponse\x12;\n" +
 	"\vListSources\x12\x0e.control.Empty\x1a\x1c.control.ListSourcesResponse\x12F\n" +
 	"\tAddSource\x12\x19.control.AddSourceRequest\x1a\x1e.control.SourceControlResponse\x12L\n" +
-	"\fRemoveSource\x12\x1c.control.RemoveSourceRequest\x1a\x1e.control.SourceControlResponseB\x14Z\x12./src/grpc_controlb\x06proto3"
+	"\fRemoveSource\x12\x1c.control.RemoveSourceRequest\x1a\x1e.control.SourceControlResponse\x12W\n" +
+	"\x14GetCorrelationMatrix\x12\x1b.control.CorrelationRequest\x1a\".control.CorrelationMatrixResponse\x12`\n" +
+	"\x15GetTopCorrelatedPairs\x12\".control.TopCorrelatedPairsRequest\x1a#.control.TopCorrelatedPairsResponse\x12W\n" +
+	"\x14GetCorrelationAlerts\x12\x1b.control.CorrelationRequest\x1a\".control.CorrelationAlertsResponse\x12A\n" +
+	"\x0eListAlertRules\x12\x0e.control.Empty\x1a\x1f.control.ListAlertRulesResponse\x12A\n" +
+	"\x0fCreateAlertRule\x12\x12.control.AlertRule\x1a\x1a.control.AlertRuleResponse\x12A\n" +
+	"\x0fUpdateAlertRule\x12\x12.control.AlertRule\x1a\x1a.control.AlertRuleResponse\x12J\n" +
+	"\x0fDeleteAlertRule\x12\x1b.control.AlertRuleIdRequest\x1a\x1a.control.AlertRuleResponse\x12T\n" +
+	"\x0fListAlertEvents\x12\x1f.control.ListAlertEventsRequest\x1a .control.ListAlertEventsResponse\x12<\n" +
+	"\tRunScreen\x12\x16.control.ScreenRequest\x1a\x17.control.ScreenResponse\x12;\n" +
+	"\vListScreens\x12\x0e.control.Empty\x1a\x1c.control.ListScreensResponse\x12B\n" +
+	"\fCreateScreen\x12\x14.control.SavedScreen\x1a\x1c.control.SavedScreenResponse\x12B\n" +
+	"\fUpdateScreen\x12\x14.control.SavedScreen\x1a\x1c.control.SavedScreenResponse\x12F\n" +
+	"\fDeleteScreen\x12\x18.control.ScreenIdRequest\x1a\x1c.control.SavedScreenResponse\x12U\n" +
+	"\x18ListSyntheticInstruments\x12\x0e.control.Empty\x1a).control.ListSyntheticInstrumentsResponse\x12\\\n" +
+	"\x16AddSyntheticInstrument\x12\x1c.control.SyntheticInstrument\x1a$.control.SyntheticInstrumentResponse\x12f\n" +
+	"\x19RemoveSyntheticInstrument\x12#.control.SyntheticInstrumentRequest\x1a$.control.SyntheticInstrumentResponse\x12Q\n" +
+	"\x10GetVolumeProfile\x12\x1d.control.VolumeProfileRequest\x1a\x1e.control.VolumeProfileResponseB\x14Z\x12./src/grpc_controlb\x06proto3"
 
 var (
 	file_src_grpc_control_market_observer_proto_rawDescOnce sync.Once
@@ -610,39 +2960,123 @@ func file_src_grpc_control_market_observer_proto_rawDescGZIP() []byte {
 	return file_src_grpc_control_market_observer_proto_rawDescData
 }
 
-var file_src_grpc_control_market_observer_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_src_grpc_control_market_observer_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
 var file_src_grpc_control_market_observer_proto_goTypes = []any{
-	(*ListSourcesResponse)(nil),   // 0: control.ListSourcesResponse
-	(*AddSourceRequest)(nil),      // 1: control.AddSourceRequest
-	(*RemoveSourceRequest)(nil),   // 2: control.RemoveSourceRequest
-	(*UpdateSymbolsRequest)(nil),  // 3: control.UpdateSymbolsRequest
-	(*UpdateSymbolsResponse)(nil), // 4: control.UpdateSymbolsResponse
-	(*SourceControlRequest)(nil),  // 5: control.SourceControlRequest
-	(*SourceControlResponse)(nil), // 6: control.SourceControlResponse
-	(*Empty)(nil),                 // 7: control.Empty
-	(*StatusResponse)(nil),        // 8: control.StatusResponse
-	(*SourceStatus)(nil),          // 9: control.SourceStatus
+	(*ListSourcesResponse)(nil),              // 0: control.ListSourcesResponse
+	(*AddSourceRequest)(nil),                 // 1: control.AddSourceRequest
+	(*RemoveSourceRequest)(nil),              // 2: control.RemoveSourceRequest
+	(*UpdateSymbolsRequest)(nil),             // 3: control.UpdateSymbolsRequest
+	(*UpdateSymbolsResponse)(nil),            // 4: control.UpdateSymbolsResponse
+	(*SourceControlRequest)(nil),             // 5: control.SourceControlRequest
+	(*SourceControlResponse)(nil),            // 6: control.SourceControlResponse
+	(*Empty)(nil),                            // 7: control.Empty
+	(*StatusResponse)(nil),                   // 8: control.StatusResponse
+	(*SourceStatus)(nil),                     // 9: control.SourceStatus
+	(*CorrelationRequest)(nil),               // 10: control.CorrelationRequest
+	(*CorrelationRow)(nil),                   // 11: control.CorrelationRow
+	(*CorrelationMatrixResponse)(nil),        // 12: control.CorrelationMatrixResponse
+	(*TopCorrelatedPairsRequest)(nil),        // 13: control.TopCorrelatedPairsRequest
+	(*CorrelationPair)(nil),                  // 14: control.CorrelationPair
+	(*TopCorrelatedPairsResponse)(nil),       // 15: control.TopCorrelatedPairsResponse
+	(*CorrelationAlert)(nil),                 // 16: control.CorrelationAlert
+	(*CorrelationAlertsResponse)(nil),        // 17: control.CorrelationAlertsResponse
+	(*AlertRule)(nil),                        // 18: control.AlertRule
+	(*AlertRuleIdRequest)(nil),               // 19: control.AlertRuleIdRequest
+	(*AlertRuleResponse)(nil),                // 20: control.AlertRuleResponse
+	(*ListAlertRulesResponse)(nil),           // 21: control.ListAlertRulesResponse
+	(*ListAlertEventsRequest)(nil),           // 22: control.ListAlertEventsRequest
+	(*AlertEvent)(nil),                       // 23: control.AlertEvent
+	(*ListAlertEventsResponse)(nil),          // 24: control.ListAlertEventsResponse
+	(*ScreenFilter)(nil),                     // 25: control.ScreenFilter
+	(*ScreenRequest)(nil),                    // 26: control.ScreenRequest
+	(*ScreenMatch)(nil),                      // 27: control.ScreenMatch
+	(*ScreenResponse)(nil),                   // 28: control.ScreenResponse
+	(*SavedScreen)(nil),                      // 29: control.SavedScreen
+	(*ScreenIdRequest)(nil),                  // 30: control.ScreenIdRequest
+	(*SavedScreenResponse)(nil),              // 31: control.SavedScreenResponse
+	(*ListScreensResponse)(nil),              // 32: control.ListScreensResponse
+	(*SyntheticLeg)(nil),                     // 33: control.SyntheticLeg
+	(*SyntheticInstrument)(nil),              // 34: control.SyntheticInstrument
+	(*SyntheticInstrumentRequest)(nil),       // 35: control.SyntheticInstrumentRequest
+	(*SyntheticInstrumentResponse)(nil),      // 36: control.SyntheticInstrumentResponse
+	(*ListSyntheticInstrumentsResponse)(nil), // 37: control.ListSyntheticInstrumentsResponse
+	(*VolumeProfileRequest)(nil),             // 38: control.VolumeProfileRequest
+	(*VolumeProfileBucket)(nil),              // 39: control.VolumeProfileBucket
+	(*VolumeProfileResponse)(nil),            // 40: control.VolumeProfileResponse
+	nil,                                      // 41: control.AlertEvent.ValuesEntry
+	nil,                                      // 42: control.ScreenMatch.ValuesEntry
 }
 var file_src_grpc_control_market_observer_proto_depIdxs = []int32{
-	9, // 0: control.ListSourcesResponse.sources:type_name -> control.SourceStatus
-	9, // 1: control.StatusResponse.sources:type_name -> control.SourceStatus
-	3, // 2: control.MarketObserverControl.UpdateSymbols:input_type -> control.UpdateSymbolsRequest
-	5, // 3: control.MarketObserverControl.StartSource:input_type -> control.SourceControlRequest
-	5, // 4: control.MarketObserverControl.StopSource:input_type -> control.SourceControlRequest
-	7, // 5: control.MarketObserverControl.ListSources:input_type -> control.Empty
-	1, // 6: control.MarketObserverControl.AddSource:input_type -> control.AddSourceRequest
-	2, // 7: control.MarketObserverControl.RemoveSource:input_type -> control.RemoveSourceRequest
-	4, // 8: control.MarketObserverControl.UpdateSymbols:output_type -> control.UpdateSymbolsResponse
-	6, // 9: control.MarketObserverControl.StartSource:output_type -> control.SourceControlResponse
-	6, // 10: control.MarketObserverControl.StopSource:output_type -> control.SourceControlResponse
-	0, // 11: control.MarketObserverControl.ListSources:output_type -> control.ListSourcesResponse
-	6, // 12: control.MarketObserverControl.AddSource:output_type -> control.SourceControlResponse
-	6, // 13: control.MarketObserverControl.RemoveSource:output_type -> control.SourceControlResponse
-	8, // [8:14] is the sub-list for method output_type
-	2, // [2:8] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	9,  // 0: control.ListSourcesResponse.sources:type_name -> control.SourceStatus
+	9,  // 1: control.StatusResponse.sources:type_name -> control.SourceStatus
+	11, // 2: control.CorrelationMatrixResponse.rows:type_name -> control.CorrelationRow
+	14, // 3: control.TopCorrelatedPairsResponse.pairs:type_name -> control.CorrelationPair
+	16, // 4: control.CorrelationAlertsResponse.alerts:type_name -> control.CorrelationAlert
+	18, // 5: control.AlertRuleResponse.rule:type_name -> control.AlertRule
+	18, // 6: control.ListAlertRulesResponse.rules:type_name -> control.AlertRule
+	41, // 7: control.AlertEvent.values:type_name -> control.AlertEvent.ValuesEntry
+	23, // 8: control.ListAlertEventsResponse.events:type_name -> control.AlertEvent
+	25, // 9: control.ScreenRequest.filters:type_name -> control.ScreenFilter
+	42, // 10: control.ScreenMatch.values:type_name -> control.ScreenMatch.ValuesEntry
+	27, // 11: control.ScreenResponse.matches:type_name -> control.ScreenMatch
+	26, // 12: control.SavedScreen.request:type_name -> control.ScreenRequest
+	29, // 13: control.SavedScreenResponse.screen:type_name -> control.SavedScreen
+	29, // 14: control.ListScreensResponse.screens:type_name -> control.SavedScreen
+	33, // 15: control.SyntheticInstrument.legs:type_name -> control.SyntheticLeg
+	34, // 16: control.SyntheticInstrumentResponse.instrument:type_name -> control.SyntheticInstrument
+	34, // 17: control.ListSyntheticInstrumentsResponse.instruments:type_name -> control.SyntheticInstrument
+	39, // 18: control.VolumeProfileResponse.buckets:type_name -> control.VolumeProfileBucket
+	3,  // 19: control.MarketObserverControl.UpdateSymbols:input_type -> control.UpdateSymbolsRequest
+	5,  // 20: control.MarketObserverControl.StartSource:input_type -> control.SourceControlRequest
+	5,  // 21: control.MarketObserverControl.StopSource:input_type -> control.SourceControlRequest
+	7,  // 22: control.MarketObserverControl.ListSources:input_type -> control.Empty
+	1,  // 23: control.MarketObserverControl.AddSource:input_type -> control.AddSourceRequest
+	2,  // 24: control.MarketObserverControl.RemoveSource:input_type -> control.RemoveSourceRequest
+	10, // 25: control.MarketObserverControl.GetCorrelationMatrix:input_type -> control.CorrelationRequest
+	13, // 26: control.MarketObserverControl.GetTopCorrelatedPairs:input_type -> control.TopCorrelatedPairsRequest
+	10, // 27: control.MarketObserverControl.GetCorrelationAlerts:input_type -> control.CorrelationRequest
+	7,  // 28: control.MarketObserverControl.ListAlertRules:input_type -> control.Empty
+	18, // 29: control.MarketObserverControl.CreateAlertRule:input_type -> control.AlertRule
+	18, // 30: control.MarketObserverControl.UpdateAlertRule:input_type -> control.AlertRule
+	19, // 31: control.MarketObserverControl.DeleteAlertRule:input_type -> control.AlertRuleIdRequest
+	22, // 32: control.MarketObserverControl.ListAlertEvents:input_type -> control.ListAlertEventsRequest
+	26, // 33: control.MarketObserverControl.RunScreen:input_type -> control.ScreenRequest
+	7,  // 34: control.MarketObserverControl.ListScreens:input_type -> control.Empty
+	29, // 35: control.MarketObserverControl.CreateScreen:input_type -> control.SavedScreen
+	29, // 36: control.MarketObserverControl.UpdateScreen:input_type -> control.SavedScreen
+	30, // 37: control.MarketObserverControl.DeleteScreen:input_type -> control.ScreenIdRequest
+	7,  // 38: control.MarketObserverControl.ListSyntheticInstruments:input_type -> control.Empty
+	34, // 39: control.MarketObserverControl.AddSyntheticInstrument:input_type -> control.SyntheticInstrument
+	35, // 40: control.MarketObserverControl.RemoveSyntheticInstrument:input_type -> control.SyntheticInstrumentRequest
+	38, // 41: control.MarketObserverControl.GetVolumeProfile:input_type -> control.VolumeProfileRequest
+	4,  // 42: control.MarketObserverControl.UpdateSymbols:output_type -> control.UpdateSymbolsResponse
+	6,  // 43: control.MarketObserverControl.StartSource:output_type -> control.SourceControlResponse
+	6,  // 44: control.MarketObserverControl.StopSource:output_type -> control.SourceControlResponse
+	0,  // 45: control.MarketObserverControl.ListSources:output_type -> control.ListSourcesResponse
+	6,  // 46: control.MarketObserverControl.AddSource:output_type -> control.SourceControlResponse
+	6,  // 47: control.MarketObserverControl.RemoveSource:output_type -> control.SourceControlResponse
+	12, // 48: control.MarketObserverControl.GetCorrelationMatrix:output_type -> control.CorrelationMatrixResponse
+	15, // 49: control.MarketObserverControl.GetTopCorrelatedPairs:output_type -> control.TopCorrelatedPairsResponse
+	17, // 50: control.MarketObserverControl.GetCorrelationAlerts:output_type -> control.CorrelationAlertsResponse
+	21, // 51: control.MarketObserverControl.ListAlertRules:output_type -> control.ListAlertRulesResponse
+	20, // 52: control.MarketObserverControl.CreateAlertRule:output_type -> control.AlertRuleResponse
+	20, // 53: control.MarketObserverControl.UpdateAlertRule:output_type -> control.AlertRuleResponse
+	20, // 54: control.MarketObserverControl.DeleteAlertRule:output_type -> control.AlertRuleResponse
+	24, // 55: control.MarketObserverControl.ListAlertEvents:output_type -> control.ListAlertEventsResponse
+	28, // 56: control.MarketObserverControl.RunScreen:output_type -> control.ScreenResponse
+	32, // 57: control.MarketObserverControl.ListScreens:output_type -> control.ListScreensResponse
+	31, // 58: control.MarketObserverControl.CreateScreen:output_type -> control.SavedScreenResponse
+	31, // 59: control.MarketObserverControl.UpdateScreen:output_type -> control.SavedScreenResponse
+	31, // 60: control.MarketObserverControl.DeleteScreen:output_type -> control.SavedScreenResponse
+	37, // 61: control.MarketObserverControl.ListSyntheticInstruments:output_type -> control.ListSyntheticInstrumentsResponse
+	36, // 62: control.MarketObserverControl.AddSyntheticInstrument:output_type -> control.SyntheticInstrumentResponse
+	36, // 63: control.MarketObserverControl.RemoveSyntheticInstrument:output_type -> control.SyntheticInstrumentResponse
+	40, // 64: control.MarketObserverControl.GetVolumeProfile:output_type -> control.VolumeProfileResponse
+	42, // [42:65] is the sub-list for method output_type
+	19, // [19:42] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
 }
 
 func init() { file_src_grpc_control_market_observer_proto_init() }
@@ -656,7 +3090,7 @@ func file_src_grpc_control_market_observer_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_src_grpc_control_market_observer_proto_rawDesc), len(file_src_grpc_control_market_observer_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   43,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,12 +19,29 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MarketObserverControl_UpdateSymbols_FullMethodName = "/control.MarketObserverControl/UpdateSymbols"
-	MarketObserverControl_StartSource_FullMethodName   = "/control.MarketObserverControl/StartSource"
-	MarketObserverControl_StopSource_FullMethodName    = "/control.MarketObserverControl/StopSource"
-	MarketObserverControl_ListSources_FullMethodName   = "/control.MarketObserverControl/ListSources"
-	MarketObserverControl_AddSource_FullMethodName     = "/control.MarketObserverControl/AddSource"
-	MarketObserverControl_RemoveSource_FullMethodName  = "/control.MarketObserverControl/RemoveSource"
+	MarketObserverControl_UpdateSymbols_FullMethodName             = "/control.MarketObserverControl/UpdateSymbols"
+	MarketObserverControl_StartSource_FullMethodName               = "/control.MarketObserverControl/StartSource"
+	MarketObserverControl_StopSource_FullMethodName                = "/control.MarketObserverControl/StopSource"
+	MarketObserverControl_ListSources_FullMethodName               = "/control.MarketObserverControl/ListSources"
+	MarketObserverControl_AddSource_FullMethodName                 = "/control.MarketObserverControl/AddSource"
+	MarketObserverControl_RemoveSource_FullMethodName              = "/control.MarketObserverControl/RemoveSource"
+	MarketObserverControl_GetCorrelationMatrix_FullMethodName      = "/control.MarketObserverControl/GetCorrelationMatrix"
+	MarketObserverControl_GetTopCorrelatedPairs_FullMethodName     = "/control.MarketObserverControl/GetTopCorrelatedPairs"
+	MarketObserverControl_GetCorrelationAlerts_FullMethodName      = "/control.MarketObserverControl/GetCorrelationAlerts"
+	MarketObserverControl_ListAlertRules_FullMethodName            = "/control.MarketObserverControl/ListAlertRules"
+	MarketObserverControl_CreateAlertRule_FullMethodName           = "/control.MarketObserverControl/CreateAlertRule"
+	MarketObserverControl_UpdateAlertRule_FullMethodName           = "/control.MarketObserverControl/UpdateAlertRule"
+	MarketObserverControl_DeleteAlertRule_FullMethodName           = "/control.MarketObserverControl/DeleteAlertRule"
+	MarketObserverControl_ListAlertEvents_FullMethodName           = "/control.MarketObserverControl/ListAlertEvents"
+	MarketObserverControl_RunScreen_FullMethodName                 = "/control.MarketObserverControl/RunScreen"
+	MarketObserverControl_ListScreens_FullMethodName               = "/control.MarketObserverControl/ListScreens"
+	MarketObserverControl_CreateScreen_FullMethodName              = "/control.MarketObserverControl/CreateScreen"
+	MarketObserverControl_UpdateScreen_FullMethodName              = "/control.MarketObserverControl/UpdateScreen"
+	MarketObserverControl_DeleteScreen_FullMethodName              = "/control.MarketObserverControl/DeleteScreen"
+	MarketObserverControl_ListSyntheticInstruments_FullMethodName  = "/control.MarketObserverControl/ListSyntheticInstruments"
+	MarketObserverControl_AddSyntheticInstrument_FullMethodName    = "/control.MarketObserverControl/AddSyntheticInstrument"
+	MarketObserverControl_RemoveSyntheticInstrument_FullMethodName = "/control.MarketObserverControl/RemoveSyntheticInstrument"
+	MarketObserverControl_GetVolumeProfile_FullMethodName          = "/control.MarketObserverControl/GetVolumeProfile"
 )
 
 // MarketObserverControlClient is the client API for MarketObserverControl service.
@@ -43,6 +60,40 @@ type MarketObserverControlClient interface {
 	AddSource(ctx context.Context, in *AddSourceRequest, opts ...grpc.CallOption) (*SourceControlResponse, error)
 	// Dynamically remove a data source
 	RemoveSource(ctx context.Context, in *RemoveSourceRequest, opts ...grpc.CallOption) (*SourceControlResponse, error)
+	// Get the rolling return correlation matrix of a window
+	GetCorrelationMatrix(ctx context.Context, in *CorrelationRequest, opts ...grpc.CallOption) (*CorrelationMatrixResponse, error)
+	// Get the most correlated pairs of a window
+	GetTopCorrelatedPairs(ctx context.Context, in *TopCorrelatedPairsRequest, opts ...grpc.CallOption) (*TopCorrelatedPairsResponse, error)
+	// Get recent correlation breakdown alerts (all windows if window is empty)
+	GetCorrelationAlerts(ctx context.Context, in *CorrelationRequest, opts ...grpc.CallOption) (*CorrelationAlertsResponse, error)
+	// List all alert rules
+	ListAlertRules(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListAlertRulesResponse, error)
+	// Create an alert rule (id is ignored)
+	CreateAlertRule(ctx context.Context, in *AlertRule, opts ...grpc.CallOption) (*AlertRuleResponse, error)
+	// Replace an existing alert rule (matched by id)
+	UpdateAlertRule(ctx context.Context, in *AlertRule, opts ...grpc.CallOption) (*AlertRuleResponse, error)
+	// Delete an alert rule
+	DeleteAlertRule(ctx context.Context, in *AlertRuleIdRequest, opts ...grpc.CallOption) (*AlertRuleResponse, error)
+	// List the most recent alert events
+	ListAlertEvents(ctx context.Context, in *ListAlertEventsRequest, opts ...grpc.CallOption) (*ListAlertEventsResponse, error)
+	// Run a screen over the latest candles
+	RunScreen(ctx context.Context, in *ScreenRequest, opts ...grpc.CallOption) (*ScreenResponse, error)
+	// List all saved screens
+	ListScreens(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListScreensResponse, error)
+	// Create a saved screen (id is ignored)
+	CreateScreen(ctx context.Context, in *SavedScreen, opts ...grpc.CallOption) (*SavedScreenResponse, error)
+	// Replace an existing saved screen (matched by id)
+	UpdateScreen(ctx context.Context, in *SavedScreen, opts ...grpc.CallOption) (*SavedScreenResponse, error)
+	// Delete a saved screen
+	DeleteScreen(ctx context.Context, in *ScreenIdRequest, opts ...grpc.CallOption) (*SavedScreenResponse, error)
+	// List the synthetic instruments (ratios, spreads, baskets)
+	ListSyntheticInstruments(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListSyntheticInstrumentsResponse, error)
+	// Add a synthetic instrument computed from source symbols
+	AddSyntheticInstrument(ctx context.Context, in *SyntheticInstrument, opts ...grpc.CallOption) (*SyntheticInstrumentResponse, error)
+	// Remove a synthetic instrument
+	RemoveSyntheticInstrument(ctx context.Context, in *SyntheticInstrumentRequest, opts ...grpc.CallOption) (*SyntheticInstrumentResponse, error)
+	// Volume profile of a symbol over a session or a time range
+	GetVolumeProfile(ctx context.Context, in *VolumeProfileRequest, opts ...grpc.CallOption) (*VolumeProfileResponse, error)
 }
 
 type marketObserverControlClient struct {
@@ -113,6 +164,176 @@ func (c *marketObserverControlClient) RemoveSource(ctx context.Context, in *Remo
 	return out, nil
 }
 
+func (c *marketObserverControlClient) GetCorrelationMatrix(ctx context.Context, in *CorrelationRequest, opts ...grpc.CallOption) (*CorrelationMatrixResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CorrelationMatrixResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_GetCorrelationMatrix_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketObserverControlClient) GetTopCorrelatedPairs(ctx context.Context, in *TopCorrelatedPairsRequest, opts ...grpc.CallOption) (*TopCorrelatedPairsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TopCorrelatedPairsResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_GetTopCorrelatedPairs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketObserverControlClient) GetCorrelationAlerts(ctx context.Context, in *CorrelationRequest, opts ...grpc.CallOption) (*CorrelationAlertsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CorrelationAlertsResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_GetCorrelationAlerts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketObserverControlClient) ListAlertRules(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListAlertRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAlertRulesResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_ListAlertRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketObserverControlClient) CreateAlertRule(ctx context.Context, in *AlertRule, opts ...grpc.CallOption) (*AlertRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AlertRuleResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_CreateAlertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketObserverControlClient) UpdateAlertRule(ctx context.Context, in *AlertRule, opts ...grpc.CallOption) (*AlertRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AlertRuleResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_UpdateAlertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketObserverControlClient) DeleteAlertRule(ctx context.Context, in *AlertRuleIdRequest, opts ...grpc.CallOption) (*AlertRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AlertRuleResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_DeleteAlertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketObserverControlClient) ListAlertEvents(ctx context.Context, in *ListAlertEventsRequest, opts ...grpc.CallOption) (*ListAlertEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAlertEventsResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_ListAlertEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketObserverControlClient) RunScreen(ctx context.Context, in *ScreenRequest, opts ...grpc.CallOption) (*ScreenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScreenResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_RunScreen_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketObserverControlClient) ListScreens(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListScreensResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListScreensResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_ListScreens_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketObserverControlClient) CreateScreen(ctx context.Context, in *SavedScreen, opts ...grpc.CallOption) (*SavedScreenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SavedScreenResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_CreateScreen_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketObserverControlClient) UpdateScreen(ctx context.Context, in *SavedScreen, opts ...grpc.CallOption) (*SavedScreenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SavedScreenResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_UpdateScreen_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketObserverControlClient) DeleteScreen(ctx context.Context, in *ScreenIdRequest, opts ...grpc.CallOption) (*SavedScreenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SavedScreenResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_DeleteScreen_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketObserverControlClient) ListSyntheticInstruments(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListSyntheticInstrumentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSyntheticInstrumentsResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_ListSyntheticInstruments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketObserverControlClient) AddSyntheticInstrument(ctx context.Context, in *SyntheticInstrument, opts ...grpc.CallOption) (*SyntheticInstrumentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyntheticInstrumentResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_AddSyntheticInstrument_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketObserverControlClient) RemoveSyntheticInstrument(ctx context.Context, in *SyntheticInstrumentRequest, opts ...grpc.CallOption) (*SyntheticInstrumentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyntheticInstrumentResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_RemoveSyntheticInstrument_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketObserverControlClient) GetVolumeProfile(ctx context.Context, in *VolumeProfileRequest, opts ...grpc.CallOption) (*VolumeProfileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VolumeProfileResponse)
+	err := c.cc.Invoke(ctx, MarketObserverControl_GetVolumeProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MarketObserverControlServer is the server API for MarketObserverControl service.
 // All implementations must embed UnimplementedMarketObserverControlServer
 // for forward compatibility.
@@ -129,6 +350,40 @@ type MarketObserverControlServer interface {
 	AddSource(context.Context, *AddSourceRequest) (*SourceControlResponse, error)
 	// Dynamically remove a data source
 	RemoveSource(context.Context, *RemoveSourceRequest) (*SourceControlResponse, error)
+	// Get the rolling return correlation matrix of a window
+	GetCorrelationMatrix(context.Context, *CorrelationRequest) (*CorrelationMatrixResponse, error)
+	// Get the most correlated pairs of a window
+	GetTopCorrelatedPairs(context.Context, *TopCorrelatedPairsRequest) (*TopCorrelatedPairsResponse, error)
+	// Get recent correlation breakdown alerts (all windows if window is empty)
+	GetCorrelationAlerts(context.Context, *CorrelationRequest) (*CorrelationAlertsResponse, error)
+	// List all alert rules
+	ListAlertRules(context.Context, *Empty) (*ListAlertRulesResponse, error)
+	// Create an alert rule (id is ignored)
+	CreateAlertRule(context.Context, *AlertRule) (*AlertRuleResponse, error)
+	// Replace an existing alert rule (matched by id)
+	UpdateAlertRule(context.Context, *AlertRule) (*AlertRuleResponse, error)
+	// Delete an alert rule
+	DeleteAlertRule(context.Context, *AlertRuleIdRequest) (*AlertRuleResponse, error)
+	// List the most recent alert events
+	ListAlertEvents(context.Context, *ListAlertEventsRequest) (*ListAlertEventsResponse, error)
+	// Run a screen over the latest candles
+	RunScreen(context.Context, *ScreenRequest) (*ScreenResponse, error)
+	// List all saved screens
+	ListScreens(context.Context, *Empty) (*ListScreensResponse, error)
+	// Create a saved screen (id is ignored)
+	CreateScreen(context.Context, *SavedScreen) (*SavedScreenResponse, error)
+	// Replace an existing saved screen (matched by id)
+	UpdateScreen(context.Context, *SavedScreen) (*SavedScreenResponse, error)
+	// Delete a saved screen
+	DeleteScreen(context.Context, *ScreenIdRequest) (*SavedScreenResponse, error)
+	// List the synthetic instruments (ratios, spreads, baskets)
+	ListSyntheticInstruments(context.Context, *Empty) (*ListSyntheticInstrumentsResponse, error)
+	// Add a synthetic instrument computed from source symbols
+	AddSyntheticInstrument(context.Context, *SyntheticInstrument) (*SyntheticInstrumentResponse, error)
+	// Remove a synthetic instrument
+	RemoveSyntheticInstrument(context.Context, *SyntheticInstrumentRequest) (*SyntheticInstrumentResponse, error)
+	// Volume profile of a symbol over a session or a time range
+	GetVolumeProfile(context.Context, *VolumeProfileRequest) (*VolumeProfileResponse, error)
 	mustEmbedUnimplementedMarketObserverControlServer()
 }
 
@@ -157,6 +412,57 @@ func (UnimplementedMarketObserverControlServer) AddSource(context.Context, *AddS
 func (UnimplementedMarketObserverControlServer) RemoveSource(context.Context, *RemoveSourceRequest) (*SourceControlResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveSource not implemented")
 }
+func (UnimplementedMarketObserverControlServer) GetCorrelationMatrix(context.Context, *CorrelationRequest) (*CorrelationMatrixResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCorrelationMatrix not implemented")
+}
+func (UnimplementedMarketObserverControlServer) GetTopCorrelatedPairs(context.Context, *TopCorrelatedPairsRequest) (*TopCorrelatedPairsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTopCorrelatedPairs not implemented")
+}
+func (UnimplementedMarketObserverControlServer) GetCorrelationAlerts(context.Context, *CorrelationRequest) (*CorrelationAlertsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCorrelationAlerts not implemented")
+}
+func (UnimplementedMarketObserverControlServer) ListAlertRules(context.Context, *Empty) (*ListAlertRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlertRules not implemented")
+}
+func (UnimplementedMarketObserverControlServer) CreateAlertRule(context.Context, *AlertRule) (*AlertRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAlertRule not implemented")
+}
+func (UnimplementedMarketObserverControlServer) UpdateAlertRule(context.Context, *AlertRule) (*AlertRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAlertRule not implemented")
+}
+func (UnimplementedMarketObserverControlServer) DeleteAlertRule(context.Context, *AlertRuleIdRequest) (*AlertRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAlertRule not implemented")
+}
+func (UnimplementedMarketObserverControlServer) ListAlertEvents(context.Context, *ListAlertEventsRequest) (*ListAlertEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlertEvents not implemented")
+}
+func (UnimplementedMarketObserverControlServer) RunScreen(context.Context, *ScreenRequest) (*ScreenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunScreen not implemented")
+}
+func (UnimplementedMarketObserverControlServer) ListScreens(context.Context, *Empty) (*ListScreensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScreens not implemented")
+}
+func (UnimplementedMarketObserverControlServer) CreateScreen(context.Context, *SavedScreen) (*SavedScreenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateScreen not implemented")
+}
+func (UnimplementedMarketObserverControlServer) UpdateScreen(context.Context, *SavedScreen) (*SavedScreenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateScreen not implemented")
+}
+func (UnimplementedMarketObserverControlServer) DeleteScreen(context.Context, *ScreenIdRequest) (*SavedScreenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteScreen not implemented")
+}
+func (UnimplementedMarketObserverControlServer) ListSyntheticInstruments(context.Context, *Empty) (*ListSyntheticInstrumentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSyntheticInstruments not implemented")
+}
+func (UnimplementedMarketObserverControlServer) AddSyntheticInstrument(context.Context, *SyntheticInstrument) (*SyntheticInstrumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSyntheticInstrument not implemented")
+}
+func (UnimplementedMarketObserverControlServer) RemoveSyntheticInstrument(context.Context, *SyntheticInstrumentRequest) (*SyntheticInstrumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveSyntheticInstrument not implemented")
+}
+func (UnimplementedMarketObserverControlServer) GetVolumeProfile(context.Context, *VolumeProfileRequest) (*VolumeProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVolumeProfile not implemented")
+}
 func (UnimplementedMarketObserverControlServer) mustEmbedUnimplementedMarketObserverControlServer() {}
 func (UnimplementedMarketObserverControlServer) testEmbeddedByValue()                               {}
 
@@ -286,6 +592,312 @@ func _MarketObserverControl_RemoveSource_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MarketObserverControl_GetCorrelationMatrix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CorrelationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).GetCorrelationMatrix(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_GetCorrelationMatrix_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).GetCorrelationMatrix(ctx, req.(*CorrelationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketObserverControl_GetTopCorrelatedPairs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopCorrelatedPairsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).GetTopCorrelatedPairs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_GetTopCorrelatedPairs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).GetTopCorrelatedPairs(ctx, req.(*TopCorrelatedPairsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketObserverControl_GetCorrelationAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CorrelationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).GetCorrelationAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_GetCorrelationAlerts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).GetCorrelationAlerts(ctx, req.(*CorrelationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketObserverControl_ListAlertRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).ListAlertRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_ListAlertRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).ListAlertRules(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketObserverControl_CreateAlertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlertRule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).CreateAlertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_CreateAlertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).CreateAlertRule(ctx, req.(*AlertRule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketObserverControl_UpdateAlertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlertRule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).UpdateAlertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_UpdateAlertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).UpdateAlertRule(ctx, req.(*AlertRule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketObserverControl_DeleteAlertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlertRuleIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).DeleteAlertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_DeleteAlertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).DeleteAlertRule(ctx, req.(*AlertRuleIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketObserverControl_ListAlertEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlertEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).ListAlertEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_ListAlertEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).ListAlertEvents(ctx, req.(*ListAlertEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketObserverControl_RunScreen_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScreenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).RunScreen(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_RunScreen_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).RunScreen(ctx, req.(*ScreenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketObserverControl_ListScreens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).ListScreens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_ListScreens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).ListScreens(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketObserverControl_CreateScreen_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SavedScreen)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).CreateScreen(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_CreateScreen_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).CreateScreen(ctx, req.(*SavedScreen))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketObserverControl_UpdateScreen_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SavedScreen)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).UpdateScreen(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_UpdateScreen_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).UpdateScreen(ctx, req.(*SavedScreen))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketObserverControl_DeleteScreen_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScreenIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).DeleteScreen(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_DeleteScreen_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).DeleteScreen(ctx, req.(*ScreenIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketObserverControl_ListSyntheticInstruments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).ListSyntheticInstruments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_ListSyntheticInstruments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).ListSyntheticInstruments(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketObserverControl_AddSyntheticInstrument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyntheticInstrument)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).AddSyntheticInstrument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_AddSyntheticInstrument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).AddSyntheticInstrument(ctx, req.(*SyntheticInstrument))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketObserverControl_RemoveSyntheticInstrument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyntheticInstrumentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).RemoveSyntheticInstrument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_RemoveSyntheticInstrument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).RemoveSyntheticInstrument(ctx, req.(*SyntheticInstrumentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketObserverControl_GetVolumeProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VolumeProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketObserverControlServer).GetVolumeProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketObserverControl_GetVolumeProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketObserverControlServer).GetVolumeProfile(ctx, req.(*VolumeProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MarketObserverControl_ServiceDesc is the grpc.ServiceDesc for MarketObserverControl service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -317,6 +929,74 @@ var MarketObserverControl_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveSource",
 			Handler:    _MarketObserverControl_RemoveSource_Handler,
 		},
+		{
+			MethodName: "GetCorrelationMatrix",
+			Handler:    _MarketObserverControl_GetCorrelationMatrix_Handler,
+		},
+		{
+			MethodName: "GetTopCorrelatedPairs",
+			Handler:    _MarketObserverControl_GetTopCorrelatedPairs_Handler,
+		},
+		{
+			MethodName: "GetCorrelationAlerts",
+			Handler:    _MarketObserverControl_GetCorrelationAlerts_Handler,
+		},
+		{
+			MethodName: "ListAlertRules",
+			Handler:    _MarketObserverControl_ListAlertRules_Handler,
+		},
+		{
+			MethodName: "CreateAlertRule",
+			Handler:    _MarketObserverControl_CreateAlertRule_Handler,
+		},
+		{
+			MethodName: "UpdateAlertRule",
+			Handler:    _MarketObserverControl_UpdateAlertRule_Handler,
+		},
+		{
+			MethodName: "DeleteAlertRule",
+			Handler:    _MarketObserverControl_DeleteAlertRule_Handler,
+		},
+		{
+			MethodName: "ListAlertEvents",
+			Handler:    _MarketObserverControl_ListAlertEvents_Handler,
+		},
+		{
+			MethodName: "RunScreen",
+			Handler:    _MarketObserverControl_RunScreen_Handler,
+		},
+		{
+			MethodName: "ListScreens",
+			Handler:    _MarketObserverControl_ListScreens_Handler,
+		},
+		{
+			MethodName: "CreateScreen",
+			Handler:    _MarketObserverControl_CreateScreen_Handler,
+		},
+		{
+			MethodName: "UpdateScreen",
+			Handler:    _MarketObserverControl_UpdateScreen_Handler,
+		},
+		{
+			MethodName: "DeleteScreen",
+			Handler:    _MarketObserverControl_DeleteScreen_Handler,
+		},
+		{
+			MethodName: "ListSyntheticInstruments",
+			Handler:    _MarketObserverControl_ListSyntheticInstruments_Handler,
+		},
+		{
+			MethodName: "AddSyntheticInstrument",
+			Handler:    _MarketObserverControl_AddSyntheticInstrument_Handler,
+		},
+		{
+			MethodName: "RemoveSyntheticInstrument",
+			Handler:    _MarketObserverControl_RemoveSyntheticInstrument_Handler,
+		},
+		{
+			MethodName: "GetVolumeProfile",
+			Handler:    _MarketObserverControl_GetVolumeProfile_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "src/grpc_control/market_observer.proto",
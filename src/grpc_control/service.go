@@ -2,13 +2,18 @@ package grpc_control
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"market-observer/src/alerts"
 	"market-observer/src/config"
 	datasource "market-observer/src/data_source"
 	"market-observer/src/data_source/yahoo"
 	"market-observer/src/interfaces"
 	"market-observer/src/logger"
 	"market-observer/src/models"
+	"market-observer/src/profile"
+	"market-observer/src/screener"
+	"market-observer/src/synthetic"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -22,6 +27,11 @@ type ControlService struct {
 	ConfigPath     string
 	Logger         *logger.Logger
 	NetworkManager interfaces.INetworkManager
+	Correlation    interfaces.ICorrelationProvider
+	AlertRules     interfaces.IAlertRulesManager
+	Screener       interfaces.IScreener
+	Synthetics     interfaces.ISyntheticManager
+	VolumeProfile  interfaces.IVolumeProfiler
 }
 
 // NewControlService creates a new instance of ControlService
@@ -31,6 +41,11 @@ func NewControlService(
 	cfgPath string,
 	log *logger.Logger,
 	netMgr interfaces.INetworkManager,
+	correlation interfaces.ICorrelationProvider,
+	alertRules interfaces.IAlertRulesManager,
+	screener interfaces.IScreener,
+	synthetics interfaces.ISyntheticManager,
+	volumeProfile interfaces.IVolumeProfiler,
 ) *ControlService {
 	return &ControlService{
 		Config:         cfg,
@@ -38,6 +53,11 @@ func NewControlService(
 		ConfigPath:     cfgPath,
 		Logger:         log,
 		NetworkManager: netMgr,
+		Correlation:    correlation,
+		AlertRules:     alertRules,
+		Screener:       screener,
+		Synthetics:     synthetics,
+		VolumeProfile:  volumeProfile,
 	}
 }
 
@@ -245,3 +265,570 @@ func (s *ControlService) GetStatus(ctx context.Context, req *Empty) (*StatusResp
 	}
 	return &StatusResponse{Sources: sourceStatuses}, nil
 }
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) GetCorrelationMatrix(ctx context.Context, req *CorrelationRequest) (*CorrelationMatrixResponse, error) {
+	if s.Correlation == nil {
+		return nil, status.Error(codes.Unavailable, "correlation service not available")
+	}
+	if req.Window == "" {
+		return nil, status.Error(codes.InvalidArgument, "window is required")
+	}
+
+	matrix, ok := s.Correlation.GetMatrix(req.Window)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no correlation matrix for window %s", req.Window)
+	}
+
+	rows := make([]*CorrelationRow, len(matrix.Matrix))
+	for i, values := range matrix.Matrix {
+		rows[i] = &CorrelationRow{Values: values}
+	}
+
+	return &CorrelationMatrixResponse{
+		Window:    matrix.WindowName,
+		Symbols:   matrix.Symbols,
+		Rows:      rows,
+		Lookback:  int32(matrix.Lookback),
+		Timestamp: matrix.Timestamp,
+	}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) GetTopCorrelatedPairs(ctx context.Context, req *TopCorrelatedPairsRequest) (*TopCorrelatedPairsResponse, error) {
+	if s.Correlation == nil {
+		return nil, status.Error(codes.Unavailable, "correlation service not available")
+	}
+	if req.Window == "" {
+		return nil, status.Error(codes.InvalidArgument, "window is required")
+	}
+
+	var pairs []*CorrelationPair
+	for _, p := range s.Correlation.TopPairs(req.Window, int(req.Limit)) {
+		pairs = append(pairs, &CorrelationPair{
+			SymbolA:      p.SymbolA,
+			SymbolB:      p.SymbolB,
+			Correlation:  p.Correlation,
+			Baseline:     p.Baseline,
+			Observations: int32(p.Observations),
+		})
+	}
+
+	return &TopCorrelatedPairsResponse{Window: req.Window, Pairs: pairs}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) GetCorrelationAlerts(ctx context.Context, req *CorrelationRequest) (*CorrelationAlertsResponse, error) {
+	if s.Correlation == nil {
+		return nil, status.Error(codes.Unavailable, "correlation service not available")
+	}
+
+	var alerts []*CorrelationAlert
+	for _, a := range s.Correlation.GetAlerts(req.Window) {
+		alerts = append(alerts, &CorrelationAlert{
+			Window:      a.WindowName,
+			SymbolA:     a.SymbolA,
+			SymbolB:     a.SymbolB,
+			Correlation: a.Correlation,
+			Baseline:    a.Baseline,
+			Timestamp:   a.Timestamp,
+		})
+	}
+
+	return &CorrelationAlertsResponse{Alerts: alerts}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) ListAlertRules(ctx context.Context, req *Empty) (*ListAlertRulesResponse, error) {
+	if s.AlertRules == nil {
+		return nil, status.Error(codes.Unavailable, "alert rules not available")
+	}
+
+	var rules []*AlertRule
+	for _, r := range s.AlertRules.ListRules() {
+		rules = append(rules, alertRuleToProto(r))
+	}
+	return &ListAlertRulesResponse{Rules: rules}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) CreateAlertRule(ctx context.Context, req *AlertRule) (*AlertRuleResponse, error) {
+	if s.AlertRules == nil {
+		return nil, status.Error(codes.Unavailable, "alert rules not available")
+	}
+
+	rule, err := s.AlertRules.CreateRule(alertRuleFromProto(req))
+	if err != nil {
+		return alertRuleErrorResponse(err)
+	}
+	return &AlertRuleResponse{
+		Success: true,
+		Message: fmt.Sprintf("Created alert rule %d", rule.ID),
+		Rule:    alertRuleToProto(rule),
+	}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) UpdateAlertRule(ctx context.Context, req *AlertRule) (*AlertRuleResponse, error) {
+	if s.AlertRules == nil {
+		return nil, status.Error(codes.Unavailable, "alert rules not available")
+	}
+	if req.Id == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	rule, err := s.AlertRules.UpdateRule(alertRuleFromProto(req))
+	if err != nil {
+		return alertRuleErrorResponse(err)
+	}
+	return &AlertRuleResponse{
+		Success: true,
+		Message: fmt.Sprintf("Updated alert rule %d", rule.ID),
+		Rule:    alertRuleToProto(rule),
+	}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) DeleteAlertRule(ctx context.Context, req *AlertRuleIdRequest) (*AlertRuleResponse, error) {
+	if s.AlertRules == nil {
+		return nil, status.Error(codes.Unavailable, "alert rules not available")
+	}
+	if req.Id == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	if err := s.AlertRules.DeleteRule(req.Id); err != nil {
+		return alertRuleErrorResponse(err)
+	}
+	return &AlertRuleResponse{Success: true, Message: fmt.Sprintf("Deleted alert rule %d", req.Id)}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) ListAlertEvents(ctx context.Context, req *ListAlertEventsRequest) (*ListAlertEventsResponse, error) {
+	if s.AlertRules == nil {
+		return nil, status.Error(codes.Unavailable, "alert rules not available")
+	}
+
+	var events []*AlertEvent
+	for _, e := range s.AlertRules.RecentEvents(int(req.Limit)) {
+		events = append(events, &AlertEvent{
+			RuleId:    e.RuleID,
+			RuleName:  e.RuleName,
+			Condition: e.Condition,
+			Symbol:    e.Symbol,
+			Window:    e.WindowName,
+			Timestamp: e.Timestamp,
+			Values:    e.Values,
+			Message:   e.Message,
+			StartTime: e.Candle.StartTime,
+			EndTime:   e.Candle.EndTime,
+			Open:      e.Candle.Open,
+			High:      e.Candle.High,
+			Low:       e.Candle.Low,
+			Close:     e.Candle.Close,
+			Volume:    e.Candle.Volume,
+		})
+	}
+	return &ListAlertEventsResponse{Events: events}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func alertRuleToProto(r models.MAlertRule) *AlertRule {
+	return &AlertRule{
+		Id:              r.ID,
+		Name:            r.Name,
+		Condition:       r.Condition,
+		Symbols:         r.Symbols,
+		Watchlist:       r.Watchlist,
+		CooldownSeconds: r.CooldownSeconds,
+		Hysteresis:      r.Hysteresis,
+		OpenCandles:     r.OpenCandles,
+		Enabled:         r.Enabled,
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func alertRuleFromProto(r *AlertRule) models.MAlertRule {
+	return models.MAlertRule{
+		ID:              r.Id,
+		Name:            r.Name,
+		Condition:       r.Condition,
+		Symbols:         r.Symbols,
+		Watchlist:       r.Watchlist,
+		CooldownSeconds: r.CooldownSeconds,
+		Hysteresis:      r.Hysteresis,
+		OpenCandles:     r.OpenCandles,
+		Enabled:         r.Enabled,
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// alertRuleErrorResponse maps rules engine errors to gRPC status codes
+func alertRuleErrorResponse(err error) (*AlertRuleResponse, error) {
+	switch {
+	case errors.Is(err, alerts.ErrRuleNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, alerts.ErrInvalidRule):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return &AlertRuleResponse{Success: false, Message: err.Error()}, nil
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) RunScreen(ctx context.Context, req *ScreenRequest) (*ScreenResponse, error) {
+	if s.Screener == nil {
+		return nil, status.Error(codes.Unavailable, "screener not available")
+	}
+
+	result, err := s.Screener.Run(screenRequestFromProto(req))
+	if err != nil {
+		if errors.Is(err, screener.ErrInvalidScreen) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	response := &ScreenResponse{
+		Window:    result.Window,
+		Timestamp: result.Timestamp,
+		Total:     int32(result.Total),
+	}
+	for _, m := range result.Matches {
+		response.Matches = append(response.Matches, &ScreenMatch{
+			Symbol:    m.Symbol,
+			Values:    m.Values,
+			StartTime: m.Candle.StartTime,
+			EndTime:   m.Candle.EndTime,
+			Open:      m.Candle.Open,
+			High:      m.Candle.High,
+			Low:       m.Candle.Low,
+			Close:     m.Candle.Close,
+			Volume:    m.Candle.Volume,
+		})
+	}
+	return response, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) ListScreens(ctx context.Context, req *Empty) (*ListScreensResponse, error) {
+	if s.Screener == nil {
+		return nil, status.Error(codes.Unavailable, "screener not available")
+	}
+
+	var screens []*SavedScreen
+	for _, sc := range s.Screener.ListScreens() {
+		screens = append(screens, savedScreenToProto(sc))
+	}
+	return &ListScreensResponse{Screens: screens}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) CreateScreen(ctx context.Context, req *SavedScreen) (*SavedScreenResponse, error) {
+	if s.Screener == nil {
+		return nil, status.Error(codes.Unavailable, "screener not available")
+	}
+
+	screen, err := s.Screener.CreateScreen(savedScreenFromProto(req))
+	if err != nil {
+		return screenErrorResponse(err)
+	}
+	return &SavedScreenResponse{
+		Success: true,
+		Message: fmt.Sprintf("Created screen %d", screen.ID),
+		Screen:  savedScreenToProto(screen),
+	}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) UpdateScreen(ctx context.Context, req *SavedScreen) (*SavedScreenResponse, error) {
+	if s.Screener == nil {
+		return nil, status.Error(codes.Unavailable, "screener not available")
+	}
+	if req.Id == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	screen, err := s.Screener.UpdateScreen(savedScreenFromProto(req))
+	if err != nil {
+		return screenErrorResponse(err)
+	}
+	return &SavedScreenResponse{
+		Success: true,
+		Message: fmt.Sprintf("Updated screen %d", screen.ID),
+		Screen:  savedScreenToProto(screen),
+	}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) DeleteScreen(ctx context.Context, req *ScreenIdRequest) (*SavedScreenResponse, error) {
+	if s.Screener == nil {
+		return nil, status.Error(codes.Unavailable, "screener not available")
+	}
+
+	if err := s.Screener.DeleteScreen(req.Id); err != nil {
+		return screenErrorResponse(err)
+	}
+	return &SavedScreenResponse{
+		Success: true,
+		Message: fmt.Sprintf("Deleted screen %d", req.Id),
+	}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func screenRequestFromProto(r *ScreenRequest) models.MScreenRequest {
+	if r == nil {
+		return models.MScreenRequest{}
+	}
+	request := models.MScreenRequest{
+		Window:    r.Window,
+		Condition: r.Condition,
+		Symbols:   r.Symbols,
+		Watchlist: r.Watchlist,
+		SortBy:    r.SortBy,
+		Ascending: r.Ascending,
+		Limit:     int(r.Limit),
+		Fields:    r.Fields,
+	}
+	for _, f := range r.Filters {
+		request.Filters = append(request.Filters, models.MScreenFilter{Field: f.Field, Op: f.Op, Value: f.Value})
+	}
+	return request
+}
+
+// -----------------------------------------------------------------------------
+
+func screenRequestToProto(r models.MScreenRequest) *ScreenRequest {
+	request := &ScreenRequest{
+		Window:    r.Window,
+		Condition: r.Condition,
+		Symbols:   r.Symbols,
+		Watchlist: r.Watchlist,
+		SortBy:    r.SortBy,
+		Ascending: r.Ascending,
+		Limit:     int32(r.Limit),
+		Fields:    r.Fields,
+	}
+	for _, f := range r.Filters {
+		request.Filters = append(request.Filters, &ScreenFilter{Field: f.Field, Op: f.Op, Value: f.Value})
+	}
+	return request
+}
+
+// -----------------------------------------------------------------------------
+
+func savedScreenToProto(s models.MSavedScreen) *SavedScreen {
+	return &SavedScreen{
+		Id:        s.ID,
+		Name:      s.Name,
+		Request:   screenRequestToProto(s.MScreenRequest),
+		Enabled:   s.Enabled,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func savedScreenFromProto(s *SavedScreen) models.MSavedScreen {
+	return models.MSavedScreen{
+		ID:             s.Id,
+		Name:           s.Name,
+		MScreenRequest: screenRequestFromProto(s.Request),
+		Enabled:        s.Enabled,
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// screenErrorResponse maps screener errors to gRPC status codes
+func screenErrorResponse(err error) (*SavedScreenResponse, error) {
+	switch {
+	case errors.Is(err, screener.ErrScreenNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, screener.ErrInvalidScreen):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return &SavedScreenResponse{Success: false, Message: err.Error()}, nil
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) ListSyntheticInstruments(ctx context.Context, req *Empty) (*ListSyntheticInstrumentsResponse, error) {
+	if s.Synthetics == nil {
+		return nil, status.Error(codes.Unavailable, "synthetic instruments not available")
+	}
+
+	var instruments []*SyntheticInstrument
+	for _, def := range s.Synthetics.List() {
+		instruments = append(instruments, syntheticToProto(def))
+	}
+	return &ListSyntheticInstrumentsResponse{Instruments: instruments}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) AddSyntheticInstrument(ctx context.Context, req *SyntheticInstrument) (*SyntheticInstrumentResponse, error) {
+	if s.Synthetics == nil {
+		return nil, status.Error(codes.Unavailable, "synthetic instruments not available")
+	}
+
+	def := syntheticFromProto(req)
+	if err := config.ValidateSyntheticInstrument(def, config.SourceSymbols(s.Config.MConfig)); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.Synthetics.Add(def); err != nil {
+		return syntheticErrorResponse(err)
+	}
+
+	s.Config.Synthetics = append(s.Config.Synthetics, def)
+	s.Config.Save(s.ConfigPath)
+
+	return &SyntheticInstrumentResponse{
+		Success:    true,
+		Message:    fmt.Sprintf("Added synthetic instrument %s", def.Name),
+		Instrument: syntheticToProto(def),
+	}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) RemoveSyntheticInstrument(ctx context.Context, req *SyntheticInstrumentRequest) (*SyntheticInstrumentResponse, error) {
+	if s.Synthetics == nil {
+		return nil, status.Error(codes.Unavailable, "synthetic instruments not available")
+	}
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	if err := s.Synthetics.Remove(req.Name); err != nil {
+		return syntheticErrorResponse(err)
+	}
+
+	// Clean from Config
+	remaining := []models.MSyntheticInstrument{}
+	for _, def := range s.Config.Synthetics {
+		if def.Name != req.Name {
+			remaining = append(remaining, def)
+		}
+	}
+	s.Config.Synthetics = remaining
+	s.Config.Save(s.ConfigPath)
+
+	return &SyntheticInstrumentResponse{
+		Success: true,
+		Message: fmt.Sprintf("Removed synthetic instrument %s", req.Name),
+	}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func syntheticToProto(def models.MSyntheticInstrument) *SyntheticInstrument {
+	instrument := &SyntheticInstrument{
+		Name:   def.Name,
+		Type:   def.Type,
+		Offset: def.Offset,
+	}
+	for _, leg := range def.Legs {
+		instrument.Legs = append(instrument.Legs, &SyntheticLeg{Symbol: leg.Symbol, Weight: leg.Weight})
+	}
+	return instrument
+}
+
+// -----------------------------------------------------------------------------
+
+func syntheticFromProto(s *SyntheticInstrument) models.MSyntheticInstrument {
+	def := models.MSyntheticInstrument{
+		Name:   s.Name,
+		Type:   s.Type,
+		Offset: s.Offset,
+	}
+	for _, leg := range s.Legs {
+		def.Legs = append(def.Legs, models.MSyntheticLeg{Symbol: leg.Symbol, Weight: leg.Weight})
+	}
+	return def
+}
+
+// -----------------------------------------------------------------------------
+
+// syntheticErrorResponse maps synthetic builder errors to gRPC status codes
+func syntheticErrorResponse(err error) (*SyntheticInstrumentResponse, error) {
+	switch {
+	case errors.Is(err, synthetic.ErrInstrumentNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, synthetic.ErrInstrumentExists):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return &SyntheticInstrumentResponse{Success: false, Message: err.Error()}, nil
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *ControlService) GetVolumeProfile(ctx context.Context, req *VolumeProfileRequest) (*VolumeProfileResponse, error) {
+	if s.VolumeProfile == nil {
+		return nil, status.Error(codes.Unavailable, "volume profile not available")
+	}
+
+	result, err := s.VolumeProfile.Profile(models.MVolumeProfileRequest{
+		Symbol:       req.Symbol,
+		Session:      req.Session,
+		From:         req.From,
+		To:           req.To,
+		Buckets:      int(req.Buckets),
+		BucketSize:   req.BucketSize,
+		ValueAreaPct: req.ValueAreaPct,
+	})
+	switch {
+	case errors.Is(err, profile.ErrNoProfileData):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, profile.ErrInvalidProfile):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	buckets := make([]*VolumeProfileBucket, len(result.Buckets))
+	for i, b := range result.Buckets {
+		buckets[i] = &VolumeProfileBucket{PriceLow: b.PriceLow, PriceHigh: b.PriceHigh, Volume: b.Volume}
+	}
+
+	return &VolumeProfileResponse{
+		Symbol:          result.Symbol,
+		Session:         result.Session,
+		From:            result.From,
+		To:              result.To,
+		Source:          result.Source,
+		Bars:            int32(result.Bars),
+		RangedBars:      int32(result.RangedBars),
+		BucketSize:      result.BucketSize,
+		Buckets:         buckets,
+		TotalVolume:     result.TotalVolume,
+		PointOfControl:  result.PointOfControl,
+		PocVolume:       result.POCVolume,
+		ValueAreaLow:    result.ValueAreaLow,
+		ValueAreaHigh:   result.ValueAreaHigh,
+		ValueAreaVolume: result.ValueAreaVolume,
+		ValueAreaPct:    result.ValueAreaPct,
+	}, nil
+}
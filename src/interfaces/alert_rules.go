@@ -0,0 +1,35 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// IAlertRulesManager exposes alert rule management and recent events (Server/gRPC).
+// -----------------------------------------------------------------------------
+
+type IAlertRulesManager interface {
+
+	// -----------------------------------------------------------------------------
+
+	// ListRules returns all rules ordered by ID.
+	ListRules() []models.MAlertRule
+
+	// -----------------------------------------------------------------------------
+
+	// CreateRule validates, stores and activates a new rule.
+	CreateRule(rule models.MAlertRule) (models.MAlertRule, error)
+
+	// -----------------------------------------------------------------------------
+
+	// UpdateRule replaces an existing rule (matched by ID).
+	UpdateRule(rule models.MAlertRule) (models.MAlertRule, error)
+
+	// -----------------------------------------------------------------------------
+
+	// DeleteRule removes a rule.
+	DeleteRule(id int64) error
+
+	// -----------------------------------------------------------------------------
+
+	// RecentEvents returns the most recent alert events (newest last, all when limit <= 0).
+	RecentEvents(limit int) []models.MAlertEvent
+}
@@ -0,0 +1,30 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// IAnalyzer is the plugin contract for custom candle metrics: candle in,
+// named metrics out, with optional state per symbol/window. Registered
+// analyzers run on every candle; their outputs land in MAggregation.Metrics
+// under "<name>.<metric>" and are persisted and broadcast with the candle.
+// -----------------------------------------------------------------------------
+
+type IAnalyzer interface {
+
+	// -----------------------------------------------------------------------------
+
+	// Name identifies the analyzer and prefixes its metric keys (unique, no dots).
+	Name() string
+
+	// -----------------------------------------------------------------------------
+
+	// NewState returns the initial state for a symbol/window (nil for stateless analyzers).
+	NewState(symbol, window string) interface{}
+
+	// -----------------------------------------------------------------------------
+
+	// Analyze returns the metrics of a candle. state is the value from NewState
+	// for the candle's symbol/window. Open candles are analyzed on every update:
+	// the state must only be advanced when candle.IsClosed is true.
+	Analyze(candle models.MAggregation, state interface{}) map[string]float64
+}
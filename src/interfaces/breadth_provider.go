@@ -0,0 +1,20 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// IBreadthProvider exposes the market breadth snapshots (Server).
+// -----------------------------------------------------------------------------
+
+type IBreadthProvider interface {
+
+	// -----------------------------------------------------------------------------
+
+	// Latest returns the most recent snapshot of every window.
+	Latest() map[string]models.MBreadthSnapshot
+
+	// -----------------------------------------------------------------------------
+
+	// History returns the last snapshots of a window, oldest first (false when the window is unknown).
+	History(window string, limit int) ([]models.MBreadthSnapshot, bool)
+}
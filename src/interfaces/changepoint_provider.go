@@ -0,0 +1,15 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// IChangepointProvider exposes the recent regime changes (Server).
+// -----------------------------------------------------------------------------
+
+type IChangepointProvider interface {
+
+	// -----------------------------------------------------------------------------
+
+	// Recent returns the latest changes, filtered by symbol and window ("" = all), newest last.
+	Recent(symbol, window string, limit int) []models.MChangepointEvent
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// IClusterProvider exposes the intraday profile clusters and their jumps (Server).
+// -----------------------------------------------------------------------------
+
+type IClusterProvider interface {
+
+	// -----------------------------------------------------------------------------
+
+	// Snapshot returns the clusters of the last run (false before the first one).
+	Snapshot() (models.MClusterSnapshot, bool)
+
+	// -----------------------------------------------------------------------------
+
+	// Jumps returns the latest day-over-day cluster changes, filtered by symbol ("" = all), newest last.
+	Jumps(symbol string, limit int) []models.MClusterJump
+}
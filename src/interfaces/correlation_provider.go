@@ -0,0 +1,25 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// ICorrelationProvider exposes the cross-symbol correlation results (Server/gRPC).
+// -----------------------------------------------------------------------------
+
+type ICorrelationProvider interface {
+
+	// -----------------------------------------------------------------------------
+
+	// GetMatrix returns the latest correlation matrix of a window.
+	GetMatrix(windowName string) (models.MCorrelationMatrix, bool)
+
+	// -----------------------------------------------------------------------------
+
+	// TopPairs returns the n most correlated pairs of a window (all when n <= 0).
+	TopPairs(windowName string, n int) []models.MCorrelationPair
+
+	// -----------------------------------------------------------------------------
+
+	// GetAlerts returns recent correlation breakdown alerts (all windows when empty).
+	GetAlerts(windowName string) []models.MCorrelationAlert
+}
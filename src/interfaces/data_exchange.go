@@ -10,6 +10,11 @@ type IDataExchanger interface {
 	// We use interface{} to be generic (matching FastAPIServer implementation)
 	Broadcast(payload interface{})
 
+	// -----------------------------------------------------------------------------
+	// BroadcastMessage pushes a message of its own type (e.g. BREADTH) to listeners
+	// without updating the state
+	BroadcastMessage(message interface{})
+
 	// -----------------------------------------------------------------------------
 	// AllDatas updates the internal state without broadcasting (matches Python)
 	UpdateAllDatas(data interface{})
@@ -0,0 +1,20 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// IDataQualityProvider exposes the per-symbol data-quality reports (Server).
+// -----------------------------------------------------------------------------
+
+type IDataQualityProvider interface {
+
+	// -----------------------------------------------------------------------------
+
+	// Report returns the report of a symbol with its recent gaps (false when unknown).
+	Report(symbol string) (models.MDataQualityReport, bool)
+
+	// -----------------------------------------------------------------------------
+
+	// Summary returns the report of every symbol, without gaps.
+	Summary() []models.MDataQualityReport
+}
@@ -18,14 +18,91 @@ type IDatabase interface {
 	// SaveStockPricesBulk inserts a batch of raw stock prices.
 	SaveStockPricesBulk(prices []models.MStockPrice) error
 
+	// -----------------------------------------------------------------------------
+
+	// LoadStockPrices returns the raw prices of a symbol in [from, to), oldest first.
+	LoadStockPrices(symbol string, from, to int64) ([]models.MStockPrice, error)
+
 	// -----------------------------------------------------------------------------
 	// SaveAggregations for saving calculated stats (Postgres/SQLite)
 	SaveAggregations(aggs map[string]map[string][]models.MAggregation) error
 
+	// -----------------------------------------------------------------------------
+	// LoadAggregations returns the stored candles of a window started in [from, to), by symbol then start time
+	LoadAggregations(window string, from, to int64) ([]models.MAggregation, error)
+
 	// -----------------------------------------------------------------------------
 	// SaveIntermediateStats for saving rolling stats (Postgres/SQLite)
 	SaveIntermediateStats(stats []models.MIntermediateStats) error
 
+	// -----------------------------------------------------------------------------
+	// LoadIntermediateStats restores persisted rolling stats (all windows)
+	LoadIntermediateStats() ([]models.MIntermediateStats, error)
+
+	// -----------------------------------------------------------------------------
+	// SaveAlertRule inserts a rule (ID == 0, ID is set) or updates an existing one
+	SaveAlertRule(rule *models.MAlertRule) error
+
+	// -----------------------------------------------------------------------------
+	// DeleteAlertRule removes a rule by ID
+	DeleteAlertRule(id int64) error
+
+	// -----------------------------------------------------------------------------
+	// LoadAlertRules returns all stored rules
+	LoadAlertRules() ([]models.MAlertRule, error)
+
+	// -----------------------------------------------------------------------------
+	// SaveScreen inserts a saved screen (ID == 0, ID is set) or updates an existing one
+	SaveScreen(screen *models.MSavedScreen) error
+
+	// -----------------------------------------------------------------------------
+	// DeleteScreen removes a saved screen by ID
+	DeleteScreen(id int64) error
+
+	// -----------------------------------------------------------------------------
+	// LoadScreens returns all saved screens
+	LoadScreens() ([]models.MSavedScreen, error)
+
+	// -----------------------------------------------------------------------------
+	// SaveAlertEvents appends fired alert events to the event log
+	SaveAlertEvents(events []models.MAlertEvent) error
+
+	// -----------------------------------------------------------------------------
+	// SaveNotificationDelivery appends an entry to the notification delivery log
+	SaveNotificationDelivery(delivery models.MNotificationDelivery) error
+
+	// -----------------------------------------------------------------------------
+	// SaveQuarantinedPrice stores a point held back by validation (ID is set)
+	SaveQuarantinedPrice(q *models.MQuarantinedPrice) error
+
+	// -----------------------------------------------------------------------------
+	// ReleaseQuarantinedPrice marks a quarantined point as released
+	ReleaseQuarantinedPrice(id int64, releasedAt int64) error
+
+	// -----------------------------------------------------------------------------
+	// LoadQuarantinedPrices returns the points still in quarantine
+	LoadQuarantinedPrices() ([]models.MQuarantinedPrice, error)
+
+	// -----------------------------------------------------------------------------
+	// SaveBreadth appends market breadth snapshots to the time series
+	SaveBreadth(snapshots []models.MBreadthSnapshot) error
+
+	// -----------------------------------------------------------------------------
+	// SaveChangepointEvents appends detected regime changes to the event log
+	SaveChangepointEvents(events []models.MChangepointEvent) error
+
+	// -----------------------------------------------------------------------------
+	// SavePatternEvents appends detected candlestick patterns to the event log
+	SavePatternEvents(events []models.MPatternEvent) error
+
+	// -----------------------------------------------------------------------------
+	// SaveLevelEvents appends support/resistance breakouts and breakdowns to the event log
+	SaveLevelEvents(events []models.MLevelBreakEvent) error
+
+	// -----------------------------------------------------------------------------
+	// SaveClusterJumps appends day-over-day cluster changes to the jump log
+	SaveClusterJumps(jumps []models.MClusterJump) error
+
 	// -----------------------------------------------------------------------------
 
 	// CleanupOldData removes data older than the retention policy.
@@ -0,0 +1,20 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// IEventStudyProvider exposes the event study of past volume anomalies (Server).
+// -----------------------------------------------------------------------------
+
+type IEventStudyProvider interface {
+
+	// -----------------------------------------------------------------------------
+
+	// Report returns the last report filtered by symbol, window and severity ("" = all), false before the first run.
+	Report(symbol, window, severity string) (models.MEventStudyReport, bool)
+
+	// -----------------------------------------------------------------------------
+
+	// Events returns the latest studied anomalies, filtered by symbol, window and severity ("" = all), newest last.
+	Events(symbol, window, severity string, limit int) []models.MAnomalyEvent
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// ILeadLagProvider exposes the lead/lag relationships of symbol pairs (Server).
+// -----------------------------------------------------------------------------
+
+type ILeadLagProvider interface {
+
+	// -----------------------------------------------------------------------------
+
+	// Results returns the estimated pairs involving a symbol ("" = all), strongest lead first.
+	Results(symbol string) []models.MLeadLagResult
+
+	// -----------------------------------------------------------------------------
+
+	// Pair returns the result of a pair, in either symbol order.
+	Pair(a, b string) (models.MLeadLagResult, bool)
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// ILeaderboardProvider exposes the ranked leaderboards per window (Server).
+// -----------------------------------------------------------------------------
+
+type ILeaderboardProvider interface {
+
+	// -----------------------------------------------------------------------------
+
+	// Snapshot returns the top entries of every leaderboard of a window ("" = all windows).
+	Snapshot(window string) models.MLeaderboardSnapshot
+
+	// -----------------------------------------------------------------------------
+
+	// Ranking returns the first entries of one leaderboard (false when the window or board is unknown).
+	Ranking(window, board string, limit int) ([]models.MLeaderboardEntry, bool)
+}
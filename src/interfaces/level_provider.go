@@ -0,0 +1,25 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// ILevelProvider exposes the support/resistance levels and their breaks (Server).
+// -----------------------------------------------------------------------------
+
+type ILevelProvider interface {
+
+	// -----------------------------------------------------------------------------
+
+	// Levels returns the current levels of a symbol (false when none were derived).
+	Levels(symbol string) (models.MSymbolLevels, bool)
+
+	// -----------------------------------------------------------------------------
+
+	// All returns the current levels of every symbol, sorted by symbol.
+	All() []models.MSymbolLevels
+
+	// -----------------------------------------------------------------------------
+
+	// Recent returns the latest breakouts/breakdowns, filtered by symbol and type ("" = all), newest last.
+	Recent(symbol, eventType string, limit int) []models.MLevelBreakEvent
+}
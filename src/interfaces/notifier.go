@@ -0,0 +1,20 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// INotifier exposes the notification delivery log and test sends (Server).
+// -----------------------------------------------------------------------------
+
+type INotifier interface {
+
+	// -----------------------------------------------------------------------------
+
+	// Deliveries returns the most recent delivery log entries (newest last, all when limit <= 0).
+	Deliveries(limit int) []models.MNotificationDelivery
+
+	// -----------------------------------------------------------------------------
+
+	// SendTest queues a test notification on every enabled channel and returns the channel count.
+	SendTest() int
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// IPatternProvider exposes the recent candlestick patterns (Server).
+// -----------------------------------------------------------------------------
+
+type IPatternProvider interface {
+
+	// -----------------------------------------------------------------------------
+
+	// Recent returns the latest patterns, filtered by symbol, window and pattern ("" = all), newest last.
+	Recent(symbol, window, pattern string, limit int) []models.MPatternEvent
+}
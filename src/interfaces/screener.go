@@ -0,0 +1,46 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// IScreener runs screens over the latest candles and manages saved screens (Server/gRPC).
+// -----------------------------------------------------------------------------
+
+type IScreener interface {
+
+	// -----------------------------------------------------------------------------
+
+	// Run evaluates an ad-hoc screen on the latest candles.
+	Run(request models.MScreenRequest) (models.MScreenResult, error)
+
+	// -----------------------------------------------------------------------------
+
+	// ListScreens returns all saved screens ordered by ID.
+	ListScreens() []models.MSavedScreen
+
+	// -----------------------------------------------------------------------------
+
+	// CreateScreen validates and stores a new saved screen.
+	CreateScreen(screen models.MSavedScreen) (models.MSavedScreen, error)
+
+	// -----------------------------------------------------------------------------
+
+	// UpdateScreen replaces an existing saved screen (matched by ID).
+	UpdateScreen(screen models.MSavedScreen) (models.MSavedScreen, error)
+
+	// -----------------------------------------------------------------------------
+
+	// DeleteScreen removes a saved screen.
+	DeleteScreen(id int64) error
+
+	// -----------------------------------------------------------------------------
+
+	// Snapshot returns the current members and matches of a saved screen.
+	Snapshot(id int64) (models.MScreenMessage, error)
+
+	// -----------------------------------------------------------------------------
+
+	// Refresh takes the latest candles (symbol -> window -> candle) of an update
+	// cycle and returns the membership changes of the saved screens.
+	Refresh(candles map[string]map[string]models.MAggregation, timestamp int64) []models.MScreenMessage
+}
@@ -0,0 +1,25 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// ISyntheticManager manages the synthetic instruments computed from other symbols (gRPC).
+// -----------------------------------------------------------------------------
+
+type ISyntheticManager interface {
+
+	// -----------------------------------------------------------------------------
+
+	// List returns the synthetic instrument definitions sorted by name.
+	List() []models.MSyntheticInstrument
+
+	// -----------------------------------------------------------------------------
+
+	// Add registers a validated synthetic instrument.
+	Add(def models.MSyntheticInstrument) error
+
+	// -----------------------------------------------------------------------------
+
+	// Remove stops producing points for a synthetic instrument.
+	Remove(name string) error
+}
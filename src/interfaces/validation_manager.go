@@ -0,0 +1,25 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// IValidationManager exposes the inbound validation counters and quarantine (Server).
+// -----------------------------------------------------------------------------
+
+type IValidationManager interface {
+
+	// -----------------------------------------------------------------------------
+
+	// Stats returns the validation counters summed over all symbols and per symbol.
+	Stats() (models.MValidationStats, []models.MValidationStats)
+
+	// -----------------------------------------------------------------------------
+
+	// Quarantined returns the pending quarantined points (newest last, all symbols when symbol is "", all when limit <= 0).
+	Quarantined(symbol string, limit int) []models.MQuarantinedPrice
+
+	// -----------------------------------------------------------------------------
+
+	// Release sends a quarantined point back into the pipeline.
+	Release(id int64) (models.MQuarantinedPrice, error)
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import "market-observer/src/models"
+
+// -----------------------------------------------------------------------------
+// IVolumeProfiler builds volume profiles of a symbol on demand (Server/gRPC).
+// -----------------------------------------------------------------------------
+
+type IVolumeProfiler interface {
+
+	// -----------------------------------------------------------------------------
+
+	// Profile returns the volume by price, point of control and value area of a session or time range.
+	Profile(request models.MVolumeProfileRequest) (models.MVolumeProfile, error)
+}
@@ -4,20 +4,56 @@ import "time"
 
 // MAggregation represents a calculated candle for a specific time window.
 type MAggregation struct {
-	Symbol                 string    `json:"symbol"`
-	WindowName             string    `json:"window_name"` // e.g., "5m", "1h"
-	Open                   float64   `json:"open"`
-	High                   float64   `json:"high"`
-	Low                    float64   `json:"low"`
-	Close                  float64   `json:"close"`
-	Volume                 float64   `json:"volume"`
-	AvgPrice               float64   `json:"avg_price"`
-	PricePercentChange     float64   `json:"price_percent_change"`
-	VolumePercentChange    float64   `json:"volume_percent_change"`
-	PriceVolumeCorrelation float64   `json:"price_volume_correlation"`
-	VolumeAnomalyRatio     float64   `json:"volume_anomaly_ratio"`
-	StartTime              int64     `json:"start_time"`
-	EndTime                int64     `json:"end_time"`
-	DataPoints             int       `json:"data_points"`
-	CreatedAt              time.Time `json:"created_at"`
+	Symbol                 string             `json:"symbol"`
+	WindowName             string             `json:"window_name"` // e.g., "5m", "1h"
+	Open                   float64            `json:"open"`
+	High                   float64            `json:"high"`
+	Low                    float64            `json:"low"`
+	Close                  float64            `json:"close"`
+	Volume                 float64            `json:"volume"`
+	AvgPrice               float64            `json:"avg_price"`            // Arithmetic mean of prices
+	VWAP                   float64            `json:"vwap"`                 // Volume-weighted average price of the window
+	SessionVWAP            float64            `json:"session_vwap"`         // VWAP anchored at the exchange session open
+	SessionVWAPUpper1      float64            `json:"session_vwap_upper_1"` // +1 sigma band
+	SessionVWAPLower1      float64            `json:"session_vwap_lower_1"` // -1 sigma band
+	SessionVWAPUpper2      float64            `json:"session_vwap_upper_2"` // +2 sigma band
+	SessionVWAPLower2      float64            `json:"session_vwap_lower_2"` // -2 sigma band
+	PricePercentChange     float64            `json:"price_percent_change"`
+	VolumePercentChange    float64            `json:"volume_percent_change"`
+	PriceVolumeCorrelation float64            `json:"price_volume_correlation"`
+	VolumeAnomalyRatio     float64            `json:"volume_anomaly_ratio"`
+	StartTime              int64              `json:"start_time"`
+	EndTime                int64              `json:"end_time"`
+	DataPoints             int                `json:"data_points"`
+	IsClosed               bool               `json:"is_closed"`          // false while the window is still receiving points
+	MissingBars            int                `json:"missing_bars"`       // Expected bars absent from the feed (see data_quality)
+	FilledBars             int                `json:"filled_bars"`        // Bars synthesized by the gap policy
+	IsComplete             bool               `json:"is_complete"`        // No missing bars in the window
+	TrueOHLC               bool               `json:"true_ohlc"`          // High/low from the bars' own highs and lows (false = from closes only)
+	Patterns               []string           `json:"patterns,omitempty"` // Candlestick patterns completed by this candle (closed candles)
+	Metrics                map[string]float64 `json:"metrics,omitempty"`  // Analyzer plugin outputs ("<analyzer>.<metric>")
+
+	// Versus the symbol's benchmark (same window, see benchmarks config)
+	Benchmark        string  `json:"benchmark,omitempty"`
+	BenchmarkReturn  float64 `json:"benchmark_return"`  // Benchmark price % change over the window
+	ExcessReturn     float64 `json:"excess_return"`     // Return - benchmark return
+	RelativeStrength float64 `json:"relative_strength"` // (1 + return) / (1 + benchmark return) - 1
+	Beta             float64 `json:"beta"`              // Rolling OLS beta of window returns
+	Alpha            float64 `json:"alpha"`             // Rolling OLS alpha (per window)
+	ResidualReturn   float64 `json:"residual_return"`   // Return not explained by the benchmark (alpha + beta * benchmark return)
+	ResidualZScore   float64 `json:"residual_zscore"`   // Residual return / residual std (own-news moves stand out)
+
+	// Annualized volatility over the last bars of the window (see volatility config)
+	RealizedVol    float64 `json:"realized_vol"`     // Close-to-close
+	ParkinsonVol   float64 `json:"parkinson_vol"`    // High-low range
+	GarmanKlassVol float64 `json:"garman_klass_vol"` // OHLC
+	YangZhangVol   float64 `json:"yang_zhang_vol"`   // OHLC + gaps between bars
+	VolPercentile  float64 `json:"vol_percentile"`   // Rank of the regime estimator in its rolling history (0..1)
+	HighVolRegime  bool    `json:"high_vol_regime"`  // vol_percentile >= high_percentile
+	LowVolRegime   bool    `json:"low_vol_regime"`   // vol_percentile <= low_percentile
+
+	// Time-of-day volume baseline (see volume_seasonality config)
+	ExpectedVolume   float64   `json:"expected_volume"`    // Usual volume of this intraday slot (0 = flat baseline)
+	VolumeSlotZScore float64   `json:"volume_slot_zscore"` // (volume - expected) / slot dispersion (std or 1.4826 MAD)
+	CreatedAt        time.Time `json:"created_at"`
 }
@@ -0,0 +1,30 @@
+package models
+
+// MAlertRule is a user-defined condition evaluated on every closed candle
+// (and on open candles when OpenCandles is set)
+type MAlertRule struct {
+	ID              int64    `json:"id"`
+	Name            string   `json:"name"`
+	Condition       string   `json:"condition"`           // e.g. "volume_anomaly_ratio > 3 AND price_percent_change > 0.02 on 15m"
+	Symbols         []string `json:"symbols,omitempty"`   // Empty = all symbols (unless Watchlist is set)
+	Watchlist       string   `json:"watchlist,omitempty"` // Named symbol list from config (alerts.watchlists)
+	CooldownSeconds int64    `json:"cooldown_seconds"`    // Minimum delay between two events of a rule for one symbol/window
+	Hysteresis      float64  `json:"hysteresis"`          // Relative threshold margin to clear before the rule re-arms
+	OpenCandles     bool     `json:"open_candles"`        // Also evaluate still-open candles (may fire on intermediate values)
+	Enabled         bool     `json:"enabled"`
+	CreatedAt       int64    `json:"created_at"`
+	UpdatedAt       int64    `json:"updated_at"`
+}
+
+// MAlertEvent is produced when a rule fires, with the candle that triggered it
+type MAlertEvent struct {
+	RuleID     int64              `json:"rule_id"`
+	RuleName   string             `json:"rule_name"`
+	Condition  string             `json:"condition"`
+	Symbol     string             `json:"symbol"`
+	WindowName string             `json:"window_name"`
+	Timestamp  int64              `json:"timestamp"`
+	Values     map[string]float64 `json:"values"` // Fields/indicators referenced by the condition
+	Candle     MAggregation       `json:"candle"`
+	Message    string             `json:"message"`
+}
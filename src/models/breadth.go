@@ -0,0 +1,26 @@
+package models
+
+// MBreadthSnapshot is the market breadth of one window across the monitored universe
+type MBreadthSnapshot struct {
+	WindowName        string  `json:"window_name"`
+	Timestamp         int64   `json:"timestamp"` // Update cycle that produced the snapshot
+	Symbols           int     `json:"symbols"`   // Symbols with a candle in the current window
+	Advancers         int     `json:"advancers"`
+	Decliners         int     `json:"decliners"`
+	Unchanged         int     `json:"unchanged"`
+	UpVolume          float64 `json:"up_volume"`            // Volume of the advancers
+	DownVolume        float64 `json:"down_volume"`          // Volume of the decliners
+	UpDownVolumeRatio float64 `json:"up_down_volume_ratio"` // 0 when there is no down volume
+	AboveAverage      int     `json:"above_average"`        // Symbols closing above their N-period average
+	PctAboveAverage   float64 `json:"pct_above_average"`    // Share of the symbols with a full average (0..1)
+	NewHighs          int     `json:"new_highs"`            // Symbols that made a new session high this cycle
+	NewLows           int     `json:"new_lows"`             // Symbols that made a new session low this cycle
+	Anomalies         int     `json:"anomalies"`            // Symbols with a volume anomaly (see breadth.anomaly_ratio)
+}
+
+// MBreadthMessage is the BREADTH WebSocket message (one snapshot per window)
+type MBreadthMessage struct {
+	Type      string                      `json:"type"` // "BREADTH"
+	Breadth   map[string]MBreadthSnapshot `json:"breadth"`
+	Timestamp int64                       `json:"timestamp"`
+}
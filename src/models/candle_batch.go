@@ -0,0 +1,7 @@
+package models
+
+// MCandleBatch groups the candles touched by one update cycle of the CandleEngine.
+type MCandleBatch struct {
+	Updated map[string]map[string][]MAggregation // Latest (still open) candle per symbol/window
+	Closed  map[string]map[string][]MAggregation // Candles finalized during the cycle
+}
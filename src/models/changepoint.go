@@ -0,0 +1,22 @@
+package models
+
+// MSegmentStats summarizes the observations of a regime
+type MSegmentStats struct {
+	Mean  float64 `json:"mean"`
+	Std   float64 `json:"std"`
+	Count int     `json:"count"`
+}
+
+// MChangepointEvent is a sustained shift detected in a symbol's return or volume stream
+type MChangepointEvent struct {
+	Symbol     string        `json:"symbol"`
+	WindowName string        `json:"window_name"`
+	Series     string        `json:"series"`    // "return" (price % change) or "volume" (log volume)
+	Method     string        `json:"method"`    // "cusum" or "bocpd"
+	Direction  string        `json:"direction"` // "up" or "down"
+	ChangeAt   int64         `json:"change_at"` // Start of the first candle of the new regime
+	Timestamp  int64         `json:"timestamp"` // End of the candle that confirmed the change
+	Score      float64       `json:"score"`     // CUSUM statistic or posterior probability of a recent change
+	Before     MSegmentStats `json:"before"`
+	After      MSegmentStats `json:"after"`
+}
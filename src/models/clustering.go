@@ -0,0 +1,55 @@
+package models
+
+// MCluster is a group of symbols with similar intraday behaviour. Profiles are
+// the mean of its members' profiles (one value per intraday slot).
+type MCluster struct {
+	ID                int       `json:"id"` // 0 = largest cluster
+	Size              int       `json:"size"`
+	Symbols           []string  `json:"symbols"`
+	Centroid          []float64 `json:"centroid"`           // In normalized feature space (see MClusterSnapshot.Features)
+	VolumeProfile     []float64 `json:"volume_profile"`     // Share of the session volume per slot
+	VolatilityProfile []float64 `json:"volatility_profile"` // Share of the session absolute returns per slot
+	ReturnProfile     []float64 `json:"return_profile"`     // Net return per slot / session absolute returns
+}
+
+// MClusterAssignment is the cluster of a symbol, with the clusters of its last two sessions
+type MClusterAssignment struct {
+	Symbol          string  `json:"symbol"`
+	Cluster         int     `json:"cluster"`  // From the lookback profile
+	Distance        float64 `json:"distance"` // To the cluster centroid (normalized space)
+	Sessions        int     `json:"sessions"` // Sessions in the lookback profile
+	LatestSession   string  `json:"latest_session"`
+	LatestCluster   int     `json:"latest_cluster"` // Nearest centroid of the latest session alone
+	PreviousSession string  `json:"previous_session,omitempty"`
+	PreviousCluster int     `json:"previous_cluster"` // -1 without a previous session
+	Jumped          bool    `json:"jumped"`           // Latest and previous sessions in different clusters
+}
+
+// MClusterSnapshot is the result of a clustering run
+type MClusterSnapshot struct {
+	Clusters     []MCluster           `json:"clusters"`
+	Assignments  []MClusterAssignment `json:"assignments"` // Sorted by symbol
+	Features     []string             `json:"features"`    // Names of the centroid dimensions
+	Slots        int                  `json:"slots"`
+	LookbackDays int                  `json:"lookback_days"` // Sessions every clustered symbol has
+	Skipped      []MClusterSkipped    `json:"skipped"`       // Left out for lack of history, sorted by symbol
+	Inertia      float64              `json:"inertia"`
+	Timestamp    int64                `json:"timestamp"`
+}
+
+// MClusterSkipped is a symbol left out of a run: fewer sessions than the lookback
+type MClusterSkipped struct {
+	Symbol   string `json:"symbol"`
+	Sessions int    `json:"sessions"` // Sessions with enough points found in storage
+}
+
+// MClusterJump is a symbol whose latest session behaved like another cluster than the session before
+type MClusterJump struct {
+	Symbol          string  `json:"symbol"`
+	Session         string  `json:"session"` // YYYY-MM-DD, exchange timezone
+	PreviousSession string  `json:"previous_session"`
+	FromCluster     int     `json:"from_cluster"`
+	ToCluster       int     `json:"to_cluster"`
+	Distance        float64 `json:"distance"` // Latest session to its new centroid (normalized space)
+	Timestamp       int64   `json:"timestamp"`
+}
@@ -2,16 +2,36 @@ package models
 
 // MConfig Structure
 type MConfig struct {
-	Name       string            `yaml:"name"`
-	Host       string            `yaml:"host"`
-	Port       int               `yaml:"port"`
-	LogLevel   string            `yaml:"log_level"`
-	GrpcHost   string            `yaml:"grpc_host"`
-	GrpcPort   int               `yaml:"grpc_port"`
-	Storage    MStorageConfig    `yaml:"storage"`
-	Network    MNetworkConfig    `yaml:"network"`
-	DataSource MDataSourceConfig `yaml:"data_source"`
-	WindowsAgg []string          `yaml:"windows_aggregation"`
+	Name          string                 `yaml:"name"`
+	Host          string                 `yaml:"host"`
+	Port          int                    `yaml:"port"`
+	LogLevel      string                 `yaml:"log_level"`
+	GrpcHost      string                 `yaml:"grpc_host"`
+	GrpcPort      int                    `yaml:"grpc_port"`
+	Storage       MStorageConfig         `yaml:"storage"`
+	Network       MNetworkConfig         `yaml:"network"`
+	DataSource    MDataSourceConfig      `yaml:"data_source"`
+	WindowsAgg    []string               `yaml:"windows_aggregation"`
+	RollingStats  MRollingStatsConfig    `yaml:"rolling_stats"`
+	Correlation   MCorrelationConfig     `yaml:"correlation"`
+	Alerts        MAlertsConfig          `yaml:"alerts"`
+	Notifications MNotificationsConfig   `yaml:"notifications"`
+	DataQuality   MDataQualityConfig     `yaml:"data_quality"`
+	Validation    MValidationConfig      `yaml:"validation"`
+	Analyzers     []string               `yaml:"analyzers"` // Analyzer plugins to enable (by name)
+	Benchmarks    MBenchmarksConfig      `yaml:"benchmarks"`
+	Breadth       MBreadthConfig         `yaml:"breadth"`
+	Volatility    MVolatilityConfig      `yaml:"volatility"`
+	Seasonality   MSeasonalityConfig     `yaml:"volume_seasonality"`
+	Changepoints  MChangepointConfig     `yaml:"changepoints"`
+	Patterns      MPatternConfig         `yaml:"candle_patterns"`
+	Levels        MLevelsConfig          `yaml:"levels"`
+	VolumeProfile MVolumeProfileConfig   `yaml:"volume_profile"`
+	LeadLag       MLeadLagConfig         `yaml:"lead_lag"`
+	Clustering    MClusteringConfig      `yaml:"clustering"`
+	EventStudy    MEventStudyConfig      `yaml:"event_study"`
+	Leaderboards  MLeaderboardConfig     `yaml:"leaderboards"`
+	Synthetics    []MSyntheticInstrument `yaml:"synthetic_instruments"` // Symbols computed from other symbols
 }
 
 type MStorageConfig struct {
@@ -39,4 +59,207 @@ type MSourceConfig struct {
 	Name    string   `yaml:"name"`
 	Symbols []string `yaml:"symbols"`
 	APIKey  string   `yaml:"api_key"` // Optional
+
+	Benchmark string `yaml:"benchmark"` // Benchmark symbol for this source's symbols (optional)
+}
+
+// MSyntheticInstrument defines a symbol whose points are computed from the
+// points of existing symbols (ratio, hedged spread or weighted basket).
+type MSyntheticInstrument struct {
+	Name   string          `yaml:"name"`
+	Type   string          `yaml:"type"` // "ratio", "spread" or "basket"
+	Legs   []MSyntheticLeg `yaml:"legs"`
+	Offset float64         `yaml:"offset"` // Added to the computed price (keeps spreads positive)
+}
+
+type MSyntheticLeg struct {
+	Symbol string  `yaml:"symbol"`
+	Weight float64 `yaml:"weight"` // Basket weight or spread hedge ratio (0 = 1, ignored by ratios)
+}
+
+type MRollingStatsConfig struct {
+	Method                 string `yaml:"method"`                   // "ewma" or "welford"
+	Lookback               int    `yaml:"lookback"`                 // Number of closed candles (EWMA span / Welford cap)
+	PersistIntervalSeconds int    `yaml:"persist_interval_seconds"` // How often rolling stats are saved
+}
+
+type MCorrelationConfig struct {
+	Lookback          int     `yaml:"lookback"`           // Number of window returns in the rolling matrix
+	BaselineLookback  int     `yaml:"baseline_lookback"`  // Window returns before the lookback defining the "normal" correlation
+	MinObservations   int     `yaml:"min_observations"`   // Minimum overlapping returns for a pair
+	BreakdownBaseline float64 `yaml:"breakdown_baseline"` // Pairs with baseline >= this are "normally correlated"
+	BreakdownDrop     float64 `yaml:"breakdown_drop"`     // Alert when correlation falls this much below baseline
+}
+
+type MAlertsConfig struct {
+	Watchlists map[string][]string `yaml:"watchlists"` // Named symbol lists usable to scope rules
+	MaxEvents  int                 `yaml:"max_events"` // Recent alert events kept in memory
+}
+
+type MNotificationsConfig struct {
+	DedupWindowSeconds int                          `yaml:"dedup_window_seconds"` // Same dedup key is delivered once per window
+	QueueSize          int                          `yaml:"queue_size"`           // Per-channel pending notifications
+	MaxDeliveries      int                          `yaml:"max_deliveries"`       // Recent delivery log entries kept in memory
+	Channels           []MNotificationChannelConfig `yaml:"channels"`
+}
+
+type MNotificationChannelConfig struct {
+	Name    string   `yaml:"name"`
+	Type    string   `yaml:"type"` // "webhook", "slack", "teams" or "smtp"
+	Enabled bool     `yaml:"enabled"`
+	Kinds   []string `yaml:"kinds"` // Notification kinds to deliver (empty = all)
+
+	// webhook / slack / teams
+	URL     string            `yaml:"url"`
+	Secret  string            `yaml:"secret"` // HMAC-SHA256 signing key (webhook)
+	Headers map[string]string `yaml:"headers"`
+
+	// smtp
+	SMTPHost string   `yaml:"smtp_host"`
+	SMTPPort int      `yaml:"smtp_port"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+
+	// Delivery policy (zero values fall back to defaults)
+	RateLimitPerMinute int `yaml:"rate_limit_per_minute"`
+	MaxRetries         int `yaml:"max_retries"`
+	InitialBackoffMs   int `yaml:"initial_backoff_ms"`
+	MaxBackoffMs       int `yaml:"max_backoff_ms"`
+	TimeoutSeconds     int `yaml:"timeout_seconds"`
+}
+
+type MDataQualityConfig struct {
+	GapPolicy          string `yaml:"gap_policy"`           // "none", "forward_fill" or "interpolate"
+	BarIntervalSeconds int    `yaml:"bar_interval_seconds"` // Expected spacing of the feed
+	MaxFillBars        int    `yaml:"max_fill_bars"`        // Longer gaps are left unfilled
+	MaxGaps            int    `yaml:"max_gaps"`             // Recent gaps kept per symbol
+}
+
+type MBenchmarksConfig struct {
+	Default         string            `yaml:"default"`          // Benchmark for symbols without a watchlist/source benchmark
+	Watchlists      map[string]string `yaml:"watchlists"`       // Watchlist (alerts.watchlists) -> benchmark symbol, takes precedence
+	Lookback        int               `yaml:"lookback"`         // Closed window returns in the rolling beta/alpha
+	MinObservations int               `yaml:"min_observations"` // Returns needed before beta/alpha are reported
+}
+
+type MBreadthConfig struct {
+	AveragePeriod int     `yaml:"average_period"` // Closed candles in the moving average of the "% above average"
+	AnomalyRatio  float64 `yaml:"anomaly_ratio"`  // Volume anomaly ratio counted as an anomaly
+	MaxHistory    int     `yaml:"max_history"`    // Snapshots kept in memory per window (REST history)
+}
+
+type MLeaderboardConfig struct {
+	Size int `yaml:"size"` // Entries per leaderboard pushed over WebSocket (REST can ask for more)
+}
+
+type MSeasonalityConfig struct {
+	Method       string `yaml:"method"`        // "flat" (average of all windows), "mean" or "median" (median/MAD) per slot
+	LookbackDays int    `yaml:"lookback_days"` // Sessions in each slot's baseline
+	MinDays      int    `yaml:"min_days"`      // Sessions needed before a slot replaces the flat baseline
+}
+
+// MChangepointSettings are the detector settings of a window (zero values inherit the defaults)
+type MChangepointSettings struct {
+	Method     string  `yaml:"method"`      // "cusum" or "bocpd"
+	Threshold  float64 `yaml:"threshold"`   // CUSUM decision interval (in standard deviations)
+	Drift      float64 `yaml:"drift"`       // CUSUM slack per observation (in standard deviations)
+	Hazard     float64 `yaml:"hazard"`      // BOCPD expected run length between changes (candles)
+	Confidence float64 `yaml:"confidence"`  // BOCPD posterior mass on a recent change needed to emit an event
+	MinSegment int     `yaml:"min_segment"` // Candles in a regime before a new change can be detected
+}
+
+type MChangepointConfig struct {
+	Enabled              bool                            `yaml:"enabled"`
+	MChangepointSettings `yaml:",inline"`                // Defaults of every window
+	Windows              map[string]MChangepointSettings `yaml:"windows"`    // Per-window overrides
+	MaxEvents            int                             `yaml:"max_events"` // Recent events kept in memory
+}
+
+type MPatternConfig struct {
+	Enabled         bool     `yaml:"enabled"`
+	Patterns        []string `yaml:"patterns"`          // Patterns to detect (empty = all)
+	DojiBodyRatio   float64  `yaml:"doji_body_ratio"`   // Body / range at or below which a candle is a doji
+	ShadowRatio     float64  `yaml:"shadow_ratio"`      // Hammer lower shadow / body from which it counts
+	StarBodyRatio   float64  `yaml:"star_body_ratio"`   // Star body / first candle body at or below which it counts
+	RequireTrueOHLC bool     `yaml:"require_true_ohlc"` // Skip patterns on candles built from closes only
+	MaxEvents       int      `yaml:"max_events"`        // Recent events kept in memory
+}
+
+type MLevelsConfig struct {
+	Enabled                bool    `yaml:"enabled"`
+	RefreshIntervalSeconds int     `yaml:"refresh_interval_seconds"` // Levels are recomputed from the history this often
+	SwingBars              int     `yaml:"swing_bars"`               // Bars on each side of a swing high/low
+	BucketPct              float64 `yaml:"bucket_pct"`               // Volume-by-price bucket width (fraction of the last price)
+	VolumeNodeRatio        float64 `yaml:"volume_node_ratio"`        // Bucket volume / mean bucket volume of a high-volume node
+	MergePct               float64 `yaml:"merge_pct"`                // Candidates closer than this (fraction of the last price) are one level
+	MaxLevels              int     `yaml:"max_levels"`               // Strongest levels kept per symbol
+	Window                 string  `yaml:"window"`                   // Window whose closed candles break levels ("" = first window)
+	BreakoutVolumeRatio    float64 `yaml:"breakout_volume_ratio"`    // Volume anomaly ratio a breaking candle needs
+	MaxEvents              int     `yaml:"max_events"`               // Recent events kept in memory
+}
+
+type MLeadLagConfig struct {
+	Enabled         bool           `yaml:"enabled"`
+	Window          string         `yaml:"window"`           // Return interval ("" = first window)
+	Pairs           []MLeadLagPair `yaml:"pairs"`            // Explicit pairs
+	Watchlists      []string       `yaml:"watchlists"`       // Every pair within these watchlists (alerts.watchlists)
+	MaxLag          int            `yaml:"max_lag"`          // Largest lead/lag tested, in windows
+	Lookback        int            `yaml:"lookback"`         // Returns of each estimate
+	Segments        int            `yaml:"segments"`         // Sub-periods of the stability check
+	MinObservations int            `yaml:"min_observations"` // Overlapping returns needed at a lag
+}
+
+type MLeadLagPair struct {
+	A string `yaml:"a"`
+	B string `yaml:"b"`
+}
+
+type MClusteringConfig struct {
+	Enabled                bool `yaml:"enabled"`
+	Clusters               int  `yaml:"clusters"`                 // k of k-means (fewer when the universe is smaller)
+	LookbackDays           int  `yaml:"lookback_days"`            // Sessions averaged into each symbol's profile
+	Slots                  int  `yaml:"slots"`                    // Intraday slots a session is split into
+	MinSessionPoints       int  `yaml:"min_session_points"`       // Points a session needs to have a profile
+	RefreshIntervalSeconds int  `yaml:"refresh_interval_seconds"` // Clustering job period
+	Restarts               int  `yaml:"restarts"`                 // k-means runs (the lowest inertia is kept)
+	MaxJumps               int  `yaml:"max_jumps"`                // Recent cluster jumps kept in memory
+}
+
+type MEventStudyConfig struct {
+	Enabled                bool      `yaml:"enabled"`
+	Windows                []string  `yaml:"windows"`                  // Windows studied (empty = all)
+	Horizons               []int     `yaml:"horizons"`                 // Forward horizons in windows of the event's window
+	Severities             []float64 `yaml:"severities"`               // Ascending volume anomaly ratio bounds (the first is the anomaly threshold)
+	Model                  string    `yaml:"model"`                    // Abnormal return model: "market" or "beta"
+	LookbackDays           int       `yaml:"lookback_days"`            // Stored aggregations studied (0 = data_retention_days, capped by it)
+	RefreshIntervalSeconds int       `yaml:"refresh_interval_seconds"` // Event study job period
+	MaxEvents              int       `yaml:"max_events"`               // Most recent events kept for listing
+}
+
+type MVolumeProfileConfig struct {
+	Buckets      int     `yaml:"buckets"`        // Price buckets when a request sets neither buckets nor bucket_size
+	ValueAreaPct float64 `yaml:"value_area_pct"` // Share of the volume in the value area (0..1)
+	MaxBuckets   int     `yaml:"max_buckets"`    // Upper bound on the buckets of a profile
+}
+
+type MVolatilityConfig struct {
+	Lookback         int     `yaml:"lookback"`           // Bars in each estimator (current candle included)
+	RegimeEstimator  string  `yaml:"regime_estimator"`   // realized, parkinson, garman_klass or yang_zhang
+	RegimeLookback   int     `yaml:"regime_lookback"`    // Closed-window estimates in the rolling percentile
+	RegimeMinSamples int     `yaml:"regime_min_samples"` // Estimates needed before regimes are flagged
+	HighPercentile   float64 `yaml:"high_percentile"`    // High-volatility regime from this percentile (0..1)
+	LowPercentile    float64 `yaml:"low_percentile"`     // Low-volatility regime up to this percentile (0..1)
+}
+
+type MValidationConfig struct {
+	Enabled           bool    `yaml:"enabled"`
+	JumpSigma         float64 `yaml:"jump_sigma"`          // Quarantine returns beyond N sigma (per-bar volatility)
+	MinJumpPct        float64 `yaml:"min_jump_pct"`        // Smaller moves are never jumps (fraction, 0.02 = 2%)
+	JumpMinSamples    int     `yaml:"jump_min_samples"`    // Returns needed before the jump rule applies
+	JumpConfirmPoints int     `yaml:"jump_confirm_points"` // Consecutive prints at the new level that confirm it
+	StaleRepeatLimit  int     `yaml:"stale_repeat_limit"`  // Identical price/volume prints tolerated in a row
+	MaxFutureSeconds  int     `yaml:"max_future_seconds"`  // Clock skew tolerated for timestamps ahead of now
+	MaxQuarantine     int     `yaml:"max_quarantine"`      // Pending quarantined points kept in memory
 }
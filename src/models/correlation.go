@@ -0,0 +1,29 @@
+package models
+
+// MCorrelationMatrix is the rolling return correlation matrix of the universe for one window
+type MCorrelationMatrix struct {
+	WindowName string      `json:"window_name"`
+	Symbols    []string    `json:"symbols"`
+	Matrix     [][]float64 `json:"matrix"`   // Matrix[i][j] = corr(Symbols[i], Symbols[j]), 0 when not enough overlap
+	Lookback   int         `json:"lookback"` // Number of window returns used
+	Timestamp  int64       `json:"timestamp"`
+}
+
+// MCorrelationPair is the correlation of two symbols, with its long-run baseline
+type MCorrelationPair struct {
+	SymbolA      string  `json:"symbol_a"`
+	SymbolB      string  `json:"symbol_b"`
+	Correlation  float64 `json:"correlation"`
+	Baseline     float64 `json:"baseline"`
+	Observations int     `json:"observations"`
+}
+
+// MCorrelationAlert is raised when a normally correlated pair decouples
+type MCorrelationAlert struct {
+	WindowName  string  `json:"window_name"`
+	SymbolA     string  `json:"symbol_a"`
+	SymbolB     string  `json:"symbol_b"`
+	Correlation float64 `json:"correlation"`
+	Baseline    float64 `json:"baseline"`
+	Timestamp   int64   `json:"timestamp"`
+}
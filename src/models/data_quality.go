@@ -0,0 +1,44 @@
+package models
+
+// MDataGap is a run of expected bars missing from a symbol's feed
+type MDataGap struct {
+	Symbol      string `json:"symbol"`
+	From        int64  `json:"from"`         // Timestamp of the last point before the gap
+	To          int64  `json:"to"`           // Timestamp of the first point after the gap
+	MissingBars int    `json:"missing_bars"` // Expected bars (market open) absent from the feed
+	FilledBars  int    `json:"filled_bars"`  // Bars synthesized by the gap policy
+	DetectedAt  int64  `json:"detected_at"`
+}
+
+// MDataQualityReport summarizes the feed quality of one symbol
+type MDataQualityReport struct {
+	Symbol        string     `json:"symbol"`
+	Policy        string     `json:"policy"`
+	BarInterval   int64      `json:"bar_interval"` // Expected seconds between bars
+	Points        int        `json:"points"`       // Real points received
+	MissingBars   int        `json:"missing_bars"`
+	FilledBars    int        `json:"filled_bars"`
+	Completeness  float64    `json:"completeness"` // points / (points + missing bars)
+	LastTimestamp int64      `json:"last_timestamp"`
+	Gaps          []MDataGap `json:"gaps,omitempty"` // Most recent last
+}
+
+// MQuarantinedPrice is an inbound point held back by the validation stage
+type MQuarantinedPrice struct {
+	ID            int64       `json:"id"`
+	Price         MStockPrice `json:"price"`
+	Reason        string      `json:"reason"` // "price_jump", "stale_print", "timestamp_regression", "future_timestamp"
+	Detail        string      `json:"detail"`
+	QuarantinedAt int64       `json:"quarantined_at"`
+	ReleasedAt    int64       `json:"released_at,omitempty"` // 0 while pending
+}
+
+// MValidationStats are the validation counters of one symbol (or all symbols)
+type MValidationStats struct {
+	Symbol      string         `json:"symbol,omitempty"`
+	Checked     int            `json:"checked"`
+	Accepted    int            `json:"accepted"`
+	Quarantined int            `json:"quarantined"`
+	Released    int            `json:"released"`
+	ByReason    map[string]int `json:"by_reason"`
+}
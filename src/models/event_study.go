@@ -0,0 +1,67 @@
+package models
+
+// MEventForwardReturn is the move of a symbol over a horizon after an anomaly
+// (fractions like price_percent_change, from the close of the anomalous candle).
+type MEventForwardReturn struct {
+	Bars            int     `json:"bars"` // Windows after the event
+	Return          float64 `json:"return"`
+	BenchmarkReturn float64 `json:"benchmark_return"`
+	AbnormalReturn  float64 `json:"abnormal_return"` // Return not explained by the benchmark (see event_study model)
+	HasBenchmark    bool    `json:"has_benchmark"`   // Every candle of the horizon had a benchmark return
+}
+
+// MAnomalyEvent is a past volume anomaly with its forward returns
+type MAnomalyEvent struct {
+	Symbol             string                `json:"symbol"`
+	WindowName         string                `json:"window_name"`
+	StartTime          int64                 `json:"start_time"`
+	EndTime            int64                 `json:"end_time"`
+	Severity           string                `json:"severity"` // Volume anomaly ratio bucket, e.g. "2-3x"
+	VolumeAnomalyRatio float64               `json:"volume_anomaly_ratio"`
+	Return             float64               `json:"return"` // Price % change of the anomalous candle
+	Close              float64               `json:"close"`
+	Benchmark          string                `json:"benchmark,omitempty"`
+	Forward            []MEventForwardReturn `json:"forward"` // Horizons the stored candles reach without a gap, shortest first
+}
+
+// MEventStudyHorizon summarizes the forward returns of a group of events at one horizon
+type MEventStudyHorizon struct {
+	Bars             int     `json:"bars"`
+	Events           int     `json:"events"`          // Events with this horizon
+	AbnormalEvents   int     `json:"abnormal_events"` // Of which with a benchmark return
+	MeanReturn       float64 `json:"mean_return"`
+	MedianReturn     float64 `json:"median_return"`
+	MeanAbnormal     float64 `json:"mean_abnormal"`
+	StdAbnormal      float64 `json:"std_abnormal"`
+	TStat            float64 `json:"t_stat"`            // Mean abnormal return / standard error
+	HitRate          float64 `json:"hit_rate"`          // Share of positive abnormal returns
+	MeanContinuation float64 `json:"mean_continuation"` // Mean abnormal return in the direction of the anomalous candle
+}
+
+// MEventStudyGroup aggregates the events of a symbol, window and severity ("*" = pooled)
+type MEventStudyGroup struct {
+	Symbol     string               `json:"symbol"`
+	WindowName string               `json:"window_name"`
+	Severity   string               `json:"severity"`
+	Events     int                  `json:"events"`
+	From       int64                `json:"from"` // Start times of the first and last event
+	To         int64                `json:"to"`
+	MeanRatio  float64              `json:"mean_ratio"` // Mean volume anomaly ratio of the events
+	Horizons   []MEventStudyHorizon `json:"horizons"`
+}
+
+// MEventStudyReport is the result of an event study run
+type MEventStudyReport struct {
+	Model       string             `json:"model"`
+	Horizons    []int              `json:"horizons"`
+	Severities  []string           `json:"severities"`
+	From        int64              `json:"from"` // Lookback requested (start times)
+	To          int64              `json:"to"`
+	Candles     int                `json:"candles"`      // Closed candles studied, all windows
+	HistoryFrom int64              `json:"history_from"` // Start of the first candle studied (0 without candles)
+	HistoryTo   int64              `json:"history_to"`   // End of the last candle studied
+	Events      int                `json:"events"`
+	GapHorizons int                `json:"gap_horizons"` // Forward horizons left out: a candle is missing within them
+	Groups      []MEventStudyGroup `json:"groups"`
+	Timestamp   int64              `json:"timestamp"`
+}
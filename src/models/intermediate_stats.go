@@ -8,6 +8,8 @@ type MIntermediateStats struct {
 	WindowName           string
 	AvgVolumeHistory     float64
 	StdVolumeHistory     float64
+	AvgReturnHistory     float64 // Mean close-to-close return per window
+	StdReturnHistory     float64
 	DataPointsHistory    int
 	LastHistoryTimestamp int64
 	UpdatedAt            time.Time
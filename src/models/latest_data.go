@@ -5,11 +5,16 @@ package models
 // -----------------------------------------------------------------------------
 
 type MLatestData struct {
-	Type              string                               `json:"type"` // "INITIAL" or "UPDATE"
-	RawData           map[string]MStockPrice               `json:"raw_data"`
-	Aggregations      map[string]map[string][]MAggregation `json:"aggregations"`
-	Timestamp         int64                                `json:"timestamp"`
-	ProcessingMetrics MProcessingMetrics                   `json:"processing_metrics"`
+	Type               string                               `json:"type"` // "INITIAL" or "UPDATE"
+	RawData            map[string]MStockPrice               `json:"raw_data"`
+	Aggregations       map[string]map[string][]MAggregation `json:"aggregations"`
+	ClosedAggregations map[string]map[string][]MAggregation `json:"closed_aggregations,omitempty"` // Candles finalized in this update (broadcast only)
+	Alerts             []MAlertEvent                        `json:"alerts,omitempty"`              // Alert rule events fired in this update (broadcast only)
+	Changepoints       []MChangepointEvent                  `json:"changepoints,omitempty"`        // Regime changes detected in this update (broadcast only)
+	Patterns           []MPatternEvent                      `json:"patterns,omitempty"`            // Candlestick patterns completed in this update (broadcast only)
+	LevelBreaks        []MLevelBreakEvent                   `json:"level_breaks,omitempty"`        // Support/resistance breaks in this update (broadcast only)
+	Timestamp          int64                                `json:"timestamp"`
+	ProcessingMetrics  MProcessingMetrics                   `json:"processing_metrics"`
 }
 
 // -----------------------------------------------------------------------------
@@ -21,4 +26,5 @@ type MSubscribeCommand struct {
 	ClientType string   `json:"clientType"`
 	Symbols    []string `json:"symbols"`
 	Timeframe  string   `json:"timeframe"`
+	ScreenID   int64    `json:"screen_id,omitempty"` // "subscribe_screen" / "unsubscribe_screen"
 }
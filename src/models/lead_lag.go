@@ -0,0 +1,27 @@
+package models
+
+// MLagCorrelation is the correlation of A's returns with B's returns Lag windows later
+type MLagCorrelation struct {
+	Lag          int     `json:"lag"`
+	Correlation  float64 `json:"correlation"`
+	Observations int     `json:"observations"`
+}
+
+// MLeadLagResult is the lead/lag relationship of a symbol pair on one window
+type MLeadLagResult struct {
+	SymbolA          string            `json:"symbol_a"`
+	SymbolB          string            `json:"symbol_b"`
+	WindowName       string            `json:"window_name"`
+	Leader           string            `json:"leader"`   // "" when the strongest correlation is contemporaneous
+	Follower         string            `json:"follower"` // "" when the strongest correlation is contemporaneous
+	LagBars          int               `json:"lag_bars"` // Best lag: > 0 A leads B, < 0 B leads A
+	LagSeconds       int64             `json:"lag_seconds"`
+	Correlation      float64           `json:"correlation"`       // At the best lag
+	Contemporaneous  float64           `json:"contemporaneous"`   // At lag 0
+	Edge             float64           `json:"edge"`              // |correlation| - |contemporaneous|
+	SegmentLags      []int             `json:"segment_lags"`      // Best lag of each sub-period with enough data, oldest first
+	Stability        float64           `json:"stability"`         // Share of those sub-periods agreeing with the best lag (0..1)
+	Observations     int               `json:"observations"`      // Overlapping returns at the best lag
+	CrossCorrelation []MLagCorrelation `json:"cross_correlation"` // Every tested lag, most negative first
+	Timestamp        int64             `json:"timestamp"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// MLeaderboardEntry is a ranked symbol of a leaderboard (rank 1 = first)
+type MLeaderboardEntry struct {
+	Symbol string  `json:"symbol"`
+	Rank   int     `json:"rank"`
+	Value  float64 `json:"value"`
+}
+
+// MLeaderboardSnapshot holds the leaderboards of every window (window -> board -> entries)
+type MLeaderboardSnapshot struct {
+	Sequence     int64                                     `json:"sequence"` // Last LEADERBOARD message included
+	Timestamp    int64                                     `json:"timestamp"`
+	Leaderboards map[string]map[string][]MLeaderboardEntry `json:"leaderboards"`
+}
+
+// MLeaderboardChange is the diff of one leaderboard since the previous message
+type MLeaderboardChange struct {
+	Window  string              `json:"window"`
+	Board   string              `json:"board"`
+	Updated []MLeaderboardEntry `json:"updated,omitempty"` // New entries and entries whose rank or value changed
+	Removed []string            `json:"removed,omitempty"` // Symbols that left the board
+}
+
+// MLeaderboardMessage pushes the leaderboard diffs of an update cycle
+type MLeaderboardMessage struct {
+	Type      string               `json:"type"`     // "LEADERBOARD"
+	Sequence  int64                `json:"sequence"` // Increments by one per message
+	Changes   []MLeaderboardChange `json:"changes"`
+	Timestamp int64                `json:"timestamp"`
+}
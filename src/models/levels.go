@@ -0,0 +1,38 @@
+package models
+
+// MPriceLevel is a support or resistance level of a symbol
+type MPriceLevel struct {
+	Price     float64 `json:"price"`
+	Kind      string  `json:"kind"`       // "support" (below the last price) or "resistance" (above)
+	Source    string  `json:"source"`     // "swing", "volume" or "swing+volume"
+	Touches   int     `json:"touches"`    // Swing highs/lows merged into the level
+	Volume    float64 `json:"volume"`     // Volume traded around the level
+	Strength  float64 `json:"strength"`   // Touches + volume / mean bucket volume
+	LastTouch int64   `json:"last_touch"` // Latest swing at the level (0 = volume node only)
+	Broken    bool    `json:"broken"`     // Crossed since the last refresh (kind already flipped)
+}
+
+// MSymbolLevels are the levels of a symbol, highest price first
+type MSymbolLevels struct {
+	Symbol    string        `json:"symbol"`
+	Price     float64       `json:"price"` // Last price of the history the levels were built from
+	Levels    []MPriceLevel `json:"levels"`
+	From      int64         `json:"from"` // History range
+	To        int64         `json:"to"`
+	UpdatedAt int64         `json:"updated_at"`
+}
+
+// MLevelBreakEvent is a closed candle crossing a level on elevated volume
+type MLevelBreakEvent struct {
+	Symbol        string  `json:"symbol"`
+	WindowName    string  `json:"window_name"`
+	Type          string  `json:"type"`     // "breakout" (crossed above) or "breakdown" (crossed below)
+	Level         float64 `json:"level"`    // Price of the broken level
+	Kind          string  `json:"kind"`     // Kind of the level before the break
+	Source        string  `json:"source"`   // Source of the level
+	Strength      float64 `json:"strength"` // Strength of the level
+	PreviousClose float64 `json:"previous_close"`
+	Close         float64 `json:"close"`
+	VolumeRatio   float64 `json:"volume_ratio"` // Volume anomaly ratio of the breaking candle
+	Timestamp     int64   `json:"timestamp"`    // End of the breaking candle
+}
@@ -0,0 +1,25 @@
+package models
+
+// MNotification is an outbound message handed to the notification dispatcher
+type MNotification struct {
+	Kind      string      `json:"kind"` // "alert", "correlation_breakdown", "test"
+	Title     string      `json:"title"`
+	Message   string      `json:"message"`
+	Symbol    string      `json:"symbol,omitempty"`
+	Window    string      `json:"window,omitempty"`
+	Timestamp int64       `json:"timestamp"`
+	DedupKey  string      `json:"dedup_key"` // Same key within the dedup window is delivered once
+	Payload   interface{} `json:"payload,omitempty"`
+}
+
+// MNotificationDelivery is one entry of the delivery log
+type MNotificationDelivery struct {
+	Channel   string `json:"channel"`
+	Kind      string `json:"kind"`
+	DedupKey  string `json:"dedup_key"`
+	Title     string `json:"title"`
+	Status    string `json:"status"` // "delivered", "failed", "dropped", "deduplicated"
+	Attempts  int    `json:"attempts"`
+	Error     string `json:"error,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// MPatternEvent is a candlestick pattern completed by a closed candle
+type MPatternEvent struct {
+	Symbol     string  `json:"symbol"`
+	WindowName string  `json:"window_name"`
+	Pattern    string  `json:"pattern"`    // e.g. "doji", "bullish_engulfing", "morning_star"
+	Direction  string  `json:"direction"`  // "bullish", "bearish" or "neutral"
+	Bars       int     `json:"bars"`       // Candles forming the pattern (the last one completes it)
+	StartTime  int64   `json:"start_time"` // Start of the first candle of the pattern
+	Timestamp  int64   `json:"timestamp"`  // End of the candle that completed the pattern
+	Close      float64 `json:"close"`      // Close of that candle
+	TrueOHLC   bool    `json:"true_ohlc"`  // Every candle of the pattern had real highs and lows
+}
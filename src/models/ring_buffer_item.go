@@ -7,5 +7,8 @@ const (
 	RB_IDX_VOLUME    = 2
 	RB_IDX_PRICE_PCT = 3
 	RB_IDX_VOL_PCT   = 4
-	RB_NUM_FEATURES  = 5
+	RB_IDX_OPEN      = 5
+	RB_IDX_HIGH      = 6
+	RB_IDX_LOW       = 7
+	RB_NUM_FEATURES  = 8
 )
@@ -0,0 +1,59 @@
+package models
+
+// MScreenFilter is one threshold of a screen ("field op value")
+type MScreenFilter struct {
+	Field string  `json:"field"` // MAggregation field, indicator or "metrics.<analyzer>.<metric>"
+	Op    string  `json:"op"`    // ">", ">=", "<", "<=", "==" or "!="
+	Value float64 `json:"value"`
+}
+
+// MScreenRequest selects symbols on their latest candle of a window
+type MScreenRequest struct {
+	Window    string          `json:"window"`
+	Filters   []MScreenFilter `json:"filters,omitempty"`   // All must hold
+	Condition string          `json:"condition,omitempty"` // Extra alert rule expression (AND/OR/parentheses, no "on")
+	Symbols   []string        `json:"symbols,omitempty"`   // Empty = all symbols (unless Watchlist is set)
+	Watchlist string          `json:"watchlist,omitempty"` // Named symbol list from config (alerts.watchlists)
+	SortBy    string          `json:"sort_by,omitempty"`   // Field to rank by ("" = symbol)
+	Ascending bool            `json:"ascending,omitempty"` // Default descending when SortBy is set
+	Limit     int             `json:"limit,omitempty"`     // Top N after sorting (0 = all)
+	Fields    []string        `json:"fields,omitempty"`    // Extra values returned with each match
+}
+
+// MScreenMatch is a symbol passing a screen with the values it was judged on
+type MScreenMatch struct {
+	Symbol string             `json:"symbol"`
+	Values map[string]float64 `json:"values"` // Filter, sort and requested fields
+	Candle MAggregation       `json:"candle"`
+}
+
+// MScreenResult is the outcome of a screen over the latest candles
+type MScreenResult struct {
+	Window    string         `json:"window"`
+	Timestamp int64          `json:"timestamp"` // Update the candles belong to
+	Total     int            `json:"total"`     // Matches before the limit
+	Matches   []MScreenMatch `json:"matches"`
+}
+
+// MSavedScreen is a stored screen, re-run on every update cycle
+type MSavedScreen struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	MScreenRequest
+	Enabled   bool  `json:"enabled"`
+	CreatedAt int64 `json:"created_at"`
+	UpdatedAt int64 `json:"updated_at"`
+}
+
+// MScreenMessage pushes the membership of a saved screen to its WebSocket subscribers
+type MScreenMessage struct {
+	Type      string         `json:"type"` // "SCREEN"
+	ScreenID  int64          `json:"screen_id"`
+	Name      string         `json:"name"`
+	Window    string         `json:"window"`
+	Members   []string       `json:"members"`           // Current members, in screen order
+	Entered   []string       `json:"entered,omitempty"` // Joined since the previous update
+	Exited    []string       `json:"exited,omitempty"`  // Left since the previous update
+	Matches   []MScreenMatch `json:"matches"`
+	Timestamp int64          `json:"timestamp"`
+}
@@ -12,4 +12,10 @@ type MStockPrice struct {
 	Timestamp           int64     `json:"timestamp"`
 	FetchedAt           int64     `json:"fetched_at"`
 	CreatedAt           time.Time `json:"created_at"`
+	Filled              bool      `json:"filled,omitempty"` // Synthesized by the gap policy (never stored)
+
+	// Bar OHLC when the source provides it (0 = only the close is known)
+	Open float64 `json:"open,omitempty"`
+	High float64 `json:"high,omitempty"`
+	Low  float64 `json:"low,omitempty"`
 }
@@ -0,0 +1,42 @@
+package models
+
+// MVolumeProfileRequest selects the bars of a volume profile: a trading session
+// (YYYY-MM-DD, exchange timezone) or a time range, the latest session when
+// neither is given. Zero bucket settings use the configured defaults.
+type MVolumeProfileRequest struct {
+	Symbol       string  `json:"symbol"`
+	Session      string  `json:"session,omitempty"`
+	From         int64   `json:"from,omitempty"` // Unix seconds, inclusive
+	To           int64   `json:"to,omitempty"`   // Unix seconds, exclusive (0 = now)
+	Buckets      int     `json:"buckets,omitempty"`
+	BucketSize   float64 `json:"bucket_size,omitempty"` // Takes precedence over buckets (aligned on multiples of it)
+	ValueAreaPct float64 `json:"value_area_pct,omitempty"`
+}
+
+// MVolumeProfileBucket is the volume traded in [PriceLow, PriceHigh)
+type MVolumeProfileBucket struct {
+	PriceLow  float64 `json:"price_low"`
+	PriceHigh float64 `json:"price_high"`
+	Volume    float64 `json:"volume"`
+}
+
+// MVolumeProfile is the volume-by-price histogram of a symbol over a range
+type MVolumeProfile struct {
+	Symbol     string                 `json:"symbol"`
+	Session    string                 `json:"session,omitempty"` // Set for session profiles
+	From       int64                  `json:"from"`
+	To         int64                  `json:"to"`
+	Source     string                 `json:"source"`      // "memory" or "storage"
+	Bars       int                    `json:"bars"`        // Base bars in the range
+	RangedBars int                    `json:"ranged_bars"` // Bars with their own high/low (others count at their close)
+	BucketSize float64                `json:"bucket_size"`
+	Buckets    []MVolumeProfileBucket `json:"buckets"` // Lowest price first
+
+	TotalVolume     float64 `json:"total_volume"`
+	PointOfControl  float64 `json:"point_of_control"` // Middle of the fullest bucket
+	POCVolume       float64 `json:"poc_volume"`
+	ValueAreaLow    float64 `json:"value_area_low"`
+	ValueAreaHigh   float64 `json:"value_area_high"`
+	ValueAreaVolume float64 `json:"value_area_volume"`
+	ValueAreaPct    float64 `json:"value_area_pct"` // Requested share of the volume
+}
@@ -0,0 +1,338 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"market-observer/src/interfaces"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// -----------------------------------------------------------------------------
+// Dispatcher delivers notifications to the configured channels. Each channel
+// has its own queue and worker, rate limit and retry policy; duplicates are
+// filtered before fan-out and every outcome is written to the delivery log.
+// -----------------------------------------------------------------------------
+
+type Dispatcher struct {
+	Config *models.MConfig
+	DB     interfaces.IDatabase
+	Logger *logger.Logger
+
+	channels      []*channel
+	dedupWindow   int64
+	seen          map[string]int64 // dedup key -> last accepted (unix seconds)
+	lastPurge     int64
+	deliveries    []models.MNotificationDelivery // Most recent last
+	maxDeliveries int
+	mu            sync.Mutex
+}
+
+type channel struct {
+	cfg            models.MNotificationChannelConfig
+	sender         sender
+	limiter        *rateLimiter
+	queue          chan models.MNotification
+	kinds          map[string]bool // nil = all kinds
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// -----------------------------------------------------------------------------
+
+func NewDispatcher(cfg *models.MConfig, db interfaces.IDatabase, log *logger.Logger) *Dispatcher {
+	nc := cfg.Notifications
+
+	d := &Dispatcher{
+		Config:        cfg,
+		DB:            db,
+		Logger:        log,
+		dedupWindow:   int64(orDefault(nc.DedupWindowSeconds, utils.DefaultNotifyDedupWindow)),
+		seen:          make(map[string]int64),
+		maxDeliveries: orDefault(nc.MaxDeliveries, utils.DefaultNotifyMaxDeliveries),
+	}
+
+	queueSize := orDefault(nc.QueueSize, utils.DefaultNotifyQueueSize)
+	for _, cc := range nc.Channels {
+		if !cc.Enabled {
+			continue
+		}
+
+		timeout := time.Duration(orDefault(cc.TimeoutSeconds, utils.DefaultNotifyTimeout)) * time.Second
+		s, err := newSender(cc, timeout)
+		if err != nil {
+			log.Error("Notification channel skipped: %v", err)
+			continue
+		}
+
+		ch := &channel{
+			cfg:            cc,
+			sender:         s,
+			limiter:        newRateLimiter(orDefault(cc.RateLimitPerMinute, utils.DefaultNotifyRatePerMinute)),
+			queue:          make(chan models.MNotification, queueSize),
+			maxRetries:     orDefault(cc.MaxRetries, utils.DefaultNotifyMaxRetries),
+			initialBackoff: time.Duration(orDefault(cc.InitialBackoffMs, utils.DefaultNotifyInitialBackoff)) * time.Millisecond,
+			maxBackoff:     time.Duration(orDefault(cc.MaxBackoffMs, utils.DefaultNotifyMaxBackoff)) * time.Millisecond,
+		}
+		if len(cc.Kinds) > 0 {
+			ch.kinds = make(map[string]bool)
+			for _, k := range cc.Kinds {
+				ch.kinds[k] = true
+			}
+		}
+		d.channels = append(d.channels, ch)
+		log.Info("Notification channel %s (%s) enabled", cc.Name, cc.Type)
+	}
+
+	return d
+}
+
+// -----------------------------------------------------------------------------
+
+// Start runs one delivery worker per channel until ctx is cancelled.
+func (d *Dispatcher) Start(ctx context.Context, wg *sync.WaitGroup) {
+	for _, ch := range d.channels {
+		wg.Add(1)
+		go func(ch *channel) {
+			defer wg.Done()
+			d.run(ctx, ch)
+		}(ch)
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Notify queues a notification on every matching channel, unless the same
+// dedup key was accepted within the dedup window.
+func (d *Dispatcher) Notify(n models.MNotification) {
+	if len(d.channels) == 0 {
+		return
+	}
+	if n.Timestamp == 0 {
+		n.Timestamp = time.Now().UTC().Unix()
+	}
+
+	if n.DedupKey != "" && d.isDuplicate(n.DedupKey) {
+		d.record(models.MNotificationDelivery{
+			Kind:      n.Kind,
+			DedupKey:  n.DedupKey,
+			Title:     n.Title,
+			Status:    "deduplicated",
+			Timestamp: time.Now().UTC().Unix(),
+		})
+		return
+	}
+
+	for _, ch := range d.channels {
+		if ch.kinds != nil && !ch.kinds[n.Kind] {
+			continue
+		}
+		select {
+		case ch.queue <- n:
+		default:
+			d.Logger.Warning("Notification queue full for channel %s, dropping %s", ch.cfg.Name, n.Title)
+			d.record(models.MNotificationDelivery{
+				Channel:   ch.cfg.Name,
+				Kind:      n.Kind,
+				DedupKey:  n.DedupKey,
+				Title:     n.Title,
+				Status:    "dropped",
+				Error:     "queue full",
+				Timestamp: time.Now().UTC().Unix(),
+			})
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// NotifyAlerts forwards alert rule events.
+func (d *Dispatcher) NotifyAlerts(events []models.MAlertEvent) {
+	for _, e := range events {
+		d.Notify(models.MNotification{
+			Kind:      "alert",
+			Title:     fmt.Sprintf("[%s] %s %s", e.RuleName, e.Symbol, e.WindowName),
+			Message:   e.Message,
+			Symbol:    e.Symbol,
+			Window:    e.WindowName,
+			Timestamp: e.Timestamp,
+			DedupKey:  fmt.Sprintf("alert|%d|%s|%s", e.RuleID, e.Symbol, e.WindowName),
+			Payload:   e,
+		})
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// NotifyCorrelationAlerts forwards correlation breakdown alerts.
+func (d *Dispatcher) NotifyCorrelationAlerts(alerts []models.MCorrelationAlert) {
+	for _, a := range alerts {
+		d.Notify(models.MNotification{
+			Kind:      "correlation_breakdown",
+			Title:     fmt.Sprintf("Correlation breakdown %s/%s %s", a.SymbolA, a.SymbolB, a.WindowName),
+			Message:   fmt.Sprintf("%s/%s on %s: correlation %.2f vs baseline %.2f", a.SymbolA, a.SymbolB, a.WindowName, a.Correlation, a.Baseline),
+			Window:    a.WindowName,
+			Timestamp: a.Timestamp,
+			DedupKey:  fmt.Sprintf("correlation|%s|%s|%s", a.WindowName, a.SymbolA, a.SymbolB),
+			Payload:   a,
+		})
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// SendTest queues a test notification on every channel and returns the channel count.
+func (d *Dispatcher) SendTest() int {
+	now := time.Now().UTC()
+	d.Notify(models.MNotification{
+		Kind:      "test",
+		Title:     "MarketObserver test notification",
+		Message:   fmt.Sprintf("Test notification sent at %s", now.Format(time.RFC3339)),
+		Timestamp: now.Unix(),
+	})
+	return len(d.channels)
+}
+
+// -----------------------------------------------------------------------------
+
+// Deliveries returns the most recent delivery log entries (newest last, all when limit <= 0).
+func (d *Dispatcher) Deliveries(limit int) []models.MNotificationDelivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	list := d.deliveries
+	if limit > 0 && limit < len(list) {
+		list = list[len(list)-limit:]
+	}
+	result := make([]models.MNotificationDelivery, len(list))
+	copy(result, list)
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// run is the delivery loop of one channel.
+func (d *Dispatcher) run(ctx context.Context, ch *channel) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-ch.queue:
+			if err := ch.limiter.Wait(ctx); err != nil {
+				return
+			}
+			d.deliver(ctx, ch, n)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// deliver sends one notification with retries and exponential backoff.
+func (d *Dispatcher) deliver(ctx context.Context, ch *channel, n models.MNotification) {
+	entry := models.MNotificationDelivery{
+		Channel:  ch.cfg.Name,
+		Kind:     n.Kind,
+		DedupKey: n.DedupKey,
+		Title:    n.Title,
+	}
+
+	backoff := ch.initialBackoff
+	var err error
+	for attempt := 1; attempt <= ch.maxRetries+1; attempt++ {
+		entry.Attempts = attempt
+		if err = ch.sender.Send(ctx, n); err == nil || isPermanent(err) {
+			break
+		}
+		if attempt > ch.maxRetries {
+			break
+		}
+
+		// Jittered exponential backoff
+		wait := jitter(backoff)
+		d.Logger.Warning("Notification to %s failed (attempt %d): %v, retrying in %s", ch.cfg.Name, attempt, err, wait)
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			attempt = ch.maxRetries + 1
+		case <-time.After(wait):
+		}
+		if backoff *= 2; backoff > ch.maxBackoff {
+			backoff = ch.maxBackoff
+		}
+	}
+
+	entry.Timestamp = time.Now().UTC().Unix()
+	if err != nil {
+		entry.Status = "failed"
+		entry.Error = err.Error()
+		d.Logger.Error("Notification to %s failed after %d attempt(s): %v", ch.cfg.Name, entry.Attempts, err)
+	} else {
+		entry.Status = "delivered"
+	}
+	d.record(entry)
+}
+
+// -----------------------------------------------------------------------------
+
+// isDuplicate reports whether key was accepted within the dedup window, and accepts it otherwise.
+func (d *Dispatcher) isDuplicate(key string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now().UTC().Unix()
+	if now-d.lastPurge > d.dedupWindow {
+		for k, ts := range d.seen {
+			if now-ts >= d.dedupWindow {
+				delete(d.seen, k)
+			}
+		}
+		d.lastPurge = now
+	}
+
+	if ts, ok := d.seen[key]; ok && now-ts < d.dedupWindow {
+		return true
+	}
+	d.seen[key] = now
+	return false
+}
+
+// -----------------------------------------------------------------------------
+
+// record appends an entry to the delivery log (memory + storage).
+func (d *Dispatcher) record(entry models.MNotificationDelivery) {
+	d.mu.Lock()
+	d.deliveries = append(d.deliveries, entry)
+	if overflow := len(d.deliveries) - d.maxDeliveries; overflow > 0 {
+		d.deliveries = append([]models.MNotificationDelivery(nil), d.deliveries[overflow:]...)
+	}
+	d.mu.Unlock()
+
+	if err := d.DB.SaveNotificationDelivery(entry); err != nil {
+		d.Logger.Error("Failed to store notification delivery: %v", err)
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// jitter returns a random delay in [backoff/2, backoff], so a retry never waits
+// longer than the (capped) backoff.
+func jitter(backoff time.Duration) time.Duration {
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
+
+// -----------------------------------------------------------------------------
+
+func orDefault(value, def int) int {
+	if value <= 0 {
+		return def
+	}
+	return value
+}
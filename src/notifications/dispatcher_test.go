@@ -0,0 +1,317 @@
+package notifications
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/storage"
+)
+
+// newTestDispatcher builds a dispatcher with one channel and a temporary delivery log
+func newTestDispatcher(t *testing.T, cc models.MNotificationChannelConfig) (*Dispatcher, *channel) {
+	t.Helper()
+	cfg := &models.MConfig{}
+	cfg.Storage.DBPath = filepath.Join(t.TempDir(), "notifications.db")
+	cc.Enabled = true
+	cfg.Notifications.Channels = []models.MNotificationChannelConfig{cc}
+	log := logger.NewLogger(cfg, "test")
+
+	db, err := storage.NewAsyncSQLiteDB(cfg, log)
+	if err != nil {
+		t.Fatalf("NewAsyncSQLiteDB: %v", err)
+	}
+	if err := db.Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	d := NewDispatcher(cfg, db, log)
+	if len(d.channels) != 1 {
+		t.Fatalf("channel %s was not enabled", cc.Name)
+	}
+	return d, d.channels[0]
+}
+
+// statusServer answers with the given statuses in turn (the last one repeats)
+type statusServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newStatusServer(t *testing.T, statuses ...int) *statusServer {
+	s := &statusServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		status := s.statuses[min(len(s.requests), len(s.statuses)-1)]
+		s.requests = append(s.requests, r)
+		s.bodies = append(s.bodies, body)
+		s.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *statusServer) hits() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+func webhookChannel(url string) models.MNotificationChannelConfig {
+	return models.MNotificationChannelConfig{
+		Name:             "hook",
+		Type:             "webhook",
+		URL:              url,
+		MaxRetries:       3,
+		InitialBackoffMs: 1,
+		MaxBackoffMs:     4,
+		TimeoutSeconds:   5,
+	}
+}
+
+func testNotification() models.MNotification {
+	return models.MNotification{Kind: "alert", Title: "spike TEST 5m", Message: "volume spike", DedupKey: "alert|1|TEST|5m"}
+}
+
+// -----------------------------------------------------------------------------
+
+func TestWebhookSignature(t *testing.T) {
+	srv := newStatusServer(t, http.StatusOK)
+	cc := webhookChannel(srv.URL)
+	cc.Secret = "s3cret"
+	cc.Headers = map[string]string{"X-Custom": "yes"}
+	d, ch := newTestDispatcher(t, cc)
+
+	d.deliver(context.Background(), ch, testNotification())
+
+	if srv.hits() != 1 {
+		t.Fatalf("webhook received %d request(s), want 1", srv.hits())
+	}
+	req, body := srv.requests[0], srv.bodies[0]
+
+	ts := req.Header.Get(TimestampHeader)
+	if ts == "" {
+		t.Fatalf("missing %s header", TimestampHeader)
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(ts + "." + string(body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := req.Header.Get(SignatureHeader); got != want {
+		t.Errorf("%s = %q, want %q", SignatureHeader, got, want)
+	}
+	if req.Header.Get("X-Custom") != "yes" || req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected headers: %v", req.Header)
+	}
+
+	var n models.MNotification
+	if err := json.Unmarshal(body, &n); err != nil || n.Title != "spike TEST 5m" {
+		t.Errorf("body = %s (%v)", body, err)
+	}
+}
+
+func TestWebhookUnsignedWithoutSecret(t *testing.T) {
+	srv := newStatusServer(t, http.StatusOK)
+	d, ch := newTestDispatcher(t, webhookChannel(srv.URL))
+
+	d.deliver(context.Background(), ch, testNotification())
+	if srv.requests[0].Header.Get(SignatureHeader) != "" {
+		t.Errorf("request signed without a secret")
+	}
+}
+
+func TestSlackPayload(t *testing.T) {
+	srv := newStatusServer(t, http.StatusOK)
+	cc := webhookChannel(srv.URL)
+	cc.Type = "slack"
+	d, ch := newTestDispatcher(t, cc)
+
+	d.deliver(context.Background(), ch, testNotification())
+
+	var payload map[string]string
+	if err := json.Unmarshal(srv.bodies[0], &payload); err != nil {
+		t.Fatalf("slack body: %v", err)
+	}
+	if payload["text"] != "*spike TEST 5m*\nvolume spike" {
+		t.Errorf("slack text = %q", payload["text"])
+	}
+}
+
+func TestDeliverRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		status   string
+		attempts int
+	}{
+		{"success", []int{200}, "delivered", 1},
+		{"5xx then success", []int{500, 503, 200}, "delivered", 3},
+		{"429 is retried", []int{429, 200}, "delivered", 2},
+		{"4xx is permanent", []int{400}, "failed", 1},
+		{"404 after a 5xx", []int{502, 404}, "failed", 2},
+		{"5xx until retries run out", []int{500}, "failed", 4}, // max_retries 3 = 4 attempts
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newStatusServer(t, tt.statuses...)
+			d, ch := newTestDispatcher(t, webhookChannel(srv.URL))
+
+			d.deliver(context.Background(), ch, testNotification())
+
+			log := d.Deliveries(0)
+			if len(log) != 1 {
+				t.Fatalf("delivery log has %d entries, want 1", len(log))
+			}
+			if log[0].Status != tt.status || log[0].Attempts != tt.attempts || srv.hits() != tt.attempts {
+				t.Errorf("status %s after %d attempt(s) (%d requests), want %s after %d",
+					log[0].Status, log[0].Attempts, srv.hits(), tt.status, tt.attempts)
+			}
+			if tt.status == "failed" && log[0].Error == "" {
+				t.Errorf("failed delivery without an error")
+			}
+		})
+	}
+}
+
+func TestDeliverStopsOnCancel(t *testing.T) {
+	srv := newStatusServer(t, http.StatusInternalServerError)
+	cc := webhookChannel(srv.URL)
+	cc.InitialBackoffMs = 60000
+	cc.MaxBackoffMs = 60000
+	d, ch := newTestDispatcher(t, cc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	d.deliver(ctx, ch, testNotification())
+
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("deliver kept waiting after cancellation")
+	}
+	if log := d.Deliveries(0); log[0].Status != "failed" {
+		t.Errorf("status = %s, want failed", log[0].Status)
+	}
+}
+
+func TestJitterStaysWithinBackoff(t *testing.T) {
+	for _, backoff := range []time.Duration{0, 1, 2, time.Millisecond, 500 * time.Millisecond, 30 * time.Second} {
+		for i := 0; i < 1000; i++ {
+			wait := jitter(backoff)
+			if wait < backoff/2 || wait > backoff {
+				t.Fatalf("jitter(%s) = %s, want within [%s, %s]", backoff, wait, backoff/2, backoff)
+			}
+		}
+	}
+}
+
+func TestNotifyDeduplicates(t *testing.T) {
+	srv := newStatusServer(t, http.StatusOK)
+	d, ch := newTestDispatcher(t, webhookChannel(srv.URL))
+
+	n := testNotification()
+	d.Notify(n)
+	d.Notify(n)
+	if len(ch.queue) != 1 {
+		t.Fatalf("queued %d notification(s), want 1", len(ch.queue))
+	}
+	if log := d.Deliveries(0); len(log) != 1 || log[0].Status != "deduplicated" {
+		t.Fatalf("delivery log = %+v, want one deduplicated entry", log)
+	}
+
+	// Another key is not affected
+	other := n
+	other.DedupKey = "alert|1|OTHER|5m"
+	d.Notify(other)
+	if len(ch.queue) != 2 {
+		t.Fatalf("queued %d notification(s), want 2", len(ch.queue))
+	}
+
+	// Once the window has passed the key is accepted again
+	d.mu.Lock()
+	d.seen[n.DedupKey] -= d.dedupWindow
+	d.mu.Unlock()
+	d.Notify(n)
+	if len(ch.queue) != 3 {
+		t.Fatalf("queued %d notification(s) after the dedup window, want 3", len(ch.queue))
+	}
+}
+
+func TestNotifyKindsFilter(t *testing.T) {
+	srv := newStatusServer(t, http.StatusOK)
+	cc := webhookChannel(srv.URL)
+	cc.Kinds = []string{"correlation_breakdown"}
+	d, ch := newTestDispatcher(t, cc)
+
+	d.Notify(testNotification())
+	if len(ch.queue) != 0 {
+		t.Fatalf("alert queued on a correlation-only channel")
+	}
+}
+
+func TestRateLimiterThrottles(t *testing.T) {
+	l := newRateLimiter(2) // Burst of 2, then one token every 30s
+
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("burst token %d: %v", i, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err == nil {
+		t.Fatalf("third send within the burst window was not throttled")
+	}
+
+	// Refilled after the token interval
+	l.mu.Lock()
+	l.last = l.last.Add(-30 * time.Second)
+	l.mu.Unlock()
+	ctx2, cancel2 := context.WithTimeout(context.Background(), time.Second)
+	defer cancel2()
+	if err := l.Wait(ctx2); err != nil {
+		t.Fatalf("token not refilled: %v", err)
+	}
+}
+
+func TestDispatcherWorkerDelivers(t *testing.T) {
+	srv := newStatusServer(t, http.StatusOK)
+	d, _ := newTestDispatcher(t, webhookChannel(srv.URL))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	d.Start(ctx, &wg)
+	d.SendTest()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for srv.hits() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	wg.Wait()
+
+	if srv.hits() != 1 {
+		t.Fatalf("worker sent %d request(s), want 1", srv.hits())
+	}
+	if !strings.Contains(string(srv.bodies[0]), `"kind":"test"`) {
+		t.Errorf("unexpected body %s", srv.bodies[0])
+	}
+}
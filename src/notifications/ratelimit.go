@@ -0,0 +1,60 @@
+package notifications
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// rateLimiter is a token bucket allowing a burst of perMinute sends, refilled
+// continuously at perMinute tokens per minute.
+// -----------------------------------------------------------------------------
+
+type rateLimiter struct {
+	capacity float64
+	tokens   float64
+	rate     float64 // Tokens per second
+	last     time.Time
+	mu       sync.Mutex
+}
+
+// -----------------------------------------------------------------------------
+
+func newRateLimiter(perMinute int) *rateLimiter {
+	return &rateLimiter{
+		capacity: float64(perMinute),
+		tokens:   float64(perMinute),
+		rate:     float64(perMinute) / 60,
+		last:     time.Now(),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Wait blocks until a token is available or ctx is cancelled.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.capacity {
+			l.tokens = l.capacity
+		}
+		l.last = now
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"market-observer/src/models"
+)
+
+// -----------------------------------------------------------------------------
+// Channel senders: generic JSON webhook (HMAC signed), Slack/Teams incoming
+// webhooks and SMTP email.
+// -----------------------------------------------------------------------------
+
+// Signature headers of the generic webhook: HMAC-SHA256(secret, timestamp + "." + body)
+const (
+	SignatureHeader = "X-MarketObserver-Signature"
+	TimestampHeader = "X-MarketObserver-Timestamp"
+)
+
+type sender interface {
+	Send(ctx context.Context, n models.MNotification) error
+}
+
+// permanentError marks a failure that retrying will not fix (e.g. HTTP 4xx)
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// -----------------------------------------------------------------------------
+
+func isPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// -----------------------------------------------------------------------------
+
+func newSender(cfg models.MNotificationChannelConfig, timeout time.Duration) (sender, error) {
+	switch cfg.Type {
+	case "webhook", "slack", "teams":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("channel %s: url is required", cfg.Name)
+		}
+		return &webhookSender{
+			format:  cfg.Type,
+			url:     cfg.URL,
+			secret:  cfg.Secret,
+			headers: cfg.Headers,
+			client:  &http.Client{Timeout: timeout},
+		}, nil
+
+	case "smtp":
+		if cfg.SMTPHost == "" || cfg.From == "" || len(cfg.To) == 0 {
+			return nil, fmt.Errorf("channel %s: smtp_host, from and to are required", cfg.Name)
+		}
+		port := cfg.SMTPPort
+		if port == 0 {
+			port = 25
+		}
+		return &smtpSender{
+			addr:     net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(port)),
+			host:     cfg.SMTPHost,
+			username: cfg.Username,
+			password: cfg.Password,
+			from:     cfg.From,
+			to:       cfg.To,
+			timeout:  timeout,
+		}, nil
+	}
+	return nil, fmt.Errorf("channel %s: unsupported type %q", cfg.Name, cfg.Type)
+}
+
+// -----------------------------------------------------------------------------
+// Webhook (generic JSON, Slack, Teams)
+// -----------------------------------------------------------------------------
+
+type webhookSender struct {
+	format  string // "webhook", "slack" or "teams"
+	url     string
+	secret  string
+	headers map[string]string
+	client  *http.Client
+}
+
+// -----------------------------------------------------------------------------
+
+func (w *webhookSender) Send(ctx context.Context, n models.MNotification) error {
+	body, err := json.Marshal(w.buildPayload(n))
+	if err != nil {
+		return &permanentError{err}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+	if w.secret != "" {
+		ts := strconv.FormatInt(time.Now().UTC().Unix(), 10)
+		req.Header.Set(TimestampHeader, ts)
+		req.Header.Set(SignatureHeader, "sha256="+Sign(w.secret, ts, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	default:
+		return &permanentError{fmt.Errorf("webhook returned %s", resp.Status)}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func (w *webhookSender) buildPayload(n models.MNotification) interface{} {
+	switch w.format {
+	case "slack":
+		return map[string]interface{}{
+			"text": fmt.Sprintf("*%s*\n%s", n.Title, n.Message),
+		}
+	case "teams":
+		return map[string]interface{}{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    n.Title,
+			"themeColor": "D9534F",
+			"title":      n.Title,
+			"text":       n.Message,
+		}
+	}
+	return n
+}
+
+// -----------------------------------------------------------------------------
+
+// Sign returns the hex HMAC-SHA256 of timestamp + "." + body (webhook receivers
+// recompute it to authenticate the request).
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// -----------------------------------------------------------------------------
+// SMTP
+// -----------------------------------------------------------------------------
+
+type smtpSender struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+	to       []string
+	timeout  time.Duration
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *smtpSender) Send(ctx context.Context, n models.MNotification) error {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(s.timeout))
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	if s.username != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+				return smtpError(err)
+			}
+		}
+	}
+
+	if err := client.Mail(s.from); err != nil {
+		return smtpError(err)
+	}
+	for _, rcpt := range s.to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return smtpError(err)
+		}
+	}
+
+	wc, err := client.Data()
+	if err != nil {
+		return smtpError(err)
+	}
+	if _, err := wc.Write(s.buildMessage(n)); err != nil {
+		wc.Close()
+		return err
+	}
+	if err := wc.Close(); err != nil {
+		return smtpError(err)
+	}
+	return client.Quit()
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *smtpSender) buildMessage(n models.MNotification) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + s.from + "\r\n")
+	b.WriteString("To: " + strings.Join(s.to, ", ") + "\r\n")
+	b.WriteString("Subject: " + strings.NewReplacer("\r", " ", "\n", " ").Replace(n.Title) + "\r\n")
+	b.WriteString("Date: " + time.Now().UTC().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(n.Message, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// -----------------------------------------------------------------------------
+
+// smtpError marks 5xx SMTP replies as permanent
+func smtpError(err error) error {
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) && tpErr.Code >= 500 {
+		return &permanentError{err}
+	}
+	return err
+}
@@ -0,0 +1,208 @@
+package notifications
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"market-observer/src/models"
+)
+
+// smtpStandIn is a minimal in-process SMTP server (no TLS, no AUTH). A reply
+// set in rejectRcpt is returned to RCPT TO instead of 250.
+type smtpStandIn struct {
+	listener   net.Listener
+	rejectRcpt string
+
+	mu       sync.Mutex
+	from     string
+	rcpts    []string
+	messages []string
+}
+
+func newSMTPStandIn(t *testing.T, rejectRcpt string) *smtpStandIn {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &smtpStandIn{listener: l, rejectRcpt: rejectRcpt}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP stand-in")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		upper := strings.ToUpper(cmd)
+
+		switch {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			s.mu.Lock()
+			s.from = strings.Trim(cmd[len("MAIL FROM:"):], "<> ")
+			s.mu.Unlock()
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			if s.rejectRcpt != "" {
+				reply(s.rejectRcpt)
+				continue
+			}
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, strings.Trim(cmd[len("RCPT TO:"):], "<> "))
+			s.mu.Unlock()
+			reply("250 OK")
+		case upper == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var b strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				b.WriteString(l)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, b.String())
+			s.mu.Unlock()
+			reply("250 OK queued")
+		case upper == "RSET", upper == "NOOP":
+			reply("250 OK")
+		case upper == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func (s *smtpStandIn) sender(t *testing.T) sender {
+	t.Helper()
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	snd, err := newSender(models.MNotificationChannelConfig{
+		Name:     "mail",
+		Type:     "smtp",
+		SMTPHost: host,
+		SMTPPort: p,
+		From:     "observer@example.com",
+		To:       []string{"desk@example.com", "risk@example.com"},
+	}, 5*time.Second)
+	if err != nil {
+		t.Fatalf("newSender: %v", err)
+	}
+	return snd
+}
+
+// -----------------------------------------------------------------------------
+
+func TestSMTPSenderDelivers(t *testing.T) {
+	srv := newSMTPStandIn(t, "")
+	n := models.MNotification{Title: "spike\r\nBcc: evil@example.com", Message: "line 1\nline 2"}
+
+	if err := srv.sender(t).Send(context.Background(), n); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.from != "observer@example.com" {
+		t.Errorf("MAIL FROM = %q", srv.from)
+	}
+	if strings.Join(srv.rcpts, ",") != "desk@example.com,risk@example.com" {
+		t.Errorf("RCPT TO = %v", srv.rcpts)
+	}
+	if len(srv.messages) != 1 {
+		t.Fatalf("received %d message(s), want 1", len(srv.messages))
+	}
+	msg := srv.messages[0]
+	for _, want := range []string{
+		"From: observer@example.com\r\n",
+		"To: desk@example.com, risk@example.com\r\n",
+		"Subject: spike  Bcc: evil@example.com\r\n", // Header injection is flattened
+		"\r\n\r\nline 1\r\nline 2\r\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message lacks %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestSMTPSenderErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		reply     string
+		permanent bool
+	}{
+		{"5xx rejection is permanent", "550 No such user", true},
+		{"4xx rejection is retried", "451 Try again later", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newSMTPStandIn(t, tt.reply)
+			err := srv.sender(t).Send(context.Background(), models.MNotification{Title: "x", Message: "y"})
+			if err == nil {
+				t.Fatalf("Send succeeded on %q", tt.reply)
+			}
+			if isPermanent(err) != tt.permanent {
+				t.Errorf("isPermanent(%v) = %v, want %v", err, isPermanent(err), tt.permanent)
+			}
+		})
+	}
+}
+
+func TestSMTPSenderUnreachable(t *testing.T) {
+	srv := newSMTPStandIn(t, "")
+	snd := srv.sender(t)
+	srv.listener.Close()
+
+	err := snd.Send(context.Background(), models.MNotification{Title: "x"})
+	if err == nil || isPermanent(err) {
+		t.Fatalf("unreachable server: err = %v, want a retryable error", err)
+	}
+}
+
+func TestNewSenderValidation(t *testing.T) {
+	invalid := []models.MNotificationChannelConfig{
+		{Name: "hook", Type: "webhook"},
+		{Name: "mail", Type: "smtp", From: "a@example.com", To: []string{"b@example.com"}},
+		{Name: "mail", Type: "smtp", SMTPHost: "localhost", To: []string{"b@example.com"}},
+		{Name: "mail", Type: "smtp", SMTPHost: "localhost", From: "a@example.com"},
+		{Name: "pager", Type: "pager", URL: "http://localhost"},
+	}
+	for _, cc := range invalid {
+		if _, err := newSender(cc, time.Second); err == nil {
+			t.Errorf("newSender(%+v) accepted an invalid channel", cc)
+		}
+	}
+}
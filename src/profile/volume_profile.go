@@ -0,0 +1,261 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/interfaces"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+var (
+	// ErrInvalidProfile wraps volume profile request errors
+	ErrInvalidProfile = errors.New("invalid volume profile request")
+	// ErrNoProfileData is returned when the range holds no bars of the symbol
+	ErrNoProfileData = errors.New("no data for volume profile")
+)
+
+// -----------------------------------------------------------------------------
+// Profiler builds volume profiles (volume by price bucket, point of control,
+// value area) of a symbol over a trading session or a time range, on demand,
+// from the base bars. The MemoryManager history is used when it reaches back to
+// the start of the range, the stored raw prices otherwise.
+// -----------------------------------------------------------------------------
+
+type Profiler struct {
+	Config *models.MConfig
+	Memory *utils.MemoryManager
+	DB     interfaces.IDatabase // May be nil (memory only)
+	Logger *logger.Logger
+
+	Buckets      int
+	ValueAreaPct float64
+	MaxBuckets   int
+}
+
+// -----------------------------------------------------------------------------
+
+func NewProfiler(cfg *models.MConfig, memManager *utils.MemoryManager, db interfaces.IDatabase, log *logger.Logger) *Profiler {
+	vp := cfg.VolumeProfile
+
+	p := &Profiler{
+		Config:       cfg,
+		Memory:       memManager,
+		DB:           db,
+		Logger:       log,
+		Buckets:      vp.Buckets,
+		ValueAreaPct: vp.ValueAreaPct,
+		MaxBuckets:   vp.MaxBuckets,
+	}
+	if p.Buckets <= 0 {
+		p.Buckets = utils.DefaultProfileBuckets
+	}
+	if p.ValueAreaPct <= 0 {
+		p.ValueAreaPct = utils.DefaultProfileValueAreaPct
+	}
+	if p.MaxBuckets <= 0 {
+		p.MaxBuckets = utils.DefaultProfileMaxBuckets
+	}
+	return p
+}
+
+// -----------------------------------------------------------------------------
+
+// Profile builds the volume profile of a request. Bars spread their volume
+// over their high-low range when the source provides it, and count at their
+// close otherwise.
+func (p *Profiler) Profile(request models.MVolumeProfileRequest) (models.MVolumeProfile, error) {
+	if request.Symbol == "" {
+		return models.MVolumeProfile{}, fmt.Errorf("%w: symbol is required", ErrInvalidProfile)
+	}
+	if request.Buckets < 0 || request.BucketSize < 0 {
+		return models.MVolumeProfile{}, fmt.Errorf("%w: buckets and bucket_size cannot be negative", ErrInvalidProfile)
+	}
+	if request.Buckets > p.MaxBuckets {
+		return models.MVolumeProfile{}, fmt.Errorf("%w: at most %d buckets", ErrInvalidProfile, p.MaxBuckets)
+	}
+	if request.ValueAreaPct < 0 || request.ValueAreaPct > 1 {
+		return models.MVolumeProfile{}, fmt.Errorf("%w: value_area_pct must be between 0 and 1", ErrInvalidProfile)
+	}
+
+	history := p.Memory.GetHistory(request.Symbol)
+	profile, err := p.resolveRange(request, history)
+	if err != nil {
+		return models.MVolumeProfile{}, err
+	}
+
+	points, err := p.load(&profile, history)
+	if err != nil {
+		return models.MVolumeProfile{}, err
+	}
+	if len(points) == 0 {
+		return models.MVolumeProfile{}, fmt.Errorf("%w: %s between %d and %d", ErrNoProfileData, request.Symbol, profile.From, profile.To)
+	}
+
+	profile.ValueAreaPct = request.ValueAreaPct
+	if profile.ValueAreaPct == 0 {
+		profile.ValueAreaPct = p.ValueAreaPct
+	}
+	if err := p.build(&profile, points, request); err != nil {
+		return models.MVolumeProfile{}, err
+	}
+	return profile, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// resolveRange turns the session or time range of a request into [From, To).
+func (p *Profiler) resolveRange(request models.MVolumeProfileRequest, history []models.MStockPrice) (models.MVolumeProfile, error) {
+	profile := models.MVolumeProfile{Symbol: request.Symbol}
+	calendar := utils.GetCalendar(request.Symbol)
+	location := time.UTC
+	if calendar.Timezone != nil {
+		location = calendar.Timezone
+	}
+
+	switch {
+	case request.Session != "":
+		if request.From != 0 || request.To != 0 {
+			return profile, fmt.Errorf("%w: session and from/to are exclusive", ErrInvalidProfile)
+		}
+		day, err := time.ParseInLocation("2006-01-02", request.Session, location)
+		if err != nil {
+			return profile, fmt.Errorf("%w: session must be a YYYY-MM-DD date", ErrInvalidProfile)
+		}
+		if !calendar.IsTradingDay(day) {
+			return profile, fmt.Errorf("%w: %s is not a trading day", ErrInvalidProfile, request.Session)
+		}
+		profile.Session = request.Session
+		profile.From = calendar.SessionOpen(day).Unix()
+		profile.To = calendar.SessionClose(day).Unix()
+
+	case request.From == 0 && request.To == 0:
+		// Latest session with data
+		if len(history) == 0 {
+			return profile, fmt.Errorf("%w: %s has no recent bars", ErrNoProfileData, request.Symbol)
+		}
+		open, close, _ := calendar.SessionBounds(time.Unix(history[len(history)-1].Timestamp, 0))
+		profile.Session = open.In(location).Format("2006-01-02")
+		profile.From = open.Unix()
+		profile.To = close.Unix()
+
+	default:
+		profile.From, profile.To = request.From, request.To
+		if profile.To == 0 {
+			profile.To = time.Now().UTC().Unix()
+		}
+		if profile.From >= profile.To {
+			return profile, fmt.Errorf("%w: from must be before to", ErrInvalidProfile)
+		}
+	}
+	return profile, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// load returns the bars of the range: from memory when its history covers the
+// start of the range, from storage otherwise (memory again if storage has none).
+func (p *Profiler) load(profile *models.MVolumeProfile, history []models.MStockPrice) ([]models.MStockPrice, error) {
+	if p.DB != nil && (len(history) == 0 || history[0].Timestamp > profile.From) {
+		points, err := p.DB.LoadStockPrices(profile.Symbol, profile.From, profile.To)
+		if err != nil {
+			return nil, err
+		}
+		if len(points) > 0 {
+			profile.Source = utils.ProfileSourceStorage
+			return points, nil
+		}
+	}
+
+	profile.Source = utils.ProfileSourceMemory
+	var points []models.MStockPrice
+	for _, pt := range history {
+		if pt.Timestamp >= profile.From && pt.Timestamp < profile.To && !pt.Filled {
+			points = append(points, pt)
+		}
+	}
+	return points, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// build fills the buckets, the point of control and the value area.
+func (p *Profiler) build(profile *models.MVolumeProfile, points []models.MStockPrice, request models.MVolumeProfileRequest) error {
+	lows := make([]float64, 0, len(points))
+	highs := make([]float64, 0, len(points))
+	volumes := make([]float64, 0, len(points))
+	for _, pt := range points {
+		if pt.Price <= 0 {
+			continue
+		}
+		low, high := pt.Price, pt.Price
+		if pt.High > 0 && pt.Low > 0 {
+			low, high = pt.Low, pt.High
+			profile.RangedBars++
+		}
+		lows = append(lows, low)
+		highs = append(highs, high)
+		volumes = append(volumes, pt.Volume)
+	}
+	profile.Bars = len(lows)
+	if profile.Bars == 0 {
+		return fmt.Errorf("%w: %s has no priced bars in the range", ErrNoProfileData, profile.Symbol)
+	}
+
+	minLow, maxHigh := lows[0], highs[0]
+	for i := 1; i < len(lows); i++ {
+		minLow = math.Min(minLow, lows[i])
+		maxHigh = math.Max(maxHigh, highs[i])
+	}
+
+	// Buckets of a requested size are aligned on its multiples; otherwise the range is split evenly
+	origin, size, count := minLow, 0.0, 1
+	switch {
+	case request.BucketSize > 0:
+		size = request.BucketSize
+		origin = math.Floor(minLow/size) * size
+		if buckets := (maxHigh - origin) / size; buckets >= float64(p.MaxBuckets) {
+			return fmt.Errorf("%w: bucket_size %g gives more than %d buckets", ErrInvalidProfile, size, p.MaxBuckets)
+		}
+		count = int((maxHigh-origin)/size) + 1
+	case maxHigh > minLow:
+		count = request.Buckets
+		if count == 0 {
+			count = p.Buckets
+		}
+		size = (maxHigh - minLow) / float64(count)
+	}
+
+	// A flat range is a single bucket
+	histBucket := size
+	if histBucket <= 0 {
+		histBucket = 1
+	}
+	hist := core.CalculateVolumeByPrice(lows, highs, volumes, origin, histBucket, count)
+
+	profile.BucketSize = size
+	profile.Buckets = make([]models.MVolumeProfileBucket, count)
+	for i, v := range hist {
+		profile.Buckets[i] = models.MVolumeProfileBucket{
+			PriceLow:  origin + float64(i)*size,
+			PriceHigh: origin + float64(i+1)*size,
+			Volume:    v,
+		}
+		profile.TotalVolume += v
+	}
+
+	poc, low, high := core.CalculateValueArea(hist, profile.ValueAreaPct)
+	profile.PointOfControl = origin + (float64(poc)+0.5)*size
+	profile.POCVolume = hist[poc]
+	profile.ValueAreaLow = profile.Buckets[low].PriceLow
+	profile.ValueAreaHigh = profile.Buckets[high].PriceHigh
+	for i := low; i <= high; i++ {
+		profile.ValueAreaVolume += hist[i]
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package profile
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+// nyseTime returns the Unix time of a 2024 New York wall-clock time.
+func nyseTime(t *testing.T, month time.Month, day, hour, min int) int64 {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	return time.Date(2024, month, day, hour, min, 0, 0, loc).Unix()
+}
+
+// newTestProfiler keeps two AAPL sessions in memory: close-only bars on the
+// 11th, a ranged bar and a close-only bar on the 12th.
+func newTestProfiler(t *testing.T) *Profiler {
+	cfg := &models.MConfig{}
+	cfg.VolumeProfile.MaxBuckets = 10
+	mm := utils.NewMemoryManager(64, 100)
+	for _, p := range []models.MStockPrice{
+		{Price: 100, Volume: 10, Timestamp: nyseTime(t, 3, 11, 10, 0)},
+		{Price: 102, Volume: 20, Timestamp: nyseTime(t, 3, 11, 11, 0)},
+		{Price: 102, Low: 101, High: 103, Volume: 40, Timestamp: nyseTime(t, 3, 12, 10, 0)},
+		{Price: 104, Volume: 10, Timestamp: nyseTime(t, 3, 12, 11, 0)},
+	} {
+		p.Symbol = "AAPL"
+		mm.AddDataPoint("AAPL", p)
+	}
+	return NewProfiler(cfg, mm, nil, logger.NewLogger(cfg, "test"))
+}
+
+func TestProfileRange(t *testing.T) {
+	p := newTestProfiler(t)
+
+	tests := []struct {
+		name     string
+		request  models.MVolumeProfileRequest
+		session  string
+		from, to int64
+		bars     int
+		ranged   int
+	}{
+		{"latest session", models.MVolumeProfileRequest{}, "2024-03-12", nyseTime(t, 3, 12, 9, 30), nyseTime(t, 3, 12, 16, 0), 2, 1},
+		{"session", models.MVolumeProfileRequest{Session: "2024-03-11"}, "2024-03-11", nyseTime(t, 3, 11, 9, 30), nyseTime(t, 3, 11, 16, 0), 2, 0},
+		{"from/to", models.MVolumeProfileRequest{From: nyseTime(t, 3, 11, 10, 30), To: nyseTime(t, 3, 12, 10, 30)}, "",
+			nyseTime(t, 3, 11, 10, 30), nyseTime(t, 3, 12, 10, 30), 2, 1},
+		{"from/to ends at the bar", models.MVolumeProfileRequest{From: nyseTime(t, 3, 11, 10, 0), To: nyseTime(t, 3, 11, 11, 0)}, "",
+			nyseTime(t, 3, 11, 10, 0), nyseTime(t, 3, 11, 11, 0), 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.request.Symbol = "AAPL"
+			profile, err := p.Profile(tt.request)
+			if err != nil {
+				t.Fatalf("Profile: %v", err)
+			}
+			if profile.Session != tt.session || profile.From != tt.from || profile.To != tt.to {
+				t.Errorf("range = %s [%d, %d), want %s [%d, %d)", profile.Session, profile.From, profile.To, tt.session, tt.from, tt.to)
+			}
+			if profile.Bars != tt.bars || profile.RangedBars != tt.ranged || profile.Source != utils.ProfileSourceMemory {
+				t.Errorf("bars %d ranged %d source %s, want %d %d memory", profile.Bars, profile.RangedBars, profile.Source, tt.bars, tt.ranged)
+			}
+		})
+	}
+
+	// To defaults to now
+	profile, err := p.Profile(models.MVolumeProfileRequest{Symbol: "AAPL", From: nyseTime(t, 3, 11, 0, 0)})
+	if err != nil || profile.Bars != 4 || profile.To < time.Now().Unix()-60 {
+		t.Errorf("open-ended range = %+v, %v", profile, err)
+	}
+}
+
+func TestProfileBuckets(t *testing.T) {
+	p := newTestProfiler(t)
+
+	tests := []struct {
+		name        string
+		request     models.MVolumeProfileRequest
+		size        float64
+		volumes     []float64
+		poc         float64
+		valueArea   [2]float64
+		valueVolume float64
+	}{
+		// The ranged bar spreads over 101-103; 104 is clamped into the last bucket
+		{"even buckets", models.MVolumeProfileRequest{Session: "2024-03-12", Buckets: 2}, 1.5, []float64{30, 20}, 101.75, [2]float64{101, 104}, 50},
+		{"bucket size", models.MVolumeProfileRequest{Session: "2024-03-12", BucketSize: 1, ValueAreaPct: 0.5}, 1,
+			[]float64{20, 20, 0, 10}, 101.5, [2]float64{101, 103}, 40},
+		{"bucket size aligned", models.MVolumeProfileRequest{Session: "2024-03-11", BucketSize: 3}, 3, []float64{10, 20}, 103.5, [2]float64{99, 105}, 30},
+		{"flat range", models.MVolumeProfileRequest{From: nyseTime(t, 3, 11, 10, 0), To: nyseTime(t, 3, 11, 10, 30), Buckets: 5}, 0,
+			[]float64{10}, 100, [2]float64{100, 100}, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.request.Symbol = "AAPL"
+			profile, err := p.Profile(tt.request)
+			if err != nil {
+				t.Fatalf("Profile: %v", err)
+			}
+			if profile.BucketSize != tt.size || len(profile.Buckets) != len(tt.volumes) {
+				t.Fatalf("bucket size %v buckets %+v, want %v and %d", profile.BucketSize, profile.Buckets, tt.size, len(tt.volumes))
+			}
+			for i, b := range profile.Buckets {
+				if math.Abs(b.Volume-tt.volumes[i]) > 1e-9 {
+					t.Errorf("bucket %d = %+v, want volume %v", i, b, tt.volumes[i])
+				}
+			}
+			if math.Abs(profile.PointOfControl-tt.poc) > 1e-9 ||
+				math.Abs(profile.ValueAreaLow-tt.valueArea[0]) > 1e-9 || math.Abs(profile.ValueAreaHigh-tt.valueArea[1]) > 1e-9 ||
+				math.Abs(profile.ValueAreaVolume-tt.valueVolume) > 1e-9 {
+				t.Errorf("poc %v value area [%v, %v] %v, want %v %v %v", profile.PointOfControl,
+					profile.ValueAreaLow, profile.ValueAreaHigh, profile.ValueAreaVolume, tt.poc, tt.valueArea, tt.valueVolume)
+			}
+		})
+	}
+}
+
+func TestProfileErrors(t *testing.T) {
+	p := newTestProfiler(t)
+
+	tests := []struct {
+		name    string
+		request models.MVolumeProfileRequest
+		err     error
+	}{
+		{"no symbol", models.MVolumeProfileRequest{}, ErrInvalidProfile},
+		{"negative buckets", models.MVolumeProfileRequest{Symbol: "AAPL", Buckets: -1}, ErrInvalidProfile},
+		{"too many buckets", models.MVolumeProfileRequest{Symbol: "AAPL", Buckets: 11}, ErrInvalidProfile},
+		{"bucket size too small", models.MVolumeProfileRequest{Symbol: "AAPL", Session: "2024-03-12", BucketSize: 0.1}, ErrInvalidProfile},
+		{"value area above 1", models.MVolumeProfileRequest{Symbol: "AAPL", ValueAreaPct: 1.5}, ErrInvalidProfile},
+		{"session and range", models.MVolumeProfileRequest{Symbol: "AAPL", Session: "2024-03-12", From: 1}, ErrInvalidProfile},
+		{"malformed session", models.MVolumeProfileRequest{Symbol: "AAPL", Session: "12/03/2024"}, ErrInvalidProfile},
+		{"weekend session", models.MVolumeProfileRequest{Symbol: "AAPL", Session: "2024-03-09"}, ErrInvalidProfile},
+		{"empty range", models.MVolumeProfileRequest{Symbol: "AAPL", From: 100, To: 100}, ErrInvalidProfile},
+		{"session without bars", models.MVolumeProfileRequest{Symbol: "AAPL", Session: "2024-03-13"}, ErrNoProfileData},
+		{"unknown symbol", models.MVolumeProfileRequest{Symbol: "MSFT"}, ErrNoProfileData},
+	}
+
+	for _, tt := range tests {
+		if _, err := p.Profile(tt.request); !errors.Is(err, tt.err) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,444 @@
+package screener
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"market-observer/src/alerts"
+	"market-observer/src/analysis"
+	"market-observer/src/interfaces"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+)
+
+var (
+	// ErrScreenNotFound is returned when a saved screen ID does not exist
+	ErrScreenNotFound = errors.New("screen not found")
+	// ErrInvalidScreen wraps screen validation errors
+	ErrInvalidScreen = errors.New("invalid screen")
+)
+
+// -----------------------------------------------------------------------------
+// Screener filters, ranks and limits the symbols of the universe on their
+// latest candle of a window. Filters use the alert rule fields (candle fields,
+// indicators, analyzer metrics). Saved screens are stored in the database and
+// re-run on every update cycle; membership changes are returned as SCREEN
+// messages for their WebSocket subscribers.
+// -----------------------------------------------------------------------------
+
+type Screener struct {
+	Config *models.MConfig
+	DB     interfaces.IDatabase
+	Stats  *analysis.RollingStats // For z-score indicators (may be nil)
+	Logger *logger.Logger
+
+	screens   map[int64]*compiledScreen
+	members   map[int64][]string                        // screen -> members at the last run
+	candles   map[string]map[string]models.MAggregation // symbol -> window -> latest candle
+	timestamp int64                                     // Update the candles belong to
+	known     map[string]bool
+	mu        sync.RWMutex
+}
+
+type compiledScreen struct {
+	screen models.MSavedScreen
+	query  *query
+}
+
+// query is a validated screen request
+type query struct {
+	request models.MScreenRequest
+	expr    *alerts.Expression // nil = no filter
+	symbols map[string]bool    // nil = all symbols
+	fields  []string           // Values reported with each match
+}
+
+// -----------------------------------------------------------------------------
+
+func NewScreener(cfg *models.MConfig, db interfaces.IDatabase, stats *analysis.RollingStats, log *logger.Logger) *Screener {
+	return &Screener{
+		Config:  cfg,
+		DB:      db,
+		Stats:   stats,
+		Logger:  log,
+		screens: make(map[int64]*compiledScreen),
+		members: make(map[int64][]string),
+		candles: make(map[string]map[string]models.MAggregation),
+		known:   alerts.KnownFields(),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Load restores the saved screens. Invalid screens are skipped with a warning.
+func (s *Screener) Load() error {
+	screens, err := s.DB.LoadScreens()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sc := range screens {
+		compiled, err := s.compileScreen(sc)
+		if err != nil {
+			s.Logger.Warning("Skipping screen %d (%s): %v", sc.ID, sc.Name, err)
+			continue
+		}
+		s.screens[sc.ID] = compiled
+	}
+	s.Logger.Info("Loaded %d saved screen(s)", len(s.screens))
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// Run evaluates an ad-hoc screen on the latest candles.
+func (s *Screener) Run(request models.MScreenRequest) (models.MScreenResult, error) {
+	q, err := s.compile(request)
+	if err != nil {
+		return models.MScreenResult{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.run(q), nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *Screener) ListScreens() []models.MSavedScreen {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	screens := make([]models.MSavedScreen, 0, len(s.screens))
+	for _, c := range s.screens {
+		screens = append(screens, c.screen)
+	}
+	sort.Slice(screens, func(i, j int) bool { return screens[i].ID < screens[j].ID })
+	return screens
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *Screener) CreateScreen(screen models.MSavedScreen) (models.MSavedScreen, error) {
+	screen.ID = 0
+	compiled, err := s.compileScreen(screen)
+	if err != nil {
+		return screen, err
+	}
+
+	now := time.Now().UTC().Unix()
+	compiled.screen.CreatedAt = now
+	compiled.screen.UpdatedAt = now
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.DB.SaveScreen(&compiled.screen); err != nil {
+		return screen, fmt.Errorf("failed to store screen: %w", err)
+	}
+	s.screens[compiled.screen.ID] = compiled
+	s.members[compiled.screen.ID] = memberSymbols(s.run(compiled.query))
+	s.Logger.Info("Created screen %d (%s) on %s", compiled.screen.ID, compiled.screen.Name, compiled.screen.Window)
+	return compiled.screen, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *Screener) UpdateScreen(screen models.MSavedScreen) (models.MSavedScreen, error) {
+	compiled, err := s.compileScreen(screen)
+	if err != nil {
+		return screen, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.screens[screen.ID]
+	if !ok {
+		return screen, ErrScreenNotFound
+	}
+	compiled.screen.CreatedAt = existing.screen.CreatedAt
+	compiled.screen.UpdatedAt = time.Now().UTC().Unix()
+
+	if err := s.DB.SaveScreen(&compiled.screen); err != nil {
+		return screen, fmt.Errorf("failed to store screen: %w", err)
+	}
+	s.screens[screen.ID] = compiled
+	s.members[screen.ID] = memberSymbols(s.run(compiled.query))
+	s.Logger.Info("Updated screen %d (%s)", screen.ID, compiled.screen.Name)
+	return compiled.screen, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *Screener) DeleteScreen(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.screens[id]; !ok {
+		return ErrScreenNotFound
+	}
+	if err := s.DB.DeleteScreen(id); err != nil {
+		return fmt.Errorf("failed to delete screen: %w", err)
+	}
+	delete(s.screens, id)
+	delete(s.members, id)
+	s.Logger.Info("Deleted screen %d", id)
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// Snapshot returns the current membership of a saved screen (no entered/exited).
+func (s *Screener) Snapshot(id int64) (models.MScreenMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.screens[id]
+	if !ok {
+		return models.MScreenMessage{}, ErrScreenNotFound
+	}
+	result := s.run(c.query)
+	return screenMessage(c.screen, result, memberSymbols(result), nil, nil), nil
+}
+
+// -----------------------------------------------------------------------------
+
+// Refresh replaces the latest candles (symbol -> window -> candle) and re-runs
+// every enabled saved screen. Returns one message per screen whose membership
+// changed; the first run of a screen only records its members.
+func (s *Screener) Refresh(candles map[string]map[string]models.MAggregation, timestamp int64) []models.MScreenMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.candles = candles
+	s.timestamp = timestamp
+
+	ids := make([]int64, 0, len(s.screens))
+	for id := range s.screens {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	var messages []models.MScreenMessage
+	for _, id := range ids {
+		c := s.screens[id]
+		if !c.screen.Enabled {
+			continue
+		}
+
+		result := s.run(c.query)
+		members := memberSymbols(result)
+		previous, seen := s.members[id]
+		s.members[id] = members
+		if !seen {
+			continue
+		}
+
+		entered, exited := diffMembers(previous, members)
+		if len(entered) == 0 && len(exited) == 0 {
+			continue
+		}
+		messages = append(messages, screenMessage(c.screen, result, members, entered, exited))
+	}
+	return messages
+}
+
+// -----------------------------------------------------------------------------
+
+// run evaluates a query on the latest candles (caller holds the lock).
+func (s *Screener) run(q *query) models.MScreenResult {
+	window := q.request.Window
+	result := models.MScreenResult{Window: window, Timestamp: s.timestamp, Matches: []models.MScreenMatch{}}
+
+	for symbol, windows := range s.candles {
+		c, ok := windows[window]
+		if !ok {
+			continue
+		}
+		if q.symbols != nil && !q.symbols[symbol] {
+			continue
+		}
+
+		var stats *models.MIntermediateStats
+		if s.Stats != nil {
+			if st, ok := s.Stats.Get(symbol, window); ok {
+				stats = &st
+			}
+		}
+
+		values := alerts.FieldValues(c, stats, q.fields)
+		if q.expr != nil && !q.expr.Eval(values) {
+			continue
+		}
+		result.Matches = append(result.Matches, models.MScreenMatch{Symbol: symbol, Values: values, Candle: c})
+	}
+
+	sortBy := q.request.SortBy
+	sort.Slice(result.Matches, func(i, j int) bool {
+		a, b := result.Matches[i], result.Matches[j]
+		if sortBy != "" && a.Values[sortBy] != b.Values[sortBy] {
+			if q.request.Ascending {
+				return a.Values[sortBy] < b.Values[sortBy]
+			}
+			return a.Values[sortBy] > b.Values[sortBy]
+		}
+		return a.Symbol < b.Symbol
+	})
+
+	result.Total = len(result.Matches)
+	if q.request.Limit > 0 && len(result.Matches) > q.request.Limit {
+		result.Matches = result.Matches[:q.request.Limit]
+	}
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// compileScreen validates a saved screen and its request.
+func (s *Screener) compileScreen(screen models.MSavedScreen) (*compiledScreen, error) {
+	screen.Name = strings.TrimSpace(screen.Name)
+	if screen.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidScreen)
+	}
+
+	q, err := s.compile(screen.MScreenRequest)
+	if err != nil {
+		return nil, err
+	}
+	screen.MScreenRequest = q.request
+	return &compiledScreen{screen: screen, query: q}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// compile validates a request and combines its filters and condition into one
+// expression (filters AND (condition)).
+func (s *Screener) compile(request models.MScreenRequest) (*query, error) {
+	request.Window = strings.TrimSpace(request.Window)
+	request.Condition = strings.TrimSpace(request.Condition)
+	request.SortBy = strings.ToLower(strings.TrimSpace(request.SortBy))
+
+	if request.Window == "" {
+		return nil, fmt.Errorf("%w: window is required", ErrInvalidScreen)
+	}
+	if !slices.Contains(s.Config.WindowsAgg, request.Window) {
+		return nil, fmt.Errorf("%w: unknown window %q", ErrInvalidScreen, request.Window)
+	}
+	if request.Limit < 0 {
+		return nil, fmt.Errorf("%w: limit cannot be negative", ErrInvalidScreen)
+	}
+
+	// Filters are built as comparisons, never re-parsed from text
+	var parts []*alerts.Expression
+	for _, f := range request.Filters {
+		expr, err := alerts.Compare(strings.TrimSpace(f.Field), strings.TrimSpace(f.Op), f.Value, s.known)
+		if err != nil {
+			return nil, fmt.Errorf("%w: filter: %v", ErrInvalidScreen, err)
+		}
+		parts = append(parts, expr)
+	}
+	if request.Condition != "" {
+		// The window comes from the request, not from the condition
+		expr, err := alerts.ParseExpression(request.Condition, s.known)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidScreen, err)
+		}
+		if expr.Window != "" {
+			return nil, fmt.Errorf("%w: use window instead of 'on' in the condition", ErrInvalidScreen)
+		}
+		parts = append(parts, expr)
+	}
+
+	q := &query{request: request}
+	fields := make(map[string]bool)
+	if len(parts) > 0 {
+		q.expr = alerts.All(parts...)
+		for _, f := range q.expr.Fields {
+			fields[f] = true
+		}
+	}
+
+	for _, f := range append([]string{request.SortBy}, request.Fields...) {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" {
+			continue
+		}
+		if !s.known[f] && !strings.HasPrefix(f, alerts.MetricFieldPrefix) {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidScreen, f)
+		}
+		fields[f] = true
+	}
+	for f := range fields {
+		q.fields = append(q.fields, f)
+	}
+	sort.Strings(q.fields)
+
+	if len(request.Symbols) > 0 || request.Watchlist != "" {
+		q.symbols = make(map[string]bool)
+		for _, sym := range request.Symbols {
+			q.symbols[strings.TrimSpace(sym)] = true
+		}
+		if request.Watchlist != "" {
+			list, ok := s.Config.Alerts.Watchlists[request.Watchlist]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown watchlist %q", ErrInvalidScreen, request.Watchlist)
+			}
+			for _, sym := range list {
+				q.symbols[sym] = true
+			}
+		}
+	}
+	return q, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func memberSymbols(result models.MScreenResult) []string {
+	members := make([]string, len(result.Matches))
+	for i, m := range result.Matches {
+		members[i] = m.Symbol
+	}
+	return members
+}
+
+// -----------------------------------------------------------------------------
+
+// diffMembers returns the symbols that joined and left between two runs.
+func diffMembers(previous, current []string) (entered, exited []string) {
+	for _, sym := range current {
+		if !slices.Contains(previous, sym) {
+			entered = append(entered, sym)
+		}
+	}
+	for _, sym := range previous {
+		if !slices.Contains(current, sym) {
+			exited = append(exited, sym)
+		}
+	}
+	return entered, exited
+}
+
+// -----------------------------------------------------------------------------
+
+func screenMessage(screen models.MSavedScreen, result models.MScreenResult, members, entered, exited []string) models.MScreenMessage {
+	return models.MScreenMessage{
+		Type:      "SCREEN",
+		ScreenID:  screen.ID,
+		Name:      screen.Name,
+		Window:    screen.Window,
+		Members:   members,
+		Entered:   entered,
+		Exited:    exited,
+		Matches:   result.Matches,
+		Timestamp: result.Timestamp,
+	}
+}
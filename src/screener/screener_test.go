@@ -0,0 +1,201 @@
+package screener
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/storage"
+)
+
+func newTestScreener(t *testing.T) *Screener {
+	t.Helper()
+	cfg := &models.MConfig{WindowsAgg: []string{"5m", "15m"}}
+	cfg.Alerts.Watchlists = map[string][]string{"tech": {"AAPL", "MSFT"}}
+	cfg.Storage.DBPath = filepath.Join(t.TempDir(), "screener.db")
+	log := logger.NewLogger(cfg, "test")
+
+	db, err := storage.NewAsyncSQLiteDB(cfg, log)
+	if err != nil {
+		t.Fatalf("NewAsyncSQLiteDB: %v", err)
+	}
+	if err := db.Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewScreener(cfg, db, nil, log)
+}
+
+// screenCandles builds the latest 5m candles from symbol -> (volume, price change).
+func screenCandles(values map[string][2]float64) map[string]map[string]models.MAggregation {
+	candles := make(map[string]map[string]models.MAggregation)
+	for symbol, v := range values {
+		candles[symbol] = map[string]models.MAggregation{
+			"5m": {Symbol: symbol, WindowName: "5m", Volume: v[0], PricePercentChange: v[1]},
+		}
+	}
+	return candles
+}
+
+func TestScreenerCompile(t *testing.T) {
+	s := newTestScreener(t)
+	s.Refresh(screenCandles(map[string][2]float64{
+		"AAPL": {500, 0.02},
+		"MSFT": {300, -0.01},
+		"TSLA": {900, 0.05},
+		"IBM":  {100, 0.03},
+	}), 1)
+
+	tests := []struct {
+		name    string
+		request models.MScreenRequest
+		want    []string
+		err     bool
+	}{
+		{"all symbols", models.MScreenRequest{Window: "5m"}, []string{"AAPL", "IBM", "MSFT", "TSLA"}, false},
+		{"filters are ANDed", models.MScreenRequest{Window: "5m", Filters: []models.MScreenFilter{
+			{Field: "volume", Op: ">=", Value: 300}, {Field: "Price_Percent_Change", Op: ">", Value: 0}}}, []string{"AAPL", "TSLA"}, false},
+		{"negative and exponent values", models.MScreenRequest{Window: "5m", Filters: []models.MScreenFilter{
+			{Field: "price_percent_change", Op: "<", Value: -1e-3}}}, []string{"MSFT"}, false},
+		{"filters and condition", models.MScreenRequest{Window: "5m", Filters: []models.MScreenFilter{{Field: "volume", Op: "<", Value: 600}},
+			Condition: "price_percent_change > 0.025 OR price_percent_change < 0"}, []string{"IBM", "MSFT"}, false},
+		{"watchlist", models.MScreenRequest{Window: "5m", Watchlist: "tech"}, []string{"AAPL", "MSFT"}, false},
+		{"sort and limit", models.MScreenRequest{Window: "5m", SortBy: "volume", Limit: 2}, []string{"TSLA", "AAPL"}, false},
+		{"sort ascending", models.MScreenRequest{Window: "5m", SortBy: "volume", Ascending: true, Limit: 1}, []string{"IBM"}, false},
+		{"other window", models.MScreenRequest{Window: "15m"}, []string{}, false},
+
+		{"unknown window", models.MScreenRequest{Window: "1h"}, nil, true},
+		{"unknown field", models.MScreenRequest{Window: "5m", Filters: []models.MScreenFilter{{Field: "bogus", Op: ">", Value: 1}}}, nil, true},
+		{"invalid operator", models.MScreenRequest{Window: "5m", Filters: []models.MScreenFilter{{Field: "volume", Op: "=>", Value: 1}}}, nil, true},
+		// A field that would change the expression if it were parsed as text
+		{"injected field", models.MScreenRequest{Window: "5m", Filters: []models.MScreenFilter{
+			{Field: "volume > 0 OR volume", Op: ">", Value: 1e9}}}, nil, true},
+		{"injected operator", models.MScreenRequest{Window: "5m", Filters: []models.MScreenFilter{
+			{Field: "volume", Op: "> 0 OR volume >", Value: 1e9}}}, nil, true},
+		{"window in condition", models.MScreenRequest{Window: "5m", Condition: "volume > 1 on 5m"}, nil, true},
+		{"unknown sort field", models.MScreenRequest{Window: "5m", SortBy: "bogus"}, nil, true},
+		{"unknown watchlist", models.MScreenRequest{Window: "5m", Watchlist: "energy"}, nil, true},
+		{"negative limit", models.MScreenRequest{Window: "5m", Limit: -1}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.Run(tt.request)
+			if tt.err {
+				if !errors.Is(err, ErrInvalidScreen) {
+					t.Fatalf("Run error = %v, want ErrInvalidScreen", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if got := memberSymbols(result); !slices.Equal(got, tt.want) {
+				t.Errorf("members = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScreenerSavedScreens(t *testing.T) {
+	s := newTestScreener(t)
+	request := models.MScreenRequest{Window: "5m", Filters: []models.MScreenFilter{{Field: "volume", Op: ">", Value: 100}}}
+
+	if _, err := s.CreateScreen(models.MSavedScreen{Name: "  ", MScreenRequest: request}); !errors.Is(err, ErrInvalidScreen) {
+		t.Fatalf("CreateScreen without name error = %v", err)
+	}
+
+	created, err := s.CreateScreen(models.MSavedScreen{ID: 42, Name: " movers ", MScreenRequest: request, Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateScreen: %v", err)
+	}
+	if created.ID == 0 || created.ID == 42 || created.Name != "movers" || created.CreatedAt == 0 {
+		t.Errorf("created = %+v", created)
+	}
+
+	created.Name = "big movers"
+	created.Filters[0].Value = 1000
+	updated, err := s.UpdateScreen(created)
+	if err != nil {
+		t.Fatalf("UpdateScreen: %v", err)
+	}
+	if updated.CreatedAt != created.CreatedAt || updated.Name != "big movers" {
+		t.Errorf("updated = %+v", updated)
+	}
+	if _, err := s.UpdateScreen(models.MSavedScreen{ID: 999, Name: "x", MScreenRequest: request}); !errors.Is(err, ErrScreenNotFound) {
+		t.Errorf("UpdateScreen of a missing screen error = %v", err)
+	}
+
+	// Saved screens survive a restart
+	restored := NewScreener(s.Config, s.DB, nil, s.Logger)
+	if err := restored.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	screens := restored.ListScreens()
+	if len(screens) != 1 || screens[0].Name != "big movers" || screens[0].Filters[0].Value != 1000 {
+		t.Errorf("loaded screens = %+v", screens)
+	}
+
+	if err := s.DeleteScreen(created.ID); err != nil {
+		t.Fatalf("DeleteScreen: %v", err)
+	}
+	if err := s.DeleteScreen(created.ID); !errors.Is(err, ErrScreenNotFound) {
+		t.Errorf("second DeleteScreen error = %v", err)
+	}
+	if _, err := s.Snapshot(created.ID); !errors.Is(err, ErrScreenNotFound) {
+		t.Errorf("Snapshot of a deleted screen error = %v", err)
+	}
+	if screens, _ := s.DB.LoadScreens(); len(screens) != 0 {
+		t.Errorf("stored screens after delete = %+v", screens)
+	}
+}
+
+func TestScreenerRefreshMembership(t *testing.T) {
+	s := newTestScreener(t)
+	s.Refresh(screenCandles(map[string][2]float64{"AAPL": {500, 0}, "MSFT": {50, 0}}), 1)
+
+	screen, err := s.CreateScreen(models.MSavedScreen{Name: "active", Enabled: true, MScreenRequest: models.MScreenRequest{
+		Window: "5m", Filters: []models.MScreenFilter{{Field: "volume", Op: ">", Value: 100}}}})
+	if err != nil {
+		t.Fatalf("CreateScreen: %v", err)
+	}
+	if _, err := s.CreateScreen(models.MSavedScreen{Name: "disabled", MScreenRequest: models.MScreenRequest{Window: "5m"}}); err != nil {
+		t.Fatalf("CreateScreen: %v", err)
+	}
+
+	steps := []struct {
+		name    string
+		values  map[string][2]float64
+		entered []string
+		exited  []string
+		message bool
+	}{
+		{"unchanged", map[string][2]float64{"AAPL": {600, 0}, "MSFT": {60, 0}}, nil, nil, false},
+		{"one enters", map[string][2]float64{"AAPL": {600, 0}, "MSFT": {200, 0}}, []string{"MSFT"}, nil, true},
+		{"swap", map[string][2]float64{"AAPL": {90, 0}, "MSFT": {200, 0}, "TSLA": {300, 0}}, []string{"TSLA"}, []string{"AAPL"}, true},
+		{"all exit", map[string][2]float64{"AAPL": {0, 0}}, nil, []string{"MSFT", "TSLA"}, true},
+	}
+
+	for i, step := range steps {
+		messages := s.Refresh(screenCandles(step.values), int64(i+2))
+		if !step.message {
+			if len(messages) != 0 {
+				t.Errorf("%s: messages = %+v, want none", step.name, messages)
+			}
+			continue
+		}
+		if len(messages) != 1 {
+			t.Fatalf("%s: %d messages, want 1 (disabled screens stay silent)", step.name, len(messages))
+		}
+		m := messages[0]
+		if m.ScreenID != screen.ID || m.Type != "SCREEN" || m.Timestamp != int64(i+2) {
+			t.Errorf("%s: message = %+v", step.name, m)
+		}
+		if !slices.Equal(m.Entered, step.entered) || !slices.Equal(m.Exited, step.exited) {
+			t.Errorf("%s: entered %v exited %v, want %v %v", step.name, m.Entered, m.Exited, step.entered, step.exited)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"market-observer/src/alerts"
+	"market-observer/src/interfaces"
+	"market-observer/src/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// -----------------------------------------------------------------------------
+// Alert rules endpoints (rules CRUD + recent events)
+// -----------------------------------------------------------------------------
+
+// SetAlertRulesManager wires the rules engine used by the /api/alerts routes
+func (s *FastAPIServer) SetAlertRulesManager(manager interfaces.IAlertRulesManager) {
+	s.alertRules = manager
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) listAlertRules(c *gin.Context) {
+	if s.alertRules == nil {
+		c.JSON(503, gin.H{"error": "alert rules not available"})
+		return
+	}
+	c.JSON(200, gin.H{"rules": s.alertRules.ListRules()})
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) createAlertRule(c *gin.Context) {
+	if s.alertRules == nil {
+		c.JSON(503, gin.H{"error": "alert rules not available"})
+		return
+	}
+
+	var rule models.MAlertRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := s.alertRules.CreateRule(rule)
+	if err != nil {
+		c.JSON(alertRuleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(201, created)
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) updateAlertRule(c *gin.Context) {
+	if s.alertRules == nil {
+		c.JSON(503, gin.H{"error": "alert rules not available"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid rule id"})
+		return
+	}
+
+	var rule models.MAlertRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	rule.ID = id
+
+	updated, err := s.alertRules.UpdateRule(rule)
+	if err != nil {
+		c.JSON(alertRuleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, updated)
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) deleteAlertRule(c *gin.Context) {
+	if s.alertRules == nil {
+		c.JSON(503, gin.H{"error": "alert rules not available"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid rule id"})
+		return
+	}
+
+	if err := s.alertRules.DeleteRule(id); err != nil {
+		c.JSON(alertRuleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"status": "deleted", "id": id})
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) listAlertEvents(c *gin.Context) {
+	if s.alertRules == nil {
+		c.JSON(503, gin.H{"error": "alert rules not available"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "limit must be an integer"})
+		return
+	}
+	c.JSON(200, gin.H{"events": s.alertRules.RecentEvents(limit)})
+}
+
+// -----------------------------------------------------------------------------
+
+// alertRuleErrorStatus maps rules engine errors to HTTP status codes
+func alertRuleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, alerts.ErrRuleNotFound):
+		return 404
+	case errors.Is(err, alerts.ErrInvalidRule):
+		return 400
+	default:
+		return 500
+	}
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"strconv"
+
+	"market-observer/src/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// -----------------------------------------------------------------------------
+// Market breadth endpoints (latest snapshot per window + per-window history)
+// -----------------------------------------------------------------------------
+
+// SetBreadthProvider wires the provider used by the /api/breadth routes
+func (s *FastAPIServer) SetBreadthProvider(provider interfaces.IBreadthProvider) {
+	s.breadth = provider
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) getBreadthLatest(c *gin.Context) {
+	if s.breadth == nil {
+		c.JSON(503, gin.H{"error": "breadth not available"})
+		return
+	}
+	c.JSON(200, gin.H{"breadth": s.breadth.Latest()})
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) getBreadthHistory(c *gin.Context) {
+	if s.breadth == nil {
+		c.JSON(503, gin.H{"error": "breadth not available"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "limit must be an integer"})
+		return
+	}
+
+	window := c.Param("window")
+	history, ok := s.breadth.History(window, limit)
+	if !ok {
+		c.JSON(404, gin.H{"error": "unknown window " + window})
+		return
+	}
+	c.JSON(200, gin.H{"window": window, "history": history})
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"strconv"
+
+	"market-observer/src/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// -----------------------------------------------------------------------------
+// Changepoint endpoints (recent regime changes)
+// -----------------------------------------------------------------------------
+
+// SetChangepointProvider wires the provider used by the /api/changepoints route
+func (s *FastAPIServer) SetChangepointProvider(provider interfaces.IChangepointProvider) {
+	s.changepoints = provider
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) listChangepoints(c *gin.Context) {
+	if s.changepoints == nil {
+		c.JSON(503, gin.H{"error": "changepoint detection not available"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "limit must be an integer"})
+		return
+	}
+	c.JSON(200, gin.H{"changepoints": s.changepoints.Recent(c.Query("symbol"), c.Query("window"), limit)})
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -25,6 +26,38 @@ type Client struct {
 	hub  *FastAPIServer
 	conn *websocket.Conn
 	send chan interface{}
+
+	// Saved screens the client follows (set by readPump, read by the Hub loop)
+	screens   map[int64]struct{}
+	screensMu sync.RWMutex
+}
+
+// -----------------------------------------------------------------------------
+
+func (c *Client) subscribeScreen(id int64) {
+	c.screensMu.Lock()
+	defer c.screensMu.Unlock()
+	if c.screens == nil {
+		c.screens = make(map[int64]struct{})
+	}
+	c.screens[id] = struct{}{}
+}
+
+// -----------------------------------------------------------------------------
+
+func (c *Client) unsubscribeScreen(id int64) {
+	c.screensMu.Lock()
+	defer c.screensMu.Unlock()
+	delete(c.screens, id)
+}
+
+// -----------------------------------------------------------------------------
+
+func (c *Client) isSubscribedToScreen(id int64) bool {
+	c.screensMu.RLock()
+	defer c.screensMu.RUnlock()
+	_, ok := c.screens[id]
+	return ok
 }
 
 // -----------------------------------------------------------------------------
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"strconv"
+
+	"market-observer/src/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// -----------------------------------------------------------------------------
+// Clustering endpoints (intraday profile clusters + day-over-day jumps)
+// -----------------------------------------------------------------------------
+
+// SetClusterProvider wires the provider used by the /api/clusters routes
+func (s *FastAPIServer) SetClusterProvider(provider interfaces.IClusterProvider) {
+	s.clusters = provider
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) getClusters(c *gin.Context) {
+	if s.clusters == nil {
+		c.JSON(503, gin.H{"error": "clustering not available"})
+		return
+	}
+
+	snapshot, ok := s.clusters.Snapshot()
+	if !ok {
+		c.JSON(404, gin.H{"error": "clusters not computed yet"})
+		return
+	}
+	c.JSON(200, snapshot)
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) listClusterJumps(c *gin.Context) {
+	if s.clusters == nil {
+		c.JSON(503, gin.H{"error": "clustering not available"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "limit must be an integer"})
+		return
+	}
+	c.JSON(200, gin.H{"jumps": s.clusters.Jumps(c.Query("symbol"), limit)})
+}
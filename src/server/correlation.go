@@ -0,0 +1,69 @@
+package server
+
+import (
+	"strconv"
+
+	"market-observer/src/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// -----------------------------------------------------------------------------
+// Correlation endpoints (served from the CorrelationService cache)
+// -----------------------------------------------------------------------------
+
+// SetCorrelationProvider wires the correlation service used by the /api/correlation routes
+func (s *FastAPIServer) SetCorrelationProvider(provider interfaces.ICorrelationProvider) {
+	s.correlation = provider
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) getCorrelationMatrix(c *gin.Context) {
+	if s.correlation == nil {
+		c.JSON(503, gin.H{"error": "correlation service not available"})
+		return
+	}
+
+	window := c.Param("window")
+	matrix, ok := s.correlation.GetMatrix(window)
+	if !ok {
+		c.JSON(404, gin.H{"error": "no correlation matrix for window " + window})
+		return
+	}
+	c.JSON(200, matrix)
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) getCorrelationTopPairs(c *gin.Context) {
+	if s.correlation == nil {
+		c.JSON(503, gin.H{"error": "correlation service not available"})
+		return
+	}
+
+	n, err := strconv.Atoi(c.DefaultQuery("n", "10"))
+	if err != nil || n < 0 {
+		c.JSON(400, gin.H{"error": "n must be a non-negative integer (0 = all pairs)"})
+		return
+	}
+
+	window := c.Param("window")
+	c.JSON(200, gin.H{
+		"window_name": window,
+		"pairs":       s.correlation.TopPairs(window, n),
+	})
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) getCorrelationAlerts(c *gin.Context) {
+	if s.correlation == nil {
+		c.JSON(503, gin.H{"error": "correlation service not available"})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"alerts": s.correlation.GetAlerts(c.Query("window")),
+	})
+}
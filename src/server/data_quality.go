@@ -0,0 +1,43 @@
+package server
+
+import (
+	"market-observer/src/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// -----------------------------------------------------------------------------
+// Data-quality endpoints (completeness summary + per-symbol gap report)
+// -----------------------------------------------------------------------------
+
+// SetDataQualityProvider wires the provider used by the /api/data-quality routes
+func (s *FastAPIServer) SetDataQualityProvider(provider interfaces.IDataQualityProvider) {
+	s.dataQuality = provider
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) getDataQualitySummary(c *gin.Context) {
+	if s.dataQuality == nil {
+		c.JSON(503, gin.H{"error": "data quality not available"})
+		return
+	}
+	c.JSON(200, gin.H{"symbols": s.dataQuality.Summary()})
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) getDataQualityReport(c *gin.Context) {
+	if s.dataQuality == nil {
+		c.JSON(503, gin.H{"error": "data quality not available"})
+		return
+	}
+
+	symbol := c.Param("symbol")
+	report, ok := s.dataQuality.Report(symbol)
+	if !ok {
+		c.JSON(404, gin.H{"error": "unknown symbol " + symbol})
+		return
+	}
+	c.JSON(200, report)
+}
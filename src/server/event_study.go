@@ -0,0 +1,50 @@
+package server
+
+import (
+	"strconv"
+
+	"market-observer/src/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// -----------------------------------------------------------------------------
+// Event study endpoints (forward/abnormal returns after volume anomalies)
+// -----------------------------------------------------------------------------
+
+// SetEventStudyProvider wires the provider used by the /api/event-study routes
+func (s *FastAPIServer) SetEventStudyProvider(provider interfaces.IEventStudyProvider) {
+	s.eventStudy = provider
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) getEventStudyReport(c *gin.Context) {
+	if s.eventStudy == nil {
+		c.JSON(503, gin.H{"error": "event study not available"})
+		return
+	}
+
+	report, ok := s.eventStudy.Report(c.Query("symbol"), c.Query("window"), c.Query("severity"))
+	if !ok {
+		c.JSON(404, gin.H{"error": "event study not computed yet"})
+		return
+	}
+	c.JSON(200, report)
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) listEventStudyEvents(c *gin.Context) {
+	if s.eventStudy == nil {
+		c.JSON(503, gin.H{"error": "event study not available"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "limit must be an integer"})
+		return
+	}
+	c.JSON(200, gin.H{"events": s.eventStudy.Events(c.Query("symbol"), c.Query("window"), c.Query("severity"), limit)})
+}
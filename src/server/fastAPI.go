@@ -5,6 +5,7 @@ import (
 	"strings"
 	"sync"
 
+	"market-observer/src/interfaces"
 	"market-observer/src/logger"
 	"market-observer/src/models"
 
@@ -23,12 +24,30 @@ type FastAPIServer struct {
 	// WebSocket clients
 	clients    map[*Client]struct{}
 	broadcast  chan *models.MLatestData // Strongly typed and Buffered Queue
+	messages   chan interface{}         // Other message types (e.g. BREADTH), not cached as state
 	register   chan *Client
 	unregister chan *Client
 
 	// Local cache
 	latestState *models.MLatestData
 	stateMutex  sync.RWMutex
+
+	// Optional analytics providers (nil until wired)
+	correlation   interfaces.ICorrelationProvider
+	alertRules    interfaces.IAlertRulesManager
+	notifier      interfaces.INotifier
+	dataQuality   interfaces.IDataQualityProvider
+	validator     interfaces.IValidationManager
+	breadth       interfaces.IBreadthProvider
+	changepoints  interfaces.IChangepointProvider
+	patterns      interfaces.IPatternProvider
+	levels        interfaces.ILevelProvider
+	clusters      interfaces.IClusterProvider
+	eventStudy    interfaces.IEventStudyProvider
+	volumeProfile interfaces.IVolumeProfiler
+	leadLag       interfaces.ILeadLagProvider
+	screener      interfaces.IScreener
+	leaderboards  interfaces.ILeaderboardProvider
 }
 
 // -----------------------------------------------------------------------------
@@ -49,6 +68,7 @@ func NewFastAPIServer(cfg *models.MConfig, logger *logger.Logger) *FastAPIServer
 		// Buffered channel to prevent lock/blocking
 		// Queue size of 256 ensures we can handle bursts of updates
 		broadcast:  make(chan *models.MLatestData, 256),
+		messages:   make(chan interface{}, 256),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		latestState: &models.MLatestData{
@@ -68,7 +88,7 @@ func NewFastAPIServer(cfg *models.MConfig, logger *logger.Logger) *FastAPIServer
 		}
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -93,6 +113,73 @@ func (s *FastAPIServer) setupRoutes() {
 	s.engine.GET("/api/config", s.getConfig)
 	s.engine.GET("/api/health", s.getHealth)
 
+	// Correlation
+	s.engine.GET("/api/correlation/matrix/:window", s.getCorrelationMatrix)
+	s.engine.GET("/api/correlation/top/:window", s.getCorrelationTopPairs)
+	s.engine.GET("/api/correlation/alerts", s.getCorrelationAlerts)
+
+	// Lead-lag
+	s.engine.GET("/api/lead-lag", s.listLeadLag)
+	s.engine.GET("/api/lead-lag/:a/:b", s.getLeadLagPair)
+
+	// Alert rules
+	s.engine.GET("/api/alerts/rules", s.listAlertRules)
+	s.engine.POST("/api/alerts/rules", s.createAlertRule)
+	s.engine.PUT("/api/alerts/rules/:id", s.updateAlertRule)
+	s.engine.DELETE("/api/alerts/rules/:id", s.deleteAlertRule)
+	s.engine.GET("/api/alerts/events", s.listAlertEvents)
+
+	// Notifications
+	s.engine.GET("/api/notifications/deliveries", s.listNotificationDeliveries)
+	s.engine.POST("/api/notifications/test", s.sendTestNotification)
+
+	// Data quality
+	s.engine.GET("/api/data-quality", s.getDataQualitySummary)
+	s.engine.GET("/api/data-quality/:symbol", s.getDataQualityReport)
+
+	// Inbound validation
+	s.engine.GET("/api/validation/stats", s.getValidationStats)
+	s.engine.GET("/api/validation/quarantine", s.listQuarantine)
+	s.engine.POST("/api/validation/quarantine/:id/release", s.releaseQuarantined)
+
+	// Market breadth
+	s.engine.GET("/api/breadth", s.getBreadthLatest)
+	s.engine.GET("/api/breadth/:window", s.getBreadthHistory)
+
+	// Regime changes
+	s.engine.GET("/api/changepoints", s.listChangepoints)
+
+	// Candlestick patterns
+	s.engine.GET("/api/patterns", s.listPatterns)
+
+	// Support/resistance levels
+	s.engine.GET("/api/levels", s.listLevels)
+	s.engine.GET("/api/levels/breaks", s.listLevelBreaks)
+	s.engine.GET("/api/levels/:symbol", s.getSymbolLevels)
+
+	// Intraday profile clusters
+	s.engine.GET("/api/clusters", s.getClusters)
+	s.engine.GET("/api/clusters/jumps", s.listClusterJumps)
+
+	// Event study of volume anomalies
+	s.engine.GET("/api/event-study", s.getEventStudyReport)
+	s.engine.GET("/api/event-study/events", s.listEventStudyEvents)
+
+	// Volume profile
+	s.engine.GET("/api/volume-profile/:symbol", s.getVolumeProfile)
+
+	// Leaderboards
+	s.engine.GET("/api/leaderboards", s.getLeaderboards)
+	s.engine.GET("/api/leaderboards/:window/:board", s.getLeaderboardRanking)
+
+	// Screener
+	s.engine.POST("/api/screener", s.runScreen)
+	s.engine.GET("/api/screener/screens", s.listScreens)
+	s.engine.POST("/api/screener/screens", s.createScreen)
+	s.engine.GET("/api/screener/screens/:id", s.getScreenResults)
+	s.engine.PUT("/api/screener/screens/:id", s.updateScreen)
+	s.engine.DELETE("/api/screener/screens/:id", s.deleteScreen)
+
 	// WebSocket endpoint
 	s.engine.GET("/ws", s.handleWebSocket)
 }
@@ -115,6 +202,7 @@ func (s *FastAPIServer) Start() error {
 func (s *FastAPIServer) Stop() error {
 	// Clean shutdown
 	close(s.broadcast)
+	close(s.messages)
 	close(s.register)
 	close(s.unregister)
 	return nil
@@ -89,6 +89,28 @@ func safeAggregationsMap(data map[string]interface{}, key string) map[string]map
 
 // -----------------------------------------------------------------------------
 
+// safeSlice reads a list of T from a payload, accepting the typed slice or its
+// generic JSON form.
+func safeSlice[T any](data map[string]interface{}, key string) []T {
+	if val, ok := data[key]; ok {
+		if list, ok := val.([]T); ok {
+			return list
+		}
+
+		// Fallback for generic structure
+		if list, ok := val.([]interface{}); ok {
+			var items []T
+			jsonBytes, _ := json.Marshal(list)
+			if err := json.Unmarshal(jsonBytes, &items); err == nil {
+				return items
+			}
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
 func safeFloat64(data map[string]interface{}, key string) float64 {
 	if val, ok := data[key]; ok {
 		switch v := val.(type) {
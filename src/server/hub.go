@@ -41,17 +41,32 @@ func (s *FastAPIServer) handleWebsockets() {
 			s.stateMutex.Unlock()
 
 			// Broadcast to all clients
-			for client := range s.clients {
-				select {
-				case client.send <- message:
-					// Message sent successfully
-				default:
-					// Client too slow, disconnect to prevent Hub blocking
-					// This ensures reliable 24/7 operation by pruning dead/slow consumers
-					delete(s.clients, client)
-					close(client.send)
-				}
+			s.sendToClients(message)
+
+		case message := <-s.messages:
+			// Screen updates only go to the screen's subscribers
+			if screen, ok := message.(models.MScreenMessage); ok {
+				s.sendToScreenSubscribers(screen)
+				continue
 			}
+			s.sendToClients(message)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// sendToClients queues a message on every client (called from the Hub loop only)
+func (s *FastAPIServer) sendToClients(message interface{}) {
+	for client := range s.clients {
+		select {
+		case client.send <- message:
+			// Message sent successfully
+		default:
+			// Client too slow, disconnect to prevent Hub blocking
+			// This ensures reliable 24/7 operation by pruning dead/slow consumers
+			delete(s.clients, client)
+			close(client.send)
 		}
 	}
 }
@@ -71,11 +86,11 @@ func (s *FastAPIServer) UpdateAllDatas(data interface{}) {
 
 	newRaw := safeStockPriceMap(dataMap, "raw_data")
 	newAggs := safeAggregationsMap(dataMap, "aggregations")
+	newClosed := safeAggregationsMap(dataMap, "closed_aggregations")
 	newTs := safeInt64(dataMap, "timestamp")
 	newMetrics := safeProcessingMetrics(dataMap, "processing_metrics")
 
 	s.stateMutex.Lock()
-	defer s.stateMutex.Unlock()
 
 	// 1. Merge Raw Data
 	if s.latestState.RawData == nil {
@@ -100,10 +115,32 @@ func (s *FastAPIServer) UpdateAllDatas(data interface{}) {
 		}
 	}
 
+	// Candles closed without a newer open one (closed by the clock) replace
+	// their open version
+	for sym, windows := range newClosed {
+		if s.latestState.Aggregations[sym] == nil {
+			s.latestState.Aggregations[sym] = make(map[string][]models.MAggregation)
+		}
+		for wName, closed := range windows {
+			if len(closed) == 0 || len(newAggs[sym][wName]) > 0 {
+				continue
+			}
+			last := closed[len(closed)-1]
+			if current := s.latestState.Aggregations[sym][wName]; len(current) > 0 && current[len(current)-1].StartTime > last.StartTime {
+				continue
+			}
+			s.latestState.Aggregations[sym][wName] = []models.MAggregation{last}
+		}
+	}
+
 	// 3. Update Metadata
 	s.latestState.Timestamp = newTs
 	s.latestState.ProcessingMetrics = newMetrics
 	s.latestState.Type = "UPDATE"
+	s.stateMutex.Unlock()
+
+	// 4. Re-run saved screens on the merged state
+	s.refreshScreens()
 }
 
 // -----------------------------------------------------------------------------`
@@ -121,11 +158,16 @@ func (s *FastAPIServer) Broadcast(message interface{}) {
 	// Convert to strongly typed structure BEFORE entering the channel
 	// This optimization prevents the Hub from doing data processing
 	state := &models.MLatestData{
-		Type:              "UPDATE",
-		RawData:           safeStockPriceMap(dataMap, "raw_data"),
-		Aggregations:      safeAggregationsMap(dataMap, "aggregations"),
-		Timestamp:         safeInt64(dataMap, "timestamp"),
-		ProcessingMetrics: safeProcessingMetrics(dataMap, "processing_metrics"),
+		Type:               "UPDATE",
+		RawData:            safeStockPriceMap(dataMap, "raw_data"),
+		Aggregations:       safeAggregationsMap(dataMap, "aggregations"),
+		ClosedAggregations: safeAggregationsMap(dataMap, "closed_aggregations"),
+		Alerts:             safeSlice[models.MAlertEvent](dataMap, "alerts"),
+		Changepoints:       safeSlice[models.MChangepointEvent](dataMap, "changepoints"),
+		Patterns:           safeSlice[models.MPatternEvent](dataMap, "patterns"),
+		LevelBreaks:        safeSlice[models.MLevelBreakEvent](dataMap, "level_breaks"),
+		Timestamp:          safeInt64(dataMap, "timestamp"),
+		ProcessingMetrics:  safeProcessingMetrics(dataMap, "processing_metrics"),
 	}
 
 	// Non-blocking send if buffer is full (optional, but safer for "prevent lock")
@@ -135,6 +177,14 @@ func (s *FastAPIServer) Broadcast(message interface{}) {
 	s.broadcast <- state
 }
 
+// -----------------------------------------------------------------------------
+
+// BroadcastMessage - queues a self-describing message (own "type") for all clients
+// without touching the cached state
+func (s *FastAPIServer) BroadcastMessage(message interface{}) {
+	s.messages <- message
+}
+
 // -----------------------------------------------------------------------------
 // Helper Methods
 // -----------------------------------------------------------------------------
@@ -194,6 +244,10 @@ func (s *FastAPIServer) HandleClientMessage(client *Client, message []byte) {
 		return
 	}
 
+	if cmd.Command == "subscribe_screen" || cmd.Command == "unsubscribe_screen" {
+		s.handleScreenCommand(client, cmd)
+		return
+	}
 	if cmd.Command != "subscribe" {
 		return
 	}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"market-observer/src/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// -----------------------------------------------------------------------------
+// Lead-lag endpoints (cross-correlation of symbol pairs)
+// -----------------------------------------------------------------------------
+
+// SetLeadLagProvider wires the provider used by the /api/lead-lag routes
+func (s *FastAPIServer) SetLeadLagProvider(provider interfaces.ILeadLagProvider) {
+	s.leadLag = provider
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) listLeadLag(c *gin.Context) {
+	if s.leadLag == nil {
+		c.JSON(503, gin.H{"error": "lead-lag analysis not available"})
+		return
+	}
+	c.JSON(200, gin.H{"pairs": s.leadLag.Results(c.Query("symbol"))})
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) getLeadLagPair(c *gin.Context) {
+	if s.leadLag == nil {
+		c.JSON(503, gin.H{"error": "lead-lag analysis not available"})
+		return
+	}
+
+	a, b := c.Param("a"), c.Param("b")
+	result, ok := s.leadLag.Pair(a, b)
+	if !ok {
+		c.JSON(404, gin.H{"error": "no lead-lag estimate for " + a + "/" + b})
+		return
+	}
+	c.JSON(200, result)
+}
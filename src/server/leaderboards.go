@@ -0,0 +1,51 @@
+package server
+
+import (
+	"strconv"
+
+	"market-observer/src/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// -----------------------------------------------------------------------------
+// Leaderboard endpoints (top entries of every board + full ranking of one board)
+// -----------------------------------------------------------------------------
+
+// SetLeaderboardProvider wires the provider used by the /api/leaderboards routes
+func (s *FastAPIServer) SetLeaderboardProvider(provider interfaces.ILeaderboardProvider) {
+	s.leaderboards = provider
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) getLeaderboards(c *gin.Context) {
+	if s.leaderboards == nil {
+		c.JSON(503, gin.H{"error": "leaderboards not available"})
+		return
+	}
+	c.JSON(200, s.leaderboards.Snapshot(c.Query("window")))
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) getLeaderboardRanking(c *gin.Context) {
+	if s.leaderboards == nil {
+		c.JSON(503, gin.H{"error": "leaderboards not available"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "limit must be an integer"})
+		return
+	}
+
+	window, board := c.Param("window"), c.Param("board")
+	entries, ok := s.leaderboards.Ranking(window, board, limit)
+	if !ok {
+		c.JSON(404, gin.H{"error": "unknown leaderboard " + window + "/" + board})
+		return
+	}
+	c.JSON(200, gin.H{"window": window, "board": board, "entries": entries})
+}
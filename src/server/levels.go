@@ -0,0 +1,61 @@
+package server
+
+import (
+	"strconv"
+
+	"market-observer/src/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// -----------------------------------------------------------------------------
+// Support/resistance endpoints (current levels per symbol + recent breaks)
+// -----------------------------------------------------------------------------
+
+// SetLevelProvider wires the provider used by the /api/levels routes
+func (s *FastAPIServer) SetLevelProvider(provider interfaces.ILevelProvider) {
+	s.levels = provider
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) listLevels(c *gin.Context) {
+	if s.levels == nil {
+		c.JSON(503, gin.H{"error": "support/resistance levels not available"})
+		return
+	}
+	c.JSON(200, gin.H{"levels": s.levels.All()})
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) getSymbolLevels(c *gin.Context) {
+	if s.levels == nil {
+		c.JSON(503, gin.H{"error": "support/resistance levels not available"})
+		return
+	}
+
+	symbol := c.Param("symbol")
+	levels, ok := s.levels.Levels(symbol)
+	if !ok {
+		c.JSON(404, gin.H{"error": "no levels for " + symbol})
+		return
+	}
+	c.JSON(200, levels)
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) listLevelBreaks(c *gin.Context) {
+	if s.levels == nil {
+		c.JSON(503, gin.H{"error": "support/resistance levels not available"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "limit must be an integer"})
+		return
+	}
+	c.JSON(200, gin.H{"breaks": s.levels.Recent(c.Query("symbol"), c.Query("type"), limit)})
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"strconv"
+
+	"market-observer/src/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// -----------------------------------------------------------------------------
+// Notification endpoints (delivery log + test send)
+// -----------------------------------------------------------------------------
+
+// SetNotifier wires the notification dispatcher used by the /api/notifications routes
+func (s *FastAPIServer) SetNotifier(notifier interfaces.INotifier) {
+	s.notifier = notifier
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) listNotificationDeliveries(c *gin.Context) {
+	if s.notifier == nil {
+		c.JSON(503, gin.H{"error": "notifications not available"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "limit must be an integer"})
+		return
+	}
+	c.JSON(200, gin.H{"deliveries": s.notifier.Deliveries(limit)})
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) sendTestNotification(c *gin.Context) {
+	if s.notifier == nil {
+		c.JSON(503, gin.H{"error": "notifications not available"})
+		return
+	}
+	c.JSON(202, gin.H{"status": "queued", "channels": s.notifier.SendTest()})
+}
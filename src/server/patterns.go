@@ -0,0 +1,34 @@
+package server
+
+import (
+	"strconv"
+
+	"market-observer/src/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// -----------------------------------------------------------------------------
+// Candlestick pattern endpoints (recent patterns on closed candles)
+// -----------------------------------------------------------------------------
+
+// SetPatternProvider wires the provider used by the /api/patterns route
+func (s *FastAPIServer) SetPatternProvider(provider interfaces.IPatternProvider) {
+	s.patterns = provider
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) listPatterns(c *gin.Context) {
+	if s.patterns == nil {
+		c.JSON(503, gin.H{"error": "candle pattern detection not available"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "limit must be an integer"})
+		return
+	}
+	c.JSON(200, gin.H{"patterns": s.patterns.Recent(c.Query("symbol"), c.Query("window"), c.Query("pattern"), limit)})
+}
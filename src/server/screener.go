@@ -0,0 +1,237 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"market-observer/src/interfaces"
+	"market-observer/src/models"
+	"market-observer/src/screener"
+
+	"github.com/gin-gonic/gin"
+)
+
+// -----------------------------------------------------------------------------
+// Screener endpoints (ad-hoc screens, saved screens CRUD) and screen pushes
+// -----------------------------------------------------------------------------
+
+// SetScreener wires the screener used by the /api/screener routes and the
+// "subscribe_screen" WebSocket command; saved screens start from the current state
+func (s *FastAPIServer) SetScreener(provider interfaces.IScreener) {
+	s.screener = provider
+	s.refreshScreens()
+}
+
+// -----------------------------------------------------------------------------
+
+// refreshScreens re-runs the saved screens on the latest candle of every
+// symbol/window and queues the membership changes for the subscribers
+func (s *FastAPIServer) refreshScreens() {
+	if s.screener == nil {
+		return
+	}
+
+	s.stateMutex.RLock()
+	candles := make(map[string]map[string]models.MAggregation, len(s.latestState.Aggregations))
+	for sym, windows := range s.latestState.Aggregations {
+		latest := make(map[string]models.MAggregation, len(windows))
+		for wName, list := range windows {
+			if len(list) > 0 {
+				latest[wName] = list[len(list)-1]
+			}
+		}
+		candles[sym] = latest
+	}
+	timestamp := s.latestState.Timestamp
+	s.stateMutex.RUnlock()
+
+	for _, message := range s.screener.Refresh(candles, timestamp) {
+		s.messages <- message
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// sendToScreenSubscribers queues a screen message on the clients subscribed to
+// the screen (called from the Hub loop only)
+func (s *FastAPIServer) sendToScreenSubscribers(message models.MScreenMessage) {
+	for client := range s.clients {
+		if !client.isSubscribedToScreen(message.ScreenID) {
+			continue
+		}
+		select {
+		case client.send <- message:
+		default:
+			// Client too slow, disconnect to prevent Hub blocking
+			delete(s.clients, client)
+			close(client.send)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// handleScreenCommand handles "subscribe_screen" / "unsubscribe_screen"; a new
+// subscriber receives the current members right away
+func (s *FastAPIServer) handleScreenCommand(client *Client, cmd models.MSubscribeCommand) {
+	if cmd.Command == "unsubscribe_screen" {
+		client.unsubscribeScreen(cmd.ScreenID)
+		return
+	}
+
+	if s.screener == nil {
+		s.Logger.Info("Screen subscription ignored: screener not available")
+		return
+	}
+	snapshot, err := s.screener.Snapshot(cmd.ScreenID)
+	if err != nil {
+		s.Logger.Info("Screen subscription to %d rejected: %v", cmd.ScreenID, err)
+		return
+	}
+	client.subscribeScreen(cmd.ScreenID)
+
+	select {
+	case client.send <- snapshot:
+	default:
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) runScreen(c *gin.Context) {
+	if s.screener == nil {
+		c.JSON(503, gin.H{"error": "screener not available"})
+		return
+	}
+
+	var request models.MScreenRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := s.screener.Run(request)
+	if err != nil {
+		c.JSON(screenErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, result)
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) listScreens(c *gin.Context) {
+	if s.screener == nil {
+		c.JSON(503, gin.H{"error": "screener not available"})
+		return
+	}
+	c.JSON(200, gin.H{"screens": s.screener.ListScreens()})
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) createScreen(c *gin.Context) {
+	if s.screener == nil {
+		c.JSON(503, gin.H{"error": "screener not available"})
+		return
+	}
+
+	var screen models.MSavedScreen
+	if err := c.ShouldBindJSON(&screen); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := s.screener.CreateScreen(screen)
+	if err != nil {
+		c.JSON(screenErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(201, created)
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) getScreenResults(c *gin.Context) {
+	if s.screener == nil {
+		c.JSON(503, gin.H{"error": "screener not available"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid screen id"})
+		return
+	}
+
+	snapshot, err := s.screener.Snapshot(id)
+	if err != nil {
+		c.JSON(screenErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, snapshot)
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) updateScreen(c *gin.Context) {
+	if s.screener == nil {
+		c.JSON(503, gin.H{"error": "screener not available"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid screen id"})
+		return
+	}
+
+	var screen models.MSavedScreen
+	if err := c.ShouldBindJSON(&screen); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	screen.ID = id
+
+	updated, err := s.screener.UpdateScreen(screen)
+	if err != nil {
+		c.JSON(screenErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, updated)
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) deleteScreen(c *gin.Context) {
+	if s.screener == nil {
+		c.JSON(503, gin.H{"error": "screener not available"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid screen id"})
+		return
+	}
+
+	if err := s.screener.DeleteScreen(id); err != nil {
+		c.JSON(screenErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"status": "deleted", "id": id})
+}
+
+// -----------------------------------------------------------------------------
+
+// screenErrorStatus maps screener errors to HTTP status codes
+func screenErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, screener.ErrScreenNotFound):
+		return 404
+	case errors.Is(err, screener.ErrInvalidScreen):
+		return 400
+	default:
+		return 500
+	}
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"market-observer/src/interfaces"
+	"market-observer/src/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// -----------------------------------------------------------------------------
+// Inbound validation endpoints (counters, quarantine review and release)
+// -----------------------------------------------------------------------------
+
+// SetValidationManager wires the validation stage used by the /api/validation routes
+func (s *FastAPIServer) SetValidationManager(validator interfaces.IValidationManager) {
+	s.validator = validator
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) getValidationStats(c *gin.Context) {
+	if s.validator == nil {
+		c.JSON(503, gin.H{"error": "validation not available"})
+		return
+	}
+
+	total, perSymbol := s.validator.Stats()
+	c.JSON(200, gin.H{"total": total, "symbols": perSymbol})
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) listQuarantine(c *gin.Context) {
+	if s.validator == nil {
+		c.JSON(503, gin.H{"error": "validation not available"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "limit must be an integer"})
+		return
+	}
+	c.JSON(200, gin.H{"quarantined": s.validator.Quarantined(c.Query("symbol"), limit)})
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) releaseQuarantined(c *gin.Context) {
+	if s.validator == nil {
+		c.JSON(503, gin.H{"error": "validation not available"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid quarantine id"})
+		return
+	}
+
+	released, err := s.validator.Release(id)
+	if err != nil {
+		c.JSON(releaseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"status": "released", "point": released})
+}
+
+// -----------------------------------------------------------------------------
+
+// releaseErrorStatus maps validation errors to HTTP status codes
+func releaseErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, validation.ErrQuarantineNotFound):
+		return 404
+	case errors.Is(err, validation.ErrReleaseQueueFull):
+		return 503
+	case errors.Is(err, validation.ErrReleaseTooLate):
+		return 409
+	default:
+		return 500
+	}
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"market-observer/src/interfaces"
+	"market-observer/src/models"
+	"market-observer/src/profile"
+
+	"github.com/gin-gonic/gin"
+)
+
+// -----------------------------------------------------------------------------
+// Volume profile endpoint (volume by price of a session or time range)
+// -----------------------------------------------------------------------------
+
+// SetVolumeProfiler wires the profiler used by the /api/volume-profile route
+func (s *FastAPIServer) SetVolumeProfiler(profiler interfaces.IVolumeProfiler) {
+	s.volumeProfile = profiler
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *FastAPIServer) getVolumeProfile(c *gin.Context) {
+	if s.volumeProfile == nil {
+		c.JSON(503, gin.H{"error": "volume profile not available"})
+		return
+	}
+
+	request := models.MVolumeProfileRequest{
+		Symbol:  c.Param("symbol"),
+		Session: c.Query("session"),
+	}
+	var err error
+	if request.From, err = strconv.ParseInt(c.DefaultQuery("from", "0"), 10, 64); err != nil {
+		c.JSON(400, gin.H{"error": "from must be a unix timestamp"})
+		return
+	}
+	if request.To, err = strconv.ParseInt(c.DefaultQuery("to", "0"), 10, 64); err != nil {
+		c.JSON(400, gin.H{"error": "to must be a unix timestamp"})
+		return
+	}
+	if request.Buckets, err = strconv.Atoi(c.DefaultQuery("buckets", "0")); err != nil {
+		c.JSON(400, gin.H{"error": "buckets must be an integer"})
+		return
+	}
+	if request.BucketSize, err = strconv.ParseFloat(c.DefaultQuery("bucket_size", "0"), 64); err != nil {
+		c.JSON(400, gin.H{"error": "bucket_size must be a number"})
+		return
+	}
+	if request.ValueAreaPct, err = strconv.ParseFloat(c.DefaultQuery("value_area_pct", "0"), 64); err != nil {
+		c.JSON(400, gin.H{"error": "value_area_pct must be a number"})
+		return
+	}
+
+	result, err := s.volumeProfile.Profile(request)
+	if err != nil {
+		c.JSON(profileErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, result)
+}
+
+// -----------------------------------------------------------------------------
+
+// profileErrorStatus maps volume profile errors to HTTP status codes
+func profileErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, profile.ErrNoProfileData):
+		return 404
+	case errors.Is(err, profile.ErrInvalidProfile):
+		return 400
+	default:
+		return 500
+	}
+}
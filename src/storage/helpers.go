@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+
+// metricsJSON encodes analyzer metrics for the metrics_json column ("" when empty)
+func metricsJSON(metrics map[string]float64) string {
+	if len(metrics) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(metrics)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// -----------------------------------------------------------------------------
+
+// patternsText encodes candlestick patterns for the patterns column ("" when none)
+func patternsText(patterns []string) string {
+	return strings.Join(patterns, ",")
+}
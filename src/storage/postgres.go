@@ -6,6 +6,7 @@ import (
 	"log"
 	"market-observer/src/logger"
 	"market-observer/src/models"
+	"market-observer/src/utils"
 	"os"
 	"path/filepath"
 	"strings"
@@ -102,6 +103,9 @@ func (d *PostgresDB) recreateTables() error {
 			volume DOUBLE PRECISION,
 			price_percent_change DOUBLE PRECISION,
 			volume_percent_change DOUBLE PRECISION,
+			open DOUBLE PRECISION,
+			high DOUBLE PRECISION,
+			low DOUBLE PRECISION,
 			PRIMARY KEY (symbol, timestamp)
 		);
 	`, d.Schema)
@@ -112,7 +116,7 @@ func (d *PostgresDB) recreateTables() error {
 	// Dynamic tables for each window
 	for _, w := range d.Config.WindowsAgg {
 		// Aggregations
-		aggTable := fmt.Sprintf(`"%s"."aggregations_%s"`, d.Schema, w)
+		aggTable := fmt.Sprintf(`"%s"."aggregations_%s"`, d.Schema, utils.WindowTableSuffix(w))
 		if _, err := d.DB.Exec(fmt.Sprintf(`DROP TABLE IF EXISTS %s`, aggTable)); err != nil {
 			return fmt.Errorf("failed to drop %s: %w", aggTable, err)
 		}
@@ -129,6 +133,36 @@ func (d *PostgresDB) recreateTables() error {
 				volume DOUBLE PRECISION,
 				price_percent_change DOUBLE PRECISION,
 				volume_percent_change DOUBLE PRECISION,
+				vwap DOUBLE PRECISION,
+				session_vwap DOUBLE PRECISION,
+				session_vwap_upper_1 DOUBLE PRECISION,
+				session_vwap_lower_1 DOUBLE PRECISION,
+				session_vwap_upper_2 DOUBLE PRECISION,
+				session_vwap_lower_2 DOUBLE PRECISION,
+				missing_bars INTEGER,
+				filled_bars INTEGER,
+				is_complete BOOLEAN,
+				metrics_json TEXT,
+				benchmark TEXT,
+				benchmark_return DOUBLE PRECISION,
+				excess_return DOUBLE PRECISION,
+				relative_strength DOUBLE PRECISION,
+				beta DOUBLE PRECISION,
+				alpha DOUBLE PRECISION,
+				residual_return DOUBLE PRECISION,
+				residual_zscore DOUBLE PRECISION,
+				realized_vol DOUBLE PRECISION,
+				parkinson_vol DOUBLE PRECISION,
+				garman_klass_vol DOUBLE PRECISION,
+				yang_zhang_vol DOUBLE PRECISION,
+				vol_percentile DOUBLE PRECISION,
+				high_vol_regime BOOLEAN,
+				low_vol_regime BOOLEAN,
+				expected_volume DOUBLE PRECISION,
+				volume_slot_zscore DOUBLE PRECISION,
+				true_ohlc BOOLEAN,
+				patterns TEXT,
+				volume_anomaly_ratio DOUBLE PRECISION,
 				PRIMARY KEY (symbol, start_time)
 			);
 		`, aggTable)
@@ -136,18 +170,16 @@ func (d *PostgresDB) recreateTables() error {
 			return fmt.Errorf("failed to create %s: %w", aggTable, err)
 		}
 
-		// Intermediate Stats
-		statsTable := fmt.Sprintf(`"%s"."intermediate_stats_%s"`, d.Schema, w)
-		if _, err := d.DB.Exec(fmt.Sprintf(`DROP TABLE IF EXISTS %s`, statsTable)); err != nil {
-			return fmt.Errorf("failed to drop %s: %w", statsTable, err)
-		}
-
+		// Intermediate Stats (kept across restarts so rolling stats can be reloaded)
+		statsTable := fmt.Sprintf(`"%s"."intermediate_stats_%s"`, d.Schema, utils.WindowTableSuffix(w))
 		query = fmt.Sprintf(`
-			CREATE TABLE %s (
+			CREATE TABLE IF NOT EXISTS %s (
 				symbol TEXT,
 				window_name TEXT,
 				avg_volume_history DOUBLE PRECISION,
 				std_volume_history DOUBLE PRECISION,
+				avg_return_history DOUBLE PRECISION DEFAULT 0,
+				std_return_history DOUBLE PRECISION DEFAULT 0,
 				data_points_history INTEGER,
 				last_history_timestamp BIGINT,
 				updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -157,6 +189,16 @@ func (d *PostgresDB) recreateTables() error {
 		if _, err := d.DB.Exec(query); err != nil {
 			return fmt.Errorf("failed to create %s: %w", statsTable, err)
 		}
+
+		// Tables created by older versions lack the return columns
+		query = fmt.Sprintf(`
+			ALTER TABLE %s
+				ADD COLUMN IF NOT EXISTS avg_return_history DOUBLE PRECISION DEFAULT 0,
+				ADD COLUMN IF NOT EXISTS std_return_history DOUBLE PRECISION DEFAULT 0;
+		`, statsTable)
+		if _, err := d.DB.Exec(query); err != nil {
+			return fmt.Errorf("failed to migrate %s: %w", statsTable, err)
+		}
 	}
 
 	// Create symbols table (Config/Metadata)
@@ -180,7 +222,43 @@ func (d *PostgresDB) recreateTables() error {
 		return fmt.Errorf("failed to create %s: %w", symbolsTable, err)
 	}
 
-	return nil
+	// Alert rules and events
+	if err := d.createAlertTables(); err != nil {
+		return err
+	}
+
+	// Quarantined inbound points
+	if err := d.createValidationTables(); err != nil {
+		return err
+	}
+
+	// Market breadth time series
+	if err := d.createBreadthTables(); err != nil {
+		return err
+	}
+
+	// Regime changes
+	if err := d.createChangepointTables(); err != nil {
+		return err
+	}
+
+	// Candlestick patterns
+	if err := d.createPatternTables(); err != nil {
+		return err
+	}
+
+	// Support/resistance breaks
+	if err := d.createLevelTables(); err != nil {
+		return err
+	}
+
+	// Cluster jumps
+	if err := d.createClusterTables(); err != nil {
+		return err
+	}
+
+	// Saved screens
+	return d.createScreenTables()
 }
 
 // -----------------------------------------------------------------------------
@@ -197,8 +275,8 @@ func (d *PostgresDB) SaveStockPricesBulk(prices []models.MStockPrice) error {
 	defer tx.Rollback()
 
 	query := fmt.Sprintf(`
-		INSERT INTO "%s"."stock_prices" (symbol, timestamp, price, volume, price_percent_change, volume_percent_change)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO "%s"."stock_prices" (symbol, timestamp, price, volume, price_percent_change, volume_percent_change, open, high, low)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`, d.Schema)
 	stmt, err := tx.Prepare(query)
 	if err != nil {
@@ -207,7 +285,7 @@ func (d *PostgresDB) SaveStockPricesBulk(prices []models.MStockPrice) error {
 	defer stmt.Close()
 
 	for _, p := range prices {
-		_, err := stmt.Exec(p.Symbol, p.Timestamp, p.Price, p.Volume, p.PricePercentChange, p.VolumePercentChange)
+		_, err := stmt.Exec(p.Symbol, p.Timestamp, p.Price, p.Volume, p.PricePercentChange, p.VolumePercentChange, p.Open, p.High, p.Low)
 		if err != nil {
 			return err
 		}
@@ -218,6 +296,29 @@ func (d *PostgresDB) SaveStockPricesBulk(prices []models.MStockPrice) error {
 
 // -----------------------------------------------------------------------------
 
+func (d *PostgresDB) LoadStockPrices(symbol string, from, to int64) ([]models.MStockPrice, error) {
+	rows, err := d.DB.Query(fmt.Sprintf(`
+		SELECT timestamp, price, volume, price_percent_change, volume_percent_change, open, high, low
+		FROM "%s"."stock_prices" WHERE symbol = $1 AND timestamp >= $2 AND timestamp < $3 ORDER BY timestamp
+	`, d.Schema), symbol, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stock_prices: %w", err)
+	}
+	defer rows.Close()
+
+	var prices []models.MStockPrice
+	for rows.Next() {
+		p := models.MStockPrice{Symbol: symbol}
+		if err := rows.Scan(&p.Timestamp, &p.Price, &p.Volume, &p.PricePercentChange, &p.VolumePercentChange, &p.Open, &p.High, &p.Low); err != nil {
+			return nil, fmt.Errorf("failed to scan stock_prices: %w", err)
+		}
+		prices = append(prices, p)
+	}
+	return prices, rows.Err()
+}
+
+// -----------------------------------------------------------------------------
+
 func (d *PostgresDB) SaveAggregations(aggs map[string]map[string][]models.MAggregation) error {
 	tx, err := d.DB.Begin()
 	if err != nil {
@@ -230,12 +331,54 @@ func (d *PostgresDB) SaveAggregations(aggs map[string]map[string][]models.MAggre
 			if len(items) == 0 {
 				continue
 			}
-			tableName := fmt.Sprintf(`"%s"."aggregations_%s"`, d.Schema, w)
+			tableName := fmt.Sprintf(`"%s"."aggregations_%s"`, d.Schema, utils.WindowTableSuffix(w))
 
 			// Simple loop insert for now. Copy would be faster but more complex to setup.
 			query := fmt.Sprintf(`
-				INSERT INTO %s (symbol, start_time, end_time, open, high, low, close, volume, price_percent_change, volume_percent_change)
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+				INSERT INTO %s (symbol, start_time, end_time, open, high, low, close, volume, price_percent_change, volume_percent_change, vwap, session_vwap, session_vwap_upper_1, session_vwap_lower_1, session_vwap_upper_2, session_vwap_lower_2, missing_bars, filled_bars, is_complete, metrics_json,
+					benchmark, benchmark_return, excess_return, relative_strength, beta, alpha, residual_return, residual_zscore,
+					realized_vol, parkinson_vol, garman_klass_vol, yang_zhang_vol, vol_percentile, high_vol_regime, low_vol_regime,
+					expected_volume, volume_slot_zscore, true_ohlc, patterns, volume_anomaly_ratio)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40)
+				ON CONFLICT (symbol, start_time) DO UPDATE SET
+					end_time = EXCLUDED.end_time,
+					open = EXCLUDED.open,
+					high = EXCLUDED.high,
+					low = EXCLUDED.low,
+					close = EXCLUDED.close,
+					volume = EXCLUDED.volume,
+					price_percent_change = EXCLUDED.price_percent_change,
+					volume_percent_change = EXCLUDED.volume_percent_change,
+					vwap = EXCLUDED.vwap,
+					session_vwap = EXCLUDED.session_vwap,
+					session_vwap_upper_1 = EXCLUDED.session_vwap_upper_1,
+					session_vwap_lower_1 = EXCLUDED.session_vwap_lower_1,
+					session_vwap_upper_2 = EXCLUDED.session_vwap_upper_2,
+					session_vwap_lower_2 = EXCLUDED.session_vwap_lower_2,
+					missing_bars = EXCLUDED.missing_bars,
+					filled_bars = EXCLUDED.filled_bars,
+					is_complete = EXCLUDED.is_complete,
+					metrics_json = EXCLUDED.metrics_json,
+					benchmark = EXCLUDED.benchmark,
+					benchmark_return = EXCLUDED.benchmark_return,
+					excess_return = EXCLUDED.excess_return,
+					relative_strength = EXCLUDED.relative_strength,
+					beta = EXCLUDED.beta,
+					alpha = EXCLUDED.alpha,
+					residual_return = EXCLUDED.residual_return,
+					residual_zscore = EXCLUDED.residual_zscore,
+					realized_vol = EXCLUDED.realized_vol,
+					parkinson_vol = EXCLUDED.parkinson_vol,
+					garman_klass_vol = EXCLUDED.garman_klass_vol,
+					yang_zhang_vol = EXCLUDED.yang_zhang_vol,
+					vol_percentile = EXCLUDED.vol_percentile,
+					high_vol_regime = EXCLUDED.high_vol_regime,
+					low_vol_regime = EXCLUDED.low_vol_regime,
+					expected_volume = EXCLUDED.expected_volume,
+					volume_slot_zscore = EXCLUDED.volume_slot_zscore,
+					true_ohlc = EXCLUDED.true_ohlc,
+					patterns = EXCLUDED.patterns,
+					volume_anomaly_ratio = EXCLUDED.volume_anomaly_ratio
 			`, tableName)
 
 			stmt, err := tx.Prepare(query)
@@ -252,7 +395,12 @@ func (d *PostgresDB) SaveAggregations(aggs map[string]map[string][]models.MAggre
 			defer stmt.Close()
 
 			for _, agg := range items {
-				_, err = stmt.Exec(agg.Symbol, agg.StartTime, agg.EndTime, agg.Open, agg.High, agg.Low, agg.Close, agg.Volume, agg.PricePercentChange, agg.VolumePercentChange)
+				_, err = stmt.Exec(agg.Symbol, agg.StartTime, agg.EndTime, agg.Open, agg.High, agg.Low, agg.Close, agg.Volume, agg.PricePercentChange, agg.VolumePercentChange,
+					agg.VWAP, agg.SessionVWAP, agg.SessionVWAPUpper1, agg.SessionVWAPLower1, agg.SessionVWAPUpper2, agg.SessionVWAPLower2,
+					agg.MissingBars, agg.FilledBars, agg.IsComplete, metricsJSON(agg.Metrics),
+					agg.Benchmark, agg.BenchmarkReturn, agg.ExcessReturn, agg.RelativeStrength, agg.Beta, agg.Alpha, agg.ResidualReturn, agg.ResidualZScore,
+					agg.RealizedVol, agg.ParkinsonVol, agg.GarmanKlassVol, agg.YangZhangVol, agg.VolPercentile, agg.HighVolRegime, agg.LowVolRegime,
+					agg.ExpectedVolume, agg.VolumeSlotZScore, agg.TrueOHLC, patternsText(agg.Patterns), agg.VolumeAnomalyRatio)
 				if err != nil {
 					return err
 				}
@@ -265,6 +413,35 @@ func (d *PostgresDB) SaveAggregations(aggs map[string]map[string][]models.MAggre
 
 // -----------------------------------------------------------------------------
 
+func (d *PostgresDB) LoadAggregations(window string, from, to int64) ([]models.MAggregation, error) {
+	tableName := fmt.Sprintf(`"%s"."aggregations_%s"`, d.Schema, utils.WindowTableSuffix(window))
+	rows, err := d.DB.Query(fmt.Sprintf(`
+		SELECT symbol, start_time, end_time, open, high, low, close, volume, price_percent_change, volume_anomaly_ratio,
+			benchmark, benchmark_return, beta
+		FROM %s WHERE start_time >= $1 AND start_time < $2 ORDER BY symbol, start_time
+	`, tableName), from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", tableName, err)
+	}
+	defer rows.Close()
+
+	var aggs []models.MAggregation
+	for rows.Next() {
+		a := models.MAggregation{WindowName: window}
+		var ratio, benchmarkReturn, beta sql.NullFloat64
+		var benchmark sql.NullString
+		if err := rows.Scan(&a.Symbol, &a.StartTime, &a.EndTime, &a.Open, &a.High, &a.Low, &a.Close, &a.Volume, &a.PricePercentChange, &ratio,
+			&benchmark, &benchmarkReturn, &beta); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", tableName, err)
+		}
+		a.VolumeAnomalyRatio, a.Benchmark, a.BenchmarkReturn, a.Beta = ratio.Float64, benchmark.String, benchmarkReturn.Float64, beta.Float64
+		aggs = append(aggs, a)
+	}
+	return aggs, rows.Err()
+}
+
+// -----------------------------------------------------------------------------
+
 func (d *PostgresDB) SaveIntermediateStats(stats []models.MIntermediateStats) error {
 	if len(stats) == 0 {
 		return nil
@@ -283,14 +460,16 @@ func (d *PostgresDB) SaveIntermediateStats(stats []models.MIntermediateStats) er
 	}
 
 	for w, list := range statsByWindow {
-		tableName := fmt.Sprintf(`"%s"."intermediate_stats_%s"`, d.Schema, w)
+		tableName := fmt.Sprintf(`"%s"."intermediate_stats_%s"`, d.Schema, utils.WindowTableSuffix(w))
 
 		query := fmt.Sprintf(`
-			INSERT INTO %s (symbol, window_name, avg_volume_history, std_volume_history, data_points_history, last_history_timestamp, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			INSERT INTO %s (symbol, window_name, avg_volume_history, std_volume_history, avg_return_history, std_return_history, data_points_history, last_history_timestamp, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 			ON CONFLICT (symbol, window_name) DO UPDATE SET
 				avg_volume_history = EXCLUDED.avg_volume_history,
 				std_volume_history = EXCLUDED.std_volume_history,
+				avg_return_history = EXCLUDED.avg_return_history,
+				std_return_history = EXCLUDED.std_return_history,
 				data_points_history = EXCLUDED.data_points_history,
 				last_history_timestamp = EXCLUDED.last_history_timestamp,
 				updated_at = EXCLUDED.updated_at
@@ -303,7 +482,7 @@ func (d *PostgresDB) SaveIntermediateStats(stats []models.MIntermediateStats) er
 		defer stmt.Close()
 
 		for _, s := range list {
-			_, err = stmt.Exec(s.Symbol, s.WindowName, s.AvgVolumeHistory, s.StdVolumeHistory, s.DataPointsHistory, s.LastHistoryTimestamp, time.Now().UTC())
+			_, err = stmt.Exec(s.Symbol, s.WindowName, s.AvgVolumeHistory, s.StdVolumeHistory, s.AvgReturnHistory, s.StdReturnHistory, s.DataPointsHistory, s.LastHistoryTimestamp, time.Now().UTC())
 			if err != nil {
 				return err
 			}
@@ -315,6 +494,36 @@ func (d *PostgresDB) SaveIntermediateStats(stats []models.MIntermediateStats) er
 
 // -----------------------------------------------------------------------------
 
+func (d *PostgresDB) LoadIntermediateStats() ([]models.MIntermediateStats, error) {
+	var stats []models.MIntermediateStats
+
+	for _, w := range d.Config.WindowsAgg {
+		tableName := fmt.Sprintf(`"%s"."intermediate_stats_%s"`, d.Schema, utils.WindowTableSuffix(w))
+
+		rows, err := d.DB.Query(fmt.Sprintf(`
+			SELECT symbol, window_name, avg_volume_history, std_volume_history, avg_return_history, std_return_history, data_points_history, last_history_timestamp
+			FROM %s
+		`, tableName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", tableName, err)
+		}
+
+		for rows.Next() {
+			var s models.MIntermediateStats
+			if err := rows.Scan(&s.Symbol, &s.WindowName, &s.AvgVolumeHistory, &s.StdVolumeHistory, &s.AvgReturnHistory, &s.StdReturnHistory, &s.DataPointsHistory, &s.LastHistoryTimestamp); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan %s: %w", tableName, err)
+			}
+			stats = append(stats, s)
+		}
+		rows.Close()
+	}
+
+	return stats, nil
+}
+
+// -----------------------------------------------------------------------------
+
 func (d *PostgresDB) CleanupOldData() error {
 	retentionDays := d.Config.DataSource.DataRetentionDays
 	cutoff := time.Now().UTC().AddDate(0, 0, -retentionDays).Unix()
@@ -328,12 +537,37 @@ func (d *PostgresDB) CleanupOldData() error {
 
 	// Clean aggregation tables
 	for _, w := range d.Config.WindowsAgg {
-		tableName := fmt.Sprintf(`"%s"."aggregations_%s"`, d.Schema, w)
+		tableName := fmt.Sprintf(`"%s"."aggregations_%s"`, d.Schema, utils.WindowTableSuffix(w))
 		if _, err := d.DB.Exec(fmt.Sprintf("DELETE FROM %s WHERE end_time < $1", tableName), cutoff); err != nil {
 			log.Printf("Cleanup %s error: %v", tableName, err)
 		}
 	}
 
+	// Clean market breadth
+	if _, err := d.DB.Exec(fmt.Sprintf(`DELETE FROM "%s"."market_breadth" WHERE timestamp < $1`, d.Schema), cutoff); err != nil {
+		log.Printf("Cleanup market_breadth error: %v", err)
+	}
+
+	// Clean regime change events
+	if _, err := d.DB.Exec(fmt.Sprintf(`DELETE FROM "%s"."changepoint_events" WHERE timestamp < $1`, d.Schema), cutoff); err != nil {
+		log.Printf("Cleanup changepoint_events error: %v", err)
+	}
+
+	// Clean candlestick pattern events
+	if _, err := d.DB.Exec(fmt.Sprintf(`DELETE FROM "%s"."pattern_events" WHERE timestamp < $1`, d.Schema), cutoff); err != nil {
+		log.Printf("Cleanup pattern_events error: %v", err)
+	}
+
+	// Clean level break events
+	if _, err := d.DB.Exec(fmt.Sprintf(`DELETE FROM "%s"."level_events" WHERE timestamp < $1`, d.Schema), cutoff); err != nil {
+		log.Printf("Cleanup level_events error: %v", err)
+	}
+
+	// Clean cluster jumps
+	if _, err := d.DB.Exec(fmt.Sprintf(`DELETE FROM "%s"."cluster_jumps" WHERE timestamp < $1`, d.Schema), cutoff); err != nil {
+		log.Printf("Cleanup cluster_jumps error: %v", err)
+	}
+
 	return nil
 }
 
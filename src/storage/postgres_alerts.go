@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"market-observer/src/models"
+)
+
+// Info: Separate file for alert rules/events persistence (Postgres)
+
+// -----------------------------------------------------------------------------
+
+// createAlertTables creates the alert tables (kept across restarts)
+func (d *PostgresDB) createAlertTables() error {
+	rulesTable := fmt.Sprintf(`"%s"."alert_rules"`, d.Schema)
+	eventsTable := fmt.Sprintf(`"%s"."alert_events"`, d.Schema)
+	deliveriesTable := fmt.Sprintf(`"%s"."notification_deliveries"`, d.Schema)
+
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGSERIAL PRIMARY KEY,
+			name TEXT,
+			condition_expr TEXT,
+			symbols TEXT,
+			watchlist TEXT,
+			cooldown_seconds BIGINT,
+			hysteresis DOUBLE PRECISION,
+			enabled BOOLEAN,
+			created_at BIGINT,
+			updated_at BIGINT,
+			open_candles BOOLEAN DEFAULT FALSE
+		);
+	`, rulesTable)
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create %s: %w", rulesTable, err)
+	}
+
+	// Tables created by older versions lack the open candles flag
+	query = fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS open_candles BOOLEAN DEFAULT FALSE;`, rulesTable)
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to migrate %s: %w", rulesTable, err)
+	}
+
+	query = fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGSERIAL PRIMARY KEY,
+			rule_id BIGINT,
+			rule_name TEXT,
+			condition_expr TEXT,
+			symbol TEXT,
+			window_name TEXT,
+			timestamp BIGINT,
+			message TEXT,
+			values_json TEXT,
+			candle_json TEXT
+		);
+	`, eventsTable)
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create %s: %w", eventsTable, err)
+	}
+
+	query = fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGSERIAL PRIMARY KEY,
+			channel TEXT,
+			kind TEXT,
+			dedup_key TEXT,
+			title TEXT,
+			status TEXT,
+			attempts INTEGER,
+			error TEXT,
+			timestamp BIGINT
+		);
+	`, deliveriesTable)
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create %s: %w", deliveriesTable, err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *PostgresDB) SaveAlertRule(rule *models.MAlertRule) error {
+	symbols := strings.Join(rule.Symbols, ",")
+
+	if rule.ID == 0 {
+		return d.DB.QueryRow(fmt.Sprintf(`
+			INSERT INTO "%s"."alert_rules" (name, condition_expr, symbols, watchlist, cooldown_seconds, hysteresis, open_candles, enabled, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING id
+		`, d.Schema), rule.Name, rule.Condition, symbols, rule.Watchlist, rule.CooldownSeconds, rule.Hysteresis, rule.OpenCandles, rule.Enabled, rule.CreatedAt, rule.UpdatedAt).Scan(&rule.ID)
+	}
+
+	res, err := d.DB.Exec(fmt.Sprintf(`
+		UPDATE "%s"."alert_rules" SET name = $1, condition_expr = $2, symbols = $3, watchlist = $4, cooldown_seconds = $5, hysteresis = $6, open_candles = $7, enabled = $8, updated_at = $9
+		WHERE id = $10
+	`, d.Schema), rule.Name, rule.Condition, symbols, rule.Watchlist, rule.CooldownSeconds, rule.Hysteresis, rule.OpenCandles, rule.Enabled, rule.UpdatedAt, rule.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("alert rule %d not found", rule.ID)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *PostgresDB) DeleteAlertRule(id int64) error {
+	_, err := d.DB.Exec(fmt.Sprintf(`DELETE FROM "%s"."alert_rules" WHERE id = $1`, d.Schema), id)
+	return err
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *PostgresDB) LoadAlertRules() ([]models.MAlertRule, error) {
+	rows, err := d.DB.Query(fmt.Sprintf(`
+		SELECT id, name, condition_expr, symbols, watchlist, cooldown_seconds, hysteresis, COALESCE(open_candles, FALSE), enabled, created_at, updated_at
+		FROM "%s"."alert_rules" ORDER BY id
+	`, d.Schema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load alert_rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []models.MAlertRule
+	for rows.Next() {
+		var r models.MAlertRule
+		var symbols string
+		if err := rows.Scan(&r.ID, &r.Name, &r.Condition, &symbols, &r.Watchlist, &r.CooldownSeconds, &r.Hysteresis, &r.OpenCandles, &r.Enabled, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan alert_rules: %w", err)
+		}
+		if symbols != "" {
+			r.Symbols = strings.Split(symbols, ",")
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *PostgresDB) SaveAlertEvents(events []models.MAlertEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf(`
+		INSERT INTO "%s"."alert_events" (rule_id, rule_name, condition_expr, symbol, window_name, timestamp, message, values_json, candle_json)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, d.Schema))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range events {
+		valuesJSON, _ := json.Marshal(e.Values)
+		candleJSON, _ := json.Marshal(e.Candle)
+		if _, err := stmt.Exec(e.RuleID, e.RuleName, e.Condition, e.Symbol, e.WindowName, e.Timestamp, e.Message, string(valuesJSON), string(candleJSON)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *PostgresDB) SaveNotificationDelivery(delivery models.MNotificationDelivery) error {
+	_, err := d.DB.Exec(fmt.Sprintf(`
+		INSERT INTO "%s"."notification_deliveries" (channel, kind, dedup_key, title, status, attempts, error, timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, d.Schema), delivery.Channel, delivery.Kind, delivery.DedupKey, delivery.Title, delivery.Status, delivery.Attempts, delivery.Error, delivery.Timestamp)
+	return err
+}
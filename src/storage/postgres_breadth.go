@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"fmt"
+
+	"market-observer/src/models"
+)
+
+// Info: Separate file for the market breadth time series (Postgres)
+
+// -----------------------------------------------------------------------------
+
+// createBreadthTables creates the breadth time series (kept across restarts, pruned by retention)
+func (d *PostgresDB) createBreadthTables() error {
+	breadthTable := fmt.Sprintf(`"%s"."market_breadth"`, d.Schema)
+
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGSERIAL PRIMARY KEY,
+			window_name TEXT,
+			timestamp BIGINT,
+			symbols INTEGER,
+			advancers INTEGER,
+			decliners INTEGER,
+			unchanged INTEGER,
+			up_volume DOUBLE PRECISION,
+			down_volume DOUBLE PRECISION,
+			up_down_volume_ratio DOUBLE PRECISION,
+			above_average INTEGER,
+			pct_above_average DOUBLE PRECISION,
+			new_highs INTEGER,
+			new_lows INTEGER,
+			anomalies INTEGER
+		);
+		CREATE INDEX IF NOT EXISTS idx_market_breadth_window_ts ON %s (window_name, timestamp);
+	`, breadthTable, breadthTable)
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create %s: %w", breadthTable, err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *PostgresDB) SaveBreadth(snapshots []models.MBreadthSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf(`
+		INSERT INTO "%s"."market_breadth" (window_name, timestamp, symbols, advancers, decliners, unchanged, up_volume, down_volume,
+			up_down_volume_ratio, above_average, pct_above_average, new_highs, new_lows, anomalies)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`, d.Schema))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, s := range snapshots {
+		if _, err := stmt.Exec(s.WindowName, s.Timestamp, s.Symbols, s.Advancers, s.Decliners, s.Unchanged, s.UpVolume, s.DownVolume,
+			s.UpDownVolumeRatio, s.AboveAverage, s.PctAboveAverage, s.NewHighs, s.NewLows, s.Anomalies); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
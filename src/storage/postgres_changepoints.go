@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"fmt"
+
+	"market-observer/src/models"
+)
+
+// Info: Separate file for the regime change event log (Postgres)
+
+// -----------------------------------------------------------------------------
+
+// createChangepointTables creates the changepoint event log (kept across restarts)
+func (d *PostgresDB) createChangepointTables() error {
+	eventsTable := fmt.Sprintf(`"%s"."changepoint_events"`, d.Schema)
+
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGSERIAL PRIMARY KEY,
+			symbol TEXT,
+			window_name TEXT,
+			series TEXT,
+			method TEXT,
+			direction TEXT,
+			change_at BIGINT,
+			timestamp BIGINT,
+			score DOUBLE PRECISION,
+			before_mean DOUBLE PRECISION,
+			before_std DOUBLE PRECISION,
+			before_count INTEGER,
+			after_mean DOUBLE PRECISION,
+			after_std DOUBLE PRECISION,
+			after_count INTEGER
+		);
+	`, eventsTable)
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create %s: %w", eventsTable, err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *PostgresDB) SaveChangepointEvents(events []models.MChangepointEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf(`
+		INSERT INTO "%s"."changepoint_events" (symbol, window_name, series, method, direction, change_at, timestamp, score,
+			before_mean, before_std, before_count, after_mean, after_std, after_count)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`, d.Schema))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range events {
+		if _, err := stmt.Exec(e.Symbol, e.WindowName, e.Series, e.Method, e.Direction, e.ChangeAt, e.Timestamp, e.Score,
+			e.Before.Mean, e.Before.Std, e.Before.Count, e.After.Mean, e.After.Std, e.After.Count); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"fmt"
+
+	"market-observer/src/models"
+)
+
+// Info: Separate file for the cluster jump log (Postgres)
+
+// -----------------------------------------------------------------------------
+
+// createClusterTables creates the cluster jump log (kept across restarts)
+func (d *PostgresDB) createClusterTables() error {
+	jumpsTable := fmt.Sprintf(`"%s"."cluster_jumps"`, d.Schema)
+
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGSERIAL PRIMARY KEY,
+			symbol TEXT,
+			session TEXT,
+			previous_session TEXT,
+			from_cluster INTEGER,
+			to_cluster INTEGER,
+			distance DOUBLE PRECISION,
+			timestamp BIGINT
+		);
+	`, jumpsTable)
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create %s: %w", jumpsTable, err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *PostgresDB) SaveClusterJumps(jumps []models.MClusterJump) error {
+	if len(jumps) == 0 {
+		return nil
+	}
+
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf(`
+		INSERT INTO "%s"."cluster_jumps" (symbol, session, previous_session, from_cluster, to_cluster, distance, timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, d.Schema))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, j := range jumps {
+		if _, err := stmt.Exec(j.Symbol, j.Session, j.PreviousSession, j.FromCluster, j.ToCluster, j.Distance, j.Timestamp); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
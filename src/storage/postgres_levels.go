@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"fmt"
+
+	"market-observer/src/models"
+)
+
+// Info: Separate file for the support/resistance break event log (Postgres)
+
+// -----------------------------------------------------------------------------
+
+// createLevelTables creates the level break event log (kept across restarts)
+func (d *PostgresDB) createLevelTables() error {
+	eventsTable := fmt.Sprintf(`"%s"."level_events"`, d.Schema)
+
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGSERIAL PRIMARY KEY,
+			symbol TEXT,
+			window_name TEXT,
+			type TEXT,
+			level DOUBLE PRECISION,
+			kind TEXT,
+			source TEXT,
+			strength DOUBLE PRECISION,
+			previous_close DOUBLE PRECISION,
+			close DOUBLE PRECISION,
+			volume_ratio DOUBLE PRECISION,
+			timestamp BIGINT
+		);
+	`, eventsTable)
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create %s: %w", eventsTable, err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *PostgresDB) SaveLevelEvents(events []models.MLevelBreakEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf(`
+		INSERT INTO "%s"."level_events" (symbol, window_name, type, level, kind, source, strength, previous_close, close, volume_ratio, timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`, d.Schema))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range events {
+		if _, err := stmt.Exec(e.Symbol, e.WindowName, e.Type, e.Level, e.Kind, e.Source, e.Strength, e.PreviousClose, e.Close, e.VolumeRatio, e.Timestamp); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"fmt"
+
+	"market-observer/src/models"
+)
+
+// Info: Separate file for the candlestick pattern event log (Postgres)
+
+// -----------------------------------------------------------------------------
+
+// createPatternTables creates the pattern event log (kept across restarts)
+func (d *PostgresDB) createPatternTables() error {
+	eventsTable := fmt.Sprintf(`"%s"."pattern_events"`, d.Schema)
+
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGSERIAL PRIMARY KEY,
+			symbol TEXT,
+			window_name TEXT,
+			pattern TEXT,
+			direction TEXT,
+			bars INTEGER,
+			start_time BIGINT,
+			timestamp BIGINT,
+			close DOUBLE PRECISION,
+			true_ohlc BOOLEAN
+		);
+	`, eventsTable)
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create %s: %w", eventsTable, err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *PostgresDB) SavePatternEvents(events []models.MPatternEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf(`
+		INSERT INTO "%s"."pattern_events" (symbol, window_name, pattern, direction, bars, start_time, timestamp, close, true_ohlc)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, d.Schema))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range events {
+		if _, err := stmt.Exec(e.Symbol, e.WindowName, e.Pattern, e.Direction, e.Bars, e.StartTime, e.Timestamp, e.Close, e.TrueOHLC); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
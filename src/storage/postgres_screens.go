@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"market-observer/src/models"
+)
+
+// Info: Separate file for saved screens persistence (Postgres)
+
+// -----------------------------------------------------------------------------
+
+// createScreenTables creates the saved screens table (kept across restarts)
+func (d *PostgresDB) createScreenTables() error {
+	screensTable := fmt.Sprintf(`"%s"."saved_screens"`, d.Schema)
+
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGSERIAL PRIMARY KEY,
+			name TEXT,
+			definition TEXT,
+			enabled BOOLEAN,
+			created_at BIGINT,
+			updated_at BIGINT
+		);
+	`, screensTable)
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create %s: %w", screensTable, err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *PostgresDB) SaveScreen(screen *models.MSavedScreen) error {
+	definition, err := json.Marshal(screen.MScreenRequest)
+	if err != nil {
+		return err
+	}
+
+	if screen.ID == 0 {
+		return d.DB.QueryRow(fmt.Sprintf(`
+			INSERT INTO "%s"."saved_screens" (name, definition, enabled, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, d.Schema), screen.Name, string(definition), screen.Enabled, screen.CreatedAt, screen.UpdatedAt).Scan(&screen.ID)
+	}
+
+	res, err := d.DB.Exec(fmt.Sprintf(`
+		UPDATE "%s"."saved_screens" SET name = $1, definition = $2, enabled = $3, updated_at = $4
+		WHERE id = $5
+	`, d.Schema), screen.Name, string(definition), screen.Enabled, screen.UpdatedAt, screen.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("screen %d not found", screen.ID)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *PostgresDB) DeleteScreen(id int64) error {
+	_, err := d.DB.Exec(fmt.Sprintf(`DELETE FROM "%s"."saved_screens" WHERE id = $1`, d.Schema), id)
+	return err
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *PostgresDB) LoadScreens() ([]models.MSavedScreen, error) {
+	rows, err := d.DB.Query(fmt.Sprintf(`
+		SELECT id, name, definition, enabled, created_at, updated_at
+		FROM "%s"."saved_screens" ORDER BY id
+	`, d.Schema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load saved_screens: %w", err)
+	}
+	defer rows.Close()
+
+	var screens []models.MSavedScreen
+	for rows.Next() {
+		var s models.MSavedScreen
+		var definition string
+		if err := rows.Scan(&s.ID, &s.Name, &definition, &s.Enabled, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan saved_screens: %w", err)
+		}
+		if err := json.Unmarshal([]byte(definition), &s.MScreenRequest); err != nil {
+			return nil, fmt.Errorf("invalid definition of screen %d: %w", s.ID, err)
+		}
+		screens = append(screens, s)
+	}
+	return screens, rows.Err()
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"fmt"
+
+	"market-observer/src/models"
+)
+
+// Info: Separate file for quarantined inbound points (Postgres)
+
+// -----------------------------------------------------------------------------
+
+// createValidationTables creates the quarantine table (kept across restarts)
+func (d *PostgresDB) createValidationTables() error {
+	quarantineTable := fmt.Sprintf(`"%s"."quarantined_prices"`, d.Schema)
+
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			id BIGSERIAL PRIMARY KEY,
+			symbol TEXT,
+			price DOUBLE PRECISION,
+			volume DOUBLE PRECISION,
+			timestamp BIGINT,
+			fetched_at BIGINT,
+			reason TEXT,
+			detail TEXT,
+			quarantined_at BIGINT,
+			released_at BIGINT DEFAULT 0
+		);
+	`, quarantineTable)
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create %s: %w", quarantineTable, err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *PostgresDB) SaveQuarantinedPrice(q *models.MQuarantinedPrice) error {
+	return d.DB.QueryRow(fmt.Sprintf(`
+		INSERT INTO "%s"."quarantined_prices" (symbol, price, volume, timestamp, fetched_at, reason, detail, quarantined_at, released_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, d.Schema), q.Price.Symbol, q.Price.Price, q.Price.Volume, q.Price.Timestamp, q.Price.FetchedAt, q.Reason, q.Detail, q.QuarantinedAt, q.ReleasedAt).Scan(&q.ID)
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *PostgresDB) ReleaseQuarantinedPrice(id int64, releasedAt int64) error {
+	_, err := d.DB.Exec(fmt.Sprintf(`UPDATE "%s"."quarantined_prices" SET released_at = $1 WHERE id = $2`, d.Schema), releasedAt, id)
+	return err
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *PostgresDB) LoadQuarantinedPrices() ([]models.MQuarantinedPrice, error) {
+	rows, err := d.DB.Query(fmt.Sprintf(`
+		SELECT id, symbol, price, volume, timestamp, fetched_at, reason, detail, quarantined_at
+		FROM "%s"."quarantined_prices" WHERE released_at = 0 ORDER BY id
+	`, d.Schema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load quarantined_prices: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.MQuarantinedPrice
+	for rows.Next() {
+		var q models.MQuarantinedPrice
+		if err := rows.Scan(&q.ID, &q.Price.Symbol, &q.Price.Price, &q.Price.Volume, &q.Price.Timestamp, &q.Price.FetchedAt, &q.Reason, &q.Detail, &q.QuarantinedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, q)
+	}
+	return result, rows.Err()
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"market-observer/src/logger"
 	"market-observer/src/models"
+	"market-observer/src/utils"
 	"time"
 
 	_ "modernc.org/sqlite"
@@ -82,6 +83,9 @@ func (d *AsyncSQLiteDB) recreateTables() error {
 			volume REAL,
 			price_percent_change REAL,
 			volume_percent_change REAL,
+			open REAL,
+			high REAL,
+			low REAL,
 			PRIMARY KEY (symbol, timestamp)
 		);
 	`
@@ -91,7 +95,7 @@ func (d *AsyncSQLiteDB) recreateTables() error {
 
 	for _, w := range d.Config.WindowsAgg {
 		// Aggregations
-		aggTable := fmt.Sprintf("aggregations_%s", w)
+		aggTable := fmt.Sprintf("aggregations_%s", utils.WindowTableSuffix(w))
 		if _, err := d.DB.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", aggTable)); err != nil {
 			return fmt.Errorf("failed to drop %s: %w", aggTable, err)
 		}
@@ -108,6 +112,36 @@ func (d *AsyncSQLiteDB) recreateTables() error {
 				volume REAL,
 				price_percent_change REAL,
 				volume_percent_change REAL,
+				vwap REAL,
+				session_vwap REAL,
+				session_vwap_upper_1 REAL,
+				session_vwap_lower_1 REAL,
+				session_vwap_upper_2 REAL,
+				session_vwap_lower_2 REAL,
+				missing_bars INTEGER,
+				filled_bars INTEGER,
+				is_complete INTEGER,
+				metrics_json TEXT,
+				benchmark TEXT,
+				benchmark_return REAL,
+				excess_return REAL,
+				relative_strength REAL,
+				beta REAL,
+				alpha REAL,
+				residual_return REAL,
+				residual_zscore REAL,
+				realized_vol REAL,
+				parkinson_vol REAL,
+				garman_klass_vol REAL,
+				yang_zhang_vol REAL,
+				vol_percentile REAL,
+				high_vol_regime INTEGER,
+				low_vol_regime INTEGER,
+				expected_volume REAL,
+				volume_slot_zscore REAL,
+				true_ohlc INTEGER,
+				patterns TEXT,
+				volume_anomaly_ratio REAL,
 				PRIMARY KEY (symbol, start_time)
 			);
 		`, aggTable)
@@ -115,18 +149,16 @@ func (d *AsyncSQLiteDB) recreateTables() error {
 			return fmt.Errorf("failed to create %s: %w", aggTable, err)
 		}
 
-		// Intermediate Stats
-		statsTable := fmt.Sprintf("intermediate_stats_%s", w)
-		if _, err := d.DB.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", statsTable)); err != nil {
-			return fmt.Errorf("failed to drop %s: %w", statsTable, err)
-		}
-
+		// Intermediate Stats (kept across restarts so rolling stats can be reloaded)
+		statsTable := fmt.Sprintf("intermediate_stats_%s", utils.WindowTableSuffix(w))
 		query = fmt.Sprintf(`
-			CREATE TABLE %s (
+			CREATE TABLE IF NOT EXISTS %s (
 				symbol TEXT,
 				window_name TEXT,
 				avg_volume_history REAL,
 				std_volume_history REAL,
+				avg_return_history REAL DEFAULT 0,
+				std_return_history REAL DEFAULT 0,
 				data_points_history INTEGER,
 				last_history_timestamp INTEGER,
 				updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -136,8 +168,85 @@ func (d *AsyncSQLiteDB) recreateTables() error {
 		if _, err := d.DB.Exec(query); err != nil {
 			return fmt.Errorf("failed to create %s: %w", statsTable, err)
 		}
+
+		// Tables created by older versions lack the return columns
+		if err := d.ensureColumns(statsTable, map[string]string{
+			"avg_return_history": "REAL DEFAULT 0",
+			"std_return_history": "REAL DEFAULT 0",
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Alert rules and events
+	if err := d.createAlertTables(); err != nil {
+		return err
+	}
+
+	// Quarantined inbound points
+	if err := d.createValidationTables(); err != nil {
+		return err
+	}
+
+	// Market breadth time series
+	if err := d.createBreadthTables(); err != nil {
+		return err
+	}
+
+	// Regime changes
+	if err := d.createChangepointTables(); err != nil {
+		return err
+	}
+
+	// Candlestick patterns
+	if err := d.createPatternTables(); err != nil {
+		return err
 	}
 
+	// Support/resistance breaks
+	if err := d.createLevelTables(); err != nil {
+		return err
+	}
+
+	// Cluster jumps
+	if err := d.createClusterTables(); err != nil {
+		return err
+	}
+
+	// Saved screens
+	return d.createScreenTables()
+}
+
+// -----------------------------------------------------------------------------
+
+// ensureColumns adds missing columns to an existing table.
+func (d *AsyncSQLiteDB) ensureColumns(table string, columns map[string]string) error {
+	rows, err := d.DB.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan columns of %s: %w", table, err)
+		}
+		existing[name] = true
+	}
+	rows.Close()
+
+	for name, def := range columns {
+		if existing[name] {
+			continue
+		}
+		if _, err := d.DB.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, name, def)); err != nil {
+			return fmt.Errorf("failed to add column %s to %s: %w", name, table, err)
+		}
+	}
 	return nil
 }
 
@@ -155,8 +264,8 @@ func (d *AsyncSQLiteDB) SaveStockPricesBulk(prices []models.MStockPrice) error {
 	defer tx.Rollback()
 
 	stmt, err := tx.Prepare(`
-		INSERT INTO stock_prices (symbol, timestamp, price, volume, price_percent_change, volume_percent_change)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO stock_prices (symbol, timestamp, price, volume, price_percent_change, volume_percent_change, open, high, low)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return err
@@ -164,7 +273,7 @@ func (d *AsyncSQLiteDB) SaveStockPricesBulk(prices []models.MStockPrice) error {
 	defer stmt.Close()
 
 	for _, p := range prices {
-		_, err := stmt.Exec(p.Symbol, p.Timestamp, p.Price, p.Volume, p.PricePercentChange, p.VolumePercentChange)
+		_, err := stmt.Exec(p.Symbol, p.Timestamp, p.Price, p.Volume, p.PricePercentChange, p.VolumePercentChange, p.Open, p.High, p.Low)
 		if err != nil {
 			return err
 		}
@@ -175,6 +284,29 @@ func (d *AsyncSQLiteDB) SaveStockPricesBulk(prices []models.MStockPrice) error {
 
 // -----------------------------------------------------------------------------
 
+func (d *AsyncSQLiteDB) LoadStockPrices(symbol string, from, to int64) ([]models.MStockPrice, error) {
+	rows, err := d.DB.Query(`
+		SELECT timestamp, price, volume, price_percent_change, volume_percent_change, open, high, low
+		FROM stock_prices WHERE symbol = ? AND timestamp >= ? AND timestamp < ? ORDER BY timestamp
+	`, symbol, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stock_prices: %w", err)
+	}
+	defer rows.Close()
+
+	var prices []models.MStockPrice
+	for rows.Next() {
+		p := models.MStockPrice{Symbol: symbol}
+		if err := rows.Scan(&p.Timestamp, &p.Price, &p.Volume, &p.PricePercentChange, &p.VolumePercentChange, &p.Open, &p.High, &p.Low); err != nil {
+			return nil, fmt.Errorf("failed to scan stock_prices: %w", err)
+		}
+		prices = append(prices, p)
+	}
+	return prices, rows.Err()
+}
+
+// -----------------------------------------------------------------------------
+
 func (d *AsyncSQLiteDB) SaveAggregations(aggs map[string]map[string][]models.MAggregation) error {
 	tx, err := d.DB.Begin()
 	if err != nil {
@@ -187,11 +319,53 @@ func (d *AsyncSQLiteDB) SaveAggregations(aggs map[string]map[string][]models.MAg
 			if len(items) == 0 {
 				continue
 			}
-			tableName := fmt.Sprintf("aggregations_%s", w)
+			tableName := fmt.Sprintf("aggregations_%s", utils.WindowTableSuffix(w))
 
 			query := fmt.Sprintf(`
-				INSERT INTO %s (symbol, start_time, end_time, open, high, low, close, volume, price_percent_change, volume_percent_change)
-				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+				INSERT INTO %s (symbol, start_time, end_time, open, high, low, close, volume, price_percent_change, volume_percent_change, vwap, session_vwap, session_vwap_upper_1, session_vwap_lower_1, session_vwap_upper_2, session_vwap_lower_2, missing_bars, filled_bars, is_complete, metrics_json,
+					benchmark, benchmark_return, excess_return, relative_strength, beta, alpha, residual_return, residual_zscore,
+					realized_vol, parkinson_vol, garman_klass_vol, yang_zhang_vol, vol_percentile, high_vol_regime, low_vol_regime,
+					expected_volume, volume_slot_zscore, true_ohlc, patterns, volume_anomaly_ratio)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT (symbol, start_time) DO UPDATE SET
+					end_time = excluded.end_time,
+					open = excluded.open,
+					high = excluded.high,
+					low = excluded.low,
+					close = excluded.close,
+					volume = excluded.volume,
+					price_percent_change = excluded.price_percent_change,
+					volume_percent_change = excluded.volume_percent_change,
+					vwap = excluded.vwap,
+					session_vwap = excluded.session_vwap,
+					session_vwap_upper_1 = excluded.session_vwap_upper_1,
+					session_vwap_lower_1 = excluded.session_vwap_lower_1,
+					session_vwap_upper_2 = excluded.session_vwap_upper_2,
+					session_vwap_lower_2 = excluded.session_vwap_lower_2,
+					missing_bars = excluded.missing_bars,
+					filled_bars = excluded.filled_bars,
+					is_complete = excluded.is_complete,
+					metrics_json = excluded.metrics_json,
+					benchmark = excluded.benchmark,
+					benchmark_return = excluded.benchmark_return,
+					excess_return = excluded.excess_return,
+					relative_strength = excluded.relative_strength,
+					beta = excluded.beta,
+					alpha = excluded.alpha,
+					residual_return = excluded.residual_return,
+					residual_zscore = excluded.residual_zscore,
+					realized_vol = excluded.realized_vol,
+					parkinson_vol = excluded.parkinson_vol,
+					garman_klass_vol = excluded.garman_klass_vol,
+					yang_zhang_vol = excluded.yang_zhang_vol,
+					vol_percentile = excluded.vol_percentile,
+					high_vol_regime = excluded.high_vol_regime,
+					low_vol_regime = excluded.low_vol_regime,
+					expected_volume = excluded.expected_volume,
+					volume_slot_zscore = excluded.volume_slot_zscore,
+					true_ohlc = excluded.true_ohlc,
+					patterns = excluded.patterns,
+					volume_anomaly_ratio = excluded.volume_anomaly_ratio
 			`, tableName)
 
 			stmt, err := tx.Prepare(query)
@@ -201,7 +375,12 @@ func (d *AsyncSQLiteDB) SaveAggregations(aggs map[string]map[string][]models.MAg
 			defer stmt.Close()
 
 			for _, agg := range items {
-				_, err = stmt.Exec(agg.Symbol, agg.StartTime, agg.EndTime, agg.Open, agg.High, agg.Low, agg.Close, agg.Volume, agg.PricePercentChange, agg.VolumePercentChange)
+				_, err = stmt.Exec(agg.Symbol, agg.StartTime, agg.EndTime, agg.Open, agg.High, agg.Low, agg.Close, agg.Volume, agg.PricePercentChange, agg.VolumePercentChange,
+					agg.VWAP, agg.SessionVWAP, agg.SessionVWAPUpper1, agg.SessionVWAPLower1, agg.SessionVWAPUpper2, agg.SessionVWAPLower2,
+					agg.MissingBars, agg.FilledBars, agg.IsComplete, metricsJSON(agg.Metrics),
+					agg.Benchmark, agg.BenchmarkReturn, agg.ExcessReturn, agg.RelativeStrength, agg.Beta, agg.Alpha, agg.ResidualReturn, agg.ResidualZScore,
+					agg.RealizedVol, agg.ParkinsonVol, agg.GarmanKlassVol, agg.YangZhangVol, agg.VolPercentile, agg.HighVolRegime, agg.LowVolRegime,
+					agg.ExpectedVolume, agg.VolumeSlotZScore, agg.TrueOHLC, patternsText(agg.Patterns), agg.VolumeAnomalyRatio)
 				if err != nil {
 					return err
 				}
@@ -214,6 +393,35 @@ func (d *AsyncSQLiteDB) SaveAggregations(aggs map[string]map[string][]models.MAg
 
 // -----------------------------------------------------------------------------
 
+func (d *AsyncSQLiteDB) LoadAggregations(window string, from, to int64) ([]models.MAggregation, error) {
+	tableName := fmt.Sprintf("aggregations_%s", utils.WindowTableSuffix(window))
+	rows, err := d.DB.Query(fmt.Sprintf(`
+		SELECT symbol, start_time, end_time, open, high, low, close, volume, price_percent_change, volume_anomaly_ratio,
+			benchmark, benchmark_return, beta
+		FROM %s WHERE start_time >= ? AND start_time < ? ORDER BY symbol, start_time
+	`, tableName), from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", tableName, err)
+	}
+	defer rows.Close()
+
+	var aggs []models.MAggregation
+	for rows.Next() {
+		a := models.MAggregation{WindowName: window}
+		var ratio, benchmarkReturn, beta sql.NullFloat64
+		var benchmark sql.NullString
+		if err := rows.Scan(&a.Symbol, &a.StartTime, &a.EndTime, &a.Open, &a.High, &a.Low, &a.Close, &a.Volume, &a.PricePercentChange, &ratio,
+			&benchmark, &benchmarkReturn, &beta); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", tableName, err)
+		}
+		a.VolumeAnomalyRatio, a.Benchmark, a.BenchmarkReturn, a.Beta = ratio.Float64, benchmark.String, benchmarkReturn.Float64, beta.Float64
+		aggs = append(aggs, a)
+	}
+	return aggs, rows.Err()
+}
+
+// -----------------------------------------------------------------------------
+
 func (d *AsyncSQLiteDB) SaveIntermediateStats(stats []models.MIntermediateStats) error {
 	if len(stats) == 0 {
 		return nil
@@ -232,14 +440,16 @@ func (d *AsyncSQLiteDB) SaveIntermediateStats(stats []models.MIntermediateStats)
 	}
 
 	for w, list := range byWindow {
-		tableName := fmt.Sprintf("intermediate_stats_%s", w)
+		tableName := fmt.Sprintf("intermediate_stats_%s", utils.WindowTableSuffix(w))
 
 		query := fmt.Sprintf(`
-			INSERT INTO %s (symbol, window_name, avg_volume_history, std_volume_history, data_points_history, last_history_timestamp, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?)
+			INSERT INTO %s (symbol, window_name, avg_volume_history, std_volume_history, avg_return_history, std_return_history, data_points_history, last_history_timestamp, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 			ON CONFLICT (symbol, window_name) DO UPDATE SET
 				avg_volume_history = excluded.avg_volume_history,
 				std_volume_history = excluded.std_volume_history,
+				avg_return_history = excluded.avg_return_history,
+				std_return_history = excluded.std_return_history,
 				data_points_history = excluded.data_points_history,
 				last_history_timestamp = excluded.last_history_timestamp,
 				updated_at = excluded.updated_at
@@ -252,7 +462,7 @@ func (d *AsyncSQLiteDB) SaveIntermediateStats(stats []models.MIntermediateStats)
 		defer stmt.Close()
 
 		for _, s := range list {
-			_, err = stmt.Exec(s.Symbol, s.WindowName, s.AvgVolumeHistory, s.StdVolumeHistory, s.DataPointsHistory, s.LastHistoryTimestamp, time.Now().UTC())
+			_, err = stmt.Exec(s.Symbol, s.WindowName, s.AvgVolumeHistory, s.StdVolumeHistory, s.AvgReturnHistory, s.StdReturnHistory, s.DataPointsHistory, s.LastHistoryTimestamp, time.Now().UTC())
 			if err != nil {
 				return err
 			}
@@ -264,6 +474,36 @@ func (d *AsyncSQLiteDB) SaveIntermediateStats(stats []models.MIntermediateStats)
 
 // -----------------------------------------------------------------------------
 
+func (d *AsyncSQLiteDB) LoadIntermediateStats() ([]models.MIntermediateStats, error) {
+	var stats []models.MIntermediateStats
+
+	for _, w := range d.Config.WindowsAgg {
+		tableName := fmt.Sprintf("intermediate_stats_%s", utils.WindowTableSuffix(w))
+
+		rows, err := d.DB.Query(fmt.Sprintf(`
+			SELECT symbol, window_name, avg_volume_history, std_volume_history, avg_return_history, std_return_history, data_points_history, last_history_timestamp
+			FROM %s
+		`, tableName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", tableName, err)
+		}
+
+		for rows.Next() {
+			var s models.MIntermediateStats
+			if err := rows.Scan(&s.Symbol, &s.WindowName, &s.AvgVolumeHistory, &s.StdVolumeHistory, &s.AvgReturnHistory, &s.StdReturnHistory, &s.DataPointsHistory, &s.LastHistoryTimestamp); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan %s: %w", tableName, err)
+			}
+			stats = append(stats, s)
+		}
+		rows.Close()
+	}
+
+	return stats, nil
+}
+
+// -----------------------------------------------------------------------------
+
 func (d *AsyncSQLiteDB) CleanupOldData() error {
 	retentionDays := d.Config.DataSource.DataRetentionDays
 	cutoff := time.Now().UTC().AddDate(0, 0, -retentionDays).Unix()
@@ -277,12 +517,37 @@ func (d *AsyncSQLiteDB) CleanupOldData() error {
 
 	// Clean aggregation tables
 	for _, w := range d.Config.WindowsAgg {
-		tableName := fmt.Sprintf("aggregations_%s", w)
+		tableName := fmt.Sprintf("aggregations_%s", utils.WindowTableSuffix(w))
 		if _, err := d.DB.Exec(fmt.Sprintf("DELETE FROM %s WHERE end_time < ?", tableName), cutoff); err != nil {
 			d.Logger.Error("Cleanup %s error: %v", tableName, err)
 		}
 	}
 
+	// Clean market breadth
+	if _, err := d.DB.Exec("DELETE FROM market_breadth WHERE timestamp < ?", cutoff); err != nil {
+		d.Logger.Error("Cleanup market_breadth error: %v", err)
+	}
+
+	// Clean regime change events
+	if _, err := d.DB.Exec("DELETE FROM changepoint_events WHERE timestamp < ?", cutoff); err != nil {
+		d.Logger.Error("Cleanup changepoint_events error: %v", err)
+	}
+
+	// Clean candlestick pattern events
+	if _, err := d.DB.Exec("DELETE FROM pattern_events WHERE timestamp < ?", cutoff); err != nil {
+		d.Logger.Error("Cleanup pattern_events error: %v", err)
+	}
+
+	// Clean level break events
+	if _, err := d.DB.Exec("DELETE FROM level_events WHERE timestamp < ?", cutoff); err != nil {
+		d.Logger.Error("Cleanup level_events error: %v", err)
+	}
+
+	// Clean cluster jumps
+	if _, err := d.DB.Exec("DELETE FROM cluster_jumps WHERE timestamp < ?", cutoff); err != nil {
+		d.Logger.Error("Cleanup cluster_jumps error: %v", err)
+	}
+
 	d.Logger.Info("Cleanup completed")
 	return nil
 }
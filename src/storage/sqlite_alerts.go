@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"market-observer/src/models"
+)
+
+// Info: Separate file for alert rules/events persistence (SQLite)
+
+// -----------------------------------------------------------------------------
+
+// createAlertTables creates the alert tables (kept across restarts)
+func (d *AsyncSQLiteDB) createAlertTables() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS alert_rules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT,
+			condition_expr TEXT,
+			symbols TEXT,
+			watchlist TEXT,
+			cooldown_seconds INTEGER,
+			hysteresis REAL,
+			enabled INTEGER,
+			created_at INTEGER,
+			updated_at INTEGER,
+			open_candles INTEGER DEFAULT 0
+		);
+	`
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create alert_rules: %w", err)
+	}
+
+	// Tables created by older versions lack the open candles flag
+	if err := d.ensureColumns("alert_rules", map[string]string{
+		"open_candles": "INTEGER DEFAULT 0",
+	}); err != nil {
+		return err
+	}
+
+	query = `
+		CREATE TABLE IF NOT EXISTS alert_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			rule_id INTEGER,
+			rule_name TEXT,
+			condition_expr TEXT,
+			symbol TEXT,
+			window_name TEXT,
+			timestamp INTEGER,
+			message TEXT,
+			values_json TEXT,
+			candle_json TEXT
+		);
+	`
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create alert_events: %w", err)
+	}
+
+	query = `
+		CREATE TABLE IF NOT EXISTS notification_deliveries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			channel TEXT,
+			kind TEXT,
+			dedup_key TEXT,
+			title TEXT,
+			status TEXT,
+			attempts INTEGER,
+			error TEXT,
+			timestamp INTEGER
+		);
+	`
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create notification_deliveries: %w", err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *AsyncSQLiteDB) SaveAlertRule(rule *models.MAlertRule) error {
+	symbols := strings.Join(rule.Symbols, ",")
+
+	if rule.ID == 0 {
+		res, err := d.DB.Exec(`
+			INSERT INTO alert_rules (name, condition_expr, symbols, watchlist, cooldown_seconds, hysteresis, open_candles, enabled, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, rule.Name, rule.Condition, symbols, rule.Watchlist, rule.CooldownSeconds, rule.Hysteresis, rule.OpenCandles, rule.Enabled, rule.CreatedAt, rule.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		rule.ID, err = res.LastInsertId()
+		return err
+	}
+
+	res, err := d.DB.Exec(`
+		UPDATE alert_rules SET name = ?, condition_expr = ?, symbols = ?, watchlist = ?, cooldown_seconds = ?, hysteresis = ?, open_candles = ?, enabled = ?, updated_at = ?
+		WHERE id = ?
+	`, rule.Name, rule.Condition, symbols, rule.Watchlist, rule.CooldownSeconds, rule.Hysteresis, rule.OpenCandles, rule.Enabled, rule.UpdatedAt, rule.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("alert rule %d not found", rule.ID)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *AsyncSQLiteDB) DeleteAlertRule(id int64) error {
+	_, err := d.DB.Exec("DELETE FROM alert_rules WHERE id = ?", id)
+	return err
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *AsyncSQLiteDB) LoadAlertRules() ([]models.MAlertRule, error) {
+	rows, err := d.DB.Query(`
+		SELECT id, name, condition_expr, symbols, watchlist, cooldown_seconds, hysteresis, COALESCE(open_candles, 0), enabled, created_at, updated_at
+		FROM alert_rules ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load alert_rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []models.MAlertRule
+	for rows.Next() {
+		var r models.MAlertRule
+		var symbols string
+		if err := rows.Scan(&r.ID, &r.Name, &r.Condition, &symbols, &r.Watchlist, &r.CooldownSeconds, &r.Hysteresis, &r.OpenCandles, &r.Enabled, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan alert_rules: %w", err)
+		}
+		if symbols != "" {
+			r.Symbols = strings.Split(symbols, ",")
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *AsyncSQLiteDB) SaveAlertEvents(events []models.MAlertEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO alert_events (rule_id, rule_name, condition_expr, symbol, window_name, timestamp, message, values_json, candle_json)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range events {
+		valuesJSON, _ := json.Marshal(e.Values)
+		candleJSON, _ := json.Marshal(e.Candle)
+		if _, err := stmt.Exec(e.RuleID, e.RuleName, e.Condition, e.Symbol, e.WindowName, e.Timestamp, e.Message, string(valuesJSON), string(candleJSON)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *AsyncSQLiteDB) SaveNotificationDelivery(delivery models.MNotificationDelivery) error {
+	_, err := d.DB.Exec(`
+		INSERT INTO notification_deliveries (channel, kind, dedup_key, title, status, attempts, error, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, delivery.Channel, delivery.Kind, delivery.DedupKey, delivery.Title, delivery.Status, delivery.Attempts, delivery.Error, delivery.Timestamp)
+	return err
+}
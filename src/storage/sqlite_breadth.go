@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"fmt"
+
+	"market-observer/src/models"
+)
+
+// Info: Separate file for the market breadth time series (SQLite)
+
+// -----------------------------------------------------------------------------
+
+// createBreadthTables creates the breadth time series (kept across restarts, pruned by retention)
+func (d *AsyncSQLiteDB) createBreadthTables() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS market_breadth (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			window_name TEXT,
+			timestamp INTEGER,
+			symbols INTEGER,
+			advancers INTEGER,
+			decliners INTEGER,
+			unchanged INTEGER,
+			up_volume REAL,
+			down_volume REAL,
+			up_down_volume_ratio REAL,
+			above_average INTEGER,
+			pct_above_average REAL,
+			new_highs INTEGER,
+			new_lows INTEGER,
+			anomalies INTEGER
+		);
+		CREATE INDEX IF NOT EXISTS idx_market_breadth_window_ts ON market_breadth (window_name, timestamp);
+	`
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create market_breadth: %w", err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *AsyncSQLiteDB) SaveBreadth(snapshots []models.MBreadthSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO market_breadth (window_name, timestamp, symbols, advancers, decliners, unchanged, up_volume, down_volume,
+			up_down_volume_ratio, above_average, pct_above_average, new_highs, new_lows, anomalies)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, s := range snapshots {
+		if _, err := stmt.Exec(s.WindowName, s.Timestamp, s.Symbols, s.Advancers, s.Decliners, s.Unchanged, s.UpVolume, s.DownVolume,
+			s.UpDownVolumeRatio, s.AboveAverage, s.PctAboveAverage, s.NewHighs, s.NewLows, s.Anomalies); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"fmt"
+
+	"market-observer/src/models"
+)
+
+// Info: Separate file for the regime change event log (SQLite)
+
+// -----------------------------------------------------------------------------
+
+// createChangepointTables creates the changepoint event log (kept across restarts)
+func (d *AsyncSQLiteDB) createChangepointTables() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS changepoint_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			symbol TEXT,
+			window_name TEXT,
+			series TEXT,
+			method TEXT,
+			direction TEXT,
+			change_at INTEGER,
+			timestamp INTEGER,
+			score REAL,
+			before_mean REAL,
+			before_std REAL,
+			before_count INTEGER,
+			after_mean REAL,
+			after_std REAL,
+			after_count INTEGER
+		);
+	`
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create changepoint_events: %w", err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *AsyncSQLiteDB) SaveChangepointEvents(events []models.MChangepointEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO changepoint_events (symbol, window_name, series, method, direction, change_at, timestamp, score,
+			before_mean, before_std, before_count, after_mean, after_std, after_count)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range events {
+		if _, err := stmt.Exec(e.Symbol, e.WindowName, e.Series, e.Method, e.Direction, e.ChangeAt, e.Timestamp, e.Score,
+			e.Before.Mean, e.Before.Std, e.Before.Count, e.After.Mean, e.After.Std, e.After.Count); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
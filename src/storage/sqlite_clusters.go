@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"fmt"
+
+	"market-observer/src/models"
+)
+
+// Info: Separate file for the cluster jump log (SQLite)
+
+// -----------------------------------------------------------------------------
+
+// createClusterTables creates the cluster jump log (kept across restarts)
+func (d *AsyncSQLiteDB) createClusterTables() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS cluster_jumps (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			symbol TEXT,
+			session TEXT,
+			previous_session TEXT,
+			from_cluster INTEGER,
+			to_cluster INTEGER,
+			distance REAL,
+			timestamp INTEGER
+		);
+	`
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create cluster_jumps: %w", err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *AsyncSQLiteDB) SaveClusterJumps(jumps []models.MClusterJump) error {
+	if len(jumps) == 0 {
+		return nil
+	}
+
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO cluster_jumps (symbol, session, previous_session, from_cluster, to_cluster, distance, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, j := range jumps {
+		if _, err := stmt.Exec(j.Symbol, j.Session, j.PreviousSession, j.FromCluster, j.ToCluster, j.Distance, j.Timestamp); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"fmt"
+
+	"market-observer/src/models"
+)
+
+// Info: Separate file for the support/resistance break event log (SQLite)
+
+// -----------------------------------------------------------------------------
+
+// createLevelTables creates the level break event log (kept across restarts)
+func (d *AsyncSQLiteDB) createLevelTables() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS level_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			symbol TEXT,
+			window_name TEXT,
+			type TEXT,
+			level REAL,
+			kind TEXT,
+			source TEXT,
+			strength REAL,
+			previous_close REAL,
+			close REAL,
+			volume_ratio REAL,
+			timestamp INTEGER
+		);
+	`
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create level_events: %w", err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *AsyncSQLiteDB) SaveLevelEvents(events []models.MLevelBreakEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO level_events (symbol, window_name, type, level, kind, source, strength, previous_close, close, volume_ratio, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range events {
+		if _, err := stmt.Exec(e.Symbol, e.WindowName, e.Type, e.Level, e.Kind, e.Source, e.Strength, e.PreviousClose, e.Close, e.VolumeRatio, e.Timestamp); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"fmt"
+
+	"market-observer/src/models"
+)
+
+// Info: Separate file for the candlestick pattern event log (SQLite)
+
+// -----------------------------------------------------------------------------
+
+// createPatternTables creates the pattern event log (kept across restarts)
+func (d *AsyncSQLiteDB) createPatternTables() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS pattern_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			symbol TEXT,
+			window_name TEXT,
+			pattern TEXT,
+			direction TEXT,
+			bars INTEGER,
+			start_time INTEGER,
+			timestamp INTEGER,
+			close REAL,
+			true_ohlc INTEGER
+		);
+	`
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create pattern_events: %w", err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *AsyncSQLiteDB) SavePatternEvents(events []models.MPatternEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO pattern_events (symbol, window_name, pattern, direction, bars, start_time, timestamp, close, true_ohlc)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range events {
+		if _, err := stmt.Exec(e.Symbol, e.WindowName, e.Pattern, e.Direction, e.Bars, e.StartTime, e.Timestamp, e.Close, e.TrueOHLC); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
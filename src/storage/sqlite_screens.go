@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"market-observer/src/models"
+)
+
+// Info: Separate file for saved screens persistence (SQLite)
+
+// -----------------------------------------------------------------------------
+
+// createScreenTables creates the saved screens table (kept across restarts)
+func (d *AsyncSQLiteDB) createScreenTables() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS saved_screens (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT,
+			definition TEXT,
+			enabled INTEGER,
+			created_at INTEGER,
+			updated_at INTEGER
+		);
+	`
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create saved_screens: %w", err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *AsyncSQLiteDB) SaveScreen(screen *models.MSavedScreen) error {
+	definition, err := json.Marshal(screen.MScreenRequest)
+	if err != nil {
+		return err
+	}
+
+	if screen.ID == 0 {
+		res, err := d.DB.Exec(`
+			INSERT INTO saved_screens (name, definition, enabled, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?)
+		`, screen.Name, string(definition), screen.Enabled, screen.CreatedAt, screen.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		screen.ID, err = res.LastInsertId()
+		return err
+	}
+
+	res, err := d.DB.Exec(`
+		UPDATE saved_screens SET name = ?, definition = ?, enabled = ?, updated_at = ?
+		WHERE id = ?
+	`, screen.Name, string(definition), screen.Enabled, screen.UpdatedAt, screen.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("screen %d not found", screen.ID)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *AsyncSQLiteDB) DeleteScreen(id int64) error {
+	_, err := d.DB.Exec("DELETE FROM saved_screens WHERE id = ?", id)
+	return err
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *AsyncSQLiteDB) LoadScreens() ([]models.MSavedScreen, error) {
+	rows, err := d.DB.Query(`
+		SELECT id, name, definition, enabled, created_at, updated_at
+		FROM saved_screens ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load saved_screens: %w", err)
+	}
+	defer rows.Close()
+
+	var screens []models.MSavedScreen
+	for rows.Next() {
+		var s models.MSavedScreen
+		var definition string
+		if err := rows.Scan(&s.ID, &s.Name, &definition, &s.Enabled, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan saved_screens: %w", err)
+		}
+		if err := json.Unmarshal([]byte(definition), &s.MScreenRequest); err != nil {
+			return nil, fmt.Errorf("invalid definition of screen %d: %w", s.ID, err)
+		}
+		screens = append(screens, s)
+	}
+	return screens, rows.Err()
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"fmt"
+
+	"market-observer/src/models"
+)
+
+// Info: Separate file for quarantined inbound points (SQLite)
+
+// -----------------------------------------------------------------------------
+
+// createValidationTables creates the quarantine table (kept across restarts)
+func (d *AsyncSQLiteDB) createValidationTables() error {
+	query := `
+		CREATE TABLE IF NOT EXISTS quarantined_prices (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			symbol TEXT,
+			price REAL,
+			volume REAL,
+			timestamp INTEGER,
+			fetched_at INTEGER,
+			reason TEXT,
+			detail TEXT,
+			quarantined_at INTEGER,
+			released_at INTEGER DEFAULT 0
+		);
+	`
+	if _, err := d.DB.Exec(query); err != nil {
+		return fmt.Errorf("failed to create quarantined_prices: %w", err)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *AsyncSQLiteDB) SaveQuarantinedPrice(q *models.MQuarantinedPrice) error {
+	res, err := d.DB.Exec(`
+		INSERT INTO quarantined_prices (symbol, price, volume, timestamp, fetched_at, reason, detail, quarantined_at, released_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, q.Price.Symbol, q.Price.Price, q.Price.Volume, q.Price.Timestamp, q.Price.FetchedAt, q.Reason, q.Detail, q.QuarantinedAt, q.ReleasedAt)
+	if err != nil {
+		return err
+	}
+	q.ID, err = res.LastInsertId()
+	return err
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *AsyncSQLiteDB) ReleaseQuarantinedPrice(id int64, releasedAt int64) error {
+	_, err := d.DB.Exec("UPDATE quarantined_prices SET released_at = ? WHERE id = ?", releasedAt, id)
+	return err
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *AsyncSQLiteDB) LoadQuarantinedPrices() ([]models.MQuarantinedPrice, error) {
+	rows, err := d.DB.Query(`
+		SELECT id, symbol, price, volume, timestamp, fetched_at, reason, detail, quarantined_at
+		FROM quarantined_prices WHERE released_at = 0 ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load quarantined_prices: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.MQuarantinedPrice
+	for rows.Next() {
+		var q models.MQuarantinedPrice
+		if err := rows.Scan(&q.ID, &q.Price.Symbol, &q.Price.Price, &q.Price.Volume, &q.Price.Timestamp, &q.Price.FetchedAt, &q.Reason, &q.Detail, &q.QuarantinedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, q)
+	}
+	return result, rows.Err()
+}
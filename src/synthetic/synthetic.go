@@ -0,0 +1,315 @@
+package synthetic
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+var (
+	// ErrInstrumentNotFound is returned when a synthetic instrument name is unknown
+	ErrInstrumentNotFound = errors.New("synthetic instrument not found")
+	// ErrInstrumentExists is returned when a synthetic instrument name is taken
+	ErrInstrumentExists = errors.New("synthetic instrument already exists")
+)
+
+// -----------------------------------------------------------------------------
+// Builder is the stage between validation and the data loop that computes the
+// points of synthetic instruments (ratios, hedged spreads, weighted baskets)
+// from the points of their legs. A synthetic point is produced once every leg
+// has a point at the same timestamp; from there on it is aggregated, analyzed,
+// stored and broadcast like the point of any real symbol.
+// -----------------------------------------------------------------------------
+
+type Builder struct {
+	Config *models.MConfig
+	Logger *logger.Logger
+
+	maxPending  int
+	instruments map[string]*instrument // name -> state
+	bySymbol    map[string][]string    // leg symbol -> instrument names
+	mu          sync.Mutex
+}
+
+type instrument struct {
+	def     models.MSyntheticInstrument
+	pending map[int64]map[string]models.MStockPrice // timestamp -> leg symbol -> point
+	last    models.MStockPrice                      // Last point produced
+	hasLast bool
+}
+
+// -----------------------------------------------------------------------------
+
+func NewBuilder(cfg *models.MConfig, log *logger.Logger) *Builder {
+	b := &Builder{
+		Config:      cfg,
+		Logger:      log,
+		maxPending:  utils.DefaultSyntheticMaxPending,
+		instruments: make(map[string]*instrument),
+		bySymbol:    make(map[string][]string),
+	}
+	for _, def := range cfg.Synthetics {
+		b.add(def)
+	}
+	return b
+}
+
+// -----------------------------------------------------------------------------
+
+// Start runs the synthetic stage: batches from in are forwarded to out with the
+// synthetic points they complete. out is closed when the stage stops.
+func (b *Builder) Start(ctx context.Context, wg *sync.WaitGroup, in <-chan map[string][]models.MStockPrice, out chan<- map[string][]models.MStockPrice) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case updates, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- b.Apply(updates):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+}
+
+// -----------------------------------------------------------------------------
+
+// Apply returns a batch (symbol -> points, oldest first) extended with the
+// points of the synthetic instruments completed by it.
+func (b *Builder) Apply(updates map[string][]models.MStockPrice) map[string][]models.MStockPrice {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.instruments) == 0 {
+		return updates
+	}
+
+	touched := make(map[string]bool)
+	for sym, points := range updates {
+		for _, name := range b.bySymbol[sym] {
+			inst := b.instruments[name]
+			for _, p := range points {
+				inst.collect(p)
+			}
+			touched[name] = true
+		}
+	}
+	if len(touched) == 0 {
+		return updates
+	}
+
+	batch := make(map[string][]models.MStockPrice, len(updates)+len(touched))
+	for sym, points := range updates {
+		batch[sym] = points
+	}
+	for name := range touched {
+		if points := b.instruments[name].complete(b.maxPending); len(points) > 0 {
+			batch[name] = append(batch[name], points...)
+		}
+	}
+	return batch
+}
+
+// -----------------------------------------------------------------------------
+
+// List returns the synthetic instrument definitions sorted by name.
+func (b *Builder) List() []models.MSyntheticInstrument {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	defs := make([]models.MSyntheticInstrument, 0, len(b.instruments))
+	for _, inst := range b.instruments {
+		defs = append(defs, inst.def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// -----------------------------------------------------------------------------
+
+// Add registers a validated synthetic instrument. Its points start with the
+// next batch of its legs (history is rebuilt from the legs on the next start).
+func (b *Builder) Add(def models.MSyntheticInstrument) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.instruments[def.Name]; ok {
+		return ErrInstrumentExists
+	}
+	b.add(def)
+	b.Logger.Info("Added synthetic instrument %s (%s of %d legs)", def.Name, def.Type, len(def.Legs))
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// Remove stops producing points for a synthetic instrument.
+func (b *Builder) Remove(name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	inst, ok := b.instruments[name]
+	if !ok {
+		return ErrInstrumentNotFound
+	}
+	delete(b.instruments, name)
+
+	for _, leg := range inst.def.Legs {
+		names := b.bySymbol[leg.Symbol][:0]
+		for _, n := range b.bySymbol[leg.Symbol] {
+			if n != name {
+				names = append(names, n)
+			}
+		}
+		if len(names) == 0 {
+			delete(b.bySymbol, leg.Symbol)
+		} else {
+			b.bySymbol[leg.Symbol] = names
+		}
+	}
+	b.Logger.Info("Removed synthetic instrument %s", name)
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// add registers an instrument (caller holds the lock).
+func (b *Builder) add(def models.MSyntheticInstrument) {
+	b.instruments[def.Name] = &instrument{
+		def:     def,
+		pending: make(map[int64]map[string]models.MStockPrice),
+	}
+	for _, leg := range def.Legs {
+		b.bySymbol[leg.Symbol] = append(b.bySymbol[leg.Symbol], def.Name)
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// collect keeps a leg point until the other legs reach its timestamp. Points
+// at or before the last produced timestamp are dropped: that synthetic point
+// is already aggregated and stored.
+func (inst *instrument) collect(p models.MStockPrice) {
+	if inst.hasLast && p.Timestamp <= inst.last.Timestamp {
+		return // Late point or revision of a produced timestamp
+	}
+	legs, ok := inst.pending[p.Timestamp]
+	if !ok {
+		legs = make(map[string]models.MStockPrice, len(inst.def.Legs))
+		inst.pending[p.Timestamp] = legs
+	}
+	legs[p.Symbol] = p
+}
+
+// -----------------------------------------------------------------------------
+
+// complete returns the points of the timestamps every leg reached (oldest
+// first). Incomplete timestamps before a completed one are dropped, as are the
+// oldest ones beyond maxPending (a leg that stopped printing).
+func (inst *instrument) complete(maxPending int) []models.MStockPrice {
+	var points []models.MStockPrice
+	timestamps := make([]int64, 0, len(inst.pending))
+	for ts := range inst.pending {
+		timestamps = append(timestamps, ts)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+
+	for _, ts := range timestamps {
+		legs := inst.pending[ts]
+		if len(legs) < len(inst.def.Legs) {
+			continue
+		}
+		if p, ok := inst.compute(ts, legs, inst.last, inst.hasLast); ok {
+			points = append(points, p)
+			inst.last, inst.hasLast = p, true
+		}
+	}
+
+	for _, ts := range timestamps {
+		if (inst.hasLast && ts <= inst.last.Timestamp) || len(inst.pending) > maxPending {
+			delete(inst.pending, ts)
+		}
+	}
+	return points
+}
+
+// -----------------------------------------------------------------------------
+
+// compute combines the leg points of a timestamp, with percent changes from
+// the previous point. Ratios and spreads trade at the volume of their thinnest
+// leg; baskets at the weighted sum of volumes. False when the ratio
+// denominator is zero.
+func (inst *instrument) compute(ts int64, legs map[string]models.MStockPrice, previous models.MStockPrice, hasPrevious bool) (models.MStockPrice, bool) {
+	def := inst.def
+
+	var price, volume float64
+	var fetchedAt int64
+	for i, leg := range def.Legs {
+		p := legs[leg.Symbol]
+		w := leg.Weight
+		if w == 0 {
+			w = 1
+		}
+
+		switch def.Type {
+		case utils.SyntheticRatio:
+			if i == 0 {
+				price = p.Price
+			} else if p.Price == 0 {
+				return models.MStockPrice{}, false
+			} else {
+				price /= p.Price
+			}
+		case utils.SyntheticSpread:
+			if i == 0 {
+				price = w * p.Price
+			} else {
+				price -= w * p.Price
+			}
+		case utils.SyntheticBasket:
+			price += w * p.Price
+		}
+
+		if def.Type == utils.SyntheticBasket {
+			volume += math.Abs(w) * p.Volume
+		} else if i == 0 || p.Volume < volume {
+			volume = p.Volume
+		}
+		if p.FetchedAt > fetchedAt {
+			fetchedAt = p.FetchedAt
+		}
+	}
+	price += def.Offset
+
+	point := models.MStockPrice{
+		Symbol:    def.Name,
+		Price:     price,
+		Volume:    volume,
+		Timestamp: ts,
+		FetchedAt: fetchedAt,
+		CreatedAt: time.Now().UTC(),
+	}
+	if hasPrevious {
+		point.PricePercentChange = core.CalculateChangePercent(price, previous.Price)
+		point.VolumePercentChange = core.CalculateChangePercent(volume, previous.Volume)
+	}
+	return point, true
+}
@@ -0,0 +1,176 @@
+package synthetic
+
+import (
+	"math"
+	"testing"
+
+	"market-observer/src/analysis/core"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+func newTestBuilder(defs ...models.MSyntheticInstrument) *Builder {
+	cfg := &models.MConfig{Synthetics: defs}
+	return NewBuilder(cfg, logger.NewLogger(cfg, "test"))
+}
+
+func legPoint(symbol string, ts int64, price, volume float64) models.MStockPrice {
+	return models.MStockPrice{Symbol: symbol, Timestamp: ts, Price: price, Volume: volume}
+}
+
+// batch groups points by symbol (oldest first).
+func batch(points ...models.MStockPrice) map[string][]models.MStockPrice {
+	updates := make(map[string][]models.MStockPrice)
+	for _, p := range points {
+		updates[p.Symbol] = append(updates[p.Symbol], p)
+	}
+	return updates
+}
+
+var ratioDef = models.MSyntheticInstrument{
+	Name: "AAA/BBB", Type: utils.SyntheticRatio,
+	Legs: []models.MSyntheticLeg{{Symbol: "AAA"}, {Symbol: "BBB"}},
+}
+
+func TestBuilderCompute(t *testing.T) {
+	tests := []struct {
+		name   string
+		def    models.MSyntheticInstrument
+		price  float64
+		volume float64
+		ok     bool
+	}{
+		{"ratio", ratioDef, 2, 100, true},
+		{"hedged spread", models.MSyntheticInstrument{Name: "S", Type: utils.SyntheticSpread, Offset: 10,
+			Legs: []models.MSyntheticLeg{{Symbol: "AAA"}, {Symbol: "BBB", Weight: 1.5}}}, 10 + 100 - 1.5*50, 100, true},
+		{"weighted basket", models.MSyntheticInstrument{Name: "B", Type: utils.SyntheticBasket,
+			Legs: []models.MSyntheticLeg{{Symbol: "AAA", Weight: 0.5}, {Symbol: "BBB", Weight: -2}}}, 0.5*100 - 2*50, 0.5*300 + 2*100, true},
+		{"zero denominator", ratioDef, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inst := &instrument{def: tt.def}
+			legs := map[string]models.MStockPrice{"AAA": legPoint("AAA", 60, 100, 300), "BBB": legPoint("BBB", 60, 50, 100)}
+			if !tt.ok {
+				legs["BBB"] = legPoint("BBB", 60, 0, 100)
+			}
+			p, ok := inst.compute(60, legs, models.MStockPrice{}, false)
+			if ok != tt.ok {
+				t.Fatalf("compute ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && (math.Abs(p.Price-tt.price) > 1e-9 || p.Volume != tt.volume || p.Symbol != tt.def.Name || p.Timestamp != 60) {
+				t.Errorf("point = %+v, want price %v volume %v", p, tt.price, tt.volume)
+			}
+		})
+	}
+}
+
+func TestBuilderAlignsLegs(t *testing.T) {
+	b := newTestBuilder(ratioDef)
+
+	// One leg only: nothing yet, the real points pass through
+	out := b.Apply(batch(legPoint("AAA", 60, 100, 10), legPoint("CCC", 60, 1, 1)))
+	if len(out["AAA/BBB"]) != 0 || len(out["AAA"]) != 1 || len(out["CCC"]) != 1 {
+		t.Fatalf("batch = %+v", out)
+	}
+
+	// The second leg completes 60; 120 and 180 wait for AAA
+	out = b.Apply(batch(legPoint("BBB", 60, 50, 20), legPoint("BBB", 120, 40, 20), legPoint("BBB", 180, 40, 20)))
+	if got := out["AAA/BBB"]; len(got) != 1 || got[0].Timestamp != 60 || got[0].Price != 2 || got[0].Volume != 10 {
+		t.Fatalf("synthetic points = %+v", got)
+	}
+
+	// AAA skips 120: it is dropped once 180 completes
+	out = b.Apply(batch(legPoint("AAA", 180, 120, 10)))
+	got := out["AAA/BBB"]
+	if len(got) != 1 || got[0].Timestamp != 180 || got[0].Price != 3 {
+		t.Fatalf("synthetic points = %+v", got)
+	}
+	if math.Abs(got[0].PricePercentChange-core.CalculateChangePercent(3, 2)) > 1e-9 {
+		t.Errorf("price change = %v, want 50%% from 2 to 3", got[0].PricePercentChange)
+	}
+	if n := len(b.instruments["AAA/BBB"].pending); n != 0 {
+		t.Errorf("%d pending timestamps left", n)
+	}
+
+	// Without synthetic instruments the batch is returned as is
+	if out := newTestBuilder().Apply(batch(legPoint("AAA", 240, 1, 1))); len(out) != 1 {
+		t.Errorf("batch = %+v", out)
+	}
+}
+
+func TestBuilderDropsRevisions(t *testing.T) {
+	b := newTestBuilder(ratioDef)
+	b.Apply(batch(legPoint("AAA", 60, 100, 10), legPoint("BBB", 60, 50, 10)))
+
+	// A revised or late leg point of a produced timestamp produces nothing:
+	// the synthetic point is already aggregated and stored
+	out := b.Apply(batch(legPoint("AAA", 60, 110, 30), legPoint("BBB", 0, 50, 10)))
+	if len(out["AAA/BBB"]) != 0 {
+		t.Fatalf("revision produced %+v", out["AAA/BBB"])
+	}
+	if n := len(b.instruments["AAA/BBB"].pending); n != 0 {
+		t.Errorf("%d pending timestamps after a revision", n)
+	}
+
+	out = b.Apply(batch(legPoint("AAA", 120, 100, 10), legPoint("BBB", 120, 25, 10)))
+	if got := out["AAA/BBB"]; len(got) != 1 || got[0].Timestamp != 120 || got[0].Price != 4 {
+		t.Errorf("synthetic points = %+v", got)
+	}
+}
+
+func TestBuilderMaxPending(t *testing.T) {
+	b := newTestBuilder(ratioDef)
+	b.maxPending = 3
+
+	// BBB stopped printing: only the newest maxPending timestamps are kept
+	for ts := int64(60); ts <= 300; ts += 60 {
+		b.Apply(batch(legPoint("AAA", ts, 100, 10)))
+	}
+	pending := b.instruments["AAA/BBB"].pending
+	if len(pending) != 3 {
+		t.Fatalf("%d pending timestamps, want 3", len(pending))
+	}
+	for _, ts := range []int64{180, 240, 300} {
+		if _, ok := pending[ts]; !ok {
+			t.Errorf("timestamp %d evicted", ts)
+		}
+	}
+
+	// BBB resumes on an evicted timestamp, then on a kept one
+	if out := b.Apply(batch(legPoint("BBB", 60, 50, 10))); len(out["AAA/BBB"]) != 0 {
+		t.Errorf("evicted timestamp produced %+v", out["AAA/BBB"])
+	}
+	if out := b.Apply(batch(legPoint("BBB", 240, 50, 10))); len(out["AAA/BBB"]) != 1 || out["AAA/BBB"][0].Timestamp != 240 {
+		t.Errorf("synthetic points = %+v", out["AAA/BBB"])
+	}
+}
+
+func TestBuilderAddRemove(t *testing.T) {
+	b := newTestBuilder(ratioDef)
+	if err := b.Add(ratioDef); err != ErrInstrumentExists {
+		t.Errorf("Add of an existing name error = %v", err)
+	}
+	basket := models.MSyntheticInstrument{Name: "BASKET", Type: utils.SyntheticBasket, Legs: []models.MSyntheticLeg{{Symbol: "AAA"}}}
+	if err := b.Add(basket); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if out := b.Apply(batch(legPoint("AAA", 60, 100, 10))); len(out["BASKET"]) != 1 {
+		t.Errorf("basket points = %+v", out["BASKET"])
+	}
+
+	if err := b.Remove("AAA/BBB"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := b.Remove("AAA/BBB"); err != ErrInstrumentNotFound {
+		t.Errorf("second Remove error = %v", err)
+	}
+	if _, ok := b.bySymbol["BBB"]; ok || len(b.bySymbol["AAA"]) != 1 {
+		t.Errorf("legs after Remove = %v", b.bySymbol)
+	}
+	if defs := b.List(); len(defs) != 1 || defs[0].Name != "BASKET" {
+		t.Errorf("List = %+v", defs)
+	}
+}
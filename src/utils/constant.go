@@ -15,6 +15,253 @@ const (
 	DefaultRetentionDays = 7
 )
 
+// Rolling statistics defaults (used when rolling_stats is omitted from config).
+const (
+	DefaultStatsMethod          = "ewma"
+	DefaultStatsLookback        = 100
+	DefaultStatsPersistInterval = 300 // seconds
+)
+
+// Candles whose window has ended are closed on this wall-clock tick, one
+// update interval after their end (points stamped before it may still arrive).
+const CandleCloseCheckInterval = 5 // seconds
+
+// Cross-symbol correlation defaults (used when correlation is omitted from config).
+const (
+	DefaultCorrelationLookback          = 50
+	DefaultCorrelationBaselineLookback  = 200
+	DefaultCorrelationMinObservations   = 20
+	DefaultCorrelationBreakdownBaseline = 0.7
+	DefaultCorrelationBreakdownDrop     = 0.5
+	DefaultCorrelationMaxAlerts         = 500 // Recent alerts kept in memory
+)
+
+// Alert rules defaults.
+const (
+	DefaultAlertMaxEvents = 1000 // Recent alert events kept in memory
+)
+
+// Notification dispatcher defaults.
+const (
+	DefaultNotifyDedupWindow    = 300 // seconds
+	DefaultNotifyQueueSize      = 256
+	DefaultNotifyMaxDeliveries  = 1000
+	DefaultNotifyRatePerMinute  = 30
+	DefaultNotifyMaxRetries     = 3
+	DefaultNotifyInitialBackoff = 500   // milliseconds
+	DefaultNotifyMaxBackoff     = 30000 // milliseconds
+	DefaultNotifyTimeout        = 10    // seconds
+)
+
+// Data quality defaults.
+const (
+	GapPolicyNone        = "none"
+	GapPolicyForwardFill = "forward_fill"
+	GapPolicyInterpolate = "interpolate"
+
+	DefaultGapPolicy       = GapPolicyNone
+	DefaultBarInterval     = 300 // seconds (5-minute base resolution)
+	DefaultMaxFillBars     = 12
+	DefaultDataQualityGaps = 200 // Recent gaps kept per symbol
+)
+
+// Benchmark defaults.
+const (
+	DefaultBenchmarkLookback        = 60
+	DefaultBenchmarkMinObservations = 20
+)
+
+// Market breadth defaults.
+const (
+	DefaultBreadthAveragePeriod = 20
+	DefaultBreadthAnomalyRatio  = 3.0
+	DefaultBreadthMaxHistory    = 500
+)
+
+// Leaderboard names and defaults.
+const (
+	LeaderboardGainers         = "gainers"          // price_percent_change, highest first
+	LeaderboardLosers          = "losers"           // price_percent_change, lowest first
+	LeaderboardVolumeAnomalies = "volume_anomalies" // volume_anomaly_ratio, highest first
+	LeaderboardCorrelationHigh = "correlation_high" // price_volume_correlation, highest first
+	LeaderboardCorrelationLow  = "correlation_low"  // price_volume_correlation, lowest first
+
+	DefaultLeaderboardSize = 10
+)
+
+// Synthetic instrument types and defaults.
+const (
+	SyntheticRatio  = "ratio"  // first / second
+	SyntheticSpread = "spread" // first - sum(hedge * others)
+	SyntheticBasket = "basket" // sum(weight * members)
+
+	DefaultSyntheticMaxPending = 64 // Incomplete timestamps kept per instrument
+)
+
+// Candlestick pattern names, directions and defaults.
+const (
+	PatternDoji                    = "doji"
+	PatternHammer                  = "hammer"
+	PatternBullishEngulfing        = "bullish_engulfing"
+	PatternBearishEngulfing        = "bearish_engulfing"
+	PatternMorningStar             = "morning_star"
+	PatternEveningStar             = "evening_star"
+	PatternInsideBar               = "inside_bar"
+	PatternOutsideBar              = "outside_bar"
+	PatternBullishThreeBarReversal = "bullish_three_bar_reversal"
+	PatternBearishThreeBarReversal = "bearish_three_bar_reversal"
+
+	PatternBullish = "bullish"
+	PatternBearish = "bearish"
+	PatternNeutral = "neutral"
+
+	DefaultPatternDojiBodyRatio = 0.1
+	DefaultPatternShadowRatio   = 2.0
+	DefaultPatternStarBodyRatio = 0.3
+	DefaultPatternMaxEvents     = 500
+	PatternMaxBars              = 3 // Closed candles kept per symbol/window
+)
+
+// CandlePatterns lists every pattern the detector knows.
+var CandlePatterns = []string{
+	PatternDoji, PatternHammer, PatternBullishEngulfing, PatternBearishEngulfing,
+	PatternMorningStar, PatternEveningStar, PatternInsideBar, PatternOutsideBar,
+	PatternBullishThreeBarReversal, PatternBearishThreeBarReversal,
+}
+
+// Support/resistance level kinds, sources, events and defaults.
+const (
+	LevelSupport    = "support"
+	LevelResistance = "resistance"
+
+	LevelSourceSwing  = "swing"
+	LevelSourceVolume = "volume"
+	LevelSourceBoth   = "swing+volume"
+
+	LevelBreakout  = "breakout"  // Close crossed above a level
+	LevelBreakdown = "breakdown" // Close crossed below a level
+
+	DefaultLevelRefreshInterval     = 900 // Seconds
+	DefaultLevelSwingBars           = 5
+	DefaultLevelBucketPct           = 0.0025
+	DefaultLevelVolumeNodeRatio     = 2.0
+	DefaultLevelMergePct            = 0.005
+	DefaultLevelMaxLevels           = 10
+	DefaultLevelBreakoutVolumeRatio = 1.5
+	DefaultLevelMaxEvents           = 500
+	LevelMaxBuckets                 = 2000 // Volume-by-price buckets per symbol (wider buckets beyond)
+)
+
+// Lead-lag defaults.
+const (
+	DefaultLeadLagMaxLag          = 6
+	DefaultLeadLagLookback        = 390
+	DefaultLeadLagSegments        = 4
+	DefaultLeadLagMinObservations = 30
+	LeadLagMinSegmentObservations = 10 // Overlapping returns needed in a stability sub-period
+)
+
+// Clustering defaults.
+const (
+	DefaultClusterCount           = 5
+	DefaultClusterLookbackDays    = 5
+	DefaultClusterSlots           = 13 // Half hours of a US session
+	DefaultClusterMinPoints       = 20
+	DefaultClusterRefreshInterval = 3600 // Seconds
+	DefaultClusterRestarts        = 5
+	DefaultClusterMaxJumps        = 500
+	ClusterIterations             = 100 // Lloyd iterations per k-means run
+	ClusterSeed                   = 1   // Deterministic k-means seeding
+)
+
+// Event study models and defaults.
+const (
+	EventStudyModelMarket = "market" // Abnormal = return - benchmark return
+	EventStudyModelBeta   = "beta"   // Abnormal = return - beta * benchmark return
+
+	DefaultEventStudyModel           = EventStudyModelMarket
+	DefaultEventStudyRefreshInterval = 3600 // Seconds
+	DefaultEventStudyMaxEvents       = 1000
+	EventStudyAll                    = "*" // Symbol or severity of the groups pooling all of them
+)
+
+var (
+	DefaultEventStudyHorizons   = []int{1, 3, 6, 12} // Windows after the event
+	DefaultEventStudySeverities = []float64{2, 3, 5} // Volume anomaly ratio bounds
+)
+
+// Volume profile sources and defaults.
+const (
+	ProfileSourceMemory  = "memory"  // Ring buffer of the MemoryManager
+	ProfileSourceStorage = "storage" // stock_prices table
+
+	DefaultProfileBuckets      = 50
+	DefaultProfileValueAreaPct = 0.70
+	DefaultProfileMaxBuckets   = 1000
+)
+
+// Volume seasonality defaults.
+const (
+	SeasonalityMethodFlat   = "flat"
+	SeasonalityMethodMean   = "mean"
+	SeasonalityMethodMedian = "median"
+
+	DefaultSeasonalityLookbackDays = 20
+	DefaultSeasonalityMinDays      = 5
+	MADScale                       = 1.4826 // MAD -> std for normal data
+)
+
+// Changepoint detection defaults.
+const (
+	ChangepointMethodCUSUM  = "cusum"
+	ChangepointMethodBOCPD  = "bocpd"
+	ChangepointSeriesReturn = "return"
+	ChangepointSeriesVolume = "volume"
+
+	DefaultChangepointMethod     = ChangepointMethodCUSUM
+	DefaultChangepointThreshold  = 8.0
+	DefaultChangepointDrift      = 0.5
+	DefaultChangepointHazard     = 250.0
+	DefaultChangepointConfidence = 0.5
+	DefaultChangepointMinSegment = 10
+	DefaultChangepointMaxEvents  = 500
+	ChangepointMaxSegment        = 1000 // Observations kept per regime
+	ChangepointMaxRunLength      = 500  // BOCPD run lengths tracked
+	ChangepointMinDeparture      = 3.0  // BOCPD: z of a new regime past its first observation
+)
+
+// Volatility defaults.
+const (
+	VolEstimatorRealized    = "realized"
+	VolEstimatorParkinson   = "parkinson"
+	VolEstimatorGarmanKlass = "garman_klass"
+	VolEstimatorYangZhang   = "yang_zhang"
+
+	DefaultVolLookback         = 20
+	DefaultVolRegimeEstimator  = VolEstimatorYangZhang
+	DefaultVolRegimeLookback   = 250
+	DefaultVolRegimeMinSamples = 30
+	DefaultVolHighPercentile   = 0.9
+	DefaultVolLowPercentile    = 0.1
+)
+
+// Inbound validation defaults.
+const (
+	ValidationReasonPriceJump  = "price_jump"
+	ValidationReasonStale      = "stale_print"
+	ValidationReasonRegression = "timestamp_regression"
+	ValidationReasonFuture     = "future_timestamp"
+
+	DefaultValidationJumpSigma     = 8.0
+	DefaultValidationMinJumpPct    = 0.02
+	DefaultValidationMinSamples    = 20
+	DefaultValidationConfirmPoints = 3
+	DefaultValidationStaleRepeats  = 5
+	DefaultValidationMaxFuture     = 60 // seconds
+	DefaultValidationMaxQuarantine = 1000
+	ValidationVolatilityLambda     = 0.94 // EWMA decay of squared per-bar returns
+)
+
 // -----------------------------------------------------------------------------
 
 // -----------------------------------------------------------------------------
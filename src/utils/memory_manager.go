@@ -134,21 +134,36 @@ func (mm *MemoryManager) getSymbolData(symbol string, allData bool) interface{}
 // -----------------------------------------------------------------------------
 
 // GetLatestArrays returns all data as structured arrays (matches Python)
-func (mm *MemoryManager) GetLatestArrays(symbol string) [][5]float64 {
+func (mm *MemoryManager) GetLatestArrays(symbol string) [][models.RB_NUM_FEATURES]float64 {
 	mm.mu.RLock()
 	defer mm.mu.RUnlock()
 
 	buffer, ok := mm.DataStreams[symbol]
 	if !ok || buffer.Size() == 0 {
-		return [][5]float64{}
+		return [][models.RB_NUM_FEATURES]float64{}
 	}
 
-	// Get snapshot as 2D array
-	snapshot := buffer.GetSnapshot()
+	// Get snapshot as 2D array (one row of RB_NUM_FEATURES per point)
+	return buffer.GetSnapshot()
+}
+
+// -----------------------------------------------------------------------------
 
-	// Convert to [][5]float64 if needed
-	// (Assuming GetSnapshot returns [][5]float64)
-	return snapshot
+// GetHistory returns the points of a symbol, oldest first (safe to call from
+// any goroutine, unlike reading the buffer from GetBuffer).
+func (mm *MemoryManager) GetHistory(symbol string) []models.MStockPrice {
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+
+	buffer, ok := mm.DataStreams[symbol]
+	if !ok {
+		return nil
+	}
+	points := buffer.GetAll()
+	for i := range points {
+		points[i].Symbol = symbol
+	}
+	return points
 }
 
 // -----------------------------------------------------------------------------
@@ -241,3 +256,17 @@ func (mm *MemoryManager) SymbolCount() int {
 
 	return len(mm.DataStreams)
 }
+
+// -----------------------------------------------------------------------------
+
+// Symbols returns the symbols currently held in memory
+func (mm *MemoryManager) Symbols() []string {
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+
+	symbols := make([]string, 0, len(mm.DataStreams))
+	for sym := range mm.DataStreams {
+		symbols = append(symbols, sym)
+	}
+	return symbols
+}
@@ -26,7 +26,7 @@ func NewRingBuffer(capacity int) *RingBuffer {
 	}
 
 	return &RingBuffer{
-		data:     make([][5]float64, capacity),
+		data:     make([][models.RB_NUM_FEATURES]float64, capacity),
 		capacity: capacity,
 		index:    0,
 		size:     0,
@@ -43,6 +43,9 @@ func (rb *RingBuffer) Append(point models.MStockPrice) {
 		point.Volume,
 		point.PricePercentChange,
 		point.VolumePercentChange,
+		point.Open,
+		point.High,
+		point.Low,
 	}
 
 	rb.index = (rb.index + 1) % rb.capacity
@@ -82,6 +85,9 @@ func (rb *RingBuffer) GetLatest(n int) []models.MStockPrice {
 			Volume:              row[models.RB_IDX_VOLUME],
 			PricePercentChange:  row[models.RB_IDX_PRICE_PCT],
 			VolumePercentChange: row[models.RB_IDX_VOL_PCT],
+			Open:                row[models.RB_IDX_OPEN],
+			High:                row[models.RB_IDX_HIGH],
+			Low:                 row[models.RB_IDX_LOW],
 		}
 	}
 
@@ -119,6 +125,9 @@ func (rb *RingBuffer) GetAll() []models.MStockPrice {
 			Volume:              row[models.RB_IDX_VOLUME],
 			PricePercentChange:  row[models.RB_IDX_PRICE_PCT],
 			VolumePercentChange: row[models.RB_IDX_VOL_PCT],
+			Open:                row[models.RB_IDX_OPEN],
+			High:                row[models.RB_IDX_HIGH],
+			Low:                 row[models.RB_IDX_LOW],
 		}
 	}
 
@@ -128,12 +137,12 @@ func (rb *RingBuffer) GetAll() []models.MStockPrice {
 // -----------------------------------------------------------------------------
 
 // GetSnapshot returns data as 2D array
-func (rb *RingBuffer) GetSnapshot() [][5]float64 {
+func (rb *RingBuffer) GetSnapshot() [][models.RB_NUM_FEATURES]float64 {
 	if rb.size == 0 {
-		return [][5]float64{}
+		return [][models.RB_NUM_FEATURES]float64{}
 	}
 
-	result := make([][5]float64, rb.size)
+	result := make([][models.RB_NUM_FEATURES]float64, rb.size)
 
 	// Calculate start index
 	var startIdx int
@@ -178,7 +187,7 @@ func (rb *RingBuffer) Resize(newCapacity int) {
 	}
 
 	// Create new buffer
-	newData := make([][5]float64, newCapacity)
+	newData := make([][models.RB_NUM_FEATURES]float64, newCapacity)
 
 	// Copy existing data
 	// If expanding: copy all
@@ -3,6 +3,7 @@ package utils
 import (
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/scmhub/calendar"
@@ -15,6 +16,9 @@ type TradingCalendar struct {
 	Timezone *time.Location
 }
 
+// calendarCache memoizes calendars per MIC (building one loads years of holidays)
+var calendarCache sync.Map // mic -> *TradingCalendar
+
 // -----------------------------------------------------------------------------
 
 func GetCalendar(symbol string) *TradingCalendar {
@@ -65,6 +69,18 @@ func GetCalendar(symbol string) *TradingCalendar {
 		mic = "xshe"
 	}
 
+	if cached, ok := calendarCache.Load(mic); ok {
+		return cached.(*TradingCalendar)
+	}
+	tc := loadCalendar(mic)
+	calendarCache.Store(mic, tc)
+	return tc
+}
+
+// -----------------------------------------------------------------------------
+
+// loadCalendar builds the TradingCalendar for a MIC code
+func loadCalendar(mic string) *TradingCalendar {
 	// scmhub/calendar.GetCalendar returns a calendar by MIC
 	cal := calendar.GetCalendar(mic)
 	if cal == nil {
@@ -128,3 +144,73 @@ func (tc *TradingCalendar) IsOpenOnMinute(t time.Time) bool {
 
 	return tc.Calendar.IsOpen(t)
 }
+
+// -----------------------------------------------------------------------------
+
+// SessionOpen returns the regular session open of the trading date of t
+// (in the exchange timezone).
+func (tc *TradingCalendar) SessionOpen(t time.Time) time.Time {
+	if tc.Timezone != nil {
+		t = t.In(tc.Timezone)
+	}
+	bod := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	if tc.Fallback {
+		return bod.Add(9*time.Hour + 30*time.Minute)
+	}
+	return bod.Add(tc.Calendar.Session().Open)
+}
+
+// -----------------------------------------------------------------------------
+
+// SessionClose returns the regular (or early) session close of the trading date of t.
+func (tc *TradingCalendar) SessionClose(t time.Time) time.Time {
+	if tc.Timezone != nil {
+		t = t.In(tc.Timezone)
+	}
+	bod := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	if tc.Fallback {
+		return bod.Add(16 * time.Hour)
+	}
+	session := tc.Calendar.Session()
+	if session.EarlyClose != 0 && tc.Calendar.IsEarlyClose(t) {
+		return bod.Add(session.EarlyClose)
+	}
+	return bod.Add(session.Close)
+}
+
+// -----------------------------------------------------------------------------
+
+// SessionBounds returns the regular session containing t, or the last one
+// before it (off-hours and non-trading days belong to the previous session),
+// together with the open of the following session.
+func (tc *TradingCalendar) SessionBounds(t time.Time) (open, close, nextOpen time.Time) {
+	if tc.Timezone != nil {
+		t = t.In(tc.Timezone)
+	}
+
+	day := t
+	for i := 0; i < 14; i++ {
+		if tc.IsTradingDay(day) {
+			if o := tc.SessionOpen(day); !t.Before(o) {
+				open, close = o, tc.SessionClose(day)
+				break
+			}
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+	if open.IsZero() {
+		// No trading day found (broken calendar): use today's hours
+		open, close = tc.SessionOpen(t), tc.SessionClose(t)
+		if t.Before(open) {
+			open, close = tc.SessionOpen(t.AddDate(0, 0, -1)), tc.SessionClose(t.AddDate(0, 0, -1))
+		}
+	}
+
+	next := open.AddDate(0, 0, 1)
+	for i := 0; i < 14 && !tc.IsTradingDay(next); i++ {
+		next = next.AddDate(0, 0, 1)
+	}
+	return open, close, tc.SessionOpen(next)
+}
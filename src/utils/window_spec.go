@@ -0,0 +1,269 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Window anchors
+const (
+	WindowAnchorEpoch   = "epoch"   // Fixed windows aligned on the Unix epoch (ts - ts % length)
+	WindowAnchorSession = "session" // Fixed windows aligned on the exchange session open
+)
+
+// Calendar periods (exchange timezone)
+const (
+	WindowPeriodSession = "session" // Regular session (open -> close), off-hours as one extended window
+	WindowPeriodDay     = "day"
+	WindowPeriodWeek    = "week" // Monday 00:00 -> next Monday 00:00
+	WindowPeriodMonth   = "month"
+)
+
+// -----------------------------------------------------------------------------
+// WindowSpec describes how a `windows_aggregation` entry cuts time into candles.
+//
+// Accepted names:
+//
+//	15m, 1h, 4h          fixed length (Go duration), aligned on the Unix epoch
+//	1h@session           fixed length, aligned on each symbol's session open
+//	session, day, week,  calendar periods in the symbol's exchange timezone
+//	month, 1d, 1w        (1d = day, 1w = week)
+// -----------------------------------------------------------------------------
+
+type WindowSpec struct {
+	Name    string
+	Seconds int64  // Fixed window length (nominal length for calendar periods)
+	Anchor  string // WindowAnchorEpoch or WindowAnchorSession (fixed windows)
+	Period  string // Calendar period ("" for fixed windows)
+}
+
+// -----------------------------------------------------------------------------
+
+// ParseWindow parses a window name.
+func ParseWindow(name string) (WindowSpec, error) {
+	spec := WindowSpec{Name: name, Anchor: WindowAnchorEpoch}
+
+	switch strings.ToLower(name) {
+	case WindowPeriodSession:
+		spec.Period, spec.Seconds = WindowPeriodSession, int64((6*time.Hour + 30*time.Minute).Seconds())
+		return spec, nil
+	case WindowPeriodDay, "1d":
+		spec.Period, spec.Seconds = WindowPeriodDay, 86400
+		return spec, nil
+	case WindowPeriodWeek, "1w":
+		spec.Period, spec.Seconds = WindowPeriodWeek, 7*86400
+		return spec, nil
+	case WindowPeriodMonth:
+		spec.Period, spec.Seconds = WindowPeriodMonth, 30*86400
+		return spec, nil
+	}
+
+	durationPart := name
+	if base, anchor, found := strings.Cut(name, "@"); found {
+		if anchor != WindowAnchorSession {
+			return spec, fmt.Errorf("window %q: unknown anchor %q (only @session)", name, anchor)
+		}
+		durationPart = base
+		spec.Anchor = WindowAnchorSession
+	}
+
+	if strings.HasSuffix(durationPart, "d") || strings.HasSuffix(durationPart, "w") {
+		return spec, fmt.Errorf("window %q: only 1d and 1w are supported as day/week periods", name)
+	}
+	dur, err := time.ParseDuration(durationPart)
+	if err != nil {
+		return spec, fmt.Errorf("window %q: %v", name, err)
+	}
+	if dur < time.Second || dur%time.Second != 0 {
+		return spec, fmt.Errorf("window %q: length must be a whole number of seconds", name)
+	}
+	if spec.Anchor == WindowAnchorSession && dur >= 24*time.Hour {
+		return spec, fmt.Errorf("window %q: session-anchored windows must be shorter than a day", name)
+	}
+	spec.Seconds = int64(dur.Seconds())
+	return spec, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// ParseWindows parses every window name. Invalid names are skipped (they are
+// rejected at config load).
+func ParseWindows(names []string) map[string]WindowSpec {
+	specs := make(map[string]WindowSpec, len(names))
+	for _, name := range names {
+		if spec, err := ParseWindow(name); err == nil {
+			specs[name] = spec
+		}
+	}
+	return specs
+}
+
+// -----------------------------------------------------------------------------
+
+// UsesCalendar reports whether the window boundaries depend on the symbol's calendar.
+func (w WindowSpec) UsesCalendar() bool {
+	return w.Period != "" || w.Anchor == WindowAnchorSession
+}
+
+// -----------------------------------------------------------------------------
+
+// Bounds returns the [start, end) window containing ts (Unix seconds). cal is
+// only used by calendar-dependent windows and may be nil for epoch windows.
+func (w WindowSpec) Bounds(ts int64, cal *TradingCalendar) (int64, int64) {
+	if !w.UsesCalendar() || cal == nil {
+		start := ts - (ts % w.Seconds)
+		return start, start + w.Seconds
+	}
+
+	t := time.Unix(ts, 0)
+	if cal.Timezone != nil {
+		t = t.In(cal.Timezone)
+	}
+
+	switch w.Period {
+	case WindowPeriodDay:
+		start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		return start.Unix(), start.AddDate(0, 0, 1).Unix()
+	case WindowPeriodWeek:
+		offset := (int(t.Weekday()) + 6) % 7 // Days since Monday
+		start := time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+		return start.Unix(), start.AddDate(0, 0, 7).Unix()
+	case WindowPeriodMonth:
+		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		return start.Unix(), start.AddDate(0, 1, 0).Unix()
+	}
+
+	open, close, nextOpen := cal.SessionBounds(t)
+	openTs, closeTs, nextTs := open.Unix(), close.Unix(), nextOpen.Unix()
+
+	if w.Period == WindowPeriodSession {
+		if ts < closeTs {
+			return openTs, closeTs
+		}
+		return closeTs, nextTs // Off-hours until the next open
+	}
+
+	// Session-anchored grid: restarts at the open, truncated at the close,
+	// then continues from the close through the off-hours
+	anchor, limit := openTs, closeTs
+	if ts >= closeTs {
+		anchor, limit = closeTs, nextTs
+	}
+	start := anchor + ((ts-anchor)/w.Seconds)*w.Seconds
+	end := start + w.Seconds
+	if end > limit {
+		end = limit
+	}
+	return start, end
+}
+
+// -----------------------------------------------------------------------------
+
+// IsCurrent reports whether a candle ending at end is the window containing
+// asOf or the one right before it, on the symbol's own calendar. Older candles
+// belong to a stopped feed or a market that has not traded since.
+func (w WindowSpec) IsCurrent(end, asOf int64, cal *TradingCalendar) bool {
+	start, _ := w.Bounds(asOf, cal)
+	return end >= start
+}
+
+// -----------------------------------------------------------------------------
+
+// WindowTableSuffix turns a window name into a SQL-safe table name suffix.
+func WindowTableSuffix(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// -----------------------------------------------------------------------------
+// WindowHierarchy orders the configured windows so that each one is built from
+// the closed candles of its parent (the coarsest finer window it is an exact
+// multiple of). The base window (the finest) is built from raw points.
+// -----------------------------------------------------------------------------
+
+type WindowHierarchy struct {
+	Order  []string          // Parents before children, base window first
+	Parent map[string]string // "" for the base window
+}
+
+// -----------------------------------------------------------------------------
+
+// BuildWindowHierarchy resolves the parent of every window. It fails when a
+// window other than the base cannot be derived from any finer window.
+func BuildWindowHierarchy(names []string) (*WindowHierarchy, error) {
+	specs := make([]WindowSpec, 0, len(names))
+	for _, name := range names {
+		spec, err := ParseWindow(name)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	sort.SliceStable(specs, func(i, j int) bool { return specs[i].Seconds < specs[j].Seconds })
+
+	h := &WindowHierarchy{Parent: make(map[string]string, len(specs))}
+	for i, spec := range specs {
+		parent := ""
+		for j := i - 1; j >= 0; j-- {
+			if specs[j].Seconds < spec.Seconds && spec.CanDeriveFrom(specs[j]) {
+				parent = specs[j].Name
+				break
+			}
+		}
+		if parent == "" && i > 0 {
+			return nil, fmt.Errorf("window %q is not a multiple of any finer window (base window is %q)", spec.Name, specs[0].Name)
+		}
+		h.Order = append(h.Order, spec.Name)
+		h.Parent[spec.Name] = parent
+	}
+	return h, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// FlatWindowHierarchy makes every window a base window (built from raw points).
+func FlatWindowHierarchy(names []string) *WindowHierarchy {
+	h := &WindowHierarchy{Parent: make(map[string]string, len(names))}
+	for _, name := range names {
+		h.Order = append(h.Order, name)
+		h.Parent[name] = ""
+	}
+	return h
+}
+
+// -----------------------------------------------------------------------------
+
+// CanDeriveFrom reports whether every window of w is an exact union of windows
+// of finer. Exchange sessions are assumed to open and close on the half hour and
+// timezone offsets to be multiples of 15 minutes.
+func (w WindowSpec) CanDeriveFrom(finer WindowSpec) bool {
+	if finer.Period != "" {
+		// Calendar days nest in weeks and months
+		return finer.Period == WindowPeriodDay && (w.Period == WindowPeriodWeek || w.Period == WindowPeriodMonth)
+	}
+
+	if finer.Anchor == WindowAnchorSession {
+		if w.Period == WindowPeriodSession {
+			return true
+		}
+		return w.Period == "" && w.Anchor == WindowAnchorSession && w.Seconds%finer.Seconds == 0
+	}
+
+	// Epoch-aligned finer window
+	switch {
+	case w.Period == "" && w.Anchor == WindowAnchorEpoch:
+		return w.Seconds%finer.Seconds == 0
+	case w.Period == "" && w.Anchor == WindowAnchorSession:
+		return 1800%finer.Seconds == 0 && w.Seconds%finer.Seconds == 0
+	case w.Period == WindowPeriodSession:
+		return 1800%finer.Seconds == 0
+	default: // day, week, month
+		return 900%finer.Seconds == 0
+	}
+}
@@ -0,0 +1,263 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		seconds int64
+		anchor  string
+		period  string
+	}{
+		{"5m", 300, WindowAnchorEpoch, ""},
+		{"1h", 3600, WindowAnchorEpoch, ""},
+		{"2h45m", 9900, WindowAnchorEpoch, ""},
+		{"90s", 90, WindowAnchorEpoch, ""},
+		{"1h@session", 3600, WindowAnchorSession, ""},
+		{"30m@session", 1800, WindowAnchorSession, ""},
+		{"session", 23400, WindowAnchorEpoch, WindowPeriodSession},
+		{"day", 86400, WindowAnchorEpoch, WindowPeriodDay},
+		{"1d", 86400, WindowAnchorEpoch, WindowPeriodDay},
+		{"week", 7 * 86400, WindowAnchorEpoch, WindowPeriodWeek},
+		{"1w", 7 * 86400, WindowAnchorEpoch, WindowPeriodWeek},
+		{"Month", 30 * 86400, WindowAnchorEpoch, WindowPeriodMonth},
+	}
+
+	for _, tt := range tests {
+		spec, err := ParseWindow(tt.name)
+		if err != nil {
+			t.Errorf("ParseWindow(%q) error: %v", tt.name, err)
+			continue
+		}
+		if spec.Name != tt.name || spec.Seconds != tt.seconds || spec.Anchor != tt.anchor || spec.Period != tt.period {
+			t.Errorf("ParseWindow(%q) = %+v, want seconds=%d anchor=%s period=%q", tt.name, spec, tt.seconds, tt.anchor, tt.period)
+		}
+	}
+}
+
+func TestParseWindowErrors(t *testing.T) {
+	for _, name := range []string{
+		"", "abc", "5", "0s", "-5m", "500ms", "1.5s",
+		"2d", "3w", // Only 1d and 1w
+		"1h@open", "1h@", // Unknown anchor
+		"24h@session", "day@session",
+	} {
+		if spec, err := ParseWindow(name); err == nil {
+			t.Errorf("ParseWindow(%q) = %+v, want an error", name, spec)
+		}
+	}
+
+	specs := ParseWindows([]string{"5m", "bogus", "1h@session"})
+	if len(specs) != 2 {
+		t.Errorf("ParseWindows kept %d windows, want 2 (invalid skipped)", len(specs))
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func newYork(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	return loc
+}
+
+func TestWindowBoundsEpoch(t *testing.T) {
+	spec, _ := ParseWindow("15m")
+	start, end := spec.Bounds(1_700_000_050, nil)
+	if start != 1_699_999_200 || end != 1_700_000_100 {
+		t.Errorf("15m bounds = [%d, %d)", start, end)
+	}
+
+	// On a boundary the point opens the next window
+	start, end = spec.Bounds(1_700_000_100, nil)
+	if start != 1_700_000_100 || end != 1_700_001_000 {
+		t.Errorf("15m bounds on boundary = [%d, %d)", start, end)
+	}
+
+	// Calendar windows without a calendar fall back to their nominal length
+	day, _ := ParseWindow("day")
+	if start, end := day.Bounds(1_700_000_123, nil); start != 1_699_920_000 || end != 1_700_006_400 {
+		t.Errorf("day bounds without calendar = [%d, %d)", start, end)
+	}
+}
+
+func TestWindowBoundsCalendar(t *testing.T) {
+	ny := newYork(t)
+	fallback := &TradingCalendar{Fallback: true, Timezone: ny} // Mon-Fri 09:30-16:00
+	at := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, ny)
+	}
+
+	tests := []struct {
+		window     string
+		cal        *TradingCalendar
+		ts         time.Time
+		start, end time.Time
+	}{
+		// Session-anchored grid: restarts at the open, truncated at the close
+		{"1h@session", fallback, at(2024, 3, 12, 10, 45), at(2024, 3, 12, 10, 30), at(2024, 3, 12, 11, 30)},
+		{"1h@session", fallback, at(2024, 3, 12, 9, 30), at(2024, 3, 12, 9, 30), at(2024, 3, 12, 10, 30)},
+		{"1h@session", fallback, at(2024, 3, 12, 15, 45), at(2024, 3, 12, 15, 30), at(2024, 3, 12, 16, 0)},
+		// Off-hours continue the grid from the close until the next open
+		{"1h@session", fallback, at(2024, 3, 12, 16, 10), at(2024, 3, 12, 16, 0), at(2024, 3, 12, 17, 0)},
+		{"1h@session", fallback, at(2024, 3, 13, 9, 10), at(2024, 3, 13, 9, 0), at(2024, 3, 13, 9, 30)},
+
+		// Session periods
+		{"session", fallback, at(2024, 3, 12, 12, 0), at(2024, 3, 12, 9, 30), at(2024, 3, 12, 16, 0)},
+		{"session", fallback, at(2024, 3, 12, 20, 0), at(2024, 3, 12, 16, 0), at(2024, 3, 13, 9, 30)},
+		{"session", fallback, at(2024, 3, 15, 17, 0), at(2024, 3, 15, 16, 0), at(2024, 3, 18, 9, 30)}, // Friday -> Monday
+		{"session", fallback, at(2024, 3, 16, 12, 0), at(2024, 3, 15, 16, 0), at(2024, 3, 18, 9, 30)}, // Saturday
+
+		// Calendar periods in the exchange timezone
+		{"day", fallback, at(2024, 3, 12, 23, 30), at(2024, 3, 12, 0, 0), at(2024, 3, 13, 0, 0)},
+		{"day", fallback, at(2024, 3, 10, 12, 0), at(2024, 3, 10, 0, 0), at(2024, 3, 11, 0, 0)}, // 23h DST day
+		{"week", fallback, at(2024, 3, 13, 12, 0), at(2024, 3, 11, 0, 0), at(2024, 3, 18, 0, 0)},
+		{"week", fallback, at(2024, 3, 17, 23, 0), at(2024, 3, 11, 0, 0), at(2024, 3, 18, 0, 0)}, // Sunday
+		{"month", fallback, at(2024, 2, 15, 12, 0), at(2024, 2, 1, 0, 0), at(2024, 3, 1, 0, 0)},
+		{"month", fallback, at(2024, 12, 31, 23, 0), at(2024, 12, 1, 0, 0), at(2025, 1, 1, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		spec, err := ParseWindow(tt.window)
+		if err != nil {
+			t.Fatalf("ParseWindow(%q): %v", tt.window, err)
+		}
+		start, end := spec.Bounds(tt.ts.Unix(), tt.cal)
+		if start != tt.start.Unix() || end != tt.end.Unix() {
+			t.Errorf("%s at %s = [%s, %s), want [%s, %s)", tt.window, tt.ts,
+				time.Unix(start, 0).In(ny), time.Unix(end, 0).In(ny), tt.start, tt.end)
+		}
+	}
+}
+
+func TestWindowBoundsExchangeCalendar(t *testing.T) {
+	ny := newYork(t)
+	cal := GetCalendar("AAPL")
+	if cal.Fallback {
+		t.Skip("NYSE calendar unavailable")
+	}
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2024, month, day, hour, min, 0, 0, ny)
+	}
+
+	tests := []struct {
+		window     string
+		ts         time.Time
+		start, end time.Time
+	}{
+		// Early close on July 3rd, holiday on July 4th
+		{"session", at(7, 3, 12, 0), at(7, 3, 9, 30), at(7, 3, 13, 0)},
+		{"session", at(7, 4, 12, 0), at(7, 3, 13, 0), at(7, 5, 9, 30)},
+		{"1h@session", at(7, 3, 12, 45), at(7, 3, 12, 30), at(7, 3, 13, 0)},
+		{"1h@session", at(7, 3, 13, 10), at(7, 3, 13, 0), at(7, 3, 14, 0)},
+	}
+
+	for _, tt := range tests {
+		spec, _ := ParseWindow(tt.window)
+		start, end := spec.Bounds(tt.ts.Unix(), cal)
+		if start != tt.start.Unix() || end != tt.end.Unix() {
+			t.Errorf("%s at %s = [%s, %s), want [%s, %s)", tt.window, tt.ts,
+				time.Unix(start, 0).In(ny), time.Unix(end, 0).In(ny), tt.start, tt.end)
+		}
+	}
+}
+
+func TestWindowIsCurrent(t *testing.T) {
+	ny := newYork(t)
+	nyse, lse := GetCalendar("AAPL"), GetCalendar("VOD.L")
+	if nyse.Fallback || lse.Fallback {
+		t.Skip("exchange calendars unavailable")
+	}
+	at := func(day, hour, min int) int64 {
+		return time.Date(2024, time.March, day, hour, min, 0, 0, ny).Unix()
+	}
+	asOf := at(12, 14, 0) // London closed at 12:30 New York time
+
+	tests := []struct {
+		name    string
+		window  string
+		cal     *TradingCalendar
+		end     int64
+		current bool
+	}{
+		{"london session of the day", "session", lse, at(12, 12, 30), true},
+		{"london session of the day before", "session", lse, at(11, 12, 30), false},
+		{"new york open session", "session", nyse, at(12, 16, 0), true},
+		{"new york window just closed", "5m", nyse, at(12, 14, 0), true},
+		{"new york window two back", "5m", nyse, at(12, 13, 55), false},
+		{"london last bar", "5m", lse, at(12, 12, 30), false},
+		{"london day", "1d", lse, at(12, 20, 0), true},
+		{"new york day before", "1d", nyse, at(12, 0, 0), true},
+		{"new york friday", "1d", nyse, at(9, 0, 0), false},
+	}
+
+	for _, tt := range tests {
+		spec, _ := ParseWindow(tt.window)
+		if got := spec.IsCurrent(tt.end, asOf, tt.cal); got != tt.current {
+			t.Errorf("%s: IsCurrent = %v, want %v", tt.name, got, tt.current)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func TestBuildWindowHierarchy(t *testing.T) {
+	tests := []struct {
+		windows []string
+		order   []string
+		parents map[string]string
+	}{
+		{
+			[]string{"1h", "5m", "15m", "2h45m", "10m"},
+			[]string{"5m", "10m", "15m", "1h", "2h45m"},
+			map[string]string{"5m": "", "10m": "5m", "15m": "5m", "1h": "15m", "2h45m": "15m"},
+		},
+		{
+			[]string{"5m", "30m@session", "1h@session", "session", "day", "week", "month"},
+			[]string{"5m", "30m@session", "1h@session", "session", "day", "week", "month"},
+			map[string]string{"5m": "", "30m@session": "5m", "1h@session": "30m@session", "session": "1h@session", "day": "5m", "week": "day", "month": "day"},
+		},
+	}
+
+	for _, tt := range tests {
+		h, err := BuildWindowHierarchy(tt.windows)
+		if err != nil {
+			t.Fatalf("BuildWindowHierarchy(%v): %v", tt.windows, err)
+		}
+		for i, name := range tt.order {
+			if i >= len(h.Order) || h.Order[i] != name {
+				t.Fatalf("BuildWindowHierarchy(%v) order = %v, want %v", tt.windows, h.Order, tt.order)
+			}
+		}
+		for name, parent := range tt.parents {
+			if h.Parent[name] != parent {
+				t.Errorf("parent of %s = %q, want %q", name, h.Parent[name], parent)
+			}
+		}
+	}
+}
+
+func TestBuildWindowHierarchyErrors(t *testing.T) {
+	for _, windows := range [][]string{
+		{"5m", "7m"},          // Not a multiple
+		{"10m", "day"},        // 15-minute timezone offsets cannot be cut from 10m
+		{"45m", "1h@session"}, // Session opens on the half hour
+		{"1h@session", "2h"},  // Epoch windows cannot come from session-anchored ones
+		{"5m", "bogus"},       // Invalid name
+	} {
+		if h, err := BuildWindowHierarchy(windows); err == nil {
+			t.Errorf("BuildWindowHierarchy(%v) = %+v, want an error", windows, h.Parent)
+		}
+	}
+
+	flat := FlatWindowHierarchy([]string{"5m", "7m"})
+	if flat.Parent["7m"] != "" || len(flat.Order) != 2 {
+		t.Errorf("FlatWindowHierarchy = %+v", flat)
+	}
+}
@@ -0,0 +1,467 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"market-observer/src/interfaces"
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/utils"
+)
+
+var (
+	// ErrQuarantineNotFound is returned when a quarantined point ID is not pending
+	ErrQuarantineNotFound = errors.New("quarantined point not found")
+	// ErrReleaseQueueFull is returned when released points cannot be queued
+	ErrReleaseQueueFull = errors.New("release queue full, retry later")
+	// ErrReleaseTooLate is returned when the candles a quarantined point belongs to have moved on
+	ErrReleaseTooLate = errors.New("quarantined point is too old to be released")
+)
+
+// -----------------------------------------------------------------------------
+// Validator is the inbound stage between the sources and the data loop. Points
+// that look wrong (price jumps beyond N sigma, stale repeated prints, timestamp
+// regressions, future timestamps) are quarantined and stored separately; they
+// can be reviewed and released back into the pipeline.
+// -----------------------------------------------------------------------------
+
+type Validator struct {
+	Config *models.MConfig
+	DB     interfaces.IDatabase
+	Logger *logger.Logger
+
+	Enabled       bool
+	jumpSigma     float64
+	minJumpPct    float64
+	minSamples    int
+	confirmPoints int
+	staleRepeats  int
+	maxFuture     int64
+	maxQuarantine int
+	barInterval   int64
+	baseWindow    utils.WindowSpec // Window built from raw points (zero when unknown)
+	closeDelay    int64            // Seconds after its end a base window is closed
+
+	symbols    map[string]*symbolState
+	stats      map[string]*models.MValidationStats
+	quarantine []models.MQuarantinedPrice // Pending, oldest first
+	released   chan map[string][]models.MStockPrice
+	mu         sync.Mutex
+}
+
+type symbolState struct {
+	last    models.MStockPrice // Last accepted point
+	hasLast bool
+
+	variance float64 // EWMA of squared per-bar log returns
+	samples  int
+	repeats  int // Identical prints in a row
+
+	// Candidate level shift (consecutive jumps agreeing with each other)
+	jumpLevel float64
+	jumpCount int
+	shifted   bool // The next accepted point confirms a new level
+}
+
+// -----------------------------------------------------------------------------
+
+func NewValidator(cfg *models.MConfig, db interfaces.IDatabase, log *logger.Logger) *Validator {
+	vc := cfg.Validation
+
+	v := &Validator{
+		Config:        cfg,
+		DB:            db,
+		Logger:        log,
+		Enabled:       vc.Enabled,
+		jumpSigma:     vc.JumpSigma,
+		minJumpPct:    vc.MinJumpPct,
+		minSamples:    vc.JumpMinSamples,
+		confirmPoints: vc.JumpConfirmPoints,
+		staleRepeats:  vc.StaleRepeatLimit,
+		maxFuture:     int64(vc.MaxFutureSeconds),
+		maxQuarantine: vc.MaxQuarantine,
+		barInterval:   int64(cfg.DataQuality.BarIntervalSeconds),
+		closeDelay:    int64(cfg.DataSource.UpdateIntervalSeconds),
+		symbols:       make(map[string]*symbolState),
+		stats:         make(map[string]*models.MValidationStats),
+		released:      make(chan map[string][]models.MStockPrice, 64),
+	}
+
+	if v.jumpSigma <= 0 {
+		v.jumpSigma = utils.DefaultValidationJumpSigma
+	}
+	if v.minJumpPct <= 0 {
+		v.minJumpPct = utils.DefaultValidationMinJumpPct
+	}
+	if v.minSamples <= 0 {
+		v.minSamples = utils.DefaultValidationMinSamples
+	}
+	if v.confirmPoints <= 0 {
+		v.confirmPoints = utils.DefaultValidationConfirmPoints
+	}
+	if v.staleRepeats <= 0 {
+		v.staleRepeats = utils.DefaultValidationStaleRepeats
+	}
+	if v.maxFuture <= 0 {
+		v.maxFuture = utils.DefaultValidationMaxFuture
+	}
+	if v.maxQuarantine <= 0 {
+		v.maxQuarantine = utils.DefaultValidationMaxQuarantine
+	}
+	if v.barInterval <= 0 {
+		v.barInterval = utils.DefaultBarInterval
+	}
+	if h, err := utils.BuildWindowHierarchy(cfg.WindowsAgg); err == nil && len(h.Order) > 0 {
+		v.baseWindow, _ = utils.ParseWindow(h.Order[0])
+	}
+	return v
+}
+
+// -----------------------------------------------------------------------------
+
+// Load restores the points still in quarantine from a previous run.
+func (v *Validator) Load() error {
+	pending, err := v.DB.LoadQuarantinedPrices()
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.quarantine = append(v.quarantine, pending...)
+	v.trimQuarantine()
+	v.Logger.Info("Restored %d quarantined point(s)", len(v.quarantine))
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// Start runs the validation stage: batches from in are validated and forwarded
+// to out, together with released points. out is closed when the stage stops.
+func (v *Validator) Start(ctx context.Context, wg *sync.WaitGroup, in <-chan map[string][]models.MStockPrice, out chan<- map[string][]models.MStockPrice) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(out)
+
+		for {
+			var batch map[string][]models.MStockPrice
+
+			select {
+			case <-ctx.Done():
+				return
+			case updates, ok := <-in:
+				if !ok {
+					return
+				}
+				batch = v.ValidateBatch(updates)
+			case released := <-v.released:
+				batch = released // Reviewed by hand: not validated again
+			}
+
+			if len(batch) == 0 {
+				continue
+			}
+			select {
+			case out <- batch:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// -----------------------------------------------------------------------------
+
+// ValidateBatch returns the accepted points of an update batch (sorted per
+// symbol). Quarantined points are stored and kept for review.
+func (v *Validator) ValidateBatch(updates map[string][]models.MStockPrice) map[string][]models.MStockPrice {
+	if !v.Enabled {
+		return updates
+	}
+
+	now := time.Now().UTC().Unix()
+	accepted := make(map[string][]models.MStockPrice, len(updates))
+	var quarantined []models.MQuarantinedPrice
+
+	v.mu.Lock()
+	for symbol, prices := range updates {
+		sorted := make([]models.MStockPrice, len(prices))
+		copy(sorted, prices)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].Timestamp < sorted[j].Timestamp
+		})
+
+		st, ok := v.symbols[symbol]
+		if !ok {
+			st = &symbolState{}
+			v.symbols[symbol] = st
+		}
+		stats := v.symbolStats(symbol)
+
+		for _, p := range sorted {
+			stats.Checked++
+			reason, detail := v.check(symbol, st, p, now)
+			if reason == "" {
+				v.accept(st, p)
+				stats.Accepted++
+				accepted[symbol] = append(accepted[symbol], p)
+				continue
+			}
+
+			stats.Quarantined++
+			stats.ByReason[reason]++
+			quarantined = append(quarantined, models.MQuarantinedPrice{
+				Price:         p,
+				Reason:        reason,
+				Detail:        detail,
+				QuarantinedAt: now,
+			})
+		}
+	}
+	v.mu.Unlock()
+
+	if len(quarantined) == 0 {
+		return accepted
+	}
+
+	// Store outside the lock (IDs come from the database)
+	for i := range quarantined {
+		q := &quarantined[i]
+		if err := v.DB.SaveQuarantinedPrice(q); err != nil {
+			v.Logger.Error("Failed to store quarantined point %s@%d: %v", q.Price.Symbol, q.Price.Timestamp, err)
+		}
+		v.Logger.Warning("Quarantined %s@%d (%s): %s", q.Price.Symbol, q.Price.Timestamp, q.Reason, q.Detail)
+	}
+
+	v.mu.Lock()
+	v.quarantine = append(v.quarantine, quarantined...)
+	v.trimQuarantine()
+	v.mu.Unlock()
+
+	return accepted
+}
+
+// -----------------------------------------------------------------------------
+
+// Quarantined returns the pending points (newest last), optionally for one
+// symbol only (all when limit <= 0).
+func (v *Validator) Quarantined(symbol string, limit int) []models.MQuarantinedPrice {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	var result []models.MQuarantinedPrice
+	for _, q := range v.quarantine {
+		if symbol == "" || q.Price.Symbol == symbol {
+			result = append(result, q)
+		}
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result
+}
+
+// -----------------------------------------------------------------------------
+
+// Release sends a quarantined point back into the pipeline (bypassing validation).
+// Only a point newer than every accepted one of its symbol, whose base window
+// is still open, can be released: candles do not take older points back.
+func (v *Validator) Release(id int64) (models.MQuarantinedPrice, error) {
+	v.mu.Lock()
+
+	idx := -1
+	for i, q := range v.quarantine {
+		if q.ID == id {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		v.mu.Unlock()
+		return models.MQuarantinedPrice{}, ErrQuarantineNotFound
+	}
+
+	q := v.quarantine[idx]
+	if err := v.checkRelease(q.Price, time.Now().UTC().Unix()); err != nil {
+		v.mu.Unlock()
+		return models.MQuarantinedPrice{}, err
+	}
+	select {
+	case v.released <- map[string][]models.MStockPrice{q.Price.Symbol: {q.Price}}:
+	default:
+		v.mu.Unlock()
+		return models.MQuarantinedPrice{}, ErrReleaseQueueFull
+	}
+
+	q.ReleasedAt = time.Now().UTC().Unix()
+	v.quarantine = append(v.quarantine[:idx], v.quarantine[idx+1:]...)
+	if st, ok := v.symbols[q.Price.Symbol]; ok {
+		v.accept(st, q.Price) // Next points are checked against it
+	}
+	v.symbolStats(q.Price.Symbol).Released++
+	v.mu.Unlock()
+
+	if err := v.DB.ReleaseQuarantinedPrice(q.ID, q.ReleasedAt); err != nil {
+		v.Logger.Error("Failed to mark quarantined point %d as released: %v", q.ID, err)
+	}
+	v.Logger.Info("Released quarantined point %d (%s@%d)", q.ID, q.Price.Symbol, q.Price.Timestamp)
+	return q, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// Stats returns the counters summed over all symbols and per symbol.
+func (v *Validator) Stats() (models.MValidationStats, []models.MValidationStats) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	total := models.MValidationStats{ByReason: make(map[string]int)}
+	perSymbol := make([]models.MValidationStats, 0, len(v.stats))
+	for _, s := range v.stats {
+		total.Checked += s.Checked
+		total.Accepted += s.Accepted
+		total.Quarantined += s.Quarantined
+		total.Released += s.Released
+
+		entry := *s
+		entry.ByReason = make(map[string]int, len(s.ByReason))
+		for reason, n := range s.ByReason {
+			entry.ByReason[reason] = n
+			total.ByReason[reason] += n
+		}
+		perSymbol = append(perSymbol, entry)
+	}
+	sort.Slice(perSymbol, func(i, j int) bool { return perSymbol[i].Symbol < perSymbol[j].Symbol })
+	return total, perSymbol
+}
+
+// -----------------------------------------------------------------------------
+
+// check returns the quarantine reason of a point ("" when it is accepted).
+// A jump confirmed by enough consecutive prints is accepted as a new level.
+func (v *Validator) check(symbol string, st *symbolState, p models.MStockPrice, now int64) (string, string) {
+	if p.Timestamp > now+v.maxFuture {
+		return utils.ValidationReasonFuture, fmt.Sprintf("timestamp is %ds ahead of now", p.Timestamp-now)
+	}
+	if !st.hasLast {
+		return "", ""
+	}
+
+	last := st.last
+	if p.Timestamp < last.Timestamp {
+		return utils.ValidationReasonRegression, fmt.Sprintf("timestamp is %ds before the last accepted point", last.Timestamp-p.Timestamp)
+	}
+
+	if p.Timestamp > last.Timestamp && p.Price == last.Price && p.Volume == last.Volume && st.repeats+1 > v.staleRepeats {
+		return utils.ValidationReasonStale, fmt.Sprintf("price and volume unchanged for more than %d prints", v.staleRepeats+1)
+	}
+
+	if st.samples < v.minSamples || last.Price <= 0 || p.Price <= 0 {
+		return "", ""
+	}
+
+	ret := math.Log(p.Price / last.Price)
+	limit := math.Max(v.jumpSigma*math.Sqrt(st.variance*v.bars(last, p)), math.Log(1+v.minJumpPct))
+	if math.Abs(ret) <= limit {
+		st.jumpCount = 0
+		return "", ""
+	}
+
+	// Prints that agree with the previous jump build up a candidate level
+	if st.jumpCount > 0 && math.Abs(math.Log(p.Price/st.jumpLevel)) <= limit {
+		st.jumpCount++
+	} else {
+		st.jumpLevel, st.jumpCount = p.Price, 1
+	}
+	if st.jumpCount >= v.confirmPoints {
+		v.Logger.Warning("Price level shift for %s confirmed by %d prints (%.4f -> %.4f)", symbol, st.jumpCount, last.Price, p.Price)
+		st.jumpCount = 0
+		st.shifted = true
+		return "", ""
+	}
+	return utils.ValidationReasonPriceJump, fmt.Sprintf("return %.2f%% exceeds %.2f%%", ret*100, limit*100)
+}
+
+// -----------------------------------------------------------------------------
+
+// checkRelease returns ErrReleaseTooLate when p is not newer than the last
+// accepted point of its symbol (memory buffers are kept in time order) or its
+// base window is already closed (caller holds the lock).
+func (v *Validator) checkRelease(p models.MStockPrice, now int64) error {
+	if st, ok := v.symbols[p.Symbol]; ok && st.hasLast && p.Timestamp <= st.last.Timestamp {
+		return fmt.Errorf("%w: %s has newer points since %d", ErrReleaseTooLate, p.Symbol, p.Timestamp)
+	}
+	if v.baseWindow.Seconds > 0 {
+		if _, end := v.baseWindow.Bounds(p.Timestamp, utils.GetCalendar(p.Symbol)); end <= now-v.closeDelay {
+			return fmt.Errorf("%w: the %s candle of %s at %d is closed", ErrReleaseTooLate, v.baseWindow.Name, p.Symbol, p.Timestamp)
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// accept makes p the reference point of its symbol.
+func (v *Validator) accept(st *symbolState, p models.MStockPrice) {
+	if st.hasLast && p.Timestamp > st.last.Timestamp {
+		if p.Price == st.last.Price && p.Volume == st.last.Volume {
+			st.repeats++
+		} else {
+			st.repeats = 0
+		}
+
+		if st.shifted {
+			// Restart the volatility estimate at the new level (without the jump)
+			st.variance, st.samples, st.shifted = 0, 0, false
+		} else if st.last.Price > 0 && p.Price > 0 {
+			ret := math.Log(p.Price / st.last.Price)
+			sq := ret * ret / v.bars(st.last, p)
+			if st.samples == 0 {
+				st.variance = sq
+			} else {
+				lambda := utils.ValidationVolatilityLambda
+				st.variance = lambda*st.variance + (1-lambda)*sq
+			}
+			st.samples++
+		}
+	}
+
+	st.last = p
+	st.hasLast = true
+}
+
+// -----------------------------------------------------------------------------
+
+// bars is the number of bar intervals between two points (at least 1).
+func (v *Validator) bars(prev, next models.MStockPrice) float64 {
+	return math.Max(1, float64(next.Timestamp-prev.Timestamp)/float64(v.barInterval))
+}
+
+// -----------------------------------------------------------------------------
+
+func (v *Validator) symbolStats(symbol string) *models.MValidationStats {
+	s, ok := v.stats[symbol]
+	if !ok {
+		s = &models.MValidationStats{Symbol: symbol, ByReason: make(map[string]int)}
+		v.stats[symbol] = s
+	}
+	return s
+}
+
+// -----------------------------------------------------------------------------
+
+// trimQuarantine drops the oldest pending points beyond the memory limit (they
+// stay in the database).
+func (v *Validator) trimQuarantine() {
+	if overflow := len(v.quarantine) - v.maxQuarantine; overflow > 0 {
+		v.quarantine = append([]models.MQuarantinedPrice(nil), v.quarantine[overflow:]...)
+	}
+}
@@ -0,0 +1,209 @@
+package validation
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"market-observer/src/logger"
+	"market-observer/src/models"
+	"market-observer/src/storage"
+	"market-observer/src/utils"
+)
+
+const validatorTestBase = int64(1_700_000_100)
+
+// newWarmValidator returns a validator whose "TEST" symbol has a calm history
+// (about 0.1% per bar) long enough for the jump check to apply.
+func newWarmValidator(t *testing.T) (*Validator, *symbolState, int64) {
+	t.Helper()
+	cfg := &models.MConfig{}
+	cfg.Validation.Enabled = true
+	v := NewValidator(cfg, nil, logger.NewLogger(cfg, "test"))
+
+	st := &symbolState{}
+	ts := validatorTestBase
+	for i := 0; i <= utils.DefaultValidationMinSamples; i++ {
+		price := 100.0
+		if i%2 == 1 {
+			price = 100.1
+		}
+		v.accept(st, models.MStockPrice{Symbol: "TEST", Price: price, Volume: float64(i + 1), Timestamp: ts})
+		ts += 300
+	}
+	return v, st, ts
+}
+
+// feed runs prices through check/accept one bar apart and returns the reasons.
+func feed(v *Validator, st *symbolState, ts int64, prices []float64) []string {
+	reasons := make([]string, len(prices))
+	for i, price := range prices {
+		p := models.MStockPrice{Symbol: "TEST", Price: price, Volume: float64(1000 + i), Timestamp: ts}
+		reason, _ := v.check("TEST", st, p, ts)
+		if reason == "" {
+			v.accept(st, p)
+		}
+		reasons[i] = reason
+		ts += 300
+	}
+	return reasons
+}
+
+func TestValidatorJumpConfirmation(t *testing.T) {
+	const q = utils.ValidationReasonPriceJump
+
+	tests := []struct {
+		name   string
+		prices []float64
+		want   []string
+	}{
+		{"calm prints", []float64{100, 100.1, 100.05}, []string{"", "", ""}},
+		{"isolated spike", []float64{110, 100.1}, []string{q, ""}},
+		{"level shift confirmed", []float64{110, 110.1, 109.9, 110}, []string{q, q, "", ""}},
+		{"disagreeing jumps", []float64{110, 90, 110, 90}, []string{q, q, q, q}},
+		{"normal print resets candidate", []float64{110, 110, 100.1, 110, 110}, []string{q, q, "", q, q}},
+		{"move below the minimum percentage", []float64{101.5}, []string{""}},
+	}
+
+	for _, tt := range tests {
+		v, st, ts := newWarmValidator(t)
+		got := feed(v, st, ts, tt.prices)
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: reasons = %q, want %q", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestValidatorLevelShiftResetsVolatility(t *testing.T) {
+	v, st, ts := newWarmValidator(t)
+	feed(v, st, ts, []float64{110, 110, 110})
+
+	if st.last.Price != 110 || st.samples != 0 || st.variance != 0 || st.jumpCount != 0 {
+		t.Fatalf("state after confirmation = %+v", st)
+	}
+	// Without enough samples at the new level, another large move is not checked
+	if got := feed(v, st, ts+900, []float64{120}); got[0] != "" {
+		t.Errorf("move right after a confirmed shift = %q, want accepted", got[0])
+	}
+}
+
+func TestValidatorCheckReasons(t *testing.T) {
+	v, st, ts := newWarmValidator(t)
+	last := st.last
+
+	tests := []struct {
+		name  string
+		point models.MStockPrice
+		want  string
+	}{
+		{"next bar", models.MStockPrice{Price: 100.05, Volume: 5, Timestamp: ts}, ""},
+		{"same timestamp", models.MStockPrice{Price: 100.05, Volume: 5, Timestamp: last.Timestamp}, ""},
+		{"regression", models.MStockPrice{Price: 100.05, Volume: 5, Timestamp: last.Timestamp - 1}, utils.ValidationReasonRegression},
+		{"future", models.MStockPrice{Price: 100.05, Volume: 5, Timestamp: ts + utils.DefaultValidationMaxFuture + 1}, utils.ValidationReasonFuture},
+		{"future within tolerance", models.MStockPrice{Price: 100.05, Volume: 5, Timestamp: ts + utils.DefaultValidationMaxFuture}, ""},
+	}
+
+	for _, tt := range tests {
+		tt.point.Symbol = "TEST"
+		if got, _ := v.check("TEST", st, tt.point, ts); got != tt.want {
+			t.Errorf("%s: reason = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidatorStalePrints(t *testing.T) {
+	v, st, ts := newWarmValidator(t)
+
+	// The first print is new; staleRepeats identical ones after it are tolerated
+	var reasons []string
+	for i := 0; i <= utils.DefaultValidationStaleRepeats+1; i++ {
+		p := models.MStockPrice{Symbol: "TEST", Price: 100.2, Volume: 7, Timestamp: ts}
+		reason, _ := v.check("TEST", st, p, ts)
+		if reason == "" {
+			v.accept(st, p)
+		}
+		reasons = append(reasons, reason)
+		ts += 300
+	}
+
+	for i, reason := range reasons {
+		want := ""
+		if i == len(reasons)-1 {
+			want = utils.ValidationReasonStale
+		}
+		if reason != want {
+			t.Errorf("print %d: reason = %q, want %q", i, reason, want)
+		}
+	}
+}
+
+func newReleaseValidator(t *testing.T) *Validator {
+	t.Helper()
+	cfg := &models.MConfig{WindowsAgg: []string{"5m", "15m"}}
+	cfg.Validation.Enabled = true
+	cfg.DataSource.UpdateIntervalSeconds = 60
+	cfg.Storage.DBPath = filepath.Join(t.TempDir(), "validation.db")
+	log := logger.NewLogger(cfg, "test")
+
+	db, err := storage.NewAsyncSQLiteDB(cfg, log)
+	if err != nil {
+		t.Fatalf("NewAsyncSQLiteDB: %v", err)
+	}
+	if err := db.Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewValidator(cfg, db, log)
+}
+
+func TestValidatorRelease(t *testing.T) {
+	now := time.Now().UTC().Unix()
+
+	tests := []struct {
+		name     string
+		accepted int64 // Timestamp of the last accepted point (0 = none)
+		point    int64
+		err      error
+	}{
+		{"newer point, open candle", now - 10, now - 5, nil},
+		{"older than the last accepted point", now - 5, now - 10, ErrReleaseTooLate},
+		{"same timestamp as the last accepted point", now - 5, now - 5, ErrReleaseTooLate},
+		{"candle closed by the clock", 0, now - 3600, ErrReleaseTooLate},
+	}
+
+	for _, tt := range tests {
+		v := newReleaseValidator(t)
+		if tt.accepted > 0 {
+			v.ValidateBatch(map[string][]models.MStockPrice{"TEST": {{Symbol: "TEST", Price: 100, Volume: 1, Timestamp: tt.accepted}}})
+		}
+		point := models.MStockPrice{Symbol: "TEST", Price: 150, Volume: 2, Timestamp: tt.point}
+		v.quarantine = []models.MQuarantinedPrice{{ID: 7, Price: point, Reason: utils.ValidationReasonPriceJump}}
+
+		released, err := v.Release(7)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: error %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if tt.err != nil {
+			if len(v.Quarantined("", 0)) != 1 || len(v.released) != 0 {
+				t.Errorf("%s: refused point left the quarantine", tt.name)
+			}
+			continue
+		}
+
+		if released.Price != point || released.ReleasedAt == 0 || len(v.Quarantined("", 0)) != 0 {
+			t.Errorf("%s: released %+v", tt.name, released)
+		}
+		if batch := <-v.released; len(batch["TEST"]) != 1 || batch["TEST"][0] != point {
+			t.Errorf("%s: queued %+v", tt.name, batch)
+		}
+		// The released point is the new reference: an older one is now a regression
+		if reason, _ := v.check("TEST", v.symbols["TEST"], models.MStockPrice{Symbol: "TEST", Price: 150, Timestamp: tt.point - 1}, now); reason != utils.ValidationReasonRegression {
+			t.Errorf("%s: point before the released one = %q", tt.name, reason)
+		}
+	}
+}